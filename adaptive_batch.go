@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// adaptiveBatchIncrease 每次耗时达标后批大小的增量
+const adaptiveBatchIncrease = 16
+
+// adaptiveBatcher 根据 Remote MGET 的耗时调整单次读取的键数量（加性增、乘性减）
+type adaptiveBatcher struct {
+	target  time.Duration
+	minSize int
+	maxSize int
+	size    atomic.Int64
+}
+
+func newAdaptiveBatcher(target time.Duration, minSize, maxSize int) *adaptiveBatcher {
+	b := &adaptiveBatcher{target: target, minSize: minSize, maxSize: maxSize}
+	b.size.Store(int64(minSize))
+	return b
+}
+
+// batchSize 返回当前的批大小
+func (b *adaptiveBatcher) batchSize() int {
+	return int(b.size.Load())
+}
+
+// observe 根据一次读取的键数量和耗时调整批大小，只有满批的读取才会增加批大小
+func (b *adaptiveBatcher) observe(keys int, dur time.Duration) {
+	size := b.size.Load()
+	next := size
+	if dur > b.target {
+		next = max(size/2, int64(b.minSize))
+	} else if keys >= int(size) {
+		next = min(size+adaptiveBatchIncrease, int64(b.maxSize))
+	}
+	if next != size {
+		b.size.CompareAndSwap(size, next)
+	}
+}
+
+// mgetRemote 从 Remote 批量读取键及其缺失值标记，开启自适应批量读取时按当前批大小拆分为多次 MGET
+func (c *LayeredCache) mgetRemote(ctx context.Context, keys []string) (map[string][]byte, error) {
+	if c.batcher == nil {
+		return c.mgetChunked(ctx, keys)
+	}
+
+	result := make(map[string][]byte)
+	for len(keys) > 0 {
+		chunk := keys[:min(c.batcher.batchSize(), len(keys))]
+		keys = keys[len(chunk):]
+
+		start := time.Now()
+		data, err := c.remote.MGet(ctx, withNotFoundKeys(chunk))
+		if err != nil && !IsNotFound(err) {
+			return nil, err
+		}
+		c.batcher.observe(len(chunk), time.Since(start))
+
+		for key, value := range data {
+			result[key] = value
+		}
+	}
+	return result, nil
+}
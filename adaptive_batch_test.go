@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// slowRemote 按读取的键数量模拟 MGET 耗时，并记录每次读取的键数量
+type slowRemote struct {
+	storage.Remote
+	perKey time.Duration
+
+	mu      sync.Mutex
+	batches []int
+}
+
+func (r *slowRemote) MGet(ctx context.Context, keys []string) (map[string][]byte, error) {
+	r.mu.Lock()
+	r.batches = append(r.batches, len(keys)/2)
+	r.mu.Unlock()
+
+	time.Sleep(time.Duration(len(keys)) * r.perKey)
+	return r.Remote.MGet(ctx, keys)
+}
+
+func TestAdaptiveBatcher(t *testing.T) {
+	b := newAdaptiveBatcher(10*time.Millisecond, 4, 40)
+	assert.Equal(t, 4, b.batchSize())
+
+	t.Run("满批且耗时达标时增加", func(t *testing.T) {
+		b.observe(4, time.Millisecond)
+		assert.Equal(t, 20, b.batchSize())
+		b.observe(20, time.Millisecond)
+		b.observe(36, time.Millisecond)
+		assert.Equal(t, 40, b.batchSize())
+	})
+
+	t.Run("未满批时不变", func(t *testing.T) {
+		b.size.Store(20)
+		b.observe(5, time.Millisecond)
+		assert.Equal(t, 20, b.batchSize())
+	})
+
+	t.Run("超时时减半", func(t *testing.T) {
+		b.observe(20, 20*time.Millisecond)
+		assert.Equal(t, 10, b.batchSize())
+		b.observe(10, 20*time.Millisecond)
+		b.observe(5, 20*time.Millisecond)
+		assert.Equal(t, 4, b.batchSize())
+	})
+}
+
+func TestLayeredCache_AdaptiveBatch(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("配置校验", func(t *testing.T) {
+		for _, opt := range []Option{
+			WithConfigAdaptiveBatch(0, 1, 10),
+			WithConfigAdaptiveBatch(time.Millisecond, 0, 10),
+			WithConfigAdaptiveBatch(time.Millisecond, 10, 5),
+		} {
+			_, err := NewCache(WithConfigRemote(createRemoteAdapter(t)), opt)
+			assert.ErrorIs(t, err, errors.ErrInvalidAdaptiveBatch)
+		}
+
+		_, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigAdaptiveBatch(time.Millisecond, 1, 10))
+		assert.ErrorIs(t, err, errors.ErrRemoteRequired)
+	})
+
+	t.Run("拆分读取并根据耗时调整批大小", func(t *testing.T) {
+		remote := &slowRemote{Remote: createRemoteAdapter(t), perKey: 100 * time.Microsecond}
+		cache, err := NewCache(WithConfigRemote(remote), WithConfigAdaptiveBatch(5*time.Millisecond, 4, 100))
+		assert.NoError(t, err)
+
+		keys := make([]string, 100)
+		values := make(map[string]any, len(keys))
+		for i := range keys {
+			keys[i] = fmt.Sprintf("k%d", i)
+			values[keys[i]] = "v"
+		}
+		assert.NoError(t, cache.MSet(ctx, values))
+
+		for i := 0; i < 5; i++ {
+			result := make(map[string]string)
+			assert.NoError(t, cache.MGet(ctx, keys, &result))
+			assert.Len(t, result, len(keys))
+		}
+
+		// 每个键（含缺失值标记）耗时 0.2ms，5ms 的目标对应约 25 个键
+		size := cache.(*LayeredCache).batcher.batchSize()
+		assert.GreaterOrEqual(t, size, 4)
+		assert.LessOrEqual(t, size, 36)
+		assert.Equal(t, 4, remote.batches[0])
+		for _, n := range remote.batches {
+			assert.LessOrEqual(t, n, 100)
+		}
+	})
+}
@@ -0,0 +1,208 @@
+package cache
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+const (
+	// asyncWriteAttempts 异步 Remote 写入的最大尝试次数
+	asyncWriteAttempts = 3
+
+	// asyncWriteBackoff 异步 Remote 写入重试的退避时间，第 n 次重试等待 n 倍
+	asyncWriteBackoff = 50 * time.Millisecond
+)
+
+// remoteWrite 一次排队的 Remote 写入
+type remoteWrite struct {
+	ctx  context.Context
+	data map[string][]byte
+	ttl  time.Duration
+}
+
+// asyncWriter 在后台执行 Remote 写入的工作池
+// 键按哈希固定分配给一个 worker，同一个键的写入按提交顺序执行
+type asyncWriter struct {
+	queues []chan remoteWrite
+	write  func(w remoteWrite)
+
+	// closeMu 保护 closed，入队时持有读锁，保证关闭队列后不再入队
+	closeMu sync.RWMutex
+	closed  bool
+
+	// 已入队但尚未执行完的写入数，归零时关闭 idle
+	mu      sync.Mutex
+	pending int
+	idle    chan struct{}
+
+	workers sync.WaitGroup
+}
+
+func newAsyncWriter(queueSize, workers int, write func(w remoteWrite)) *asyncWriter {
+	w := &asyncWriter{
+		queues: make([]chan remoteWrite, workers),
+		write:  write,
+		idle:   make(chan struct{}),
+	}
+	close(w.idle)
+
+	for i := range w.queues {
+		queue := make(chan remoteWrite, max(1, queueSize/workers))
+		w.queues[i] = queue
+		w.workers.Add(1)
+		go func() {
+			defer w.workers.Done()
+			for rw := range queue {
+				w.write(rw)
+				w.end()
+			}
+		}()
+	}
+	return w
+}
+
+// enqueue 将写入按键分配到各 worker 的队列，队列已满时阻塞等待；已关闭时返回 false，由调用方同步写入
+func (w *asyncWriter) enqueue(ctx context.Context, data map[string][]byte, ttl time.Duration) (bool, error) {
+	w.closeMu.RLock()
+	defer w.closeMu.RUnlock()
+
+	if w.closed {
+		return false, nil
+	}
+
+	// 写入在调用返回后执行，不随调用方取消
+	writeCtx := context.WithoutCancel(ctx)
+	for i, part := range w.partition(data) {
+		if len(part) == 0 {
+			continue
+		}
+		w.begin()
+		select {
+		case w.queues[i] <- remoteWrite{ctx: writeCtx, data: part, ttl: ttl}:
+		case <-ctx.Done():
+			w.end()
+			return true, ctx.Err()
+		}
+	}
+	return true, nil
+}
+
+// partition 按键的哈希将数据分配给各 worker
+func (w *asyncWriter) partition(data map[string][]byte) []map[string][]byte {
+	parts := make([]map[string][]byte, len(w.queues))
+	if len(parts) == 1 {
+		parts[0] = data
+		return parts
+	}
+
+	for key, value := range data {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(key))
+		i := int(h.Sum32() % uint32(len(parts)))
+		if parts[i] == nil {
+			parts[i] = make(map[string][]byte)
+		}
+		parts[i][key] = value
+	}
+	return parts
+}
+
+func (w *asyncWriter) begin() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.pending == 0 {
+		w.idle = make(chan struct{})
+	}
+	w.pending++
+}
+
+func (w *asyncWriter) end() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pending--
+	if w.pending == 0 {
+		close(w.idle)
+	}
+}
+
+// flush 等待当前已入队的写入全部执行完
+func (w *asyncWriter) flush(ctx context.Context) error {
+	w.mu.Lock()
+	idle := w.idle
+	w.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// close 停止接收新的写入，等待队列中的写入执行完后退出 worker
+func (w *asyncWriter) close(ctx context.Context) error {
+	w.closeMu.Lock()
+	if !w.closed {
+		w.closed = true
+		for _, queue := range w.queues {
+			close(queue)
+		}
+	}
+	w.closeMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		w.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// writeRemoteAsync 开启异步写入时将 Remote 写入排队，返回 false 表示需要同步写入
+func (c *LayeredCache) writeRemoteAsync(ctx context.Context, data map[string][]byte, ttl time.Duration) (bool, error) {
+	if c.async == nil {
+		return false, nil
+	}
+	return c.async.enqueue(ctx, data, ttl)
+}
+
+// asyncRemoteWrite 执行一次排队的 Remote 写入，失败时退避重试，重试耗尽后丢弃并计入 AsyncWriteDrops
+func (c *LayeredCache) asyncRemoteWrite(w remoteWrite) {
+	for attempt := 1; ; attempt++ {
+		var err error
+		if len(w.data) == 1 {
+			for key, data := range w.data {
+				err = c.setRemote(w.ctx, key, data, w.ttl)
+			}
+		} else {
+			err = c.msetRemote(w.ctx, w.data, w.ttl)
+		}
+		if c.stats.remoteError(err) == nil {
+			_ = c.stats.remoteError(c.deleteRemote(w.ctx, notFoundKeys(mapKeys(w.data))))
+			return
+		}
+		if attempt == asyncWriteAttempts {
+			c.stats.asyncWriteDrops.Add(int64(len(w.data)))
+			return
+		}
+		time.Sleep(asyncWriteBackoff * time.Duration(attempt))
+	}
+}
+
+// Flush 等待调用前已排队的异步 Remote 写入全部执行完，未开启异步写入时直接返回
+func (c *LayeredCache) Flush(ctx context.Context) error {
+	if c.async == nil {
+		return nil
+	}
+	return c.async.flush(ctx)
+}
@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingRemote 写入总是失败
+type failingRemote struct {
+	storage.Remote
+}
+
+func (r failingRemote) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return stderrors.New("remote down")
+}
+
+func (r failingRemote) MSet(ctx context.Context, keyValues map[string][]byte, ttl time.Duration) error {
+	return stderrors.New("remote down")
+}
+
+func TestLayeredCache_AsyncRemoteWrites(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("配置校验", func(t *testing.T) {
+		_, err := NewCache(WithConfigRemote(createRemoteAdapter(t)), WithConfigAsyncRemoteWrites(0, 1))
+		assert.ErrorIs(t, err, errors.ErrInvalidAsyncRemoteWrites)
+
+		_, err = NewCache(WithConfigRemote(createRemoteAdapter(t)), WithConfigAsyncRemoteWrites(10, 0))
+		assert.ErrorIs(t, err, errors.ErrInvalidAsyncRemoteWrites)
+	})
+
+	t.Run("Set 不等待 Remote 写入", func(t *testing.T) {
+		remote := &gatedRemote{Remote: createRemoteAdapter(t), gate: make(chan struct{})}
+		c, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigRemote(remote),
+			WithConfigAsyncRemoteWrites(10, 2),
+		)
+		assert.NoError(t, err)
+
+		assert.NoError(t, c.Set(ctx, "k", "v"))
+		var value string
+		assert.NoError(t, c.Get(ctx, "k", &value))
+		assert.Equal(t, "v", value)
+
+		assert.Eventually(t, func() bool { return remote.setCalls.Load() == 1 }, time.Second, time.Millisecond)
+		_, err = remote.Remote.Get(ctx, "k")
+		assert.ErrorIs(t, err, ErrNotFound, "Remote 写入尚未执行")
+
+		close(remote.gate)
+		assert.NoError(t, c.Flush(ctx))
+		_, err = remote.Remote.Get(ctx, "k")
+		assert.NoError(t, err)
+	})
+
+	t.Run("重试耗尽后丢弃", func(t *testing.T) {
+		c, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigRemote(failingRemote{Remote: createRemoteAdapter(t)}),
+			WithConfigAsyncRemoteWrites(10, 1),
+		)
+		assert.NoError(t, err)
+		lc := c.(*LayeredCache)
+
+		assert.NoError(t, c.MSet(ctx, map[string]any{"a": 1, "b": 2}))
+		assert.NoError(t, c.Flush(ctx))
+		assert.Equal(t, int64(2), lc.Stats().AsyncWriteDrops)
+		assert.Equal(t, int64(asyncWriteAttempts), lc.Stats().RemoteErrors)
+	})
+
+	t.Run("Close 写完队列后改为同步写入", func(t *testing.T) {
+		remote := createRemoteAdapter(t)
+		c, err := NewCache(WithConfigRemote(remote), WithConfigAsyncRemoteWrites(100, 4))
+		assert.NoError(t, err)
+		lc := c.(*LayeredCache)
+
+		values := make(map[string]any)
+		for _, key := range []string{"a", "b", "c", "d", "e", "f"} {
+			values[key] = key
+		}
+		assert.NoError(t, c.MSet(ctx, values))
+		assert.NoError(t, lc.Close(ctx))
+
+		found, err := remote.MGet(ctx, mapKeys(values))
+		assert.NoError(t, err)
+		assert.Len(t, found, len(values))
+
+		assert.NoError(t, c.Set(ctx, "after", "v"))
+		_, err = remote.Get(ctx, "after")
+		assert.NoError(t, err)
+	})
+}
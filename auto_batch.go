@@ -0,0 +1,181 @@
+package cache
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/serializer"
+)
+
+// AutoBatcher 将一个时间窗口内的单键读取合并为一次批量读取（dataloader 模式），
+// 窗口内的所有键共用一次 Remote MGET 和一次 batchLoader 调用，适用于 GraphQL / REST 中扇出的单键读取
+type AutoBatcher struct {
+	cache   *LayeredCache
+	window  time.Duration
+	maxKeys int
+	opts    []GetOption
+
+	// serializer opts 中 WithSerializer 指定的序列化器
+	serializer serializer.Serializer
+
+	mu sync.Mutex
+	// pending 按 ctx 中的键前缀分开收集的批次
+	pending map[string]*autoBatch
+}
+
+// autoBatch 一个窗口内收集的读取
+type autoBatch struct {
+	ctx   context.Context
+	keys  []string
+	seen  map[string]struct{}
+	timer *time.Timer
+
+	// 读取结束后关闭
+	done chan struct{}
+	// tracked 批次是否已登记到缓存的生命周期，登记后 Close 等待读取结束
+	tracked bool
+
+	// 读取结果，不在 data 中的键视为不存在，notFound 为命中缺失值标记的键
+	data     map[string][]byte
+	notFound map[string]struct{}
+	err      error
+}
+
+// AutoBatcher 创建合并读取器，window 为收集读取的时间窗口，收集到 maxKeys 个不同的键时立即读取
+// opts 作用于每次合并后的批量读取，通常包含 WithBatchLoader；合并后的读取使用窗口内第一个调用方的 context（不随其取消）
+// Close 等待已收集的批次读取结束；关闭后仍可读取，但不再等待
+func (c *LayeredCache) AutoBatcher(window time.Duration, maxKeys int, opts ...GetOption) (*AutoBatcher, error) {
+	if window <= 0 || maxKeys <= 0 {
+		return nil, errors.ErrInvalidAutoBatch
+	}
+	config := newGetOptions()
+	if err := applyGetOptions(config, opts...); err != nil {
+		return nil, c.misuse(err)
+	}
+	return &AutoBatcher{cache: c, window: window, maxKeys: maxKeys, opts: opts, serializer: config.serializer, pending: make(map[string]*autoBatch)}, nil
+}
+
+// Get 读取单个键，与窗口内的其他读取合并执行；键不存在且 batchLoader 没有返回时返回 ErrNotFound，命中缺失值标记时返回 ErrNotFoundCached
+func (b *AutoBatcher) Get(ctx context.Context, key string, target any) error {
+	b.cache.checkGetTarget(target)
+
+	batch := b.add(ctx, key)
+	select {
+	case <-batch.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if batch.err != nil {
+		return batch.err
+	}
+	data, ok := batch.data[key]
+	if !ok {
+		if _, cached := batch.notFound[key]; cached {
+			return errors.ErrNotFoundCached
+		}
+		return errors.ErrNotFound
+	}
+	return b.cache.decode(data, target, b.serializer)
+}
+
+// add 将键加入当前窗口的批次，批次达到 maxKeys 时立即读取
+func (b *AutoBatcher) add(ctx context.Context, key string) *autoBatch {
+	prefix := keyPrefixOf(ctx)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	batch := b.pending[prefix]
+	if batch == nil {
+		batch = &autoBatch{
+			ctx:     context.WithoutCancel(ctx),
+			seen:    make(map[string]struct{}),
+			done:    make(chan struct{}),
+			tracked: b.cache.life.enter(),
+		}
+		batch.timer = time.AfterFunc(b.window, func() { b.flush(prefix, batch) })
+		b.pending[prefix] = batch
+	}
+	if _, ok := batch.seen[key]; !ok {
+		batch.seen[key] = struct{}{}
+		batch.keys = append(batch.keys, key)
+	}
+	if len(batch.keys) >= b.maxKeys {
+		batch.timer.Stop()
+		delete(b.pending, prefix)
+		go b.fetch(batch)
+	}
+	return batch
+}
+
+// flush 窗口到期时读取批次，批次已因达到 maxKeys 被取出时忽略
+func (b *AutoBatcher) flush(prefix string, batch *autoBatch) {
+	b.mu.Lock()
+	if b.pending[prefix] != batch {
+		b.mu.Unlock()
+		return
+	}
+	delete(b.pending, prefix)
+	b.mu.Unlock()
+
+	b.fetch(batch)
+}
+
+// fetch 批量读取批次中的键并唤醒等待者
+func (b *AutoBatcher) fetch(batch *autoBatch) {
+	c := b.cache
+	defer func() {
+		close(batch.done)
+		if batch.tracked {
+			c.life.exit()
+		}
+	}()
+
+	scope, ctx := c.takeKeyContext(batch.ctx)
+	keys, unscope := batch.keys, func(key string) string { return key }
+	opts := b.opts
+	if !scope.empty() {
+		unscope = scope.unscoper(keys)
+		keys, opts = scopeKeys(scope, keys), scopeGetOptions(scope, unscope, opts)
+	}
+
+	config := newGetOptions()
+	if batch.err = applyGetOptions(config, opts...); batch.err != nil {
+		return
+	}
+	notFoundCached := config.notFoundCached
+	config.notFoundCached = func(key string) {
+		if notFoundCached != nil {
+			notFoundCached(key)
+		}
+		if batch.notFound == nil {
+			batch.notFound = make(map[string]struct{})
+		}
+		batch.notFound[unscope(key)] = struct{}{}
+	}
+
+	found, stale, missing, err := c.batchLookup(ctx, keys, config)
+	if err != nil {
+		batch.err = err
+		return
+	}
+	loaded, err := c.batchLoad(ctx, missing, config)
+	if err != nil && !c.serveStaleBatch(found, stale, missing, config) {
+		batch.err = err
+		return
+	}
+	maps.Copy(found, loaded)
+	if config.batchLoader == nil {
+		c.serveStaleBatch(found, stale, missing, config)
+	}
+
+	batch.data = make(map[string][]byte, len(found))
+	for key, data := range found {
+		batch.data[unscope(key)] = data
+	}
+	c.stats.autoBatches.Add(1)
+}
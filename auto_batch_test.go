@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_AutoBatcher(t *testing.T) {
+	ctx := context.Background()
+
+	newCache := func(t *testing.T) (*LayeredCache, *countingRemote) {
+		remote := &countingRemote{Remote: createRemoteAdapter(t)}
+		cache, err := NewCache(WithConfigMemory(createOtterAdapter(t)), WithConfigRemote(remote))
+		assert.NoError(t, err)
+		return cache.(*LayeredCache), remote
+	}
+
+	t.Run("配置校验", func(t *testing.T) {
+		c, _ := newCache(t)
+		_, err := c.AutoBatcher(0, 10)
+		assert.ErrorIs(t, err, errors.ErrInvalidAutoBatch)
+		_, err = c.AutoBatcher(time.Millisecond, 0)
+		assert.ErrorIs(t, err, errors.ErrInvalidAutoBatch)
+	})
+
+	t.Run("窗口内的读取合并为一次 MGET 和一次 batchLoader", func(t *testing.T) {
+		c, remote := newCache(t)
+		assert.NoError(t, c.Set(ctx, "user:1", "alice"))
+		c.memory.Delete("user:1")
+
+		var loads atomic.Int32
+		b, err := c.AutoBatcher(20*time.Millisecond, 100, WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+			loads.Add(1)
+			assert.ElementsMatch(t, []string{"user:2", "user:3"}, keys)
+			return map[string]any{"user:2": "bob"}, nil
+		}))
+		assert.NoError(t, err)
+
+		results := make(map[string]string)
+		errs := make(map[string]error)
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for _, key := range []string{"user:1", "user:2", "user:3", "user:1"} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var value string
+				err := b.Get(ctx, key, &value)
+				mu.Lock()
+				results[key], errs[key] = value, err
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, "alice", results["user:1"])
+		assert.Equal(t, "bob", results["user:2"])
+		assert.ErrorIs(t, errs["user:3"], ErrNotFound)
+		assert.Equal(t, int32(1), remote.mgetCalls.Load())
+		assert.Equal(t, int32(1), loads.Load())
+		assert.Equal(t, int64(1), c.Stats().AutoBatches)
+	})
+
+	t.Run("达到 maxKeys 立即读取", func(t *testing.T) {
+		c, _ := newCache(t)
+		assert.NoError(t, c.Set(ctx, "k1", "v1"))
+		assert.NoError(t, c.Set(ctx, "k2", "v2"))
+
+		b, err := c.AutoBatcher(time.Hour, 2)
+		assert.NoError(t, err)
+
+		var wg sync.WaitGroup
+		for _, key := range []string{"k1", "k2"} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var value string
+				assert.NoError(t, b.Get(ctx, key, &value))
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("按 ctx 中的前缀分开合并", func(t *testing.T) {
+		c, _ := newCache(t)
+		assert.NoError(t, c.Set(WithKeyContext(ctx, "t1:"), "k", "one"))
+		assert.NoError(t, c.Set(WithKeyContext(ctx, "t2:"), "k", "two"))
+
+		b, err := c.AutoBatcher(10*time.Millisecond, 100)
+		assert.NoError(t, err)
+
+		var one, two string
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, b.Get(WithKeyContext(ctx, "t1:"), "k", &one))
+		}()
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, b.Get(WithKeyContext(ctx, "t2:"), "k", &two))
+		}()
+		wg.Wait()
+		assert.Equal(t, "one", one)
+		assert.Equal(t, "two", two)
+	})
+	t.Run("命中缺失值标记返回 ErrNotFoundCached", func(t *testing.T) {
+		c, _ := newCache(t)
+		var value string
+		assert.ErrorIs(t, c.Get(ctx, "missing", &value, WithCacheNotFound(true, time.Minute), WithLoader(func(ctx context.Context, key string) (any, error) {
+			return nil, errors.ErrNotFound
+		})), ErrNotFound)
+		assert.ErrorIs(t, c.Get(ctx, "missing", &value), ErrNotFoundCached)
+
+		b, err := c.AutoBatcher(time.Millisecond, 100)
+		assert.NoError(t, err)
+		assert.ErrorIs(t, b.Get(ctx, "missing", &value), ErrNotFoundCached)
+		assert.NotErrorIs(t, b.Get(ctx, "other", &value), ErrNotFoundCached)
+	})
+
+	t.Run("Close 等待正在执行的批量读取", func(t *testing.T) {
+		c, _ := newCache(t)
+		started, release := make(chan struct{}), make(chan struct{})
+		b, err := c.AutoBatcher(time.Millisecond, 1, WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+			close(started)
+			<-release
+			return map[string]any{"k": "v"}, nil
+		}))
+		assert.NoError(t, err)
+
+		done := make(chan error, 1)
+		go func() {
+			var value string
+			done <- b.Get(ctx, "k", &value)
+		}()
+		<-started
+
+		closeCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, c.Close(closeCtx), context.DeadlineExceeded)
+
+		close(release)
+		assert.NoError(t, c.Close(ctx))
+		assert.NoError(t, <-done)
+	})
+}
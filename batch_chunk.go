@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/biu7/layered-cache/storage"
+	"golang.org/x/sync/errgroup"
+)
+
+// chunkKeys 将 keys 按 size 拆分，size 不大于 0 时不拆分
+func chunkKeys(keys []string, size int) [][]string {
+	if size <= 0 || len(keys) <= size {
+		return [][]string{keys}
+	}
+	return slices.Collect(slices.Chunk(keys, size))
+}
+
+// mgetChunked 按拆分大小多次 MGET 读取键及其缺失值标记，各分组最多 remoteConcurrency 个并发执行
+func (c *LayeredCache) mgetChunked(ctx context.Context, keys []string) (map[string][]byte, error) {
+	chunks := chunkKeys(keys, c.chunkSize)
+	if len(chunks) == 1 {
+		return c.remote.MGet(ctx, withNotFoundKeys(keys))
+	}
+
+	var mu sync.Mutex
+	result := make(map[string][]byte)
+	err := c.eachChunk(ctx, chunks, func(ctx context.Context, chunk []string) error {
+		data, err := c.remote.MGet(ctx, withNotFoundKeys(chunk))
+		if err != nil && !IsNotFound(err) {
+			return err
+		}
+		mu.Lock()
+		maps.Copy(result, data)
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// msetRemote 按拆分大小多次 MSET 写入 Remote，各分组最多 remoteConcurrency 个并发执行
+func (c *LayeredCache) msetRemote(ctx context.Context, data map[string][]byte, ttl time.Duration) error {
+	if c.chunkSize <= 0 || len(data) <= c.chunkSize {
+		return c.remote.MSet(ctx, data, ttl)
+	}
+
+	return c.eachChunk(ctx, chunkKeys(slices.Collect(maps.Keys(data)), c.chunkSize), func(ctx context.Context, chunk []string) error {
+		part := make(map[string][]byte, len(chunk))
+		for _, key := range chunk {
+			part[key] = data[key]
+		}
+		return c.remote.MSet(ctx, part, ttl)
+	})
+}
+
+// msetRemoteGroups 将过期时间不同的多组数据写入 Remote
+// Remote 实现 storage.MultiTTLSetter 时合并为一次 MSetWithTTLs（按拆分大小拆分），否则每组分别写入
+func (c *LayeredCache) msetRemoteGroups(ctx context.Context, groups []ttlGroup) error {
+	setter, ok := c.remote.(storage.MultiTTLSetter)
+	if !ok || len(groups) == 1 {
+		for _, group := range groups {
+			if err := c.msetRemote(ctx, group.data, group.remoteTTL); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	entries := make(map[string]storage.ValueWithTTL)
+	for _, group := range groups {
+		for key, data := range group.data {
+			entries[key] = storage.ValueWithTTL{Value: data, TTL: group.remoteTTL}
+		}
+	}
+	if c.chunkSize <= 0 || len(entries) <= c.chunkSize {
+		return setter.MSetWithTTLs(ctx, entries)
+	}
+
+	return c.eachChunk(ctx, chunkKeys(slices.Collect(maps.Keys(entries)), c.chunkSize), func(ctx context.Context, chunk []string) error {
+		part := make(map[string]storage.ValueWithTTL, len(chunk))
+		for _, key := range chunk {
+			part[key] = entries[key]
+		}
+		return setter.MSetWithTTLs(ctx, part)
+	})
+}
+
+// eachChunk 对每个分组执行 fn，最多 remoteConcurrency 个并发，任一分组出错时取消其他分组
+func (c *LayeredCache) eachChunk(ctx context.Context, chunks [][]string, fn func(ctx context.Context, chunk []string) error) error {
+	if c.remoteConcurrency <= 1 {
+		for _, chunk := range chunks {
+			if err := fn(ctx, chunk); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(c.remoteConcurrency)
+	for _, chunk := range chunks {
+		g.Go(func() error {
+			return fn(ctx, chunk)
+		})
+	}
+	return g.Wait()
+}
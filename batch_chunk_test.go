@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// chunkRecordingRemote 记录每次 MGET/MSET 的键数量
+type chunkRecordingRemote struct {
+	storage.Remote
+
+	mu    sync.Mutex
+	mgets []int
+	msets []int
+}
+
+func (r *chunkRecordingRemote) MGet(ctx context.Context, keys []string) (map[string][]byte, error) {
+	r.mu.Lock()
+	r.mgets = append(r.mgets, len(keys))
+	r.mu.Unlock()
+	return r.Remote.MGet(ctx, keys)
+}
+
+func (r *chunkRecordingRemote) MSet(ctx context.Context, values map[string][]byte, ttl time.Duration) error {
+	r.mu.Lock()
+	r.msets = append(r.msets, len(values))
+	r.mu.Unlock()
+	return r.Remote.MSet(ctx, values, ttl)
+}
+
+func TestLayeredCache_BatchChunkSize(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("配置校验", func(t *testing.T) {
+		_, err := NewCache(WithConfigRemote(createRemoteAdapter(t)), WithConfigBatchChunkSize(-1))
+		assert.ErrorIs(t, err, errors.ErrInvalidBatchChunkSize)
+	})
+
+	t.Run("拆分 Remote 读写和 batchLoader", func(t *testing.T) {
+		remote := &chunkRecordingRemote{Remote: createRemoteAdapter(t)}
+		c, err := NewCache(WithConfigRemote(remote), WithConfigBatchChunkSize(2))
+		assert.NoError(t, err)
+
+		keys := make([]string, 5)
+		values := make(map[string]any, len(keys))
+		for i := range keys {
+			keys[i] = fmt.Sprintf("k%d", i)
+			values[keys[i]] = i
+		}
+		assert.NoError(t, c.MSet(ctx, values))
+		assert.ElementsMatch(t, []int{2, 2, 1}, remote.msets)
+
+		var loads []int
+		result := make(map[string]int)
+		err = c.MGet(ctx, append(keys, "m0", "m1", "m2"), &result, WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+			loads = append(loads, len(keys))
+			values := make(map[string]any, len(keys))
+			for _, key := range keys {
+				values[key] = 9
+			}
+			return values, nil
+		}))
+		assert.NoError(t, err)
+		assert.Len(t, result, 8)
+		assert.Equal(t, []int{2 * 2, 2 * 2, 2 * 2, 2 * 2}, remote.mgets, "每次读取包含键和缺失值标记")
+		assert.Equal(t, []int{2, 1}, loads)
+	})
+
+	t.Run("并发执行拆分后的分组", func(t *testing.T) {
+		_, err := NewCache(WithConfigRemote(createRemoteAdapter(t)), WithConfigRemoteConcurrency(0))
+		assert.ErrorIs(t, err, errors.ErrInvalidRemoteConcurrency)
+
+		remote := &chunkRecordingRemote{Remote: createRemoteAdapter(t)}
+		c, err := NewCache(WithConfigRemote(remote), WithConfigBatchChunkSize(10), WithConfigRemoteConcurrency(4))
+		assert.NoError(t, err)
+
+		values := make(map[string]any, 1000)
+		keys := make([]string, 0, 1000)
+		for i := range 1000 {
+			key := fmt.Sprintf("k%d", i)
+			values[key], keys = i, append(keys, key)
+		}
+		assert.NoError(t, c.MSet(ctx, values))
+		assert.Len(t, remote.msets, 100)
+
+		result := make(map[string]int)
+		assert.NoError(t, c.MGet(ctx, keys, &result))
+		assert.Len(t, result, 1000)
+		assert.Equal(t, 500, result["k500"])
+		assert.Len(t, remote.mgets, 100)
+	})
+}
@@ -0,0 +1,126 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+)
+
+// batchFlight 按键合并并发的批量加载，键集合部分重叠的 MGet 只加载各自独占的键，共享的键等待正在进行的加载
+type batchFlight struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// flightCall 单个键正在进行的加载
+type flightCall struct {
+	// 加载结束后关闭
+	done chan struct{}
+
+	// 加载结果，data 为 nil 表示 batchLoader 没有返回该键
+	data []byte
+	err  error
+}
+
+func newBatchFlight() *batchFlight {
+	return &batchFlight{calls: make(map[string]*flightCall)}
+}
+
+// claim 为没有正在加载的键登记加载，返回需要由本次调用加载的键以及需要等待的其他调用
+func (f *batchFlight) claim(keys []string) (owned []string, waiting map[string]*flightCall) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, key := range uniqueKeys(keys) {
+		if call, ok := f.calls[key]; ok {
+			if waiting == nil {
+				waiting = make(map[string]*flightCall)
+			}
+			waiting[key] = call
+			continue
+		}
+		f.calls[key] = &flightCall{done: make(chan struct{})}
+		owned = append(owned, key)
+	}
+	return owned, waiting
+}
+
+// finish 记录 keys 的加载结果并唤醒等待者
+func (f *batchFlight) finish(keys []string, result map[string][]byte, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, key := range keys {
+		call := f.calls[key]
+		delete(f.calls, key)
+		call.data, call.err = result[key], err
+		close(call.done)
+	}
+}
+
+// coalescedBatchLoad 加载 keys，每个键独立去重：其他调用正在加载的键等待其结果，其余的键合并为一次 batchLoader 调用
+func (c *LayeredCache) coalescedBatchLoad(ctx context.Context, keys []string, config *getOptions) (map[string][]byte, error) {
+	owned, waiting := c.flights.claim(keys)
+
+	result := make(map[string][]byte, len(keys))
+	if len(owned) > 0 {
+		chunks := chunkKeys(owned, c.chunkSize)
+		for i, chunk := range chunks {
+			loaded, err := c.load(ctx, c.buildBatchKey(chunk), config, func(ctx context.Context) (any, error) {
+				return c.batchLoadAndCache(ctx, chunk, config)
+			})
+			// 在 singleflight 之外结束登记：本次调用可能复用了其他调用尚未结束的同名加载，此时加载函数不会执行
+			var data map[string][]byte
+			if err == nil {
+				data = loaded.(map[string][]byte)
+			}
+			c.flights.finish(chunk, data, err)
+			if err != nil {
+				// 后续分组不再加载，唤醒等待这些键的调用
+				for _, rest := range chunks[i+1:] {
+					c.flights.finish(rest, nil, err)
+				}
+				return nil, err
+			}
+			for key, value := range data {
+				result[key] = value
+			}
+		}
+	} else {
+		c.observeSingleflight(true)
+	}
+
+	if len(waiting) == 0 {
+		return result, nil
+	}
+
+	timeout := config.loaderTimeout
+	if timeout == 0 {
+		timeout = c.defaultLoaderTimeout
+	}
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	for key, call := range waiting {
+		select {
+		case <-call.done:
+		case <-expired:
+			return nil, errors.ErrLoaderTimeout
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if call.err != nil {
+			return nil, call.err
+		}
+		if call.data != nil {
+			result[key] = call.data
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,141 @@
+package cache
+
+import (
+	"context"
+	stderrors "errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_BatchFlight(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("部分重叠的 MGet 只加载独占的键", func(t *testing.T) {
+		c, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigRemote(createRemoteAdapter(t)))
+		assert.NoError(t, err)
+
+		var mu sync.Mutex
+		var calls [][]string
+		started := make(chan struct{})
+		release := make(chan struct{})
+		loader := func(ctx context.Context, keys []string) (map[string]any, error) {
+			sorted := append([]string(nil), keys...)
+			sort.Strings(sorted)
+			mu.Lock()
+			calls = append(calls, sorted)
+			mu.Unlock()
+
+			if sorted[0] == "a" {
+				close(started)
+				<-release
+			}
+			values := make(map[string]any, len(keys))
+			for _, key := range keys {
+				values[key] = "value-" + key
+			}
+			return values, nil
+		}
+
+		var wg sync.WaitGroup
+		first := make(map[string]string)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, c.MGet(ctx, []string{"a", "b"}, &first, WithBatchLoader(loader)))
+		}()
+		<-started
+
+		second := make(map[string]string)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, c.MGet(ctx, []string{"b", "c"}, &second, WithBatchLoader(loader)))
+		}()
+
+		assert.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(calls) == 2
+		}, time.Second, time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, [][]string{{"a", "b"}, {"c"}}, calls)
+		assert.Equal(t, map[string]string{"a": "value-a", "b": "value-b"}, first)
+		assert.Equal(t, map[string]string{"b": "value-b", "c": "value-c"}, second)
+	})
+
+	t.Run("复用尚未结束的同名加载时仍结束登记", func(t *testing.T) {
+		c, err := NewCache(WithConfigMemory(createMemoryAdapter(t)))
+		assert.NoError(t, err)
+		lc := c.(*LayeredCache)
+
+		// 模拟上一次加载已结束登记、但 singleflight 尚未移除同名调用
+		started, release := make(chan struct{}), make(chan struct{})
+		go func() {
+			_, _, _ = lc.sf.Do(lc.buildBatchKey([]string{"k"}), func() (any, error) {
+				close(started)
+				<-release
+				return map[string][]byte{}, nil
+			})
+		}()
+		<-started
+
+		done := make(chan error)
+		go func() {
+			values := make(map[string]string)
+			done <- c.MGet(ctx, []string{"k"}, &values, WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+				return map[string]any{"k": "stale"}, nil
+			}))
+		}()
+		assert.Eventually(t, func() bool {
+			lc.flights.mu.Lock()
+			defer lc.flights.mu.Unlock()
+			return len(lc.flights.calls) == 1
+		}, time.Second, time.Millisecond)
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		assert.NoError(t, <-done)
+
+		lc.flights.mu.Lock()
+		assert.Empty(t, lc.flights.calls)
+		lc.flights.mu.Unlock()
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		values := make(map[string]string)
+		assert.NoError(t, c.MGet(timeoutCtx, []string{"k"}, &values, WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+			return map[string]any{"k": "fresh"}, nil
+		})))
+		assert.Equal(t, map[string]string{"k": "fresh"}, values)
+	})
+
+	t.Run("等待的键返回加载错误", func(t *testing.T) {
+		c, err := NewCache(WithConfigMemory(createMemoryAdapter(t)))
+		assert.NoError(t, err)
+		lc := c.(*LayeredCache)
+
+		failed := stderrors.New("db down")
+		owned, _ := lc.flights.claim([]string{"a"})
+		assert.Equal(t, []string{"a"}, owned)
+
+		done := make(chan error)
+		go func() {
+			values := make(map[string]string)
+			done <- c.MGet(ctx, []string{"a"}, &values, WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+				t.Error("正在加载的键不应再次调用 batchLoader")
+				return nil, nil
+			}))
+		}()
+
+		assert.Eventually(t, func() bool {
+			return lc.Stats().SingleflightShared == 1
+		}, time.Second, time.Millisecond)
+		lc.flights.finish(owned, nil, failed)
+		assert.ErrorIs(t, <-done, failed)
+	})
+}
@@ -3,27 +3,59 @@ package cache
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"maps"
 	"reflect"
+	"slices"
+	"sync"
 	"time"
 
 	"github.com/biu7/layered-cache/errors"
 	"github.com/biu7/layered-cache/serializer"
 	"github.com/biu7/layered-cache/storage"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/singleflight"
 )
 
 var (
-	notFoundPlaceholder = []byte("__CACHE_NOT_FOUND__")
-	ErrNotFound         = errors.ErrNotFound
+	ErrNotFound       = errors.ErrNotFound
+	ErrNotFoundCached = errors.ErrNotFoundCached
 )
 
 type Cache interface {
 	Set(ctx context.Context, key string, value any, opts ...SetOption) error
 	MSet(ctx context.Context, keyValues map[string]any, opts ...SetOption) error
+	SetNX(ctx context.Context, key string, value any, opts ...SetOption) (bool, error)
+	GetSet(ctx context.Context, key string, value any, target any, opts ...SetOption) error
 	Delete(ctx context.Context, key string) error
+	MDelete(ctx context.Context, keys []string) error
+	DeleteByPrefix(ctx context.Context, prefix string) error
+	Invalidate(ctx context.Context, key string) error
+	InvalidateTag(ctx context.Context, tag string) error
+	DependOn(ctx context.Context, child, parent string) error
 
 	Get(ctx context.Context, key string, target any, opts ...GetOption) error
 	MGet(ctx context.Context, keys []string, target any, opts ...GetOption) error
+	MGetWithMissing(ctx context.Context, keys []string, target any, opts ...GetOption) ([]string, error)
+	MultiFetch(ctx context.Context, requests []FetchRequest) ([]FetchResult, error)
+	Snapshot(ctx context.Context, keys []string) (View, error)
+	Exists(ctx context.Context, key string) (Existence, error)
+	Incr(ctx context.Context, key string, delta int64, opts ...SetOption) (int64, error)
+	Lock(ctx context.Context, key string, ttl time.Duration) (Unlock, error)
+	TTL(ctx context.Context, key string) (memoryKnown bool, remoteTTL time.Duration, err error)
+
+	Expire(ctx context.Context, key string, memoryTTL, remoteTTL time.Duration) error
+	MExpire(ctx context.Context, keys []string, memoryTTL, remoteTTL time.Duration) error
+
+	SweepMemory(ctx context.Context, budget time.Duration) (int, error)
+
+	RemoteKeys(ctx context.Context, prefix string, cursor uint64, count int) ([]string, uint64, error)
+	ScheduleInvalidation(prefix string, cron string) (stop func(), err error)
+
+	Flush(ctx context.Context) error
+	Close(ctx context.Context) error
+
+	Stats() Stats
 }
 
 // LayeredCache 分层缓存实现
@@ -43,6 +75,135 @@ type LayeredCache struct {
 	defaultCacheNotFound    bool
 	defaultCacheNotFoundTTL time.Duration
 
+	// 读修复采样比例
+	readRepairRate float64
+
+	// 删除保护，为 nil 表示关闭
+	shield *deleteShield
+
+	// 是否识别封装格式
+	envelope bool
+
+	// 是否以封装格式写入，滚动升级期间可以只识别不写入
+	envelopeWrite bool
+
+	// Remote 写入合并，为 nil 表示关闭
+	writes *writeCoalescer
+
+	// 运行计数
+	stats stats
+
+	// 开发模式，误用时直接 panic
+	devMode bool
+
+	// 内存条目大小上限，为 0 表示不检查
+	maxEntrySize int
+
+	// 键依赖存储，为 nil 表示未开启
+	deps storage.SetStore
+
+	// 指标采集，为 nil 表示不采集
+	metrics MetricsCollector
+
+	// 按标准化标签上报的指标采集，metrics 实现 LabeledCollector 时不为 nil
+	labeled LabeledCollector
+
+	// 指标前缀白名单
+	metricsPrefixes prefixAllowList
+
+	// Remote 批量读取的自适应批大小，为 nil 表示不拆分
+	batcher *adaptiveBatcher
+
+	// 批量操作的拆分大小，为 0 表示不拆分
+	chunkSize int
+
+	// 拆分后的 Remote 读写的最大并发数
+	remoteConcurrency int
+
+	// 跨实例失效消息，为 nil 表示不广播
+	bus *invalidationBus
+
+	// Set/MSet 时内存适配器拒绝写入是否返回错误
+	strictMemoryWrites bool
+
+	// 缓存级键命名空间和键哈希函数
+	keyPrefix string
+	keyHasher func(string) string
+
+	// 相邻键预取，为 nil 表示关闭
+	prefetcher *siblingPrefetcher
+
+	// 热点键统计，为 nil 表示关闭
+	hotKeys *hotKeyTracker
+
+	// 值大小上限，为 nil 表示不限制
+	valueSize *valueSizeLimit
+
+	// 等待写回 Remote 的淘汰条目，未开启 WithConfigDemoteOnEvict 时为 nil
+	demotions chan demotion
+
+	// 固定的键
+	pins pinner
+
+	// Watch 登记的键和轮询间隔
+	watches       watchHub
+	watchInterval time.Duration
+
+	// 写入时 TTL 的随机抖动比例，为 0 表示关闭
+	ttlJitter float64
+
+	// 内存 TTL 是否也随机抖动
+	memoryTTLJitter bool
+
+	// 值字节的编解码中间件
+	valueMiddlewares []ValueMiddleware
+
+	// 调用拦截器，按添加顺序由外到内执行
+	interceptors []Interceptor
+
+	// 按前缀隔离的 loader 熔断和限流，为 nil 表示关闭
+	guard *loaderGuard
+
+	// Remote 熔断，复用 loader 熔断的状态机，所有请求共用一组状态，为 nil 表示关闭
+	remoteGuard *loaderGuard
+
+	// Set/MSet 成功后的写穿回调，为 nil 表示关闭
+	writeThroughHook *writeThrough
+
+	// 反序列化失败的毒丸数据跟踪，为 nil 表示关闭
+	poison *poisonTracker
+
+	// 进程内的标签反向索引
+	tags *tagIndex
+
+	// 按键合并并发的批量加载
+	flights *batchFlight
+
+	// 异步 Remote 写入的工作池，为 nil 表示同步写入
+	async *asyncWriter
+
+	// 后台任务和正在执行的加载，Close 时等待结束
+	life *lifecycle
+
+	// 发布到 expvar 的计数来源，为 nil 表示未发布
+	expvar *expvarSlot
+
+	// Close 时是否关闭适配器
+	closeAdapters bool
+	closeOnce     sync.Once
+
+	// 提前刷新，为 nil 表示关闭
+	refresher *refresher
+
+	// OpenTelemetry tracer，为 nil 表示不追踪
+	tracer trace.Tracer
+
+	// Remote 出错时的处理策略
+	remoteFailurePolicy RemoteFailurePolicy
+
+	// 默认的 loader 超时时间，为 0 表示不限制
+	defaultLoaderTimeout time.Duration
+
 	// singleflight，防止并发请求重复调用 loader
 	sf singleflight.Group
 }
@@ -65,6 +226,121 @@ func NewCache(opts ...Option) (Cache, error) {
 
 		defaultCacheNotFound:    config.defaultCacheNotFound,
 		defaultCacheNotFoundTTL: config.defaultCacheNotFoundTTL,
+
+		readRepairRate: config.readRepairRate,
+		envelope:       config.envelope,
+		envelopeWrite:  config.envelope && !config.envelopeLegacyWrite,
+		devMode:        config.devMode,
+		metrics:        config.metrics,
+
+		metricsPrefixes: newPrefixAllowList(config.metricsPrefixes),
+
+		ttlJitter:       config.ttlJitter,
+		memoryTTLJitter: config.memoryTTLJitter,
+
+		valueMiddlewares: config.valueMiddlewares,
+		interceptors:     config.interceptors,
+
+		writeThroughHook: config.writeThrough,
+		valueSize:        config.maxValueSize,
+
+		tags:    newTagIndex(),
+		flights: newBatchFlight(),
+		life:    newLifecycle(),
+
+		closeAdapters: config.closeAdapters,
+
+		chunkSize:         config.batchChunkSize,
+		remoteConcurrency: config.remoteConcurrency,
+
+		strictMemoryWrites: config.strictMemoryWrites,
+
+		keyPrefix: config.keyPrefix,
+		keyHasher: config.keyHasher,
+
+		remoteFailurePolicy: config.remoteFailurePolicy,
+
+		defaultLoaderTimeout: config.defaultLoaderTimeout,
+		watchInterval:        config.watchInterval,
+	}
+
+	// 在启动后台任务之前占用 expvar 名称，名称已被占用时直接返回
+	if config.expvarName != "" {
+		if err := cache.publishExpvar(config.expvarName); err != nil {
+			return nil, err
+		}
+	}
+
+	if labeled, ok := config.metrics.(LabeledCollector); ok {
+		cache.labeled = labeled
+	}
+
+	if config.deleteShieldTTL > 0 {
+		cache.shield = newDeleteShield(config.deleteShieldTTL, deleteShieldCapacity)
+	}
+
+	if config.strictMemorySize && config.memoryAdapter != nil {
+		cache.maxEntrySize = config.memoryAdapter.(storage.EntrySizeLimiter).MaxEntrySize()
+	}
+
+	if config.dependencies {
+		cache.deps = config.remoteAdapter.(storage.SetStore)
+	}
+
+	if config.coalesceWrites && config.remoteAdapter != nil {
+		cache.writes = newWriteCoalescer(cache.spawn)
+	}
+
+	if a := config.asyncRemoteWrites; a != nil && config.remoteAdapter != nil {
+		cache.async = newAsyncWriter(a.queueSize, a.workers, cache.asyncRemoteWrite)
+	}
+
+	if a := config.adaptiveBatch; a != nil {
+		cache.batcher = newAdaptiveBatcher(a.target, a.minSize, a.maxSize)
+	}
+
+	if p := config.siblingPrefetch; p != nil {
+		cache.prefetcher = newSiblingPrefetcher(p.window, p.rate)
+	}
+
+	if h := config.hotKeys; h != nil {
+		cache.hotKeys = newHotKeyTracker(h.window, h.threshold, h.onHot)
+	}
+
+	if config.poisonThreshold > 0 {
+		cache.poison = newPoisonTracker(config.poisonThreshold)
+	}
+
+	if config.loaderBreaker != nil || config.loaderRateLimit != nil {
+		cache.guard = newLoaderGuard(config.loaderPrefixes, config.loaderBreaker, config.loaderRateLimit)
+	}
+
+	if config.remoteBreaker != nil {
+		cache.remoteGuard = newLoaderGuard(nil, config.remoteBreaker, nil)
+	}
+
+	if config.tracerProvider != nil {
+		cache.tracer = config.tracerProvider.Tracer(tracerName)
+	}
+
+	if r := config.refreshAhead; r != nil {
+		cache.refresher = newRefresher(r.maxKeys, r.keys)
+		cache.startRefreshAhead(r.interval)
+	}
+
+	if config.invalidationTransport != nil {
+		cache.bus = newInvalidationBus(config.invalidationTransport)
+		cache.spawn(cache.subscribeInvalidations)
+	}
+
+	if config.memoryCostFunc != nil && config.memoryAdapter != nil {
+		config.memoryAdapter.(storage.CostSetter).SetCostFunc(config.memoryCostFunc)
+	}
+
+	if config.demoteOnEvict {
+		cache.demotions = make(chan demotion, demoteQueueSize)
+		cache.spawn(cache.drainDemotions)
+		config.memoryAdapter.(storage.EvictionNotifier).OnEvict(cache.enqueueDemote)
 	}
 
 	return cache, nil
@@ -72,133 +348,473 @@ func NewCache(opts ...Option) (Cache, error) {
 
 // Set 设置缓存
 func (c *LayeredCache) Set(ctx context.Context, key string, value any, opts ...SetOption) error {
+	scope, ctx := c.takeKeyContext(ctx)
+	key, opts = scope.key(key), scopeSetOptions(scope, slices.Values([]string{key}), opts)
+	return c.intercept(ctx, Operation{Name: "set", Keys: []string{key}}, func(ctx context.Context) error {
+		return c.set(ctx, key, value, opts...)
+	})
+}
+
+// set Set 的实现，不经过拦截器
+func (c *LayeredCache) set(ctx context.Context, key string, value any, opts ...SetOption) error {
 	config := newSetOptions()
 	if err := applySetOptions(config, opts...); err != nil {
-		return err
+		return c.misuse(err)
+	}
+	c.checkLayerTTL(config.memoryTTL, config.remoteTTL)
+	if err := c.checkTags(config); err != nil {
+		return c.misuse(err)
 	}
 
-	data, err := c.Marshal(value)
+	data, err := c.encode(value, config.serializer)
 	if err != nil {
 		return err
 	}
+	if err = c.checkEntrySize(key, data); err != nil {
+		return err
+	}
+	if err = c.checkValueSize(key, data); err != nil {
+		return err
+	}
+	c.tracePayload(ctx, len(data))
 
-	memoryTTL, remoteTTL := c.calculateSetTTL(config)
+	oversized := c.oversized(map[string][]byte{key: data})
+	if err = c.dropOversized(ctx, oversized); err != nil {
+		return err
+	}
+	if len(oversized) > 0 && !c.oversizedRemote() {
+		return nil
+	}
 
-	if c.memory != nil {
-		c.memory.Set(key, data, memoryTTL)
+	memoryTTL, remoteTTL := c.jitterTTL(c.calculateSetValueTTL(config, key, value))
+	c.unshield(key)
+	c.stats.sets.Add(1)
+
+	var rejected int
+	if config.useMemory(c) && len(oversized) == 0 {
+		rejected = c.memorySet(key, data, memoryTTL)
 	}
 
-	if c.remote != nil {
-		if err = c.remote.Set(ctx, key, data, remoteTTL); err != nil {
+	var queued bool
+	if config.useRemote(c) {
+		if queued, err = c.writeRemoteAsync(ctx, map[string][]byte{key: data}, remoteTTL); err != nil {
+			return err
+		}
+	}
+	if config.useRemote(c) && !queued {
+		obs := c.observe(ctx)
+		start := obs.now()
+		err = c.remoteCall(ctx, func(ctx context.Context) error {
+			return c.setRemote(ctx, key, data, remoteTTL)
+		})
+		obs.record("set", LayerRemote, []string{key}, 0, start, err)
+		if err = c.remoteFailed(err); err != nil {
 			return err
 		}
 	}
+	// 排队的写入由异步写入执行后删除 Remote 中的标记
+	if err = c.clearNotFound(ctx, []string{key}, config.useRemote(c) && !queued); err != nil {
+		return err
+	}
 
-	return nil
+	c.broadcastInvalidation(ctx, []string{key})
+	if err = c.tagKeys(ctx, config, []string{key}, remoteTTL); err != nil {
+		return err
+	}
+	if err = c.writeThrough(ctx, map[string][]byte{key: data}); err != nil {
+		return err
+	}
+	return c.checkMemoryAdmission(rejected)
 }
 
 // MSet 批量设置缓存
 func (c *LayeredCache) MSet(ctx context.Context, keyValues map[string]any, opts ...SetOption) error {
+	scope, ctx := c.takeKeyContext(ctx)
+	keyValues, opts = scopeMap(scope, keyValues), scopeSetOptions(scope, maps.Keys(keyValues), opts)
+	if len(c.interceptors) == 0 && c.tracer == nil {
+		return c.mset(ctx, keyValues, opts...)
+	}
+	return c.intercept(ctx, Operation{Name: "mset", Keys: mapKeys(keyValues)}, func(ctx context.Context) error {
+		return c.mset(ctx, keyValues, opts...)
+	})
+}
+
+// mset MSet 的实现，不经过拦截器
+func (c *LayeredCache) mset(ctx context.Context, keyValues map[string]any, opts ...SetOption) error {
 	config := newSetOptions()
 	if err := applySetOptions(config, opts...); err != nil {
-		return err
+		return c.misuse(err)
+	}
+	c.checkLayerTTL(config.memoryTTL, config.remoteTTL)
+	if err := c.checkTags(config); err != nil {
+		return c.misuse(err)
 	}
 
 	memoryTTL, remoteTTL := c.calculateSetTTL(config)
 
 	serializedData := make(map[string][]byte)
 	for key, value := range keyValues {
-		data, err := c.Marshal(value)
+		data, err := c.encode(value, config.serializer)
 		if err != nil {
 			return err
 		}
+		if err = c.checkEntrySize(key, data); err != nil {
+			return err
+		}
+		if err = c.checkValueSize(key, data); err != nil {
+			return err
+		}
 		serializedData[key] = data
 	}
+	c.tracePayload(ctx, payloadSize(serializedData))
+
+	// 超过大小上限的值不写入内存缓存，策略不为 ValueSizeRemoteOnly 时也不写入 Remote
+	oversized := c.oversized(serializedData)
+	if err := c.dropOversized(ctx, oversized); err != nil {
+		return err
+	}
+	if !c.oversizedRemote() {
+		serializedData = withoutKeys(serializedData, oversized)
+	}
+
+	for key := range serializedData {
+		c.unshield(key)
+	}
+	c.stats.sets.Add(int64(len(serializedData)))
+
+	groups := c.jitterGroups(groupTTLs(config.ttlFunc, memoryTTL, remoteTTL, serializedData, keyValues))
 
 	// 设置到内存缓存
+	var rejected int
+	if config.useMemory(c) {
+		for _, group := range groups {
+			rejected += c.memoryMSet(withoutKeys(group.data, oversized), group.memoryTTL)
+		}
+	}
+
+	// 设置到Redis缓存，开启异步写入时排队后直接返回
+	queued := false
+	if config.useRemote(c) {
+		for _, group := range groups {
+			var err error
+			if queued, err = c.writeRemoteAsync(ctx, group.data, group.remoteTTL); err != nil {
+				return err
+			}
+		}
+	}
+	if config.useRemote(c) && !queued {
+		obs := c.observe(ctx)
+		start := obs.now()
+		err := c.remoteCall(ctx, func(ctx context.Context) error {
+			return c.msetRemoteGroups(ctx, groups)
+		})
+		if obs.active() {
+			obs.record("mset", LayerRemote, mapKeys(serializedData), 0, start, err)
+		}
+		if err = c.remoteFailed(err); err != nil {
+			return err
+		}
+	}
+	if err := c.clearNotFound(ctx, mapKeys(serializedData), config.useRemote(c) && !queued); err != nil {
+		return err
+	}
+
+	c.broadcastInvalidation(ctx, mapKeys(serializedData))
+	if err := c.tagKeys(ctx, config, mapKeys(serializedData), remoteTTL); err != nil {
+		return err
+	}
+	if err := c.writeThrough(ctx, serializedData); err != nil {
+		return err
+	}
+	return c.checkMemoryAdmission(rejected)
+}
+
+// Delete 删除缓存值，开启键依赖时级联删除依赖该键的所有子键
+func (c *LayeredCache) Delete(ctx context.Context, key string) error {
+	scope, ctx := c.takeKeyContext(ctx)
+	key = scope.key(key)
+	return c.intercept(ctx, Operation{Name: "delete", Keys: []string{key}}, func(ctx context.Context) error {
+		return c.delete(ctx, key)
+	})
+}
+
+// delete Delete 的实现，不经过拦截器
+func (c *LayeredCache) delete(ctx context.Context, key string) error {
+	if err := c.deleteKey(ctx, key); err != nil {
+		return err
+	}
+
+	if c.deps != nil {
+		return c.deleteDependents(ctx, key, map[string]struct{}{key: {}})
+	}
+	return nil
+}
+
+// MDelete 批量删除缓存值，Remote 适配器实现 storage.MultiDeleter 时在一次往返中完成
+// 开启键依赖时级联删除依赖这些键的所有子键
+func (c *LayeredCache) MDelete(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	scope, ctx := c.takeKeyContext(ctx)
+	keys = scopeKeys(scope, keys)
+	return c.intercept(ctx, Operation{Name: "mdelete", Keys: keys}, func(ctx context.Context) error {
+		return c.mdelete(ctx, keys)
+	})
+}
+
+// mdelete MDelete 的实现，不经过拦截器
+func (c *LayeredCache) mdelete(ctx context.Context, keys []string) error {
+	if err := c.deleteKeys(ctx, keys); err != nil {
+		return err
+	}
+
+	if c.deps != nil {
+		visited := make(map[string]struct{}, len(keys))
+		for _, key := range keys {
+			visited[key] = struct{}{}
+		}
+		for _, key := range keys {
+			if err := c.deleteDependents(ctx, key, visited); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// deleteKeys 从所有缓存层批量删除键
+func (c *LayeredCache) deleteKeys(ctx context.Context, keys []string) error {
+	c.stats.deletes.Add(int64(len(keys)))
+	if c.shield != nil {
+		for _, key := range keys {
+			c.shield.add(key)
+		}
+	}
+
 	if c.memory != nil {
-		c.memory.MSet(serializedData, memoryTTL)
+		for _, key := range keys {
+			c.memory.Delete(key)
+			c.memory.Delete(notFoundKey(key))
+		}
 	}
+	c.tags.remove(keys)
 
-	// 设置到Redis缓存
 	if c.remote != nil {
-		if err := c.remote.MSet(ctx, serializedData, remoteTTL); err != nil {
+		obs := c.observe(ctx)
+		start := obs.now()
+		err := c.deleteRemote(ctx, withNotFoundKeys(keys))
+		c.stats.remoteError(err)
+		obs.record("mdelete", LayerRemote, keys, 0, start, err)
+		if err != nil {
 			return err
 		}
 	}
 
+	c.broadcastInvalidation(ctx, keys)
 	return nil
 }
 
-// Delete 删除缓存值
-func (c *LayeredCache) Delete(ctx context.Context, key string) error {
+// deleteRemote 从 Remote 删除 keys，Remote 适配器未实现 storage.MultiDeleter 时逐个删除
+func (c *LayeredCache) deleteRemote(ctx context.Context, keys []string) error {
+	if deleter, ok := c.remote.(storage.MultiDeleter); ok {
+		return deleter.MDelete(ctx, keys)
+	}
+	for _, key := range keys {
+		if err := c.remote.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteKey 从所有缓存层删除单个键
+func (c *LayeredCache) deleteKey(ctx context.Context, key string) error {
+	c.stats.deletes.Add(1)
+	if c.shield != nil {
+		c.shield.add(key)
+	}
+
 	if c.memory != nil {
 		c.memory.Delete(key)
+		c.memory.Delete(notFoundKey(key))
 	}
+	c.tags.remove([]string{key})
 
 	if c.remote != nil {
-		if err := c.remote.Delete(ctx, key); err != nil {
+		obs := c.observe(ctx)
+		start := obs.now()
+		err := c.remote.Delete(ctx, key)
+		if err == nil {
+			err = c.remote.Delete(ctx, notFoundKey(key))
+		}
+		c.stats.remoteError(err)
+		obs.record("delete", LayerRemote, []string{key}, 0, start, err)
+		if err != nil {
 			return err
 		}
 	}
 
+	c.broadcastInvalidation(ctx, []string{key})
 	return nil
 }
 
 // Get 获取缓存值
 func (c *LayeredCache) Get(ctx context.Context, key string, target any, opts ...GetOption) error {
+	scope, ctx := c.takeKeyContext(ctx)
+	if !scope.empty() {
+		key, opts = scope.key(key), scopeGetOptions(scope, scope.unscoper([]string{key}), opts)
+	}
+	return c.intercept(ctx, Operation{Name: "get", Keys: []string{key}}, func(ctx context.Context) error {
+		return c.get(ctx, key, target, opts...)
+	})
+}
+
+// get Get 的实现，不经过拦截器
+func (c *LayeredCache) get(ctx context.Context, key string, target any, opts ...GetOption) error {
 	// 解析Get选项
 	config := newGetOptions()
 	if err := applyGetOptions(config, opts...); err != nil {
-		return err
+		return c.misuse(err)
 	}
+	c.checkLayerTTL(config.memoryTTL, config.remoteTTL)
 
-	if c.memory != nil {
-		if data, exists := c.memory.Get(key); exists {
-			if bytes.Equal(data, notFoundPlaceholder) {
-				return errors.ErrNotFound
+	c.checkGetTarget(target)
+
+	if config.maxAge > 0 && !c.envelope {
+		return c.misuse(errors.ErrEnvelopeRequired)
+	}
+
+	c.trackRefresh(key, config)
+	c.trackHotKeys(key)
+
+	// 删除保护窗口内跳过缓存层，直接回源
+	shielded := c.isShielded(key)
+
+	// 已失效的数据，仅在加载失败且允许降级时使用
+	var stale []byte
+
+	obs := c.observe(ctx)
+
+	if config.useMemory(c) && !shielded {
+		start := obs.now()
+		data, exists := c.memory.Get(key)
+		markerExists := false
+		if exists && isNotFoundPlaceholder(data) {
+			exists, markerExists = false, true
+		} else if !exists {
+			_, markerExists = c.memory.Get(notFoundKey(key))
+		}
+		obs.record("get", LayerMemory, []string{key}, boolToInt(exists || markerExists), start, nil)
+
+		if exists {
+			if c.isStale(data, config.maxAge) {
+				stale, exists = data, false
+			} else if config.useRemote(c) && c.shouldReadRepair() {
+				data, exists = c.repairMemory(ctx, key, data, config)
+			}
+			if exists {
+				c.stats.memoryHits.Add(1)
+				c.shadowCompare(ctx, key, data, config)
+				c.tracePayload(ctx, len(data))
+				if err := c.decode(data, target, config.serializer); !c.poisoned(ctx, key, err) {
+					return err
+				}
 			}
-			return c.Unmarshal(data, target)
+		} else if markerExists && !config.reloadNotFound {
+			c.stats.notFoundHits.Add(1)
+			return errors.ErrNotFoundCached
 		}
+		c.stats.memoryMisses.Add(1)
 	}
 
-	if c.remote != nil {
-		if data, err := c.remote.Get(ctx, key); err == nil {
-			if bytes.Equal(data, notFoundPlaceholder) {
-				return errors.ErrNotFound
-			}
+	if config.useRemote(c) && !shielded {
+		// 值与缺失值标记在一次往返中同时读取
+		start := obs.now()
+		var remoteData map[string][]byte
+		err := c.remoteCall(ctx, func(ctx context.Context) (err error) {
+			remoteData, err = c.remote.MGet(ctx, []string{key, notFoundKey(key)})
+			return err
+		})
+		obs.record("get", LayerRemote, []string{key}, boolToInt(len(remoteData) > 0), start, err)
+		if err = c.remoteFailed(err); err != nil && !IsNotFound(err) {
+			return err
+		}
+		data, exists := remoteData[key]
+		_, markerExists := remoteData[notFoundKey(key)]
+		if exists && isNotFoundPlaceholder(data) {
+			exists, markerExists = false, true
+		}
+		if exists && c.isStale(data, config.maxAge) {
+			stale, exists = data, false
+		}
+		if exists {
+			c.stats.remoteHits.Add(1)
 			// 写回内存缓存
-			if c.memory != nil {
+			if config.useMemory(c) {
 				memoryTTL, _ := c.calculateLoaderTTL(config)
-				c.memory.Set(key, data, memoryTTL)
+				c.memorySet(key, data, memoryTTL)
+				c.stats.memoryWriteBacks.Add(1)
 			}
 
-			return c.Unmarshal(data, target)
-		} else if !IsNotFound(err) {
-			return err
+			c.shadowCompare(ctx, key, data, config)
+			c.tracePayload(ctx, len(data))
+			if err := c.decode(data, target, config.serializer); !c.poisoned(ctx, key, err) {
+				return err
+			}
+		} else if markerExists && !config.reloadNotFound {
+			c.stats.notFoundHits.Add(1)
+			return errors.ErrNotFoundCached
 		}
+		c.stats.remoteMisses.Add(1)
 	}
 
 	if config.loader == nil {
+		if stale != nil && config.serveStale {
+			c.tracePayload(ctx, len(stale))
+			return c.decode(stale, target, config.serializer)
+		}
 		return errors.ErrNotFound
 	}
 
-	result, err, _ := c.sf.Do(key, func() (any, error) {
+	result, err := c.load(ctx, key, config, func(ctx context.Context) (any, error) {
+		if config.lockTTL > 0 {
+			return c.loadExclusive(ctx, key, config)
+		}
 		return c.loadAndCache(ctx, key, config)
 	})
 
 	if err != nil {
+		if stale != nil && config.serveStale && !IsNotFound(err) {
+			c.tracePayload(ctx, len(stale))
+			return c.decode(stale, target, config.serializer)
+		}
 		return err
 	}
 
-	return c.Unmarshal(result.([]byte), target)
+	data := result.([]byte)
+	c.tracePayload(ctx, len(data))
+	return c.decode(data, target, config.serializer)
 }
 
 // loadAndCache 加载数据并缓存
 func (c *LayeredCache) loadAndCache(ctx context.Context, key string, config *getOptions) ([]byte, error) {
+	done, err := c.guardLoad([]string{key})
+	if err != nil {
+		return nil, err
+	}
+
 	// 调用 loader 获取数据
-	value, err := config.loader(ctx, key)
+	c.stats.loads.Add(1)
+	obs := c.observe(ctx)
+	start := obs.now()
+	loadCtx, end := c.traceLoad(ctx, 1)
+	value, err := config.loader(loadCtx, key)
+	end(boolToInt(err == nil && value != nil), err)
+	done(err)
+	obs.record("get", LayerLoader, []string{key}, boolToInt(err == nil && value != nil), start, err)
 	if err != nil && !IsNotFound(err) {
+		c.stats.loadErrors.Add(1)
 		return nil, err
 	}
 
@@ -213,25 +829,33 @@ func (c *LayeredCache) loadAndCache(ctx context.Context, key string, config *get
 	}
 
 	// 序列化并存储到缓存
-	data, err := c.Marshal(value)
+	data, err := c.encode(value, config.serializer)
 	if err != nil {
 		return nil, err
 	}
 
 	// 计算TTL
-	memoryTTL, remoteTTL := c.calculateLoaderTTL(config)
+	memoryTTL, remoteTTL := c.jitterTTL(c.calculateValueTTL(config, key, value))
 
-	if c.memory != nil {
-		c.memory.Set(key, data, memoryTTL)
+	// 超过大小上限的值不写入内存缓存，策略不为 ValueSizeRemoteOnly 时也不写入 Remote
+	oversized := len(c.oversized(map[string][]byte{key: data})) > 0
+	if config.useMemory(c) && !oversized {
+		c.memorySet(key, data, memoryTTL)
 	}
 
 	// 设置到Redis缓存
-	if c.remote != nil {
-		if err = c.remote.Set(ctx, key, data, remoteTTL); err != nil {
+	if config.useRemote(c) && (!oversized || c.oversizedRemote()) {
+		err = c.remoteCall(ctx, func(ctx context.Context) error {
+			return c.remote.Set(ctx, key, data, remoteTTL)
+		})
+		if err = c.remoteFailed(err); err != nil {
 			return nil, err
 		}
 	}
+	// 标记删除失败不影响本次加载的结果
+	_ = c.clearNotFound(ctx, []string{key}, config.useRemote(c) && (!oversized || c.oversizedRemote()))
 
+	c.unshield(key)
 	return data, nil
 }
 
@@ -249,15 +873,21 @@ func (c *LayeredCache) cacheNotFound(ctx context.Context, keys []string, config
 
 	var cacheData = make(map[string][]byte)
 	for _, key := range keys {
-		cacheData[key] = notFoundPlaceholder
+		cacheData[notFoundKey(key)] = notFoundPlaceholder
 	}
 
-	if c.memory != nil {
-		c.memory.MSet(cacheData, cacheNotFoundTTL)
+	groups := c.jitterGroups([]ttlGroup{{memoryTTL: cacheNotFoundTTL, remoteTTL: cacheNotFoundTTL, data: cacheData}})
+	if config.useMemory(c) {
+		for _, group := range groups {
+			c.memoryMSet(group.data, group.memoryTTL)
+		}
 	}
 
-	if c.remote != nil {
-		if err := c.remote.MSet(ctx, cacheData, cacheNotFoundTTL); err != nil {
+	if config.useRemote(c) {
+		err := c.remoteCall(ctx, func(ctx context.Context) error {
+			return c.msetRemoteGroups(ctx, groups)
+		})
+		if err = c.remoteFailed(err); err != nil {
 			return err
 		}
 	}
@@ -267,11 +897,31 @@ func (c *LayeredCache) cacheNotFound(ctx context.Context, keys []string, config
 // MGet 批量获取缓存值
 // target 必须是指向 map[string]T 的指针，例如 &map[string]User{}
 func (c *LayeredCache) MGet(ctx context.Context, keys []string, target any, opts ...GetOption) error {
+	scope, ctx := c.takeKeyContext(ctx)
+	if scope.empty() {
+		return c.intercept(ctx, Operation{Name: "mget", Keys: keys}, func(ctx context.Context) error {
+			return c.mget(ctx, keys, target, opts...)
+		})
+	}
+	unscope := scope.unscoper(keys)
+	keys, opts = scopeKeys(scope, keys), scopeGetOptions(scope, unscope, opts)
+	err := c.intercept(ctx, Operation{Name: "mget", Keys: keys}, func(ctx context.Context) error {
+		return c.mget(ctx, keys, target, opts...)
+	})
+	if err == nil {
+		unscopeTarget(unscope, target)
+	}
+	return err
+}
+
+// mget MGet 的实现，不经过拦截器
+func (c *LayeredCache) mget(ctx context.Context, keys []string, target any, opts ...GetOption) error {
 	// 解析Get选项
 	config := newGetOptions()
 	if err := applyGetOptions(config, opts...); err != nil {
-		return err
+		return c.misuse(err)
 	}
+	c.checkLayerTTL(config.memoryTTL, config.remoteTTL)
 
 	if len(keys) == 0 {
 		return nil
@@ -279,87 +929,198 @@ func (c *LayeredCache) MGet(ctx context.Context, keys []string, target any, opts
 
 	// 验证 target 类型
 	if err := c.validateMGetTarget(target); err != nil {
+		return c.misuse(err)
+	}
+
+	if config.maxAge > 0 && !c.envelope {
+		return c.misuse(errors.ErrEnvelopeRequired)
+	}
+
+	result, stale, missingKeys, err := c.batchLookup(ctx, keys, config)
+	if err != nil {
 		return err
 	}
+	c.shadowCompareBatch(ctx, result, config)
 
+	// 使用 batchLoader 加载剩余的键
+	loadedData, err := c.batchLoad(ctx, missingKeys, config)
+	if err != nil {
+		if !c.serveStaleBatch(result, stale, missingKeys, config) {
+			return err
+		}
+	}
+	for key, data := range loadedData {
+		result[key] = data
+	}
+	if config.batchLoader == nil {
+		c.serveStaleBatch(result, stale, missingKeys, config)
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+	c.tracePayload(ctx, payloadSize(result))
+
+	err = c.unmarshalBatch(result, target, config.serializer)
+	if err == nil || c.poison == nil {
+		return err
+	}
+
+	// 反序列化失败的键达到阈值时删除并重新加载
+	poisoned := c.dropPoisoned(ctx, result, target, config.serializer)
+	if len(poisoned) == 0 {
+		return err
+	}
+	loadedData, err = c.batchLoad(ctx, poisoned, config)
+	if err != nil {
+		return err
+	}
+	for key, data := range loadedData {
+		result[key] = data
+	}
+	return c.unmarshalBatch(result, target, config.serializer)
+}
+
+// batchLookup 依次从内存缓存和 Remote 缓存中批量获取，返回命中的数据、已失效的数据以及仍需加载的键
+// 命中缺失值标记的键既不出现在结果中，也不需要加载；已失效的键同时出现在失效数据和仍需加载的键中
+func (c *LayeredCache) batchLookup(ctx context.Context, keys []string, config *getOptions) (map[string][]byte, map[string][]byte, []string, error) {
 	result := make(map[string][]byte)
+	stale := make(map[string][]byte)
 	missingKeys := make([]string, 0, len(keys))
+	c.trackHotKeys(keys...)
+
+	// 删除保护窗口内的键跳过缓存层，直接回源
+	var shieldedKeys []string
+	if c.shield != nil {
+		lookupKeys := make([]string, 0, len(keys))
+		for _, key := range keys {
+			if c.shield.contains(key) {
+				shieldedKeys = append(shieldedKeys, key)
+			} else {
+				lookupKeys = append(lookupKeys, key)
+			}
+		}
+		keys = lookupKeys
+	}
 
 	// 从内存缓存中批量获取
-	if c.memory != nil {
-		memoryData := c.memory.MGet(keys)
+	obs := c.observe(ctx)
+
+	if config.useMemory(c) && len(keys) > 0 {
+		start := obs.now()
+		memoryData := c.memory.MGet(withNotFoundKeys(keys))
+		obs.record("mget", LayerMemory, keys, countFound(keys, memoryData), start, nil)
+		var repairData map[string][]byte
 		for _, key := range keys {
-			if data, exists := memoryData[key]; exists {
-				if bytes.Equal(data, notFoundPlaceholder) {
+			data, exists := memoryData[key]
+			_, markerExists := memoryData[notFoundKey(key)]
+			if exists && isNotFoundPlaceholder(data) {
+				exists, markerExists = false, true
+			}
+			if exists {
+				if c.isStale(data, config.maxAge) {
+					stale[key] = data
+					missingKeys = append(missingKeys, key)
 					continue
 				}
 
+				if config.useRemote(c) && c.shouldReadRepair() {
+					if repairData == nil {
+						repairData = make(map[string][]byte)
+					}
+					repairData[key] = data
+					continue
+				}
 				result[key] = data
+			} else if markerExists && !config.reloadNotFound {
+				c.stats.notFoundHits.Add(1)
+				config.reportNotFoundCached(key)
 			} else {
 				missingKeys = append(missingKeys, key)
 			}
 		}
+
+		// 抽样校验的键以 Remote 为准，Remote 中已不存在的键按未命中处理
+		if len(repairData) > 0 {
+			repaired := c.repairMemoryBatch(ctx, repairData, config)
+			for key := range repairData {
+				if data, exists := repaired[key]; exists {
+					result[key] = data
+				} else {
+					missingKeys = append(missingKeys, key)
+				}
+			}
+		}
+
+		c.stats.memoryHits.Add(int64(len(result)))
+		c.stats.memoryMisses.Add(int64(len(missingKeys)))
 	} else {
 		missingKeys = keys
 	}
 
 	// 批量获取没有命中内存缓存的键
-	if c.remote != nil && len(missingKeys) > 0 {
-		redisData, err := c.remote.MGet(ctx, missingKeys)
-		if err != nil && !IsNotFound(err) {
+	if config.useRemote(c) && len(missingKeys) > 0 {
+		start := obs.now()
+		var redisData map[string][]byte
+		err := c.remoteCall(ctx, func(ctx context.Context) (err error) {
+			redisData, err = c.mgetRemote(ctx, missingKeys)
 			return err
+		})
+		obs.record("mget", LayerRemote, missingKeys, countFound(missingKeys, redisData), start, err)
+		if err = c.remoteFailed(err); err != nil && !IsNotFound(err) {
+			return nil, nil, nil, err
 		}
 
 		writeBackData := make(map[string][]byte)
 		remainingKeys := make([]string, 0, len(missingKeys))
 
 		for _, key := range missingKeys {
-			if data, exists := redisData[key]; exists {
-				if bytes.Equal(data, notFoundPlaceholder) {
-					continue
-				}
-
+			data, exists := redisData[key]
+			_, markerExists := redisData[notFoundKey(key)]
+			if exists && isNotFoundPlaceholder(data) {
+				exists, markerExists = false, true
+			}
+			if exists && c.isStale(data, config.maxAge) {
+				stale[key] = data
+				exists = false
+			}
+			if exists {
+				c.stats.remoteHits.Add(1)
 				result[key] = data
 
-				if c.memory != nil {
+				if config.useMemory(c) {
 					writeBackData[key] = data
 				}
+			} else if markerExists && !config.reloadNotFound {
+				c.stats.notFoundHits.Add(1)
+				config.reportNotFoundCached(key)
 			} else {
+				c.stats.remoteMisses.Add(1)
 				remainingKeys = append(remainingKeys, key)
 			}
 		}
 
 		// 批量写回内存缓存
-		if c.memory != nil && len(writeBackData) > 0 {
+		if config.useMemory(c) && len(writeBackData) > 0 {
 			memoryTTL, _ := c.calculateLoaderTTL(config)
-			c.memory.MSet(writeBackData, memoryTTL)
+			c.memoryMSet(writeBackData, memoryTTL)
+			c.stats.memoryWriteBacks.Add(int64(len(writeBackData)))
+			c.prefetchSiblings(ctx, missingKeys, keys)
 		}
 
 		missingKeys = remainingKeys
 	}
 
-	// 使用 batchLoader 加载剩余的键
-	if len(missingKeys) > 0 && config.batchLoader != nil {
-		batchKey := c.buildBatchKey(missingKeys)
-		batchResult, err, _ := c.sf.Do(batchKey, func() (any, error) {
-			return c.batchLoadAndCache(ctx, missingKeys, config)
-		})
-
-		if err != nil {
-			return err
-		}
-
-		loadedData := batchResult.(map[string][]byte)
-		for key, data := range loadedData {
-			result[key] = data
-		}
-	}
+	return result, stale, append(missingKeys, shieldedKeys...), nil
+}
 
-	if len(result) == 0 {
-		return nil
+// batchLoad 使用 batchLoader 加载缓存中不存在的键
+func (c *LayeredCache) batchLoad(ctx context.Context, keys []string, config *getOptions) (map[string][]byte, error) {
+	if len(keys) == 0 || config.batchLoader == nil {
+		return nil, nil
 	}
 
-	return c.unmarshalBatch(result, target)
+	return c.coalescedBatchLoad(ctx, keys, config)
 }
 
 // validateMGetTarget 验证 MGet 的 target 参数类型
@@ -421,7 +1182,7 @@ func (c *LayeredCache) buildBatchKey(keys []string) string {
 }
 
 // unmarshalBatch 批量反序列化结果到 target
-func (c *LayeredCache) unmarshalBatch(data map[string][]byte, target any) error {
+func (c *LayeredCache) unmarshalBatch(data map[string][]byte, target any, s serializer.Serializer) error {
 	targetValue := reflect.ValueOf(target).Elem()
 	targetType := targetValue.Type()
 	valueType := targetType.Elem()
@@ -435,7 +1196,7 @@ func (c *LayeredCache) unmarshalBatch(data map[string][]byte, target any) error
 		newValue := reflect.New(valueType)
 
 		// 反序列化
-		if err := c.Unmarshal(value, newValue.Interface()); err != nil {
+		if err := c.decode(value, newValue.Interface(), s); err != nil {
 			return err
 		}
 
@@ -450,9 +1211,22 @@ func (c *LayeredCache) unmarshalBatch(data map[string][]byte, target any) error
 
 // batchLoadAndCache 批量加载数据并缓存
 func (c *LayeredCache) batchLoadAndCache(ctx context.Context, keys []string, config *getOptions) (map[string][]byte, error) {
+	done, err := c.guardLoad(keys)
+	if err != nil {
+		return nil, err
+	}
+
 	// 调用 batchLoader 获取数据
-	values, err := config.batchLoader(ctx, keys)
+	c.stats.loads.Add(1)
+	obs := c.observe(ctx)
+	start := obs.now()
+	loadCtx, end := c.traceLoad(ctx, len(keys))
+	values, err := config.batchLoader(loadCtx, keys)
+	end(len(values), err)
+	done(err)
+	obs.record("mget", LayerLoader, keys, len(values), start, err)
 	if err != nil && !IsNotFound(err) {
+		c.stats.loadErrors.Add(1)
 		return nil, err
 	}
 
@@ -480,30 +1254,51 @@ func (c *LayeredCache) batchLoadAndCache(ctx context.Context, keys []string, con
 
 		// 序列化并存储到缓存
 		var data []byte
-		data, err = c.Marshal(value)
+		data, err = c.encode(value, config.serializer)
 		if err != nil {
 			return nil, err
 		}
 		result[key] = data
 	}
 
-	// 写入正常值缓存
-	if len(result) > 0 {
-		// 计算正常值的TTL
-		memoryTTL, remoteTTL := c.calculateLoaderTTL(config)
+	// 需要写入缓存的数据，开启 cacheExtra 时包含未请求的键
+	cacheData := result
+	if config.cacheExtra {
+		cacheData, err = c.withExtraValues(result, keys, values, config.serializer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 超过大小上限的值不写入内存缓存，策略不为 ValueSizeRemoteOnly 时也不写入 Remote
+	oversized := c.oversized(cacheData)
+	if !c.oversizedRemote() {
+		cacheData = withoutKeys(cacheData, oversized)
+	}
 
-		// 设置到内存缓存
-		if c.memory != nil {
-			c.memory.MSet(result, memoryTTL)
+	// 写入正常值缓存，按TTL分组写入
+	for key := range cacheData {
+		c.unshield(key)
+	}
+	groups := c.jitterGroups(c.groupByTTL(config, cacheData, values))
+
+	// 设置到内存缓存
+	if config.useMemory(c) {
+		for _, group := range groups {
+			c.memoryMSet(withoutKeys(group.data, oversized), group.memoryTTL)
 		}
+	}
 
-		// 设置到Redis缓存
-		if c.remote != nil {
-			if err = c.remote.MSet(ctx, result, remoteTTL); err != nil {
-				return nil, err
-			}
+	// 设置到Redis缓存
+	if config.useRemote(c) && len(groups) > 0 {
+		err = c.remoteCall(ctx, func(ctx context.Context) error {
+			return c.msetRemoteGroups(ctx, groups)
+		})
+		if err = c.remoteFailed(err); err != nil {
+			return nil, err
 		}
 	}
+	_ = c.clearNotFound(ctx, mapKeys(cacheData), config.useRemote(c))
 
 	// 写入缺失值缓存
 	if len(missingKeys) > 0 {
@@ -513,6 +1308,38 @@ func (c *LayeredCache) batchLoadAndCache(ctx context.Context, keys []string, con
 	return result, nil
 }
 
+// withExtraValues 合并 batchLoader 返回的未请求的键，返回新的 map，不修改 result
+func (c *LayeredCache) withExtraValues(result map[string][]byte, keys []string, values map[string]any, s serializer.Serializer) (map[string][]byte, error) {
+	requested := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		requested[key] = struct{}{}
+	}
+
+	merged := make(map[string][]byte, len(values))
+	for key, data := range result {
+		merged[key] = data
+	}
+	for key, value := range values {
+		if _, ok := requested[key]; ok || value == nil {
+			continue
+		}
+		data, err := c.encode(value, s)
+		if err != nil {
+			return nil, err
+		}
+		merged[key] = data
+	}
+	return merged, nil
+}
+
+// checkEntrySize 开启严格模式时检查条目是否超过内存条目大小上限
+func (c *LayeredCache) checkEntrySize(key string, data []byte) error {
+	if c.maxEntrySize > 0 && len(key)+len(data) > c.maxEntrySize {
+		return fmt.Errorf("%w: key %s size %d, limit %d", errors.ErrValueTooLarge, key, len(key)+len(data), c.maxEntrySize)
+	}
+	return nil
+}
+
 // calculateLoaderTTL 计算内存和Redis缓存的TTL
 func (c *LayeredCache) calculateLoaderTTL(config *getOptions) (memoryTTL, remoteTTL time.Duration) {
 	memoryTTL = c.defaultMemoryTTL
@@ -551,18 +1378,30 @@ func (c *LayeredCache) shouldCacheNotFound(optCacheNotFound *bool) bool {
 	return c.defaultCacheNotFound
 }
 
+// Marshal 序列化值，[]byte 和 string 原样写入
+// []byte 会被拷贝：内存适配器直接保存写入的切片，调用方之后修改自己的切片不能影响已缓存的值
 func (c *LayeredCache) Marshal(val any) ([]byte, error) {
+	return c.marshal(val, nil)
+}
+
+func (c *LayeredCache) Unmarshal(b []byte, val any) error {
+	return c.unmarshal(b, val, nil)
+}
+
+// marshal 序列化值，s 为本次调用指定的序列化器，为 nil 时按 serializerFor 选择
+func (c *LayeredCache) marshal(val any, s serializer.Serializer) ([]byte, error) {
 	switch v := val.(type) {
 	case []byte:
-		return v, nil
+		return bytes.Clone(v), nil
 	case string:
 		return []byte(v), nil
 	}
 
-	return c.serializer.Marshal(val)
+	return c.serializerFor(val, s).Marshal(val)
 }
 
-func (c *LayeredCache) Unmarshal(b []byte, val any) error {
+// unmarshal 反序列化值，s 为本次调用指定的序列化器，为 nil 时按 serializerFor 选择
+func (c *LayeredCache) unmarshal(b []byte, val any, s serializer.Serializer) error {
 	if len(b) == 0 {
 		return nil
 	}
@@ -578,7 +1417,7 @@ func (c *LayeredCache) Unmarshal(b []byte, val any) error {
 		return nil
 	}
 
-	return c.serializer.Unmarshal(b, val)
+	return c.serializerFor(val, s).Unmarshal(b, val)
 }
 
 func IsNotFound(err error) bool {
@@ -588,23 +1427,23 @@ func IsNotFound(err error) bool {
 	return errors.Is(err, errors.ErrNotFound)
 }
 
-func validMemoryTTL(memoryTTL time.Duration) error {
+func validMemoryTTL(memoryTTL time.Duration, source errors.TTLSource) error {
 	if memoryTTL <= 0 {
-		return errors.ErrInvalidMemoryExpireTime
+		return &errors.TTLError{Field: "memoryTTL", Value: memoryTTL, Source: source, Err: errors.ErrInvalidMemoryExpireTime}
 	}
 	return nil
 }
 
-func validRemoteTTL(remoteTTL time.Duration) error {
+func validRemoteTTL(remoteTTL time.Duration, source errors.TTLSource) error {
 	if remoteTTL <= 0 {
-		return errors.ErrInvalidRedisExpireTime
+		return &errors.TTLError{Field: "remoteTTL", Value: remoteTTL, Source: source, Err: errors.ErrInvalidRedisExpireTime}
 	}
 	return nil
 }
 
-func validCacheMissTTL(cacheMissTTL time.Duration) error {
+func validCacheMissTTL(cacheMissTTL time.Duration, source errors.TTLSource) error {
 	if cacheMissTTL <= 0 {
-		return errors.ErrInvalidCacheNotFondTTL
+		return &errors.TTLError{Field: "cacheNotFoundTTL", Value: cacheMissTTL, Source: source, Err: errors.ErrInvalidCacheNotFondTTL}
 	}
 	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -28,6 +29,17 @@ func createMemoryAdapter(t *testing.T) storage.Memory {
 	return otter
 }
 
+// createOtterAdapter 创建容量充足的内存适配器，写入后可立即读取
+func createOtterAdapter(t *testing.T) storage.Memory {
+	t.Helper()
+
+	otter, err := storage.NewOtter(1 << 20)
+	if err != nil {
+		panic(err)
+	}
+	return otter
+}
+
 func createRemoteAdapter(t *testing.T) storage.Remote {
 	t.Helper()
 
@@ -322,6 +334,56 @@ func TestNewCache_CustomSerializer(t *testing.T) {
 	}
 }
 
+func TestTTLError(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("默认配置", func(t *testing.T) {
+		_, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigDefaultTTL(-time.Second, time.Hour),
+		)
+
+		var ttlErr *errors.TTLError
+		assert.True(t, errors.As(err, &ttlErr))
+		assert.ErrorIs(t, err, errors.ErrInvalidMemoryExpireTime)
+		assert.Equal(t, "memoryTTL", ttlErr.Field)
+		assert.Equal(t, -time.Second, ttlErr.Value)
+		assert.Equal(t, errors.TTLSourceDefault, ttlErr.Source)
+		assert.Equal(t, "invalid memory expire time: memoryTTL=-1s (default)", err.Error())
+	})
+
+	t.Run("其他配置", func(t *testing.T) {
+		_, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigDeleteShield(-time.Second),
+		)
+
+		var ttlErr *errors.TTLError
+		assert.True(t, errors.As(err, &ttlErr))
+		assert.ErrorIs(t, err, errors.ErrInvalidDeleteShieldTTL)
+		assert.Equal(t, "deleteShieldTTL", ttlErr.Field)
+		assert.Equal(t, errors.TTLSourceConfig, ttlErr.Source)
+	})
+
+	t.Run("单次调用", func(t *testing.T) {
+		c := createTestCache(t)
+		err := c.Set(ctx, "key", "value", WithRemoteTTL(0))
+
+		var ttlErr *errors.TTLError
+		assert.True(t, errors.As(err, &ttlErr))
+		assert.ErrorIs(t, err, errors.ErrInvalidRedisExpireTime)
+		assert.Equal(t, "remoteTTL", ttlErr.Field)
+		assert.Equal(t, time.Duration(0), ttlErr.Value)
+		assert.Equal(t, errors.TTLSourcePerCall, ttlErr.Source)
+
+		var target string
+		err = c.Get(ctx, "key", &target, WithCacheNotFound(true, -time.Minute))
+		assert.True(t, errors.As(err, &ttlErr))
+		assert.Equal(t, "cacheNotFoundTTL", ttlErr.Field)
+		assert.Equal(t, -time.Minute, ttlErr.Value)
+	})
+}
+
 func TestLayeredCache_Set(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -1750,6 +1812,51 @@ func TestLayeredCache_Delete_AfterMSet(t *testing.T) {
 	}
 }
 
+// plainRemote 只实现 storage.Remote，不支持批量删除等可选能力
+type plainRemote struct {
+	storage.Remote
+}
+
+func TestLayeredCache_MDelete(t *testing.T) {
+	ctx := context.Background()
+	notFoundLoader := func(ctx context.Context, key string) (any, error) {
+		return nil, nil
+	}
+
+	remotes := map[string]func(t *testing.T) storage.Remote{
+		"批量删除": createRemoteAdapter,
+		"逐个删除": func(t *testing.T) storage.Remote {
+			return plainRemote{createRemoteAdapter(t)}
+		},
+	}
+	for name, createRemote := range remotes {
+		t.Run(name, func(t *testing.T) {
+			cache, err := NewCache(
+				WithConfigMemory(createMemoryAdapter(t)),
+				WithConfigRemote(createRemote(t)),
+			)
+			assert.NoError(t, err)
+
+			assert.NoError(t, cache.MSet(ctx, map[string]any{"k1": "v1", "k2": "v2", "k3": "v3"}))
+			var result string
+			assert.ErrorIs(t, cache.Get(ctx, "absent", &result, WithLoader(notFoundLoader), WithCacheNotFound(true, time.Minute)), ErrNotFound)
+
+			assert.NoError(t, cache.MDelete(ctx, []string{"k1", "k2", "absent", "missing"}))
+
+			validateDeleteInAdapters(t, cache, "k1")
+			validateDeleteInAdapters(t, cache, "k2")
+			validateKeyExists(t, cache, "k3")
+
+			// 缺失值标记也被删除
+			existence, err := cache.Exists(ctx, "absent")
+			assert.NoError(t, err)
+			assert.Equal(t, ExistenceUnknown, existence)
+
+			assert.NoError(t, cache.MDelete(ctx, nil))
+		})
+	}
+}
+
 func TestLayeredCache_Get(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -2031,12 +2138,12 @@ func TestLayeredCache_Get(t *testing.T) {
 				// 验证没有缓存空值
 				layeredCache := cache.(*LayeredCache)
 				if layeredCache.memory != nil {
-					if _, exists := layeredCache.memory.Get(key); exists {
+					if _, exists := layeredCache.memory.Get(notFoundKey(key)); exists {
 						t.Errorf("不应该缓存空值，但在内存中找到了键: %s", key)
 					}
 				}
 				if layeredCache.remote != nil {
-					if _, err := layeredCache.remote.Get(context.Background(), key); err == nil {
+					if _, err := layeredCache.remote.Get(context.Background(), notFoundKey(key)); err == nil {
 						t.Errorf("不应该缓存空值，但在Redis中找到了键: %s", key)
 					}
 				}
@@ -2070,7 +2177,7 @@ func TestLayeredCache_Get(t *testing.T) {
 				// 验证已经缓存了空值
 				layeredCache := cache.(*LayeredCache)
 				if layeredCache.memory != nil {
-					if data, exists := layeredCache.memory.Get(key); exists {
+					if data, exists := layeredCache.memory.Get(notFoundKey(key)); exists {
 						// 反序列化检查是否是空值占位符
 						var result interface{}
 						if err := layeredCache.Unmarshal(data, &result); err != nil {
@@ -2083,7 +2190,7 @@ func TestLayeredCache_Get(t *testing.T) {
 					}
 				}
 				if layeredCache.remote != nil {
-					if data, err := layeredCache.remote.Get(context.Background(), key); err == nil {
+					if data, err := layeredCache.remote.Get(context.Background(), notFoundKey(key)); err == nil {
 						// 反序列化检查是否是空值占位符
 						var result interface{}
 						if err := layeredCache.Unmarshal(data, &result); err != nil {
@@ -2632,7 +2739,7 @@ func TestLayeredCache_Get_WithLoader_NotFound(t *testing.T) {
 			if tt.expectCached {
 				// 应该缓存空值
 				if layeredCache.memory != nil {
-					if data, exists := layeredCache.memory.Get(key); !exists {
+					if data, exists := layeredCache.memory.Get(notFoundKey(key)); !exists {
 						t.Error("空值应该被缓存到内存，但未找到")
 					} else {
 						if !bytes.Equal(data, notFoundPlaceholder) {
@@ -2641,7 +2748,7 @@ func TestLayeredCache_Get_WithLoader_NotFound(t *testing.T) {
 					}
 				}
 				if layeredCache.remote != nil {
-					if data, err := layeredCache.remote.Get(ctx, key); err != nil {
+					if data, err := layeredCache.remote.Get(ctx, notFoundKey(key)); err != nil {
 						t.Errorf("空值应该被缓存到Redis，但未找到: %v", err)
 					} else {
 						if !bytes.Equal(data, notFoundPlaceholder) {
@@ -2652,12 +2759,12 @@ func TestLayeredCache_Get_WithLoader_NotFound(t *testing.T) {
 			} else {
 				// 不应该缓存空值
 				if layeredCache.memory != nil {
-					if _, exists := layeredCache.memory.Get(key); exists {
+					if _, exists := layeredCache.memory.Get(notFoundKey(key)); exists {
 						t.Error("空值不应该被缓存到内存，但找到了")
 					}
 				}
 				if layeredCache.remote != nil {
-					if _, err := layeredCache.remote.Get(ctx, key); err == nil {
+					if _, err := layeredCache.remote.Get(ctx, notFoundKey(key)); err == nil {
 						t.Error("空值不应该被缓存到Redis，但找到了")
 					}
 				}
@@ -3291,7 +3398,7 @@ func TestLayeredCache_MGet(t *testing.T) {
 				// 验证空值已被缓存
 				layeredCache := cache.(*LayeredCache)
 				if layeredCache.memory != nil {
-					if data, exists := layeredCache.memory.Get("nil-key"); !exists {
+					if data, exists := layeredCache.memory.Get(notFoundKey("nil-key")); !exists {
 						t.Errorf("空值应该被缓存到内存，但未找到")
 					} else if !bytes.Equal(data, notFoundPlaceholder) {
 						t.Errorf("内存缓存的空值不正确")
@@ -4160,3 +4267,89 @@ func TestLayeredCache_Get_WithLoader_EmptySlice(t *testing.T) {
 		})
 	}
 }
+
+func TestLayeredCache_StrictMemorySize(t *testing.T) {
+	ctx := context.Background()
+	memory, err := storage.NewOtter(10000)
+	assert.NoError(t, err)
+
+	cache, err := NewCache(
+		WithConfigMemory(memory),
+		WithConfigRemote(createRemoteAdapter(t)),
+		WithConfigStrictMemorySize(true),
+	)
+	assert.NoError(t, err)
+	c := cache.(*LayeredCache)
+
+	large := strings.Repeat("x", 1000)
+
+	t.Run("Set超过上限的值", func(t *testing.T) {
+		err := c.Set(ctx, "large", large)
+		assert.ErrorIs(t, err, errors.ErrValueTooLarge)
+
+		_, err = c.remote.Get(ctx, "large")
+		assert.ErrorIs(t, err, errors.ErrNotFound)
+	})
+
+	t.Run("MSet包含超过上限的值时整体拒绝", func(t *testing.T) {
+		err := c.MSet(ctx, map[string]any{"small": "v", "large": large})
+		assert.ErrorIs(t, err, errors.ErrValueTooLarge)
+
+		_, err = c.remote.Get(ctx, "small")
+		assert.ErrorIs(t, err, errors.ErrNotFound)
+	})
+
+	t.Run("未超过上限正常写入", func(t *testing.T) {
+		assert.NoError(t, c.Set(ctx, "small", "v"))
+		_, exists := c.memory.Get("small")
+		assert.True(t, exists)
+	})
+
+	t.Run("未开启时静默写入", func(t *testing.T) {
+		cache, err := NewCache(WithConfigMemory(memory), WithConfigRemote(createRemoteAdapter(t)))
+		assert.NoError(t, err)
+		assert.NoError(t, cache.Set(ctx, "large", large))
+	})
+
+	t.Run("内存适配器不支持", func(t *testing.T) {
+		_, err := NewCache(
+			WithConfigMemory(&ttlRecordingMemory{Memory: memory}),
+			WithConfigStrictMemorySize(true),
+		)
+		assert.ErrorIs(t, err, errors.ErrOperationNotSupported)
+	})
+}
+
+func TestLayeredCache_BytesNoAlias(t *testing.T) {
+	ctx := context.Background()
+	c := createTestCache(t)
+
+	t.Run("修改写入的切片不影响缓存", func(t *testing.T) {
+		value := []byte("hello")
+		assert.NoError(t, c.Set(ctx, "set", value))
+		assert.NoError(t, c.MSet(ctx, map[string]any{"mset": value}))
+		value[0] = 'X'
+
+		var got []byte
+		assert.NoError(t, c.Get(ctx, "set", &got))
+		assert.Equal(t, "hello", string(got))
+		assert.NoError(t, c.Get(ctx, "mset", &got))
+		assert.Equal(t, "hello", string(got))
+	})
+
+	t.Run("修改读取的切片不影响缓存", func(t *testing.T) {
+		assert.NoError(t, c.Set(ctx, "get", []byte("hello")))
+
+		var got []byte
+		assert.NoError(t, c.Get(ctx, "get", &got))
+		got[0] = 'X'
+
+		values := make(map[string][]byte)
+		assert.NoError(t, c.MGet(ctx, []string{"get"}, &values))
+		assert.Equal(t, "hello", string(values["get"]))
+		values["get"][0] = 'X'
+
+		assert.NoError(t, c.Get(ctx, "get", &got))
+		assert.Equal(t, "hello", string(got))
+	})
+}
@@ -0,0 +1,267 @@
+package cachemock
+
+import (
+	"context"
+	"time"
+
+	cache "github.com/biu7/layered-cache"
+)
+
+var _ cache.Cache = (*Cache)(nil)
+
+// Cache cache.Cache 的测试替身
+// 未设置 XxxFunc 时：Get 和 GetSet 返回 cache.ErrNotFound，MGetWithMissing 返回所有键，SetNX 返回 true，Lock 返回空的 Unlock，Exists 返回 ExistenceUnknown，TTL 返回 false 和 0，Snapshot 返回所有键都不存在的 View，ScheduleInvalidation 返回空的 stop，其他方法返回零值和 nil
+type Cache struct {
+	recorder
+
+	SetFunc                  func(ctx context.Context, key string, value any, opts ...cache.SetOption) error
+	MSetFunc                 func(ctx context.Context, keyValues map[string]any, opts ...cache.SetOption) error
+	SetNXFunc                func(ctx context.Context, key string, value any, opts ...cache.SetOption) (bool, error)
+	GetSetFunc               func(ctx context.Context, key string, value any, target any, opts ...cache.SetOption) error
+	DeleteFunc               func(ctx context.Context, key string) error
+	MDeleteFunc              func(ctx context.Context, keys []string) error
+	DeleteByPrefixFunc       func(ctx context.Context, prefix string) error
+	InvalidateFunc           func(ctx context.Context, key string) error
+	InvalidateTagFunc        func(ctx context.Context, tag string) error
+	DependOnFunc             func(ctx context.Context, child, parent string) error
+	GetFunc                  func(ctx context.Context, key string, target any, opts ...cache.GetOption) error
+	MGetFunc                 func(ctx context.Context, keys []string, target any, opts ...cache.GetOption) error
+	MGetWithMissingFunc      func(ctx context.Context, keys []string, target any, opts ...cache.GetOption) ([]string, error)
+	MultiFetchFunc           func(ctx context.Context, requests []cache.FetchRequest) ([]cache.FetchResult, error)
+	SnapshotFunc             func(ctx context.Context, keys []string) (cache.View, error)
+	ExistsFunc               func(ctx context.Context, key string) (cache.Existence, error)
+	LockFunc                 func(ctx context.Context, key string, ttl time.Duration) (cache.Unlock, error)
+	IncrFunc                 func(ctx context.Context, key string, delta int64, opts ...cache.SetOption) (int64, error)
+	TTLFunc                  func(ctx context.Context, key string) (memoryKnown bool, remoteTTL time.Duration, err error)
+	ExpireFunc               func(ctx context.Context, key string, memoryTTL, remoteTTL time.Duration) error
+	MExpireFunc              func(ctx context.Context, keys []string, memoryTTL, remoteTTL time.Duration) error
+	SweepMemoryFunc          func(ctx context.Context, budget time.Duration) (int, error)
+	RemoteKeysFunc           func(ctx context.Context, prefix string, cursor uint64, count int) ([]string, uint64, error)
+	ScheduleInvalidationFunc func(prefix string, cron string) (stop func(), err error)
+	FlushFunc                func(ctx context.Context) error
+	CloseFunc                func(ctx context.Context) error
+	StatsFunc                func() cache.Stats
+}
+
+func (m *Cache) Set(ctx context.Context, key string, value any, opts ...cache.SetOption) error {
+	m.record("Set", key, value)
+	if m.SetFunc != nil {
+		return m.SetFunc(ctx, key, value, opts...)
+	}
+	return nil
+}
+
+func (m *Cache) MSet(ctx context.Context, keyValues map[string]any, opts ...cache.SetOption) error {
+	m.record("MSet", keyValues)
+	if m.MSetFunc != nil {
+		return m.MSetFunc(ctx, keyValues, opts...)
+	}
+	return nil
+}
+
+func (m *Cache) SetNX(ctx context.Context, key string, value any, opts ...cache.SetOption) (bool, error) {
+	m.record("SetNX", key, value)
+	if m.SetNXFunc != nil {
+		return m.SetNXFunc(ctx, key, value, opts...)
+	}
+	return true, nil
+}
+
+func (m *Cache) GetSet(ctx context.Context, key string, value any, target any, opts ...cache.SetOption) error {
+	m.record("GetSet", key, value)
+	if m.GetSetFunc != nil {
+		return m.GetSetFunc(ctx, key, value, target, opts...)
+	}
+	return cache.ErrNotFound
+}
+
+func (m *Cache) Delete(ctx context.Context, key string) error {
+	m.record("Delete", key)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, key)
+	}
+	return nil
+}
+
+func (m *Cache) MDelete(ctx context.Context, keys []string) error {
+	m.record("MDelete", keys)
+	if m.MDeleteFunc != nil {
+		return m.MDeleteFunc(ctx, keys)
+	}
+	return nil
+}
+
+func (m *Cache) DeleteByPrefix(ctx context.Context, prefix string) error {
+	m.record("DeleteByPrefix", prefix)
+	if m.DeleteByPrefixFunc != nil {
+		return m.DeleteByPrefixFunc(ctx, prefix)
+	}
+	return nil
+}
+
+func (m *Cache) Invalidate(ctx context.Context, key string) error {
+	m.record("Invalidate", key)
+	if m.InvalidateFunc != nil {
+		return m.InvalidateFunc(ctx, key)
+	}
+	return nil
+}
+
+func (m *Cache) InvalidateTag(ctx context.Context, tag string) error {
+	m.record("InvalidateTag", tag)
+	if m.InvalidateTagFunc != nil {
+		return m.InvalidateTagFunc(ctx, tag)
+	}
+	return nil
+}
+
+func (m *Cache) DependOn(ctx context.Context, child, parent string) error {
+	m.record("DependOn", child, parent)
+	if m.DependOnFunc != nil {
+		return m.DependOnFunc(ctx, child, parent)
+	}
+	return nil
+}
+
+func (m *Cache) Get(ctx context.Context, key string, target any, opts ...cache.GetOption) error {
+	m.record("Get", key)
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, key, target, opts...)
+	}
+	return cache.ErrNotFound
+}
+
+func (m *Cache) MGet(ctx context.Context, keys []string, target any, opts ...cache.GetOption) error {
+	m.record("MGet", keys)
+	if m.MGetFunc != nil {
+		return m.MGetFunc(ctx, keys, target, opts...)
+	}
+	return nil
+}
+
+func (m *Cache) MGetWithMissing(ctx context.Context, keys []string, target any, opts ...cache.GetOption) ([]string, error) {
+	m.record("MGetWithMissing", keys)
+	if m.MGetWithMissingFunc != nil {
+		return m.MGetWithMissingFunc(ctx, keys, target, opts...)
+	}
+	return keys, nil
+}
+
+func (m *Cache) MultiFetch(ctx context.Context, requests []cache.FetchRequest) ([]cache.FetchResult, error) {
+	m.record("MultiFetch", requests)
+	if m.MultiFetchFunc != nil {
+		return m.MultiFetchFunc(ctx, requests)
+	}
+	return make([]cache.FetchResult, len(requests)), nil
+}
+
+func (m *Cache) Snapshot(ctx context.Context, keys []string) (cache.View, error) {
+	m.record("Snapshot", keys)
+	if m.SnapshotFunc != nil {
+		return m.SnapshotFunc(ctx, keys)
+	}
+	return emptyView{}, nil
+}
+
+// emptyView 所有键都不存在的快照
+type emptyView struct{}
+
+func (emptyView) Get(key string, target any) error {
+	return cache.ErrNotFound
+}
+
+func (m *Cache) Exists(ctx context.Context, key string) (cache.Existence, error) {
+	m.record("Exists", key)
+	if m.ExistsFunc != nil {
+		return m.ExistsFunc(ctx, key)
+	}
+	return cache.ExistenceUnknown, nil
+}
+
+func (m *Cache) Lock(ctx context.Context, key string, ttl time.Duration) (cache.Unlock, error) {
+	m.record("Lock", key, ttl)
+	if m.LockFunc != nil {
+		return m.LockFunc(ctx, key, ttl)
+	}
+	return func(ctx context.Context) error { return nil }, nil
+}
+
+func (m *Cache) Incr(ctx context.Context, key string, delta int64, opts ...cache.SetOption) (int64, error) {
+	m.record("Incr", key, delta)
+	if m.IncrFunc != nil {
+		return m.IncrFunc(ctx, key, delta, opts...)
+	}
+	return 0, nil
+}
+
+func (m *Cache) TTL(ctx context.Context, key string) (memoryKnown bool, remoteTTL time.Duration, err error) {
+	m.record("TTL", key)
+	if m.TTLFunc != nil {
+		return m.TTLFunc(ctx, key)
+	}
+	return false, 0, nil
+}
+
+func (m *Cache) Expire(ctx context.Context, key string, memoryTTL, remoteTTL time.Duration) error {
+	m.record("Expire", key, memoryTTL, remoteTTL)
+	if m.ExpireFunc != nil {
+		return m.ExpireFunc(ctx, key, memoryTTL, remoteTTL)
+	}
+	return nil
+}
+
+func (m *Cache) MExpire(ctx context.Context, keys []string, memoryTTL, remoteTTL time.Duration) error {
+	m.record("MExpire", keys, memoryTTL, remoteTTL)
+	if m.MExpireFunc != nil {
+		return m.MExpireFunc(ctx, keys, memoryTTL, remoteTTL)
+	}
+	return nil
+}
+
+func (m *Cache) SweepMemory(ctx context.Context, budget time.Duration) (int, error) {
+	m.record("SweepMemory", budget)
+	if m.SweepMemoryFunc != nil {
+		return m.SweepMemoryFunc(ctx, budget)
+	}
+	return 0, nil
+}
+
+func (m *Cache) RemoteKeys(ctx context.Context, prefix string, cursor uint64, count int) ([]string, uint64, error) {
+	m.record("RemoteKeys", prefix, cursor, count)
+	if m.RemoteKeysFunc != nil {
+		return m.RemoteKeysFunc(ctx, prefix, cursor, count)
+	}
+	return nil, 0, nil
+}
+
+func (m *Cache) ScheduleInvalidation(prefix string, cron string) (stop func(), err error) {
+	m.record("ScheduleInvalidation", prefix, cron)
+	if m.ScheduleInvalidationFunc != nil {
+		return m.ScheduleInvalidationFunc(prefix, cron)
+	}
+	return func() {}, nil
+}
+
+func (m *Cache) Flush(ctx context.Context) error {
+	m.record("Flush")
+	if m.FlushFunc != nil {
+		return m.FlushFunc(ctx)
+	}
+	return nil
+}
+
+func (m *Cache) Close(ctx context.Context) error {
+	m.record("Close")
+	if m.CloseFunc != nil {
+		return m.CloseFunc(ctx)
+	}
+	return nil
+}
+
+func (m *Cache) Stats() cache.Stats {
+	m.record("Stats")
+	if m.StatsFunc != nil {
+		return m.StatsFunc()
+	}
+	return cache.Stats{}
+}
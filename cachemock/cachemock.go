@@ -0,0 +1,56 @@
+// Package cachemock 提供 cache.Cache、storage.Memory、storage.Remote 和 serializer.Serializer 的测试替身
+//
+// 每个替身通过 XxxFunc 字段定制对应方法的行为，未设置时使用统一的默认行为：
+// 读取视为未命中，写入和删除成功，序列化使用标准库 JSON。所有调用都会按顺序记录，可通过 Calls 断言
+package cachemock
+
+import "sync"
+
+// Call 一次方法调用
+type Call struct {
+	Method string
+	Args   []any
+}
+
+// recorder 并发安全地记录方法调用
+type recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *recorder) record(method string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+// Calls 返回已记录的调用
+func (r *recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ret := make([]Call, len(r.calls))
+	copy(ret, r.calls)
+	return ret
+}
+
+// CallCount 返回 method 被调用的次数
+func (r *recorder) CallCount(method string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, call := range r.calls {
+		if call.Method == method {
+			count++
+		}
+	}
+	return count
+}
+
+// Reset 清空已记录的调用
+func (r *recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
@@ -0,0 +1,93 @@
+package cachemock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cache "github.com/biu7/layered-cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("默认行为", func(t *testing.T) {
+		m := &Cache{}
+		var result string
+		assert.ErrorIs(t, m.Get(ctx, "key", &result), cache.ErrNotFound)
+		assert.NoError(t, m.Set(ctx, "key", "value"))
+		existence, err := m.Exists(ctx, "key")
+		assert.NoError(t, err)
+		assert.Equal(t, cache.ExistenceUnknown, existence)
+
+		results, err := m.MultiFetch(ctx, make([]cache.FetchRequest, 2))
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+
+		view, err := m.Snapshot(ctx, []string{"key"})
+		assert.NoError(t, err)
+		assert.ErrorIs(t, view.Get("key", &result), cache.ErrNotFound)
+	})
+
+	t.Run("定制行为并记录调用", func(t *testing.T) {
+		m := &Cache{
+			GetFunc: func(ctx context.Context, key string, target any, opts ...cache.GetOption) error {
+				*target.(*string) = "mocked:" + key
+				return nil
+			},
+		}
+
+		typed := cache.Typed[int64, string](m)
+		value, err := typed.Get(ctx, "user", 1, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "mocked:user:1", value)
+		assert.NoError(t, typed.Delete(ctx, "user", 1))
+
+		assert.Equal(t, []Call{
+			{Method: "Get", Args: []any{"user:1"}},
+			{Method: "Delete", Args: []any{"user:1"}},
+		}, m.Calls())
+		assert.Equal(t, 1, m.CallCount("Get"))
+
+		m.Reset()
+		assert.Empty(t, m.Calls())
+	})
+}
+
+func TestStorageAndSerializer(t *testing.T) {
+	ctx := context.Background()
+	memory := &Memory{}
+	remote := &Remote{
+		MGetFunc: func(ctx context.Context, keys []string) (map[string][]byte, error) {
+			return map[string][]byte{keys[0]: []byte(`{"name":"alice"}`)}, nil
+		},
+	}
+	srl := &Serializer{}
+
+	c, err := cache.NewCache(
+		cache.WithConfigMemory(memory),
+		cache.WithConfigRemote(remote),
+		cache.WithConfigSerializer(srl),
+	)
+	assert.NoError(t, err)
+
+	var result struct {
+		Name string `json:"name"`
+	}
+	assert.NoError(t, c.Get(ctx, "user:1", &result))
+	assert.Equal(t, "alice", result.Name)
+
+	// 内存中值和缺失值标记都未命中后读取 Remote，命中后写回内存
+	assert.Equal(t, 2, memory.CallCount("Get"))
+	assert.Equal(t, 1, memory.CallCount("Set"))
+	assert.Equal(t, 1, remote.CallCount("MGet"))
+	assert.Equal(t, 1, srl.CallCount("Unmarshal"))
+
+	assert.NoError(t, c.Set(ctx, "user:2", result))
+	// 写入后删除缺失值标记
+	assert.Equal(t, []Call{
+		{Method: "Set", Args: []any{"user:2", []byte(`{"name":"alice"}`), 14 * 24 * time.Hour}},
+		{Method: "Delete", Args: []any{"user:2\x00nf"}},
+	}, remote.Calls()[1:])
+}
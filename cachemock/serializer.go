@@ -0,0 +1,33 @@
+package cachemock
+
+import (
+	"encoding/json"
+
+	"github.com/biu7/layered-cache/serializer"
+)
+
+var _ serializer.Serializer = (*Serializer)(nil)
+
+// Serializer serializer.Serializer 的测试替身，未设置 XxxFunc 时使用标准库 JSON
+type Serializer struct {
+	recorder
+
+	MarshalFunc   func(v any) ([]byte, error)
+	UnmarshalFunc func(data []byte, v any) error
+}
+
+func (m *Serializer) Marshal(v any) ([]byte, error) {
+	m.record("Marshal", v)
+	if m.MarshalFunc != nil {
+		return m.MarshalFunc(v)
+	}
+	return json.Marshal(v)
+}
+
+func (m *Serializer) Unmarshal(data []byte, v any) error {
+	m.record("Unmarshal", data)
+	if m.UnmarshalFunc != nil {
+		return m.UnmarshalFunc(data, v)
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,126 @@
+package cachemock
+
+import (
+	"context"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+)
+
+var (
+	_ storage.Memory = (*Memory)(nil)
+	_ storage.Remote = (*Remote)(nil)
+)
+
+// Memory storage.Memory 的测试替身
+// 未设置 XxxFunc 时：Get 未命中，MGet 返回空结果，Set/MSet 返回 0
+type Memory struct {
+	recorder
+
+	SetFunc    func(key string, value []byte, expire time.Duration) int32
+	MSetFunc   func(values map[string][]byte, expire time.Duration) int32
+	GetFunc    func(key string) ([]byte, bool)
+	MGetFunc   func(keys []string) map[string][]byte
+	DeleteFunc func(key string)
+}
+
+func (m *Memory) Set(key string, value []byte, expire time.Duration) int32 {
+	m.record("Set", key, value, expire)
+	if m.SetFunc != nil {
+		return m.SetFunc(key, value, expire)
+	}
+	return 0
+}
+
+func (m *Memory) MSet(values map[string][]byte, expire time.Duration) int32 {
+	m.record("MSet", values, expire)
+	if m.MSetFunc != nil {
+		return m.MSetFunc(values, expire)
+	}
+	return 0
+}
+
+func (m *Memory) Get(key string) ([]byte, bool) {
+	m.record("Get", key)
+	if m.GetFunc != nil {
+		return m.GetFunc(key)
+	}
+	return nil, false
+}
+
+func (m *Memory) MGet(keys []string) map[string][]byte {
+	m.record("MGet", keys)
+	if m.MGetFunc != nil {
+		return m.MGetFunc(keys)
+	}
+	return map[string][]byte{}
+}
+
+func (m *Memory) Delete(key string) {
+	m.record("Delete", key)
+	if m.DeleteFunc != nil {
+		m.DeleteFunc(key)
+	}
+}
+
+// Remote storage.Remote 的测试替身
+// 未设置 XxxFunc 时：Get 返回 errors.ErrNotFound，MGet 返回空结果，TTL 返回 0，其他方法返回 nil
+type Remote struct {
+	recorder
+
+	SetFunc    func(ctx context.Context, key string, value []byte, expire time.Duration) error
+	MSetFunc   func(ctx context.Context, values map[string][]byte, expire time.Duration) error
+	GetFunc    func(ctx context.Context, key string) ([]byte, error)
+	MGetFunc   func(ctx context.Context, keys []string) (map[string][]byte, error)
+	DeleteFunc func(ctx context.Context, key string) error
+	TTLFunc    func(ctx context.Context, key string) (time.Duration, error)
+}
+
+func (m *Remote) Set(ctx context.Context, key string, value []byte, expire time.Duration) error {
+	m.record("Set", key, value, expire)
+	if m.SetFunc != nil {
+		return m.SetFunc(ctx, key, value, expire)
+	}
+	return nil
+}
+
+func (m *Remote) MSet(ctx context.Context, values map[string][]byte, expire time.Duration) error {
+	m.record("MSet", values, expire)
+	if m.MSetFunc != nil {
+		return m.MSetFunc(ctx, values, expire)
+	}
+	return nil
+}
+
+func (m *Remote) Get(ctx context.Context, key string) ([]byte, error) {
+	m.record("Get", key)
+	if m.GetFunc != nil {
+		return m.GetFunc(ctx, key)
+	}
+	return nil, errors.ErrNotFound
+}
+
+func (m *Remote) MGet(ctx context.Context, keys []string) (map[string][]byte, error) {
+	m.record("MGet", keys)
+	if m.MGetFunc != nil {
+		return m.MGetFunc(ctx, keys)
+	}
+	return map[string][]byte{}, nil
+}
+
+func (m *Remote) Delete(ctx context.Context, key string) error {
+	m.record("Delete", key)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, key)
+	}
+	return nil
+}
+
+func (m *Remote) TTL(ctx context.Context, key string) (time.Duration, error) {
+	m.record("TTL", key)
+	if m.TTLFunc != nil {
+		return m.TTLFunc(ctx, key)
+	}
+	return 0, nil
+}
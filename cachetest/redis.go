@@ -0,0 +1,72 @@
+//go:build integration
+
+// Package cachetest 提供基于 testcontainers-go 的集成测试工具，
+// 用于在真实 Redis 上验证 Lua 脚本、键空间通知、集群路由等 miniredis 无法完整模拟的功能
+//
+// 集成测试需要本地 Docker 环境，通过 integration 构建标签开启：
+//
+//	go test -tags integration ./...
+package cachetest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// RedisImage 集成测试使用的 Redis 镜像
+const RedisImage = "redis:7-alpine"
+
+// startTimeout 等待容器就绪的超时时间
+const startTimeout = time.Minute
+
+// StartRedis 启动一个 Redis 容器并返回连接到该容器的客户端
+// 测试结束时自动关闭客户端并销毁容器；开启键空间通知以便验证依赖通知的功能
+func StartRedis(t testing.TB) *redis.Client {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+	defer cancel()
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        RedisImage,
+			ExposedPorts: []string{"6379/tcp"},
+			Cmd:          []string{"redis-server", "--notify-keyspace-events", "KEA"},
+			WaitingFor:   wait.ForLog("Ready to accept connections"),
+		},
+		Started: true,
+	})
+	if err != nil {
+		t.Fatalf("cachetest: start redis container: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := container.Terminate(context.Background()); err != nil {
+			t.Logf("cachetest: terminate redis container: %v", err)
+		}
+	})
+
+	host, err := container.Host(ctx)
+	if err != nil {
+		t.Fatalf("cachetest: redis container host: %v", err)
+	}
+	port, err := container.MappedPort(ctx, "6379")
+	if err != nil {
+		t.Fatalf("cachetest: redis container port: %v", err)
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: fmt.Sprintf("%s:%s", host, port.Port())})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	if err = client.Ping(ctx).Err(); err != nil {
+		t.Fatalf("cachetest: ping redis: %v", err)
+	}
+	return client
+}
@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+)
+
+// Incr 将计数器原子地增加 delta 并返回增加后的值，delta 为负数时减少，为 0 时读取当前值；键不存在时从 0 开始
+// 计数以 Remote（Redis INCRBY）为准，不经过序列化，也不缓存在内存中：每次调用都会删除内存中的同名键，
+// 避免读到旧值，适用于限流、浏览量等计数场景。计数器应始终通过 Incr 读取
+// opts 中的 Remote TTL 只在计数器没有过期时间（即新建）时生效，未设置时使用默认 Remote TTL；需要 Remote 实现 storage.Counter
+func (c *LayeredCache) Incr(ctx context.Context, key string, delta int64, opts ...SetOption) (int64, error) {
+	scope, ctx := c.takeKeyContext(ctx)
+	key = scope.key(key)
+
+	config := newSetOptions()
+	if err := applySetOptions(config, opts...); err != nil {
+		return 0, c.misuse(err)
+	}
+
+	counter, ok := c.remote.(storage.Counter)
+	if !ok {
+		return 0, errors.ErrOperationNotSupported
+	}
+
+	if c.memory != nil {
+		c.memory.Delete(key)
+	}
+
+	_, remoteTTL := c.calculateSetTTL(config)
+	var value int64
+	err := c.remoteCall(ctx, func(ctx context.Context) (err error) {
+		value, err = counter.IncrBy(ctx, key, delta, remoteTTL)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return value, nil
+}
@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_Incr(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("以 Remote 为准计数", func(t *testing.T) {
+		c := createTestCache(t).(*LayeredCache)
+
+		value, err := c.Incr(ctx, "views", 3, WithRemoteTTL(time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), value)
+
+		value, err = c.Incr(ctx, "views", -1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), value)
+
+		value, err = c.Incr(ctx, "views", 0)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), value)
+
+		ttl, err := c.remote.TTL(ctx, "views")
+		assert.NoError(t, err)
+		assert.Equal(t, time.Hour, ttl)
+	})
+
+	t.Run("删除内存中的同名键", func(t *testing.T) {
+		c := createTestCache(t).(*LayeredCache)
+		c.memory.Set("views", []byte("stale"), time.Minute)
+
+		_, err := c.Incr(ctx, "views", 1)
+		assert.NoError(t, err)
+		_, ok := c.memory.Get("views")
+		assert.False(t, ok)
+	})
+
+	t.Run("Remote不支持", func(t *testing.T) {
+		_, err := createMemoryOnlyCache(t).Incr(ctx, "views", 1)
+		assert.ErrorIs(t, err, errors.ErrOperationNotSupported)
+	})
+}
@@ -0,0 +1,162 @@
+package cache
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+)
+
+// cronSchedule 标准 5 段 cron 表达式（分 时 日 月 周），每一段用位图表示允许的取值
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// domAny、dowAny 日、周是否为 *，两者都不是 * 时满足其一即可
+	domAny, dowAny bool
+}
+
+// cronField cron 表达式一段的取值范围
+type cronField struct {
+	min, max int
+}
+
+var (
+	cronMinute = cronField{0, 59}
+	cronHour   = cronField{0, 23}
+	cronDom    = cronField{1, 31}
+	cronMonth  = cronField{1, 12}
+	cronDow    = cronField{0, 7} // 0 和 7 都表示周日
+)
+
+// cronDescriptors 预定义的表达式
+var cronDescriptors = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+}
+
+// parseCron 解析 cron 表达式，支持 *、列表（1,5）、范围（1-5）、步长（*/15、1-30/5）和 @daily 等预定义表达式
+func parseCron(spec string) (*cronSchedule, error) {
+	expr := strings.TrimSpace(spec)
+	if descriptor, ok := cronDescriptors[expr]; ok {
+		expr = descriptor
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: %q: expected 5 fields", errors.ErrInvalidCron, spec)
+	}
+
+	s := &cronSchedule{domAny: fields[2] == "*", dowAny: fields[4] == "*"}
+	targets := []*uint64{&s.minute, &s.hour, &s.dom, &s.month, &s.dow}
+	for i, field := range []cronField{cronMinute, cronHour, cronDom, cronMonth, cronDow} {
+		set, err := field.parse(fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", errors.ErrInvalidCron, spec, err)
+		}
+		*targets[i] = set
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+// parse 解析一段表达式，返回允许取值的位图
+func (f cronField) parse(expr string) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(expr, ",") {
+		rangeExpr, step := part, 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part)
+			}
+			rangeExpr, step = part[:i], n
+		}
+
+		low, high := f.min, f.max
+		if rangeExpr != "*" {
+			var err error
+			if i := strings.IndexByte(rangeExpr, '-'); i >= 0 {
+				low, err = strconv.Atoi(rangeExpr[:i])
+				if err == nil {
+					high, err = strconv.Atoi(rangeExpr[i+1:])
+				}
+			} else {
+				low, err = strconv.Atoi(rangeExpr)
+				high = low
+				if step > 1 {
+					high = f.max
+				}
+			}
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+		}
+		if low < f.min || high > f.max || low > high {
+			return 0, fmt.Errorf("value %q out of range [%d, %d]", part, f.min, f.max)
+		}
+
+		for v := low; v <= high; v += step {
+			set |= 1 << v
+		}
+	}
+	return set, nil
+}
+
+// next 返回 t 之后第一个满足表达式的时间（精确到分钟），5 年内没有满足的时间时返回零值
+func (s *cronSchedule) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<int(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.hour&(1<<t.Hour()) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if s.minute&(1<<t.Minute()) == 0 {
+			// 跳到当前小时内下一个允许的分钟，没有时进入下一个小时
+			rest := s.minute >> (t.Minute() + 1) << (t.Minute() + 1)
+			if rest == 0 {
+				t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			} else {
+				t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), bits.TrailingZeros64(rest), 0, 0, t.Location())
+			}
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// matchDay 判断日期是否满足日、周两段，两者都有限制时满足其一即可
+func (s *cronSchedule) matchDay(t time.Time) bool {
+	dom := s.dom&(1<<t.Day()) != 0
+	dow := s.dow&(1<<int(t.Weekday())) != 0
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
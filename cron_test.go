@@ -0,0 +1,51 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCron(t *testing.T) {
+	base := time.Date(2024, 1, 31, 10, 30, 15, 0, time.UTC) // 周三
+
+	tests := []struct {
+		name string
+		spec string
+		want time.Time
+	}{
+		{"每分钟", "* * * * *", time.Date(2024, 1, 31, 10, 31, 0, 0, time.UTC)},
+		{"每15分钟", "*/15 * * * *", time.Date(2024, 1, 31, 10, 45, 0, 0, time.UTC)},
+		{"每晚3点", "0 3 * * *", time.Date(2024, 2, 1, 3, 0, 0, 0, time.UTC)},
+		{"列表和范围", "5,20 9-11 * * *", time.Date(2024, 1, 31, 11, 5, 0, 0, time.UTC)},
+		{"跨月", "0 0 1 * *", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"闰年2月29日", "0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"周日用7表示", "0 0 * * 7", time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)},
+		{"日和周满足其一", "0 0 15 * 5", time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC)},
+		{"预定义表达式", "@daily", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"范围加步长", "10-40/10 * * * *", time.Date(2024, 1, 31, 10, 40, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseCron(tt.spec)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, s.next(base))
+		})
+	}
+
+	t.Run("不存在的日期", func(t *testing.T) {
+		s, err := parseCron("0 0 30 2 *")
+		assert.NoError(t, err)
+		assert.True(t, s.next(base).IsZero())
+	})
+
+	t.Run("无效表达式", func(t *testing.T) {
+		for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "0 0 0 * *", "*/0 * * * *", "a * * * *", "5-1 * * * *", "@every"} {
+			_, err := parseCron(spec)
+			assert.ErrorIs(t, err, errors.ErrInvalidCron, spec)
+		}
+	})
+}
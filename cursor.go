@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// cursorVersionSuffix 集合版本号的键后缀
+const cursorVersionSuffix = "ver"
+
+// Page 分页加载的结果
+type Page[T any] struct {
+	Items []T `json:"items"`
+
+	// NextCursor 下一页的游标，为空表示没有更多数据
+	NextCursor string `json:"next_cursor"`
+}
+
+// PageLoaderFunc 根据游标加载一页数据，首页的游标为空
+type PageLoaderFunc[T any] func(ctx context.Context, cursor string) (Page[T], error)
+
+// Cursor 缓存游标分页的加载结果（cursor → page）
+// 所有分页的键都带有集合版本号，集合变化时调用 Bump 更新版本号，旧版本的分页不再被读取并随 TTL 过期
+type Cursor[T any] struct {
+	cache     Cache
+	namespace string
+}
+
+// NewCursor 创建游标分页缓存，namespace 标识一个集合，例如 "user:1:orders"
+func NewCursor[T any](cache Cache, namespace string) *Cursor[T] {
+	return &Cursor[T]{cache: cache, namespace: namespace}
+}
+
+// Get 获取 cursor 对应的分页，未命中时调用 loader 加载并缓存
+func (c *Cursor[T]) Get(ctx context.Context, cursor string, loader PageLoaderFunc[T], opts ...GetOption) (Page[T], error) {
+	var page Page[T]
+
+	version, err := c.version(ctx)
+	if err != nil {
+		return page, err
+	}
+
+	if loader != nil {
+		opts = append(opts, WithLoader(func(ctx context.Context, _ string) (any, error) {
+			return loader(ctx, cursor)
+		}))
+	}
+	err = c.cache.Get(ctx, c.pageKey(version, cursor), &page, opts...)
+	return page, err
+}
+
+// Bump 更新集合版本号，使所有已缓存的分页失效
+// 其他实例内存缓存中的版本号在内存 TTL 到期前不会更新，需要及时失效时应缩短内存 TTL 或只使用 Remote
+func (c *Cursor[T]) Bump(ctx context.Context) error {
+	return c.cache.Set(ctx, c.versionKey(), newCursorVersion())
+}
+
+// version 返回当前的集合版本号，不存在时初始化
+func (c *Cursor[T]) version(ctx context.Context) (string, error) {
+	var version string
+	err := c.cache.Get(ctx, c.versionKey(), &version, WithLoader(func(ctx context.Context, _ string) (any, error) {
+		return newCursorVersion(), nil
+	}))
+	return version, err
+}
+
+func (c *Cursor[T]) versionKey() string {
+	return c.namespace + separator + cursorVersionSuffix
+}
+
+func (c *Cursor[T]) pageKey(version, cursor string) string {
+	return c.namespace + separator + version + separator + cursor
+}
+
+// newCursorVersion 生成新的版本号，使用纳秒时间戳避免不同实例之间冲突
+func newCursorVersion() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 36)
+}
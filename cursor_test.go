@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	ctx := context.Background()
+
+	// 每页两条，游标为下一页第一条的下标
+	items := []int{1, 2, 3, 4, 5}
+	calls := 0
+	loader := func(ctx context.Context, cursor string) (Page[int], error) {
+		calls++
+		start, _ := strconv.Atoi(cursor)
+		end := min(start+2, len(items))
+		page := Page[int]{Items: append([]int(nil), items[start:end]...)}
+		if end < len(items) {
+			page.NextCursor = strconv.Itoa(end)
+		}
+		return page, nil
+	}
+
+	t.Run("缓存分页结果", func(t *testing.T) {
+		cursor := NewCursor[int](createTestCache(t), "orders")
+		calls = 0
+
+		var all []int
+		next := ""
+		for {
+			page, err := cursor.Get(ctx, next, loader)
+			assert.NoError(t, err)
+			all = append(all, page.Items...)
+			if page.NextCursor == "" {
+				break
+			}
+			next = page.NextCursor
+		}
+		assert.Equal(t, items, all)
+		assert.Equal(t, 3, calls)
+
+		page, err := cursor.Get(ctx, "2", loader)
+		assert.NoError(t, err)
+		assert.Equal(t, Page[int]{Items: []int{3, 4}, NextCursor: "4"}, page)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("版本更新后失效", func(t *testing.T) {
+		cursor := NewCursor[int](createTestCache(t), "orders")
+		calls = 0
+
+		_, err := cursor.Get(ctx, "", loader)
+		assert.NoError(t, err)
+
+		items = append([]int{0}, items...)
+		assert.NoError(t, cursor.Bump(ctx))
+
+		page, err := cursor.Get(ctx, "", loader)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{0, 1}, page.Items)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("不同集合互不影响", func(t *testing.T) {
+		cache := createTestCache(t)
+		a := NewCursor[int](cache, "a")
+		b := NewCursor[int](cache, "b")
+
+		_, err := a.Get(ctx, "", loader)
+		assert.NoError(t, err)
+		assert.NoError(t, b.Bump(ctx))
+
+		calls = 0
+		_, err = a.Get(ctx, "", loader)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("没有loader", func(t *testing.T) {
+		cursor := NewCursor[int](createTestCache(t), "orders")
+		_, err := cursor.Get(ctx, "", nil)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// dashboardPath 生成的 Grafana 看板路径，由 TestDashboard 保持与指标名称一致
+const dashboardPath = "dashboards/layered-cache.json"
+
+// grafanaPanel Grafana 看板中的一个面板
+type grafanaPanel struct {
+	ID          int              `json:"id"`
+	Title       string           `json:"title"`
+	Type        string           `json:"type"`
+	Datasource  map[string]any   `json:"datasource"`
+	GridPos     map[string]int   `json:"gridPos"`
+	FieldConfig map[string]any   `json:"fieldConfig"`
+	Targets     []map[string]any `json:"targets"`
+}
+
+// dashboardFilter 所有查询共用的标签过滤
+var dashboardFilter = fmt.Sprintf(`%s=~"$prefix"`, LabelPrefix)
+
+// buildDashboard 按标准化的指标名称和标签生成 Grafana 看板
+func buildDashboard() ([]byte, error) {
+	rate := func(metric, by string) string {
+		return fmt.Sprintf("sum by (%s) (rate(%s{%s}[$__rate_interval]))", by, metric, dashboardFilter)
+	}
+	readKeys := func(by string) string {
+		return fmt.Sprintf(`sum by (%s) (rate(%s{%s, %s=~"get|mget"}[$__rate_interval]))`, by, MetricKeys, dashboardFilter, LabelOp)
+	}
+	byLayerOp := LabelLayer + ", " + LabelOp
+
+	panels := []struct {
+		title, unit, expr, legend string
+	}{
+		{
+			title:  "Hit ratio by layer",
+			unit:   "percentunit",
+			expr:   rate(MetricHits, LabelLayer) + " / " + readKeys(LabelLayer),
+			legend: "{{" + LabelLayer + "}}",
+		},
+		{
+			title:  "Hit ratio by prefix",
+			unit:   "percentunit",
+			expr:   rate(MetricHits, LabelPrefix) + " / " + readKeys(LabelPrefix),
+			legend: "{{" + LabelPrefix + "}}",
+		},
+		{
+			title:  "Operations",
+			unit:   "ops",
+			expr:   rate(MetricOperations, byLayerOp),
+			legend: "{{" + LabelLayer + "}} {{" + LabelOp + "}}",
+		},
+		{
+			title:  "Errors",
+			unit:   "ops",
+			expr:   rate(MetricErrors, byLayerOp),
+			legend: "{{" + LabelLayer + "}} {{" + LabelOp + "}}",
+		},
+		{
+			title:  "p99 latency",
+			unit:   "s",
+			expr:   fmt.Sprintf("histogram_quantile(0.99, %s)", rate(MetricDuration+"_bucket", "le, "+byLayerOp)),
+			legend: "{{" + LabelLayer + "}} {{" + LabelOp + "}}",
+		},
+		{
+			title:  "Singleflight",
+			unit:   "ops",
+			expr:   fmt.Sprintf("sum by (%s) (rate(%s[$__rate_interval]))", LabelShared, MetricSingleflight),
+			legend: LabelShared + "={{" + LabelShared + "}}",
+		},
+	}
+
+	datasource := map[string]any{"type": "prometheus", "uid": "${datasource}"}
+	grafanaPanels := make([]grafanaPanel, 0, len(panels))
+	for i, p := range panels {
+		grafanaPanels = append(grafanaPanels, grafanaPanel{
+			ID:          i + 1,
+			Title:       p.title,
+			Type:        "timeseries",
+			Datasource:  datasource,
+			GridPos:     map[string]int{"h": 8, "w": 12, "x": i % 2 * 12, "y": i / 2 * 8},
+			FieldConfig: map[string]any{"defaults": map[string]any{"unit": p.unit}},
+			Targets: []map[string]any{
+				{"refId": "A", "expr": p.expr, "legendFormat": p.legend, "datasource": datasource},
+			},
+		})
+	}
+
+	dashboard := map[string]any{
+		"title":         "LayeredCache",
+		"uid":           "layered-cache",
+		"schemaVersion": 39,
+		"time":          map[string]string{"from": "now-1h", "to": "now"},
+		"refresh":       "30s",
+		"panels":        grafanaPanels,
+		"templating": map[string]any{
+			"list": []map[string]any{
+				{"name": "datasource", "type": "datasource", "query": "prometheus"},
+				{
+					"name":       "prefix",
+					"type":       "query",
+					"datasource": datasource,
+					"query":      fmt.Sprintf("label_values(%s, %s)", MetricOperations, LabelPrefix),
+					"includeAll": true,
+					"multi":      true,
+					"allValue":   ".*",
+				},
+			},
+		},
+	}
+
+	data, err := json.MarshalIndent(dashboard, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,23 @@
+package cache
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDashboard 校验看板与指标名称保持一致，设置 UPDATE_DASHBOARD=1 重新生成
+func TestDashboard(t *testing.T) {
+	data, err := buildDashboard()
+	assert.NoError(t, err)
+
+	if os.Getenv("UPDATE_DASHBOARD") != "" {
+		assert.NoError(t, os.WriteFile(dashboardPath, data, 0o644))
+		return
+	}
+
+	existing, err := os.ReadFile(dashboardPath)
+	assert.NoError(t, err)
+	assert.Equal(t, string(data), string(existing), "看板已过期，使用 UPDATE_DASHBOARD=1 go test -run TestDashboard 重新生成")
+}
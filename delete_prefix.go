@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+)
+
+// prefixScanCount 按前缀删除时单次 SCAN 的建议数量
+const prefixScanCount = 1000
+
+// DeleteByPrefix 删除两层缓存中所有以 prefix 开头的键，例如批量更新后删除某个分类下的所有商品
+// Remote 通过 RemoteKeys 遍历后分批 MDelete，需要 Remote 适配器实现 storage.Scanner；
+// 内存适配器实现 storage.PrefixDeleter 时再遍历删除内存中剩余的键，否则只有 Remote 中存在的键会从内存中删除
+func (c *LayeredCache) DeleteByPrefix(ctx context.Context, prefix string) error {
+	if prefix == "" {
+		return c.misuse(errors.ErrEmptyPrefix)
+	}
+	scope, ctx := c.takeKeyContext(ctx)
+	if scope.hasher != nil {
+		return errors.ErrCustomKeyLayout
+	}
+	prefix = scope.prefix + prefix
+
+	deleter, _ := c.memory.(storage.PrefixDeleter)
+	if c.remote != nil {
+		if _, ok := c.remote.(storage.Scanner); !ok {
+			return errors.ErrOperationNotSupported
+		}
+	} else if deleter == nil {
+		return errors.ErrOperationNotSupported
+	}
+
+	// 先删除 Remote，避免内存删除后又从 Remote 写回
+	if c.remote != nil {
+		if err := c.deleteRemotePrefix(ctx, prefix); err != nil {
+			return err
+		}
+	}
+	if deleter != nil {
+		c.stats.deletes.Add(int64(deleter.DeletePrefix(prefix)))
+	}
+	return nil
+}
+
+// deleteRemotePrefix 删除 Remote 中以 prefix 开头的所有键，同时删除这些键的内存缓存
+func (c *LayeredCache) deleteRemotePrefix(ctx context.Context, prefix string) error {
+	var cursor uint64
+	for {
+		keys, next, err := c.RemoteKeys(ctx, prefix, cursor, prefixScanCount)
+		if err != nil {
+			return err
+		}
+		if err = c.MDelete(ctx, keys); err != nil {
+			return err
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
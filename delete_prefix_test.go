@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_DeleteByPrefix(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("删除两层中前缀下的键", func(t *testing.T) {
+		c, err := NewCache(WithConfigMemory(createOtterAdapter(t)), WithConfigRemote(createRemoteAdapter(t)))
+		assert.NoError(t, err)
+		lc := c.(*LayeredCache)
+
+		for i := 0; i < 25; i++ {
+			assert.NoError(t, c.Set(ctx, fmt.Sprintf("category:7:product:%d", i), i))
+		}
+		assert.NoError(t, c.Set(ctx, "category:8:product:1", 1))
+		// 只存在于内存中的键
+		lc.memory.Set("category:7:product:99", []byte("99"), time.Hour)
+
+		assert.NoError(t, c.DeleteByPrefix(ctx, "category:7:"))
+
+		var value int
+		for i := 0; i < 25; i++ {
+			assert.ErrorIs(t, c.Get(ctx, fmt.Sprintf("category:7:product:%d", i), &value), errors.ErrNotFound)
+		}
+		assert.ErrorIs(t, c.Get(ctx, "category:7:product:99", &value), errors.ErrNotFound)
+		assert.NoError(t, c.Get(ctx, "category:8:product:1", &value))
+	})
+
+	t.Run("TypedCache删除前缀下的所有值", func(t *testing.T) {
+		typed := Typed[int, string](createTestCache(t))
+		assert.NoError(t, typed.MSet(ctx, "product", map[int]string{1: "a", 2: "b"}))
+		assert.NoError(t, typed.Set(ctx, "productline", 1, "c"))
+
+		assert.NoError(t, typed.DeleteAll(ctx, "product"))
+
+		values, err := typed.MGet(ctx, "product", []int{1, 2}, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, values)
+
+		value, err := typed.Get(ctx, "productline", 1, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "c", value)
+	})
+
+	t.Run("参数校验", func(t *testing.T) {
+		assert.ErrorIs(t, createTestCache(t).DeleteByPrefix(ctx, ""), errors.ErrEmptyPrefix)
+
+		c, err := NewCache(WithConfigMemory(createMemoryAdapter(t)))
+		assert.NoError(t, err)
+		assert.ErrorIs(t, c.DeleteByPrefix(ctx, "product:"), errors.ErrOperationNotSupported)
+	})
+}
@@ -0,0 +1,97 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// deleteShieldCapacity 删除保护窗口最多记录的键数量，超出后淘汰最早删除的键
+const deleteShieldCapacity = 10000
+
+// deleteShield 记录最近删除的键（LRU），窗口期内这些键在本地直接视为不存在
+// 避免删除后立即读取时，读到其他节点并发写回的旧数据
+type deleteShield struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+}
+
+type deleteShieldEntry struct {
+	key      string
+	expireAt time.Time
+}
+
+func newDeleteShield(ttl time.Duration, capacity int) *deleteShield {
+	return &deleteShield{
+		ttl:      ttl,
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// add 记录被删除的键
+func (s *deleteShield) add(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expireAt := time.Now().Add(s.ttl)
+	if elem, ok := s.items[key]; ok {
+		elem.Value.(*deleteShieldEntry).expireAt = expireAt
+		s.order.MoveToFront(elem)
+		return
+	}
+
+	s.items[key] = s.order.PushFront(&deleteShieldEntry{key: key, expireAt: expireAt})
+	for s.order.Len() > s.capacity {
+		s.removeElement(s.order.Back())
+	}
+}
+
+// remove 键被重新写入后解除保护
+func (s *deleteShield) remove(keys ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		if elem, ok := s.items[key]; ok {
+			s.removeElement(elem)
+		}
+	}
+}
+
+// contains 判断键是否仍处于删除保护窗口内
+func (s *deleteShield) contains(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.items[key]
+	if !ok {
+		return false
+	}
+	if time.Now().After(elem.Value.(*deleteShieldEntry).expireAt) {
+		s.removeElement(elem)
+		return false
+	}
+	return true
+}
+
+func (s *deleteShield) removeElement(elem *list.Element) {
+	s.order.Remove(elem)
+	delete(s.items, elem.Value.(*deleteShieldEntry).key)
+}
+
+// isShielded 判断键是否处于删除保护窗口内
+func (c *LayeredCache) isShielded(key string) bool {
+	return c.shield != nil && c.shield.contains(key)
+}
+
+// unshield 键被重新写入后解除删除保护
+func (c *LayeredCache) unshield(keys ...string) {
+	if c.shield != nil {
+		c.shield.remove(keys...)
+	}
+}
@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteShield(t *testing.T) {
+	t.Run("窗口期内命中，过期后失效", func(t *testing.T) {
+		s := newDeleteShield(20*time.Millisecond, 10)
+		s.add("k")
+		assert.True(t, s.contains("k"))
+
+		time.Sleep(30 * time.Millisecond)
+		assert.False(t, s.contains("k"))
+		assert.Empty(t, s.items)
+	})
+
+	t.Run("超出容量淘汰最早删除的键", func(t *testing.T) {
+		s := newDeleteShield(time.Minute, 3)
+		for i := 0; i < 5; i++ {
+			s.add(fmt.Sprintf("k%d", i))
+		}
+		assert.False(t, s.contains("k0"))
+		assert.False(t, s.contains("k1"))
+		assert.True(t, s.contains("k2"))
+		assert.True(t, s.contains("k4"))
+		assert.Equal(t, 3, s.order.Len())
+	})
+
+	t.Run("重新写入后解除保护", func(t *testing.T) {
+		s := newDeleteShield(time.Minute, 10)
+		s.add("k")
+		s.remove("k")
+		assert.False(t, s.contains("k"))
+	})
+}
+
+func TestNewCache_DeleteShield(t *testing.T) {
+	_, err := NewCache(WithConfigMemory(createOtterAdapter(t)), WithConfigDeleteShield(-time.Second))
+	assert.ErrorIs(t, err, errors.ErrInvalidDeleteShieldTTL)
+
+	cache, err := NewCache(WithConfigMemory(createOtterAdapter(t)))
+	assert.NoError(t, err)
+	assert.Nil(t, cache.(*LayeredCache).shield)
+}
+
+func TestLayeredCache_DeleteShield(t *testing.T) {
+	ctx := context.Background()
+
+	newCache := func(t *testing.T) *LayeredCache {
+		c, err := NewCache(
+			WithConfigMemory(createOtterAdapter(t)),
+			WithConfigRemote(createRemoteAdapter(t)),
+			WithConfigDeleteShield(time.Minute),
+		)
+		assert.NoError(t, err)
+		return c.(*LayeredCache)
+	}
+
+	t.Run("删除后忽略其他节点写回的旧数据", func(t *testing.T) {
+		c := newCache(t)
+		assert.NoError(t, c.Set(ctx, "k", "v1"))
+		assert.NoError(t, c.Delete(ctx, "k"))
+
+		// 模拟其他节点并发写回旧数据
+		assert.NoError(t, c.remote.Set(ctx, "k", []byte("stale"), time.Hour))
+
+		var result string
+		assert.ErrorIs(t, c.Get(ctx, "k", &result), errors.ErrNotFound)
+
+		values := make(map[string]string)
+		assert.NoError(t, c.MGet(ctx, []string{"k"}, &values))
+		assert.Empty(t, values)
+	})
+
+	t.Run("窗口期内有loader时直接回源并解除保护", func(t *testing.T) {
+		c := newCache(t)
+		assert.NoError(t, c.Delete(ctx, "k"))
+		assert.NoError(t, c.remote.Set(ctx, "k", []byte("stale"), time.Hour))
+
+		var result string
+		err := c.Get(ctx, "k", &result, WithLoader(func(ctx context.Context, key string) (any, error) {
+			return "fresh", nil
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, "fresh", result)
+		assert.False(t, c.isShielded("k"))
+
+		assert.NoError(t, c.Get(ctx, "k", &result))
+		assert.Equal(t, "fresh", result)
+	})
+
+	t.Run("MGet中仅受保护的键回源", func(t *testing.T) {
+		c := newCache(t)
+		assert.NoError(t, c.MSet(ctx, map[string]any{"a": "va", "b": "vb"}))
+		assert.NoError(t, c.Delete(ctx, "b"))
+		assert.NoError(t, c.remote.Set(ctx, "b", []byte("stale"), time.Hour))
+
+		var loaded []string
+		values := make(map[string]string)
+		err := c.MGet(ctx, []string{"a", "b"}, &values, WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+			loaded = keys
+			return map[string]any{"b": "fresh"}, nil
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"b"}, loaded)
+		assert.Equal(t, map[string]string{"a": "va", "b": "fresh"}, values)
+	})
+
+	t.Run("Set后解除保护", func(t *testing.T) {
+		c := newCache(t)
+		assert.NoError(t, c.Delete(ctx, "k"))
+		assert.NoError(t, c.Set(ctx, "k", "v2"))
+
+		var result string
+		assert.NoError(t, c.Get(ctx, "k", &result))
+		assert.Equal(t, "v2", result)
+	})
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/biu7/layered-cache/storage"
+)
+
+const (
+	// demoteTimeout 淘汰降级写回 Remote 的超时时间
+	demoteTimeout = 5 * time.Second
+	// demoteQueueSize 等待写回 Remote 的淘汰条目数上限，队列已满时丢弃新淘汰的条目
+	demoteQueueSize = 1024
+)
+
+// demotion 等待写回 Remote 的淘汰条目
+type demotion struct {
+	key   string
+	value []byte
+}
+
+// enqueueDemote 淘汰回调，只将条目放入队列，队列已满时丢弃
+// Otter 在持有淘汰锁时同步执行回调，在这里访问 Remote 会使所有内存写入停顿
+func (c *LayeredCache) enqueueDemote(key string, value []byte) {
+	select {
+	case c.demotions <- demotion{key: key, value: value}:
+	default:
+		c.stats.demoteDrops.Add(1)
+	}
+}
+
+// drainDemotions 在后台逐个写回队列中的淘汰条目，缓存关闭时退出，队列中剩余的条目丢弃
+func (c *LayeredCache) drainDemotions() {
+	for {
+		select {
+		case d := <-c.demotions:
+			c.demote(d.key, d.value)
+		case <-c.life.done:
+			return
+		}
+	}
+}
+
+// demote 内存淘汰的条目比 Remote 中的副本更新时，将其重新写回 Remote，避免丢失
+// Remote 中不存在副本或键刚被删除时视为已被删除，不会写回；Remote 实现 storage.CompareSetter 时仅在副本未变化时写回，
+// 避免读取副本之后被删除或更新的键被写回旧值
+func (c *LayeredCache) demote(key string, value []byte) {
+	env, ok := decodeEnvelope(value)
+	if !ok {
+		return
+	}
+	if c.shield != nil && c.shield.contains(key) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), demoteTimeout)
+	defer cancel()
+
+	remoteData, err := c.remote.Get(ctx, key)
+	if err != nil {
+		return
+	}
+	if remoteEnv, ok := decodeEnvelope(remoteData); ok && !remoteEnv.createdAt.Before(env.createdAt) {
+		return
+	}
+
+	if setter, ok := c.remote.(storage.CompareSetter); ok {
+		_, _ = setter.CompareAndSet(ctx, key, remoteData, value)
+		return
+	}
+
+	remoteTTL, err := c.remote.TTL(ctx, key)
+	if err != nil || remoteTTL <= 0 {
+		remoteTTL = c.defaultRemoteTTL
+	}
+	_ = c.remote.Set(ctx, key, value, remoteTTL)
+}
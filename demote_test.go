@@ -0,0 +1,192 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCache_DemoteOnEvict(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		wantErr error
+	}{
+		{
+			name: "成功开启",
+			options: []Option{
+				WithConfigMemory(createOtterAdapter(t)),
+				WithConfigRemote(createRemoteAdapter(t)),
+				WithConfigEnvelope(true),
+				WithConfigDemoteOnEvict(true),
+			},
+		},
+		{
+			name: "失败 - 缺少Remote适配器",
+			options: []Option{
+				WithConfigMemory(createOtterAdapter(t)),
+				WithConfigEnvelope(true),
+				WithConfigDemoteOnEvict(true),
+			},
+			wantErr: errors.ErrDemoteRequiresBothLayers,
+		},
+		{
+			name: "失败 - 内存适配器不支持淘汰通知",
+			options: []Option{
+				WithConfigMemory(createMemoryAdapter(t)),
+				WithConfigRemote(createRemoteAdapter(t)),
+				WithConfigEnvelope(true),
+				WithConfigDemoteOnEvict(true),
+			},
+			wantErr: errors.ErrEvictionNotSupported,
+		},
+		{
+			name: "失败 - 未开启封装格式",
+			options: []Option{
+				WithConfigMemory(createOtterAdapter(t)),
+				WithConfigRemote(createRemoteAdapter(t)),
+				WithConfigDemoteOnEvict(true),
+			},
+			wantErr: errors.ErrEnvelopeRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCache(tt.options...)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLayeredCache_Demote(t *testing.T) {
+	ctx := context.Background()
+	cache, err := NewCache(
+		WithConfigMemory(createOtterAdapter(t)),
+		WithConfigRemote(createRemoteAdapter(t)),
+		WithConfigEnvelope(true),
+	)
+	assert.NoError(t, err)
+	c := cache.(*LayeredCache)
+
+	older := encodeEnvelope(envelope{createdAt: time.Now().Add(-time.Minute), payload: []byte("old")})
+	newer := encodeEnvelope(envelope{createdAt: time.Now(), payload: []byte("new")})
+
+	t.Run("Remote副本更旧时写回", func(t *testing.T) {
+		assert.NoError(t, c.remote.Set(ctx, "k1", older, time.Hour))
+		c.demote("k1", newer)
+
+		data, err := c.remote.Get(ctx, "k1")
+		assert.NoError(t, err)
+		assert.Equal(t, newer, data)
+
+		ttl, err := c.remote.TTL(ctx, "k1")
+		assert.NoError(t, err)
+		assert.InDelta(t, time.Hour.Seconds(), ttl.Seconds(), 5)
+	})
+
+	t.Run("Remote副本更新时不写回", func(t *testing.T) {
+		assert.NoError(t, c.remote.Set(ctx, "k2", newer, time.Hour))
+		c.demote("k2", older)
+
+		data, err := c.remote.Get(ctx, "k2")
+		assert.NoError(t, err)
+		assert.Equal(t, newer, data)
+	})
+
+	t.Run("Remote副本不存在时视为已删除", func(t *testing.T) {
+		c.demote("k3", newer)
+
+		_, err := c.remote.Get(ctx, "k3")
+		assert.ErrorIs(t, err, errors.ErrNotFound)
+	})
+
+	t.Run("队列已满时丢弃", func(t *testing.T) {
+		c.demotions = make(chan demotion, 1)
+		defer func() { c.demotions = nil }()
+
+		c.enqueueDemote("k5", newer)
+		c.enqueueDemote("k6", newer)
+		assert.Len(t, c.demotions, 1)
+		assert.Equal(t, int64(1), c.Stats().DemoteDrops)
+	})
+
+	t.Run("缺失值标记不写回", func(t *testing.T) {
+		assert.NoError(t, c.remote.Set(ctx, notFoundKey("k4"), []byte("other"), time.Hour))
+		c.demote(notFoundKey("k4"), notFoundPlaceholder)
+
+		data, err := c.remote.Get(ctx, notFoundKey("k4"))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("other"), data)
+	})
+}
+
+func TestLayeredCache_DemoteDeleted(t *testing.T) {
+	ctx := context.Background()
+	cache, err := NewCache(
+		WithConfigMemory(createOtterAdapter(t)),
+		WithConfigRemote(createRemoteAdapter(t)),
+		WithConfigEnvelope(true),
+		WithConfigDeleteShield(time.Minute),
+	)
+	assert.NoError(t, err)
+	c := cache.(*LayeredCache)
+
+	// 淘汰回调排队期间键被删除后又被其他实例写入旧值，删除保护期内不写回
+	older := encodeEnvelope(envelope{createdAt: time.Now().Add(-time.Minute), payload: []byte("old")})
+	newer := encodeEnvelope(envelope{createdAt: time.Now(), payload: []byte("new")})
+	assert.NoError(t, c.Delete(ctx, "k"))
+	assert.NoError(t, c.remote.Set(ctx, "k", older, time.Hour))
+	c.demote("k", newer)
+
+	data, err := c.remote.Get(ctx, "k")
+	assert.NoError(t, err)
+	assert.Equal(t, older, data)
+}
+
+func TestLayeredCache_DemoteOnEvict(t *testing.T) {
+	ctx := context.Background()
+	memory, err := storage.NewOtter(2000)
+	assert.NoError(t, err)
+
+	cache, err := NewCache(
+		WithConfigMemory(memory),
+		WithConfigRemote(createRemoteAdapter(t)),
+		WithConfigEnvelope(true),
+		WithConfigDemoteOnEvict(true),
+	)
+	assert.NoError(t, err)
+	c := cache.(*LayeredCache)
+
+	// Remote 中是旧数据，内存中是更新的数据（例如 Remote 写入失败）
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("evict-%d", i)
+		older := encodeEnvelope(envelope{createdAt: time.Now().Add(-time.Minute), payload: []byte("old")})
+		assert.NoError(t, c.remote.Set(ctx, key, older, time.Hour))
+		memory.Set(key, encodeEnvelope(envelope{createdAt: time.Now(), payload: []byte("new")}), time.Hour)
+	}
+
+	// 写入大量数据触发淘汰
+	for i := 0; i < 500; i++ {
+		memory.Set(fmt.Sprintf("filler-%d", i), make([]byte, 100), time.Hour)
+	}
+
+	assert.Eventually(t, func() bool {
+		for i := 0; i < 10; i++ {
+			var result string
+			data, err := c.remote.Get(ctx, fmt.Sprintf("evict-%d", i))
+			if err != nil {
+				return false
+			}
+			if err = c.decode(data, &result, nil); err == nil && result == "new" {
+				return true
+			}
+		}
+		return false
+	}, 3*time.Second, 20*time.Millisecond)
+}
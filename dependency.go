@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/biu7/layered-cache/errors"
+)
+
+// dependentsKeySuffix 依赖集合键的后缀，集合中保存依赖该键的所有子键
+const dependentsKeySuffix = "\x00deps"
+
+// dependentsKey 返回 key 对应的依赖集合键
+func dependentsKey(key string) string {
+	return key + dependentsKeySuffix
+}
+
+// DependOn 声明 child 依赖 parent，Delete(parent) 时级联删除 child
+// 依赖关系存储在 Remote 中，过期时间与默认 Remote TTL 相同，每次声明时刷新；需要开启 WithConfigDependencies
+func (c *LayeredCache) DependOn(ctx context.Context, child, parent string) error {
+	if c.deps == nil {
+		return c.misuse(errors.ErrDependencyDisabled)
+	}
+	scope, ctx := c.takeKeyContext(ctx)
+	child, parent = scope.key(child), scope.key(parent)
+	return c.deps.SAdd(ctx, dependentsKey(parent), []string{child}, c.defaultRemoteTTL)
+}
+
+// deleteDependents 级联删除依赖 parent 的子键，visited 用于避免循环依赖
+func (c *LayeredCache) deleteDependents(ctx context.Context, parent string, visited map[string]struct{}) error {
+	children, err := c.deps.SMembers(ctx, dependentsKey(parent))
+	if err != nil {
+		return err
+	}
+
+	for _, child := range children {
+		if _, ok := visited[child]; ok {
+			continue
+		}
+		visited[child] = struct{}{}
+
+		if err = c.deleteKey(ctx, child); err != nil {
+			return err
+		}
+		if err = c.deleteDependents(ctx, child, visited); err != nil {
+			return err
+		}
+	}
+
+	if len(children) == 0 {
+		return nil
+	}
+	return c.remote.Delete(ctx, dependentsKey(parent))
+}
@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_DependOn(t *testing.T) {
+	ctx := context.Background()
+	cache, err := NewCache(
+		WithConfigMemory(createOtterAdapter(t)),
+		WithConfigRemote(createRemoteAdapter(t)),
+		WithConfigDependencies(true),
+	)
+	assert.NoError(t, err)
+	c := cache.(*LayeredCache)
+
+	exists := func(key string) bool {
+		var value string
+		return c.Get(ctx, key, &value) == nil
+	}
+
+	t.Run("级联删除子键", func(t *testing.T) {
+		assert.NoError(t, c.MSet(ctx, map[string]any{
+			"user:1":          "user",
+			"view:profile:1":  "profile",
+			"view:timeline:1": "timeline",
+			"view:feed:1":     "feed",
+			"user:2":          "other",
+		}))
+		assert.NoError(t, c.DependOn(ctx, "view:profile:1", "user:1"))
+		assert.NoError(t, c.DependOn(ctx, "view:timeline:1", "user:1"))
+		// 多级依赖
+		assert.NoError(t, c.DependOn(ctx, "view:feed:1", "view:timeline:1"))
+
+		assert.NoError(t, c.Delete(ctx, "user:1"))
+
+		assert.False(t, exists("user:1"))
+		assert.False(t, exists("view:profile:1"))
+		assert.False(t, exists("view:timeline:1"))
+		assert.False(t, exists("view:feed:1"))
+		assert.True(t, exists("user:2"))
+
+		_, err := c.remote.Get(ctx, dependentsKey("user:1"))
+		assert.ErrorIs(t, err, errors.ErrNotFound)
+	})
+
+	t.Run("循环依赖", func(t *testing.T) {
+		assert.NoError(t, c.MSet(ctx, map[string]any{"a": "a", "b": "b"}))
+		assert.NoError(t, c.DependOn(ctx, "b", "a"))
+		assert.NoError(t, c.DependOn(ctx, "a", "b"))
+
+		assert.NoError(t, c.Delete(ctx, "a"))
+		assert.False(t, exists("a"))
+		assert.False(t, exists("b"))
+	})
+
+	t.Run("依赖集合不出现在RemoteKeys中", func(t *testing.T) {
+		assert.NoError(t, c.Set(ctx, "scan:1", "v"))
+		assert.NoError(t, c.DependOn(ctx, "scan:child", "scan:1"))
+
+		keys, _, err := c.RemoteKeys(ctx, "scan:", 0, 100)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"scan:1"}, keys)
+	})
+}
+
+func TestNewCache_Dependencies(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("未开启", func(t *testing.T) {
+		assert.ErrorIs(t, createTestCache(t).DependOn(ctx, "child", "parent"), errors.ErrDependencyDisabled)
+	})
+
+	t.Run("缺少Remote适配器", func(t *testing.T) {
+		_, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigDependencies(true))
+		assert.ErrorIs(t, err, errors.ErrRemoteRequired)
+	})
+
+	t.Run("Remote不支持集合", func(t *testing.T) {
+		_, err := NewCache(WithConfigRemote(&countingRemote{Remote: createRemoteAdapter(t)}), WithConfigDependencies(true))
+		assert.ErrorIs(t, err, errors.ErrOperationNotSupported)
+	})
+}
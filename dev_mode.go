@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// misuse 开发模式下对误用直接 panic，生产环境返回原错误
+func (c *LayeredCache) misuse(err error) error {
+	if c.devMode {
+		panic(fmt.Sprintf("layered-cache: %v", err))
+	}
+	return err
+}
+
+// checkGetTarget 开发模式下检查 Get 的 target 必须是非 nil 指针
+func (c *LayeredCache) checkGetTarget(target any) {
+	if !c.devMode {
+		return
+	}
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		panic(fmt.Sprintf("layered-cache: Get target must be a non-nil pointer, got %T", target))
+	}
+}
+
+// checkLayerTTL 开发模式下检查 TTL 选项对应的缓存层已配置，生产环境忽略未配置层的 TTL
+func (c *LayeredCache) checkLayerTTL(memoryTTL, remoteTTL *time.Duration) {
+	if !c.devMode {
+		return
+	}
+	if memoryTTL != nil && c.memory == nil {
+		panic("layered-cache: memory ttl option is set but no memory adapter is configured")
+	}
+	if remoteTTL != nil && c.remote == nil {
+		panic("layered-cache: remote ttl option is set but no remote adapter is configured")
+	}
+}
+
+// checkID 开发模式下检查 ID 不包含命名空间分隔符，避免不同前缀的键发生冲突
+func (c *TypedCache[ID, T]) checkID(keyPrefix string, id ID) {
+	if !c.devMode {
+		return
+	}
+	if s, ok := any(id).(string); ok && strings.Contains(s, separator) {
+		panic(fmt.Sprintf("layered-cache: id %q under prefix %q contains separator %q", s, keyPrefix, separator))
+	}
+}
@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_DevMode(t *testing.T) {
+	ctx := context.Background()
+	newCache := func(devMode bool) *LayeredCache {
+		cache, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigDevMode(devMode),
+		)
+		assert.NoError(t, err)
+		return cache.(*LayeredCache)
+	}
+
+	dev := newCache(true)
+	prod := newCache(false)
+	assert.NoError(t, dev.Set(ctx, "key", "value"))
+	assert.NoError(t, prod.Set(ctx, "key", "value"))
+
+	t.Run("Get的target不是指针", func(t *testing.T) {
+		var result string
+		assert.PanicsWithValue(t, "layered-cache: Get target must be a non-nil pointer, got string", func() {
+			_ = dev.Get(ctx, "key", result)
+		})
+		assert.NotPanics(t, func() {
+			assert.Error(t, prod.Get(ctx, "key", result))
+		})
+	})
+
+	t.Run("MGet的target无效", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_ = dev.MGet(ctx, []string{"key"}, map[string]string{})
+		})
+		assert.Error(t, prod.MGet(ctx, []string{"key"}, map[string]string{}))
+	})
+
+	t.Run("为未配置的缓存层设置TTL", func(t *testing.T) {
+		assert.PanicsWithValue(t, "layered-cache: remote ttl option is set but no remote adapter is configured", func() {
+			_ = dev.Set(ctx, "key", "value", WithRemoteTTL(time.Minute))
+		})
+		assert.NoError(t, prod.Set(ctx, "key", "value", WithRemoteTTL(time.Minute)))
+	})
+
+	t.Run("无效的选项", func(t *testing.T) {
+		var result string
+		assert.Panics(t, func() {
+			_ = dev.Get(ctx, "key", &result, WithMemoryTTL(-time.Second))
+		})
+		assert.Error(t, prod.Get(ctx, "key", &result, WithMemoryTTL(-time.Second)))
+	})
+
+	t.Run("TypedCache的ID包含分隔符", func(t *testing.T) {
+		assert.PanicsWithValue(t, `layered-cache: id "a:b" under prefix "user" contains separator ":"`, func() {
+			_ = Typed[string, string](dev).Set(ctx, "user", "a:b", "value")
+		})
+		assert.NoError(t, Typed[string, string](prod).Set(ctx, "user", "a:b", "value"))
+	})
+}
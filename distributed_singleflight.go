@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+)
+
+// lockPollInterval 等待其他实例加载时轮询 Remote 的间隔
+const lockPollInterval = 20 * time.Millisecond
+
+// withDistributedSingleflight 设置跨实例合并加载
+type withDistributedSingleflight struct {
+	readOption
+
+	ttl  time.Duration
+	wait time.Duration
+}
+
+func (w withDistributedSingleflight) applyGet(cfg *getOptions) {
+	cfg.lockTTL, cfg.lockWait = w.ttl, w.wait
+}
+
+// WithDistributedSingleflight 将 loader 的合并加载从进程内扩展到多个实例，只对单键 Get 生效
+// 键在所有缓存层都未命中时，先在 Remote 上获取过期时间为 ttl 的锁再调用 loader；未获取到锁的实例轮询 Remote，
+// 等待持有锁的实例写入结果，锁释放后仍没有结果或等待超过 wait 时自行调用 loader。
+// ttl 应大于 loader 的执行时间，为 0 表示不开启；Remote 不支持锁或出错时退化为进程内合并
+func WithDistributedSingleflight(ttl, wait time.Duration) ReadOption {
+	return withDistributedSingleflight{ttl: ttl, wait: wait}
+}
+
+// loadExclusive 获取到跨实例的锁后加载，未获取到时等待其他实例的加载结果
+func (c *LayeredCache) loadExclusive(ctx context.Context, key string, config *getOptions) ([]byte, error) {
+	if !config.useRemote(c) {
+		return c.loadAndCache(ctx, key, config)
+	}
+
+	unlock, err := c.lock(ctx, key, config.lockTTL)
+	if err == nil {
+		defer func() {
+			_ = unlock(context.WithoutCancel(ctx))
+		}()
+		// 未命中到获取锁之间其他实例可能已经写入结果
+		if data, loaded, _, err := c.probeLoaded(ctx, key, config); loaded {
+			return data, err
+		}
+		return c.loadAndCache(ctx, key, config)
+	}
+	if !stderrors.Is(err, errors.ErrLockHeld) {
+		return c.loadAndCache(ctx, key, config)
+	}
+
+	c.stats.lockWaits.Add(1)
+	data, loaded, err := c.waitLoaded(ctx, key, config)
+	if loaded || err != nil {
+		return data, err
+	}
+	return c.loadAndCache(ctx, key, config)
+}
+
+// waitLoaded 轮询 Remote 等待持有锁的实例写入结果，锁已释放、等待超时或 Remote 出错时 loaded 为 false
+func (c *LayeredCache) waitLoaded(ctx context.Context, key string, config *getOptions) (data []byte, loaded bool, err error) {
+	timer := time.NewTimer(config.lockWait)
+	defer timer.Stop()
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		case <-timer.C:
+			return nil, false, nil
+		case <-ticker.C:
+		}
+
+		data, loaded, locked, err := c.probeLoaded(ctx, key, config)
+		if loaded || !locked {
+			return data, loaded, err
+		}
+	}
+}
+
+// probeLoaded 读取一次 Remote 中的加载结果，locked 表示锁仍被持有；Remote 出错时 loaded 和 locked 都为 false
+func (c *LayeredCache) probeLoaded(ctx context.Context, key string, config *getOptions) (data []byte, loaded, locked bool, err error) {
+	var remoteData map[string][]byte
+	err = c.remoteCall(ctx, func(ctx context.Context) (err error) {
+		remoteData, err = c.remote.MGet(ctx, []string{key, notFoundKey(key), lockKey(key)})
+		return err
+	})
+	if err != nil && !IsNotFound(err) {
+		return nil, false, false, nil
+	}
+
+	data, exists := remoteData[key]
+	_, markerExists := remoteData[notFoundKey(key)]
+	if exists && isNotFoundPlaceholder(data) {
+		exists, markerExists = false, true
+	}
+	if exists && !c.isStale(data, config.maxAge) {
+		if config.useMemory(c) {
+			memoryTTL, _ := c.calculateLoaderTTL(config)
+			c.memorySet(key, data, memoryTTL)
+		}
+		return data, true, false, nil
+	}
+	if markerExists {
+		return nil, true, false, errors.ErrNotFound
+	}
+	_, locked = remoteData[lockKey(key)]
+	return nil, false, locked, nil
+}
@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// racingRemote 获取锁前写入值，模拟其他实例在未命中与获取锁之间完成加载
+type racingRemote struct {
+	*storage.Redis
+	key   string
+	value []byte
+}
+
+func (r racingRemote) SetNX(ctx context.Context, key string, value []byte, expire time.Duration) (bool, error) {
+	if err := r.Redis.Set(ctx, r.key, r.value, time.Minute); err != nil {
+		return false, err
+	}
+	return r.Redis.SetNX(ctx, key, value, expire)
+}
+
+func TestLayeredCache_DistributedSingleflight(t *testing.T) {
+	ctx := context.Background()
+
+	// 两个实例共用同一个 Remote，各自有独立的内存缓存
+	newInstances := func(t *testing.T) (Cache, Cache) {
+		remote := createRemoteAdapter(t)
+		a, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigRemote(remote))
+		assert.NoError(t, err)
+		b, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigRemote(remote))
+		assert.NoError(t, err)
+		return a, b
+	}
+
+	t.Run("配置校验", func(t *testing.T) {
+		var value string
+		err := createTestCache(t).Get(ctx, "k", &value, WithDistributedSingleflight(-time.Second, 0))
+		assert.ErrorIs(t, err, errors.ErrInvalidDistributedSingleflight)
+	})
+
+	t.Run("其他实例等待加载结果", func(t *testing.T) {
+		a, b := newInstances(t)
+		var calls atomic.Int32
+		started, release := make(chan struct{}), make(chan struct{})
+		opt := WithDistributedSingleflight(time.Minute, 5*time.Second)
+
+		done := make(chan error)
+		go func() {
+			var value string
+			done <- a.Get(ctx, "k", &value, opt, WithLoader(func(ctx context.Context, key string) (any, error) {
+				calls.Add(1)
+				close(started)
+				<-release
+				return "loaded", nil
+			}))
+		}()
+		<-started
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			close(release)
+		}()
+		var value string
+		err := b.Get(ctx, "k", &value, opt, WithLoader(func(ctx context.Context, key string) (any, error) {
+			calls.Add(1)
+			return "other", nil
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, "loaded", value)
+		assert.NoError(t, <-done)
+		assert.Equal(t, int32(1), calls.Load())
+		assert.Equal(t, int64(1), b.Stats().LockWaits)
+	})
+
+	t.Run("等待超时后自行加载", func(t *testing.T) {
+		a, b := newInstances(t)
+		unlock, err := a.Lock(ctx, "k", time.Minute)
+		assert.NoError(t, err)
+		defer unlock(ctx)
+
+		var value string
+		err = b.Get(ctx, "k", &value, WithDistributedSingleflight(time.Minute, 30*time.Millisecond), WithLoader(func(ctx context.Context, key string) (any, error) {
+			return "self", nil
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, "self", value)
+	})
+
+	t.Run("获取锁后已有结果时不再加载", func(t *testing.T) {
+		remote := racingRemote{Redis: createRemoteAdapter(t).(*storage.Redis), key: "k", value: []byte("other")}
+		c, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigRemote(remote))
+		assert.NoError(t, err)
+
+		var value string
+		err = c.Get(ctx, "k", &value, WithDistributedSingleflight(time.Minute, time.Second), WithLoader(func(ctx context.Context, key string) (any, error) {
+			t.Error("获取锁后 Remote 已有结果，不应调用 loader")
+			return "self", nil
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, "other", value)
+	})
+
+	t.Run("锁释放后没有结果时自行加载", func(t *testing.T) {
+		a, b := newInstances(t)
+		unlock, err := a.Lock(ctx, "k", time.Minute)
+		assert.NoError(t, err)
+		go func() {
+			time.Sleep(30 * time.Millisecond)
+			_ = unlock(ctx)
+		}()
+
+		var value string
+		err = b.Get(ctx, "k", &value, WithDistributedSingleflight(time.Minute, time.Minute), WithLoader(func(ctx context.Context, key string) (any, error) {
+			return "self", nil
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, "self", value)
+	})
+}
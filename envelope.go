@@ -0,0 +1,174 @@
+package cache
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/biu7/layered-cache/serializer"
+)
+
+const (
+	// envelopeMagic 封装格式的魔数，0xC1 在 msgpack 和 UTF-8 中均未被使用，不会与序列化后的数据混淆
+	envelopeMagic byte = 0xC1
+
+	// envelopeVersion 封装格式版本
+	envelopeVersion byte = 1
+
+	// envelopeHeaderSize 封装头长度：magic(1) + version(1) + flags(1) + createdAt(8)
+	envelopeHeaderSize = 11
+
+	// envelopeFlagTyped 封装头后跟随类型名称：len(1) + name
+	envelopeFlagTyped byte = 1 << 0
+
+	// envelopeFlagStale 数据已被 Invalidate 标记为失效
+	envelopeFlagStale byte = 1 << 1
+
+	// envelopeFlagFormat 封装头记录了 payload 的序列化格式，由 envelopeFlagJSON 区分 JSON 和 MessagePack
+	envelopeFlagFormat byte = 1 << 2
+
+	// envelopeFlagJSON payload 为 JSON 格式
+	envelopeFlagJSON byte = 1 << 3
+
+	// envelopeFlagNotFound 缺失值占位符，仅用于 notFoundPlaceholder
+	envelopeFlagNotFound byte = 1 << 4
+
+	// maxTypeNameLen 类型名称的最大长度
+	maxTypeNameLen = 255
+)
+
+// envelope 缓存值的封装，记录写入时间等元数据
+type envelope struct {
+	flags     byte
+	createdAt time.Time
+	typeName  string
+	payload   []byte
+}
+
+// encodeEnvelope 将数据封装为存储格式
+func encodeEnvelope(env envelope) []byte {
+	size := envelopeHeaderSize
+	env.flags &^= envelopeFlagTyped
+	if env.typeName != "" {
+		env.flags |= envelopeFlagTyped
+		size += 1 + len(env.typeName)
+	}
+
+	buf := make([]byte, size+len(env.payload))
+	buf[0] = envelopeMagic
+	buf[1] = envelopeVersion
+	buf[2] = env.flags
+	binary.BigEndian.PutUint64(buf[3:envelopeHeaderSize], uint64(env.createdAt.UnixMilli()))
+	if env.typeName != "" {
+		buf[envelopeHeaderSize] = byte(len(env.typeName))
+		copy(buf[envelopeHeaderSize+1:], env.typeName)
+	}
+	copy(buf[size:], env.payload)
+	return buf
+}
+
+// decodeEnvelope 解析存储格式，不是封装格式时返回 false
+func decodeEnvelope(data []byte) (envelope, bool) {
+	if len(data) < envelopeHeaderSize || data[0] != envelopeMagic || data[1] != envelopeVersion {
+		return envelope{}, false
+	}
+
+	env := envelope{
+		flags:     data[2],
+		createdAt: time.UnixMilli(int64(binary.BigEndian.Uint64(data[3:envelopeHeaderSize]))),
+		payload:   data[envelopeHeaderSize:],
+	}
+
+	if env.flags&envelopeFlagTyped != 0 {
+		if len(env.payload) < 1 || len(env.payload) < 1+int(env.payload[0]) {
+			return envelope{}, false
+		}
+		n := int(env.payload[0])
+		env.typeName = string(env.payload[1 : 1+n])
+		env.payload = env.payload[1+n:]
+	}
+	return env, true
+}
+
+// encode 序列化值，以封装格式写入时添加封装头，s 为本次调用指定的序列化器
+func (c *LayeredCache) encode(value any, s serializer.Serializer) ([]byte, error) {
+	data, err := c.marshal(value, s)
+	if err != nil {
+		return nil, err
+	}
+	if data, err = c.encodeValue(data); err != nil {
+		return nil, err
+	}
+
+	if !c.envelopeWrite {
+		return data, nil
+	}
+	env := envelope{createdAt: time.Now(), typeName: registeredName(value), payload: data}
+	if fs, ok := c.serializerFor(value, s).(serializer.FormatSerializer); ok && !isRaw(value) {
+		env.flags |= envelopeFlagFormat
+		if fs.Format() == serializer.FormatJSON {
+			env.flags |= envelopeFlagJSON
+		}
+	}
+	return encodeEnvelope(env), nil
+}
+
+// decode 反序列化存储的数据，开启封装时兼容读取未封装的旧数据，
+// 带有类型名称且 target 为接口类型时还原为注册的具体类型，s 为本次调用指定的序列化器
+func (c *LayeredCache) decode(data []byte, target any, s serializer.Serializer) error {
+	env, ok := envelope{}, false
+	if c.envelope {
+		env, ok = decodeEnvelope(data)
+	}
+	if !ok {
+		data, err := c.decodeValue(data)
+		if err != nil {
+			return err
+		}
+		return c.unmarshal(data, target, s)
+	}
+
+	var err error
+	if env.payload, err = c.decodeValue(env.payload); err != nil {
+		return err
+	}
+	if env.typeName != "" {
+		if decoded, err := c.decodeTyped(env, target, s); decoded {
+			return err
+		}
+	}
+	return c.unmarshalEnvelope(env, target, s)
+}
+
+// unmarshalEnvelope 反序列化封装中的数据，封装头记录了序列化格式时按该格式解析
+func (c *LayeredCache) unmarshalEnvelope(env envelope, target any, s serializer.Serializer) error {
+	fs, ok := c.serializerFor(target, s).(serializer.FormatSerializer)
+	if !ok || env.flags&envelopeFlagFormat == 0 || len(env.payload) == 0 || isRaw(target) {
+		return c.unmarshal(env.payload, target, s)
+	}
+
+	format := serializer.FormatMsgPack
+	if env.flags&envelopeFlagJSON != 0 {
+		format = serializer.FormatJSON
+	}
+	return fs.UnmarshalFormat(env.payload, format, target)
+}
+
+// isRaw 判断值是否不经过序列化器直接读写，与 Marshal、Unmarshal 的处理保持一致
+func isRaw(v any) bool {
+	switch v.(type) {
+	case []byte, string, *[]byte, *string:
+		return true
+	}
+	return false
+}
+
+// payload 返回存储数据中的序列化内容，中间件还原失败时返回 nil
+func (c *LayeredCache) payload(data []byte) []byte {
+	if c.envelope {
+		if env, ok := decodeEnvelope(data); ok {
+			data = env.payload
+		}
+	}
+	data, _ = c.decodeValue(data)
+	return data
+}
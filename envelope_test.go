@@ -0,0 +1,264 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/serializer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvelope_EncodeDecode(t *testing.T) {
+	createdAt := time.UnixMilli(1700000000123)
+	data := encodeEnvelope(envelope{flags: 0x80, createdAt: createdAt, payload: []byte(`{"id":1}`)})
+
+	assert.Equal(t, envelopeMagic, data[0])
+	assert.Len(t, data, envelopeHeaderSize+8)
+
+	env, ok := decodeEnvelope(data)
+	assert.True(t, ok)
+	assert.Equal(t, byte(0x80), env.flags)
+	assert.True(t, createdAt.Equal(env.createdAt))
+	assert.Equal(t, []byte(`{"id":1}`), env.payload)
+}
+
+func TestEnvelope_TypeName(t *testing.T) {
+	data := encodeEnvelope(envelope{createdAt: time.Now(), typeName: "user", payload: []byte(`{}`)})
+
+	env, ok := decodeEnvelope(data)
+	assert.True(t, ok)
+	assert.Equal(t, envelopeFlagTyped, env.flags&envelopeFlagTyped)
+	assert.Equal(t, "user", env.typeName)
+	assert.Equal(t, []byte(`{}`), env.payload)
+
+	// 类型名称长度越界
+	_, ok = decodeEnvelope(data[:envelopeHeaderSize+2])
+	assert.False(t, ok)
+}
+
+func TestEnvelope_DecodeLegacy(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "JSON数据", data: []byte(`{"id":1,"name":"Alice"}`)},
+		{name: "空数据", data: nil},
+		{name: "长度不足", data: []byte{envelopeMagic, envelopeVersion, 0}},
+		{name: "未知版本", data: append([]byte{envelopeMagic, 99}, make([]byte, 20)...)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok := decodeEnvelope(tt.data)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestLayeredCache_Envelope(t *testing.T) {
+	ctx := context.Background()
+	cache, err := NewCache(
+		WithConfigMemory(createOtterAdapter(t)),
+		WithConfigRemote(createRemoteAdapter(t)),
+		WithConfigEnvelope(true),
+	)
+	assert.NoError(t, err)
+	c := cache.(*LayeredCache)
+
+	t.Run("写入封装格式并正常读取", func(t *testing.T) {
+		before := time.Now().Add(-time.Second)
+		assert.NoError(t, c.Set(ctx, "user:1", TestUser{ID: 1, Name: "Alice"}))
+
+		data, err := c.remote.Get(ctx, "user:1")
+		assert.NoError(t, err)
+		env, ok := decodeEnvelope(data)
+		assert.True(t, ok)
+		assert.True(t, env.createdAt.After(before))
+
+		var user TestUser
+		assert.NoError(t, c.Get(ctx, "user:1", &user))
+		assert.Equal(t, TestUser{ID: 1, Name: "Alice"}, user)
+	})
+
+	t.Run("兼容读取未封装的旧数据", func(t *testing.T) {
+		assert.NoError(t, c.remote.Set(ctx, "user:2", []byte(`{"id":2,"name":"Bob"}`), time.Hour))
+
+		var user TestUser
+		assert.NoError(t, c.Get(ctx, "user:2", &user))
+		assert.Equal(t, TestUser{ID: 2, Name: "Bob"}, user)
+
+		users := make(map[string]TestUser)
+		assert.NoError(t, c.MGet(ctx, []string{"user:1", "user:2"}, &users))
+		assert.Len(t, users, 2)
+	})
+
+	t.Run("loader加载的数据同样封装", func(t *testing.T) {
+		var result string
+		err := c.Get(ctx, "loaded", &result, WithLoader(func(ctx context.Context, key string) (any, error) {
+			return "value", nil
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, "value", result)
+
+		data, err := c.remote.Get(ctx, "loaded")
+		assert.NoError(t, err)
+		_, ok := decodeEnvelope(data)
+		assert.True(t, ok)
+	})
+}
+
+func TestLayeredCache_EnvelopeCompat(t *testing.T) {
+	ctx := context.Background()
+	remote := createRemoteAdapter(t)
+	newCache := func(opts ...Option) *LayeredCache {
+		cache, err := NewCache(append([]Option{WithConfigRemote(remote)}, opts...)...)
+		assert.NoError(t, err)
+		return cache.(*LayeredCache)
+	}
+
+	legacy := newCache()
+	compat := newCache(WithConfigEnvelopeCompat(true))
+	upgraded := newCache(WithConfigEnvelopeCompat(false))
+
+	t.Run("兼容模式以旧格式写入，旧版本可以读取", func(t *testing.T) {
+		assert.NoError(t, compat.Set(ctx, "compat", TestUser{ID: 1, Name: "Alice"}))
+
+		data, err := remote.Get(ctx, "compat")
+		assert.NoError(t, err)
+		_, ok := decodeEnvelope(data)
+		assert.False(t, ok)
+
+		var user TestUser
+		assert.NoError(t, legacy.Get(ctx, "compat", &user))
+		assert.Equal(t, "Alice", user.Name)
+	})
+
+	t.Run("兼容模式可以读取封装格式", func(t *testing.T) {
+		assert.NoError(t, upgraded.Set(ctx, "upgraded", TestUser{ID: 2, Name: "Bob"}))
+
+		data, err := remote.Get(ctx, "upgraded")
+		assert.NoError(t, err)
+		_, ok := decodeEnvelope(data)
+		assert.True(t, ok)
+
+		var user TestUser
+		assert.NoError(t, compat.Get(ctx, "upgraded", &user))
+		assert.Equal(t, "Bob", user.Name)
+	})
+
+	t.Run("旧格式写入时不支持淘汰降级", func(t *testing.T) {
+		_, err := NewCache(
+			WithConfigMemory(createOtterAdapter(t)),
+			WithConfigRemote(remote),
+			WithConfigEnvelopeCompat(true),
+			WithConfigDemoteOnEvict(true),
+		)
+		assert.ErrorIs(t, err, errors.ErrEnvelopeRequired)
+	})
+}
+
+func TestLayeredCache_AutoSerializer(t *testing.T) {
+	ctx := context.Background()
+	type user struct {
+		ID   int64  `json:"id" msgpack:"id"`
+		Name string `json:"name" msgpack:"name"`
+	}
+
+	for _, withEnvelope := range []bool{true, false} {
+		t.Run(fmt.Sprintf("envelope=%v", withEnvelope), func(t *testing.T) {
+			remote := createRemoteAdapter(t)
+			newCache := func(env string) Cache {
+				opts := []Option{WithConfigRemote(remote), WithConfigSerializer(serializer.Auto(env))}
+				if withEnvelope {
+					opts = append(opts, WithConfigEnvelope(true))
+				}
+				c, err := NewCache(opts...)
+				assert.NoError(t, err)
+				return c
+			}
+			dev, prod := newCache("dev"), newCache("prod")
+
+			// 开发环境写入 JSON，可以直接查看
+			assert.NoError(t, dev.Set(ctx, "user:1", user{ID: 1, Name: "Alice"}))
+			raw, err := remote.Get(ctx, "user:1")
+			assert.NoError(t, err)
+			assert.True(t, bytes.HasSuffix(raw, []byte(`{"id":1,"name":"Alice"}`)))
+
+			// 生产环境写入 MessagePack
+			assert.NoError(t, prod.Set(ctx, "user:2", user{ID: 2, Name: "Bob"}))
+			raw, err = remote.Get(ctx, "user:2")
+			assert.NoError(t, err)
+			assert.False(t, bytes.Contains(raw, []byte(`"name"`)))
+
+			// 两种环境都能读取对方写入的数据
+			for _, c := range []Cache{dev, prod} {
+				var u1, u2 user
+				assert.NoError(t, c.Get(ctx, "user:1", &u1))
+				assert.NoError(t, c.Get(ctx, "user:2", &u2))
+				assert.Equal(t, "Alice", u1.Name)
+				assert.Equal(t, "Bob", u2.Name)
+			}
+
+			if withEnvelope {
+				env, ok := decodeEnvelope(raw)
+				assert.True(t, ok)
+				assert.Equal(t, envelopeFlagFormat, env.flags&(envelopeFlagFormat|envelopeFlagJSON))
+			}
+		})
+	}
+}
+
+func TestLayeredCache_MaxAge(t *testing.T) {
+	ctx := context.Background()
+
+	// writeAged 以指定的写入时间直接写入两层缓存
+	writeAged := func(t *testing.T, c *LayeredCache, key, value string, age time.Duration) {
+		t.Helper()
+		data, err := c.Marshal(value)
+		assert.NoError(t, err)
+		data = encodeEnvelope(envelope{createdAt: time.Now().Add(-age), payload: data})
+		c.memory.Set(key, data, time.Hour)
+		assert.NoError(t, c.remote.Set(ctx, key, data, time.Hour))
+	}
+
+	t.Run("超过最大年龄时重新加载", func(t *testing.T) {
+		c := createEnvelopeCache(t)
+		writeAged(t, c, "config:1", "old", time.Hour)
+
+		var result string
+		assert.NoError(t, c.Get(ctx, "config:1", &result, WithMaxAge(2*time.Hour)))
+		assert.Equal(t, "old", result)
+
+		err := c.Get(ctx, "config:1", &result, WithMaxAge(time.Minute), WithLoader(func(ctx context.Context, key string) (any, error) {
+			return "new", nil
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, "new", result)
+	})
+
+	t.Run("MGet 降级返回过期数据", func(t *testing.T) {
+		c := createEnvelopeCache(t)
+		writeAged(t, c, "config:1", "old", time.Hour)
+
+		values := make(map[string]string)
+		assert.NoError(t, c.MGet(ctx, []string{"config:1"}, &values, WithMaxAge(time.Minute)))
+		assert.Empty(t, values)
+
+		assert.NoError(t, c.MGet(ctx, []string{"config:1"}, &values, WithMaxAge(time.Minute), WithServeStale(true)))
+		assert.Equal(t, map[string]string{"config:1": "old"}, values)
+	})
+
+	t.Run("参数校验", func(t *testing.T) {
+		c := createEnvelopeCache(t)
+		var result string
+		assert.ErrorIs(t, c.Get(ctx, "k", &result, WithMaxAge(-time.Second)), errors.ErrInvalidMaxAge)
+
+		plain, err := NewCache(WithConfigMemory(createOtterAdapter(t)))
+		assert.NoError(t, err)
+		assert.ErrorIs(t, plain.Get(ctx, "k", &result, WithMaxAge(time.Minute)), errors.ErrEnvelopeRequired)
+	})
+}
@@ -1,9 +1,14 @@
 package errors
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 var (
 	Is  = errors.Is
+	As  = errors.As
 	New = errors.New
 )
 
@@ -12,6 +17,9 @@ var (
 
 	ErrNotFound = errors.New("key not found")
 
+	// ErrNotFoundCached 命中了缓存的缺失值标记，errors.Is(err, ErrNotFound) 同样成立
+	ErrNotFoundCached = fmt.Errorf("%w (cached)", ErrNotFound)
+
 	// ErrInvalidMemoryExpireTime 无效的过期时间
 	ErrInvalidMemoryExpireTime = errors.New("invalid memory expire time")
 	ErrInvalidRedisExpireTime  = errors.New("invalid redis expire time")
@@ -19,4 +27,198 @@ var (
 
 	// ErrInvalidMGetTarget 无效的目标类型
 	ErrInvalidMGetTarget = errors.New("invalid target type, must be a pointer to map[string]T")
+
+	// ErrInvalidReadRepairRate 无效的读修复采样比例
+	ErrInvalidReadRepairRate = errors.New("invalid read repair rate, must be in [0, 1]")
+
+	// ErrInvalidDeleteShieldTTL 无效的删除保护窗口
+	ErrInvalidDeleteShieldTTL = errors.New("invalid delete shield ttl")
+
+	// ErrInvalidShadowCompareRate 无效的影子比对采样比例
+	ErrInvalidShadowCompareRate = errors.New("invalid shadow compare rate, must be in [0, 1]")
+
+	// ErrEnvelopeRequired 功能依赖封装格式中的元数据
+	ErrEnvelopeRequired = errors.New("envelope is required")
+
+	// ErrDemoteRequiresBothLayers 淘汰降级需要同时配置内存和 Remote 适配器
+	ErrDemoteRequiresBothLayers = errors.New("demote on evict requires both memory and remote adapters")
+
+	// ErrEvictionNotSupported 内存适配器不支持淘汰通知
+	ErrEvictionNotSupported = errors.New("memory adapter does not support eviction notification")
+
+	// ErrRemoteRequired 操作需要配置 Remote 适配器
+	ErrRemoteRequired = errors.New("remote adapter is required")
+
+	// ErrRedisAddrsRequired 创建集群或分片客户端时没有指定节点地址
+	ErrRedisAddrsRequired = errors.New("redis addresses are required")
+
+	// ErrKeyNotInSnapshot 读取的键不在快照的键集合中
+	ErrKeyNotInSnapshot = errors.New("key is not in snapshot")
+
+	// ErrEmptyPrefix 前缀不能为空
+	ErrEmptyPrefix = errors.New("prefix must not be empty")
+
+	// ErrOperationNotSupported 适配器不支持该操作
+	ErrOperationNotSupported = errors.New("operation not supported by adapter")
+
+	// ErrValueTooLarge 值超过内存缓存单个条目的大小上限或 WithConfigMaxValueSize 设置的上限
+	ErrValueTooLarge = errors.New("value exceeds entry size limit")
+
+	// ErrMemoryRejected 内存适配器拒绝了写入，值只缓存在 Remote 中
+	ErrMemoryRejected = errors.New("memory adapter rejected the write")
+
+	// ErrLoaderRequired 操作需要通过 WithLoader 设置 loader
+	ErrLoaderRequired = errors.New("loader required")
+
+	// ErrLoaderTimeout loader 执行超时
+	ErrLoaderTimeout = errors.New("loader timeout")
+
+	// ErrInvalidLoaderTimeout 无效的 loader 超时时间
+	ErrInvalidLoaderTimeout = errors.New("invalid loader timeout")
+
+	// ErrInvalidMaxAge 无效的数据最大年龄
+	ErrInvalidMaxAge = errors.New("invalid max age")
+
+	// ErrDependencyDisabled 未开启键依赖
+	ErrDependencyDisabled = errors.New("key dependency is not enabled")
+
+	// ErrInvalidAdaptiveBatch 无效的自适应批量读取配置
+	ErrInvalidAdaptiveBatch = errors.New("invalid adaptive batch config, requires target > 0 and 0 < min <= max")
+
+	// ErrInvalidAutoBatch 无效的合并读取配置
+	ErrInvalidAutoBatch = errors.New("invalid auto batch config, requires window > 0 and max keys > 0")
+
+	// ErrInvalidHotKeys 无效的热点键统计配置
+	ErrInvalidHotKeys = errors.New("invalid hot keys config, requires window > 0 and threshold >= 0")
+
+	// ErrInvalidMaxValueSize 无效的值大小上限配置
+	ErrInvalidMaxValueSize = errors.New("invalid max value size config, requires size > 0 and a known policy")
+
+	// ErrInvalidWatchInterval 无效的 Watch 轮询间隔
+	ErrInvalidWatchInterval = errors.New("invalid watch interval")
+
+	// ErrInvalidSiblingPrefetch 无效的相邻键预取配置
+	ErrInvalidSiblingPrefetch = errors.New("invalid sibling prefetch config, requires window > 0 and rate > 0")
+
+	// ErrBothLayersRequired 功能需要同时配置内存和 Remote 适配器
+	ErrBothLayersRequired = errors.New("both memory and remote adapters are required")
+
+	// ErrInvalidTTLJitter 无效的 TTL 抖动比例
+	ErrInvalidTTLJitter = errors.New("invalid ttl jitter, must be in [0, 1)")
+
+	// ErrInvalidValueMiddleware 值中间件不能为 nil
+	ErrInvalidValueMiddleware = errors.New("value middleware must not be nil")
+
+	// ErrInvalidInterceptor 拦截器不能为 nil
+	ErrInvalidInterceptor = errors.New("interceptor must not be nil")
+
+	// ErrInvalidCron 无效的 cron 表达式
+	ErrInvalidCron = errors.New("invalid cron expression")
+
+	// ErrChecksumMismatch 缓存数据的校验和不一致
+	ErrChecksumMismatch = errors.New("cache value checksum mismatch")
+
+	// ErrCircuitOpen 熔断打开，请求被直接拒绝
+	ErrCircuitOpen = errors.New("circuit breaker is open")
+
+	// ErrRateLimited 超过限流，请求被直接拒绝
+	ErrRateLimited = errors.New("rate limit exceeded")
+
+	// ErrInvalidLoaderBreaker 无效的 loader 熔断配置
+	ErrInvalidLoaderBreaker = errors.New("invalid loader breaker config, requires failures > 0 and cooldown > 0")
+
+	// ErrInvalidRemoteBreaker 无效的 Remote 熔断配置
+	ErrInvalidRemoteBreaker = errors.New("invalid remote breaker config, requires failures > 0 and cooldown > 0")
+
+	// ErrInvalidLoaderRateLimit 无效的 loader 限流配置
+	ErrInvalidLoaderRateLimit = errors.New("invalid loader rate limit config, requires rate > 0 and burst > 0")
+
+	// ErrInvalidPoisonThreshold 无效的毒丸数据删除阈值
+	ErrInvalidPoisonThreshold = errors.New("invalid poison threshold")
+
+	// ErrInvalidWriteThrough 无效的写穿配置
+	ErrInvalidWriteThrough = errors.New("invalid write through config, requires a non-nil func and a known policy")
+
+	// ErrInvalidAsyncRemoteWrites 无效的异步 Remote 写入配置
+	ErrInvalidAsyncRemoteWrites = errors.New("invalid async remote writes config, requires queue size > 0 and workers > 0")
+
+	// ErrClosed 缓存已关闭
+	ErrClosed = errors.New("cache is closed")
+
+	// ErrInvalidRefreshAhead 无效的提前刷新配置
+	ErrInvalidRefreshAhead = errors.New("invalid refresh ahead config, requires interval > 0 and max keys > 0")
+
+	// ErrInvalidRemoteFailurePolicy 无效的 Remote 出错处理策略
+	ErrInvalidRemoteFailurePolicy = errors.New("invalid remote failure policy, requires FailClosed or FailOpen")
+
+	// ErrInvalidSkipLayers 同时跳过了内存缓存和 Remote
+	ErrInvalidSkipLayers = errors.New("invalid skip layers, cannot skip both memory and remote")
+
+	// ErrInvalidLockTTL 无效的锁过期时间
+	ErrInvalidLockTTL = errors.New("invalid lock ttl, must be greater than 0")
+
+	// ErrLockHeld 锁已被其他持有者占用
+	ErrLockHeld = errors.New("lock is held by another owner")
+
+	// ErrLockLost 释放锁时锁已过期或被其他持有者占用
+	ErrLockLost = errors.New("lock expired or was taken by another owner")
+
+	// ErrInvalidDistributedSingleflight 无效的跨实例合并加载配置
+	ErrInvalidDistributedSingleflight = errors.New("invalid distributed singleflight config, requires ttl >= 0 and wait >= 0")
+
+	// ErrInvalidBatchChunkSize 无效的批量拆分大小
+	ErrInvalidBatchChunkSize = errors.New("invalid batch chunk size, must be greater than or equal to 0")
+
+	// ErrInvalidRemoteConcurrency 无效的 Remote 并发数
+	ErrInvalidRemoteConcurrency = errors.New("invalid remote concurrency, must be greater than 0")
+
+	// ErrCustomKeyLayout 自定义键生成方式（TypedCache 的 KeyBuilder 或 WithConfigKeyHasher）下不支持按前缀遍历或删除
+	ErrCustomKeyLayout = errors.New("operation requires the default prefix:id key layout")
+
+	// ErrInvalidLayers 无效的多级 Remote 配置
+	ErrInvalidLayers = errors.New("invalid layers, requires at least one layer with a remote adapter and a non-negative ttl, and cannot be combined with WithConfigRemote")
+
+	// ErrInvalidLocalConfig 无效的进程内对象缓存配置
+	ErrInvalidLocalConfig = errors.New("invalid local cache config, requires capacity > 0 and ttl > 0")
+
+	// ErrExpvarNameExists expvar 名称已被占用
+	ErrExpvarNameExists = errors.New("expvar name already published")
+)
+
+// TTLSource TTL 的来源
+type TTLSource string
+
+const (
+	// TTLSourceDefault 通过 WithConfigDefaultTTL、WithConfigDefaultCacheNotFound 配置的默认值
+	TTLSourceDefault TTLSource = "default"
+
+	// TTLSourceConfig 通过其他 WithConfigXxx 选项配置的值
+	TTLSourceConfig TTLSource = "config"
+
+	// TTLSourcePerCall 单次调用传入的值
+	TTLSourcePerCall TTLSource = "per-call"
 )
+
+// TTLError TTL 校验失败的详细信息，记录出错的字段、取值和来源
+// 可以通过 errors.Is 匹配 Err 对应的哨兵错误，例如 ErrInvalidMemoryExpireTime
+type TTLError struct {
+	// Field 出错的字段名称，例如 memoryTTL
+	Field string
+
+	// Value 无效的取值
+	Value time.Duration
+
+	// Source 取值的来源
+	Source TTLSource
+
+	// Err 对应的哨兵错误
+	Err error
+}
+
+func (e *TTLError) Error() string {
+	return fmt.Sprintf("%v: %s=%s (%s)", e.Err, e.Field, e.Value, e.Source)
+}
+
+func (e *TTLError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,265 @@
+// Command stampede 模拟大量并发请求同时未命中缓存（缓存击穿）的场景，
+// 对比各项防护下 loader 的实际调用次数，既是这些功能的使用示例，也作为验收测试
+//
+//	go run ./example/stampede -n 10000
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/biu7/layered-cache"
+	"github.com/biu7/layered-cache/storage"
+	"github.com/redis/go-redis/v9"
+)
+
+// loadLatency 模拟一次数据库查询的耗时，并发请求在这段时间内到达
+const loadLatency = 50 * time.Millisecond
+
+// result 单个场景的统计结果
+type result struct {
+	// name 场景名称
+	name string
+
+	// requests 发出的请求数
+	requests int
+
+	// loads 实际调用 loader 的次数，即打到数据库的查询数
+	loads int64
+
+	// shared 复用其他请求加载结果的请求数
+	shared int64
+
+	// rejected 被限流拒绝的请求数
+	rejected int64
+
+	// lockWaits 未获取到分布式锁、等待其他实例加载结果的请求数
+	lockWaits int64
+
+	// elapsed 场景的运行耗时
+	elapsed time.Duration
+}
+
+// scenario 一个模拟场景，返回 loader 的调用次数等统计
+type scenario struct {
+	name string
+	run  func(ctx context.Context, env *env, n int) (result, error)
+}
+
+// env 场景共用的环境，每个场景使用独立的 Redis 数据库
+type env struct {
+	redis *miniredis.Miniredis
+	db    int
+}
+
+// newCaches 创建连接到新 Redis 数据库的缓存实例，instances 个实例共享同一个数据库
+func (e *env) newCaches(instances int, opts ...cache.Option) ([]*cache.LayeredCache, error) {
+	e.db++
+	rdb := redis.NewClient(&redis.Options{Addr: e.redis.Addr(), DB: e.db})
+	remote := storage.NewRedisWithClient(rdb)
+
+	caches := make([]*cache.LayeredCache, 0, instances)
+	for range instances {
+		memory, err := storage.NewOtter(1 << 20)
+		if err != nil {
+			return nil, err
+		}
+		c, err := cache.NewCache(append([]cache.Option{cache.WithConfigMemory(memory), cache.WithConfigRemote(remote)}, opts...)...)
+		if err != nil {
+			return nil, err
+		}
+		caches = append(caches, c.(*cache.LayeredCache))
+	}
+	return caches, nil
+}
+
+// slowLoader 模拟数据库查询，found 为 false 时返回 ErrNotFound
+func slowLoader(found bool) cache.LoaderFunc {
+	return func(ctx context.Context, key string) (any, error) {
+		time.Sleep(loadLatency)
+		if !found {
+			return nil, cache.ErrNotFound
+		}
+		return "value of " + key, nil
+	}
+}
+
+// concurrently 同时启动 n 个请求并等待全部完成
+func concurrently(n int, fn func(i int)) {
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			fn(i)
+		}()
+	}
+	close(start)
+	wg.Wait()
+}
+
+// collect 汇总多个实例的运行计数
+func collect(n int, caches []*cache.LayeredCache) result {
+	r := result{requests: n}
+	for _, c := range caches {
+		stats := c.Stats()
+		r.loads += stats.Loads
+		r.shared += stats.SingleflightShared
+		r.rejected += stats.LoadRejects
+		r.lockWaits += stats.LockWaits
+	}
+	return r
+}
+
+var scenarios = []scenario{
+	{
+		name: "无缓存，直接查询数据库",
+		run: func(ctx context.Context, _ *env, n int) (result, error) {
+			var loads atomic.Int64
+			loader := slowLoader(true)
+			concurrently(n, func(int) {
+				loads.Add(1)
+				_, _ = loader(ctx, "hot")
+			})
+			return result{requests: n, loads: loads.Load()}, nil
+		},
+	},
+	{
+		name: "单实例 singleflight",
+		run: func(ctx context.Context, e *env, n int) (result, error) {
+			caches, err := e.newCaches(1)
+			if err != nil {
+				return result{}, err
+			}
+			loader := cache.WithLoader(slowLoader(true))
+			concurrently(n, func(int) {
+				var value string
+				_ = caches[0].Get(ctx, "hot", &value, loader)
+			})
+			return collect(n, caches), nil
+		},
+	},
+	{
+		name: "4 个实例各自 singleflight",
+		run: func(ctx context.Context, e *env, n int) (result, error) {
+			caches, err := e.newCaches(4)
+			if err != nil {
+				return result{}, err
+			}
+			loader := cache.WithLoader(slowLoader(true))
+			concurrently(n, func(i int) {
+				var value string
+				_ = caches[i%len(caches)].Get(ctx, "hot", &value, loader)
+			})
+			return collect(n, caches), nil
+		},
+	},
+	{
+		name: "4 个实例，分布式 singleflight",
+		run: func(ctx context.Context, e *env, n int) (result, error) {
+			caches, err := e.newCaches(4)
+			if err != nil {
+				return result{}, err
+			}
+			// 跨实例的锁保证只有一个实例调用 loader，其他实例等待其写入 Remote 的结果
+			opts := []cache.GetOption{cache.WithLoader(slowLoader(true)), cache.WithDistributedSingleflight(time.Second, time.Second)}
+			concurrently(n, func(i int) {
+				var value string
+				_ = caches[i%len(caches)].Get(ctx, "hot", &value, opts...)
+			})
+			return collect(n, caches), nil
+		},
+	},
+	{
+		name: "不存在的键，分 10 批请求，不缓存缺失值",
+		run: func(ctx context.Context, e *env, n int) (result, error) {
+			return missingKeyWaves(ctx, e, n, false)
+		},
+	},
+	{
+		name: "不存在的键，分 10 批请求，缓存缺失值",
+		run: func(ctx context.Context, e *env, n int) (result, error) {
+			return missingKeyWaves(ctx, e, n, true)
+		},
+	},
+	{
+		name: "各不相同的键，按前缀限流 100/s",
+		run: func(ctx context.Context, e *env, n int) (result, error) {
+			caches, err := e.newCaches(1, cache.WithConfigLoaderPrefixes("item:"), cache.WithConfigLoaderRateLimit(100, 100))
+			if err != nil {
+				return result{}, err
+			}
+			loader := cache.WithLoader(slowLoader(true))
+			concurrently(n, func(i int) {
+				var value string
+				_ = caches[0].Get(ctx, fmt.Sprintf("item:%d", i), &value, loader)
+			})
+			return collect(n, caches), nil
+		},
+	},
+}
+
+// missingKeyWaves 分 10 批请求同一个不存在的键，每批之间等待上一批完成
+func missingKeyWaves(ctx context.Context, e *env, n int, cacheNotFound bool) (result, error) {
+	caches, err := e.newCaches(1)
+	if err != nil {
+		return result{}, err
+	}
+	opts := []cache.GetOption{cache.WithLoader(slowLoader(false)), cache.WithCacheNotFound(cacheNotFound, time.Minute)}
+	for range 10 {
+		concurrently(n/10, func(int) {
+			var value string
+			_ = caches[0].Get(ctx, "missing", &value, opts...)
+		})
+	}
+	return collect(n, caches), nil
+}
+
+// run 依次运行所有场景
+func run(ctx context.Context, n int) ([]result, error) {
+	redisServer, err := miniredis.Run()
+	if err != nil {
+		return nil, err
+	}
+	defer redisServer.Close()
+
+	e := &env{redis: redisServer}
+	results := make([]result, 0, len(scenarios))
+	for _, s := range scenarios {
+		start := time.Now()
+		r, err := s.run(ctx, e, n)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", s.name, err)
+		}
+		r.name, r.elapsed = s.name, time.Since(start)
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+func main() {
+	n := flag.Int("n", 10000, "每个场景的并发请求数")
+	flag.Parse()
+
+	results, err := run(context.Background(), *n)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "场景\t请求数\tloader 调用\t共享结果\t限流拒绝\t等待锁\t耗时")
+	for _, r := range results {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%s\n", r.name, r.requests, r.loads, r.shared, r.rejected, r.lockWaits, r.elapsed.Round(time.Millisecond))
+	}
+	_ = w.Flush()
+}
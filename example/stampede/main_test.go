@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStampede(t *testing.T) {
+	const n = 1000
+	// 进程内的 singleflight 只合并同时进行的加载：首次加载写入缓存前未命中、进入 singleflight 时加载已结束的请求
+	// 会再加载一次，竞态检测或机器繁忙时更常见，因此只断言上界
+	const slack = n / 100
+
+	results, err := run(context.Background(), n)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	byName := make(map[string]result, len(results))
+	for _, r := range results {
+		byName[r.name] = r
+	}
+
+	if got := byName["无缓存，直接查询数据库"].loads; got != n {
+		t.Errorf("无缓存 loads = %d, want %d", got, n)
+	}
+	if got := byName["单实例 singleflight"].loads; got < 1 || got > 1+slack {
+		t.Errorf("singleflight loads = %d, want 1~%d", got, 1+slack)
+	}
+	if got := byName["4 个实例各自 singleflight"].loads; got < 1 || got > 4+slack {
+		t.Errorf("多实例 loads = %d, want 1~%d", got, 4+slack)
+	}
+	// 获取到锁后重新读取 Remote，晚到的请求同样复用已写入的结果
+	if r := byName["4 个实例，分布式 singleflight"]; r.loads != 1 || r.lockWaits == 0 {
+		t.Errorf("分布式 singleflight loads = %d, lockWaits = %d, want 1 and > 0", r.loads, r.lockWaits)
+	}
+	if got := byName["不存在的键，分 10 批请求，不缓存缺失值"].loads; got < 10 || got > 10+slack {
+		t.Errorf("不缓存缺失值 loads = %d, want 10~%d", got, 10+slack)
+	}
+	if got := byName["不存在的键，分 10 批请求，缓存缺失值"].loads; got < 1 || got > 1+slack {
+		t.Errorf("缓存缺失值 loads = %d, want 1~%d", got, 1+slack)
+	}
+	// 令牌桶在场景运行期间持续补充，上界按实际耗时计算
+	r := byName["各不相同的键，按前缀限流 100/s"]
+	if limit := 100 + int64(100*r.elapsed.Seconds()) + 1; r.loads < 100 || r.loads > limit {
+		t.Errorf("限流 loads = %d, want 100~%d", r.loads, limit)
+	}
+}
@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+)
+
+// Existence 键在缓存中的存在状态
+type Existence int
+
+const (
+	// ExistenceUnknown 缓存中没有该键的任何信息
+	ExistenceUnknown Existence = iota
+	// ExistencePresent 缓存中有该键的值
+	ExistencePresent
+	// ExistenceKnownAbsent 缓存中记录了该键不存在（缺失值标记）
+	ExistenceKnownAbsent
+)
+
+func (e Existence) String() string {
+	switch e {
+	case ExistencePresent:
+		return "present"
+	case ExistenceKnownAbsent:
+		return "known_absent"
+	default:
+		return "unknown"
+	}
+}
+
+// Exists 检查键在缓存中的存在状态，不调用 loader，也不写回内存缓存
+// 已被 Invalidate 标记为失效的值以及删除保护窗口内的键返回 ExistenceUnknown
+func (c *LayeredCache) Exists(ctx context.Context, key string) (Existence, error) {
+	scope, ctx := c.takeKeyContext(ctx)
+	key = scope.key(key)
+
+	if c.isShielded(key) {
+		return ExistenceUnknown, nil
+	}
+
+	if c.memory != nil {
+		if data, exists := c.memory.Get(key); exists {
+			if existence := c.existenceOf(data); existence != ExistenceUnknown {
+				return existence, nil
+			}
+		} else if _, exists = c.memory.Get(notFoundKey(key)); exists {
+			return ExistenceKnownAbsent, nil
+		}
+	}
+
+	if c.remote != nil {
+		remoteData, err := c.remote.MGet(ctx, []string{key, notFoundKey(key)})
+		if err = c.stats.remoteError(err); err != nil && !IsNotFound(err) {
+			return ExistenceUnknown, err
+		}
+		if data, exists := remoteData[key]; exists {
+			if existence := c.existenceOf(data); existence != ExistenceUnknown {
+				return existence, nil
+			}
+		} else if _, exists = remoteData[notFoundKey(key)]; exists {
+			return ExistenceKnownAbsent, nil
+		}
+	}
+
+	return ExistenceUnknown, nil
+}
+
+// existenceOf 返回缓存数据对应的存在状态
+func (c *LayeredCache) existenceOf(data []byte) Existence {
+	switch {
+	case isNotFoundPlaceholder(data):
+		return ExistenceKnownAbsent
+	case c.isStale(data, 0):
+		return ExistenceUnknown
+	default:
+		return ExistencePresent
+	}
+}
+
+// TTL 返回键的剩余过期时间，不读取值本身，也不调用 loader
+// memoryKnown 只表示内存缓存中是否有该键的值，内存中的剩余过期时间通过 MemoryTTL 读取；
+// remoteTTL 为 Remote 中的剩余过期时间，没有过期时间时为 -1，Remote 中不存在或未配置 Remote 时为 0。
+// 两层都没有该键的值（包括只有缺失值标记）或键处于删除保护窗口内时返回 ErrNotFound
+func (c *LayeredCache) TTL(ctx context.Context, key string) (memoryKnown bool, remoteTTL time.Duration, err error) {
+	scope, ctx := c.takeKeyContext(ctx)
+	key = scope.key(key)
+
+	if c.isShielded(key) {
+		return false, 0, errors.ErrNotFound
+	}
+
+	if c.memory != nil {
+		data, exists := c.memory.Get(key)
+		memoryKnown = exists && !isNotFoundPlaceholder(data)
+	}
+
+	if c.remote != nil {
+		ttl, err := c.remote.TTL(ctx, key)
+		if err = c.stats.remoteError(err); err != nil && !IsNotFound(err) {
+			return memoryKnown, 0, err
+		}
+		// Redis 约定 -2 表示键不存在，-1 表示没有过期时间
+		if err == nil && ttl != -2 {
+			if ttl < 0 {
+				ttl = -1
+			}
+			remoteTTL = ttl
+		}
+	}
+
+	if !memoryKnown && remoteTTL == 0 {
+		return false, 0, errors.ErrNotFound
+	}
+	return memoryKnown, remoteTTL, nil
+}
+
+// MemoryTTL 返回键在内存缓存中的剩余过期时间，没有过期时间时为 -1，不读取 Remote，也不调用 loader
+// 内存中没有该键的值（包括只有缺失值标记）或键处于删除保护窗口内时返回 ErrNotFound；需要内存适配器实现 storage.TTLGetter
+func (c *LayeredCache) MemoryTTL(ctx context.Context, key string) (time.Duration, error) {
+	scope, _ := c.takeKeyContext(ctx)
+	key = scope.key(key)
+
+	getter, ok := c.memory.(storage.TTLGetter)
+	if !ok {
+		return 0, errors.ErrOperationNotSupported
+	}
+	if c.isShielded(key) {
+		return 0, errors.ErrNotFound
+	}
+	data, ttl, exists := getter.GetWithTTL(key)
+	if !exists || isNotFoundPlaceholder(data) {
+		return 0, errors.ErrNotFound
+	}
+	return ttl, nil
+}
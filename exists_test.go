@@ -0,0 +1,148 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_Exists(t *testing.T) {
+	ctx := context.Background()
+	notFoundLoader := func(ctx context.Context, key string) (any, error) {
+		return nil, nil
+	}
+
+	t.Run("三种状态", func(t *testing.T) {
+		c := createTestCache(t)
+		assert.NoError(t, c.Set(ctx, "present", "v"))
+		var result string
+		assert.ErrorIs(t, c.Get(ctx, "absent", &result, WithLoader(notFoundLoader), WithCacheNotFound(true, time.Minute)), ErrNotFound)
+
+		existence, err := c.Exists(ctx, "present")
+		assert.NoError(t, err)
+		assert.Equal(t, ExistencePresent, existence)
+
+		existence, err = c.Exists(ctx, "absent")
+		assert.NoError(t, err)
+		assert.Equal(t, ExistenceKnownAbsent, existence)
+
+		existence, err = c.Exists(ctx, "never")
+		assert.NoError(t, err)
+		assert.Equal(t, ExistenceUnknown, existence)
+	})
+
+	t.Run("只在Remote中", func(t *testing.T) {
+		c := createTestCache(t).(*LayeredCache)
+		assert.NoError(t, c.Set(ctx, "present", "v"))
+		var result string
+		assert.ErrorIs(t, c.Get(ctx, "absent", &result, WithLoader(notFoundLoader), WithCacheNotFound(true, time.Minute)), ErrNotFound)
+		c.memory.Delete("present")
+		c.memory.Delete(notFoundKey("absent"))
+
+		existence, err := c.Exists(ctx, "present")
+		assert.NoError(t, err)
+		assert.Equal(t, ExistencePresent, existence)
+
+		existence, err = c.Exists(ctx, "absent")
+		assert.NoError(t, err)
+		assert.Equal(t, ExistenceKnownAbsent, existence)
+
+		// 不写回内存缓存
+		_, exists := c.memory.Get("present")
+		assert.False(t, exists)
+	})
+
+	t.Run("已失效", func(t *testing.T) {
+		c := createEnvelopeCache(t)
+		assert.NoError(t, c.Set(ctx, "key", "v"))
+		assert.NoError(t, c.Invalidate(ctx, "key"))
+
+		existence, err := c.Exists(ctx, "key")
+		assert.NoError(t, err)
+		assert.Equal(t, ExistenceUnknown, existence)
+	})
+
+	t.Run("删除保护窗口内", func(t *testing.T) {
+		c, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigRemote(createRemoteAdapter(t)),
+			WithConfigDeleteShield(time.Minute),
+		)
+		assert.NoError(t, err)
+		assert.NoError(t, c.Set(ctx, "key", "v"))
+		assert.NoError(t, c.Delete(ctx, "key"))
+
+		existence, err := c.Exists(ctx, "key")
+		assert.NoError(t, err)
+		assert.Equal(t, ExistenceUnknown, existence)
+	})
+
+	t.Run("String", func(t *testing.T) {
+		assert.Equal(t, "present", ExistencePresent.String())
+		assert.Equal(t, "known_absent", ExistenceKnownAbsent.String())
+		assert.Equal(t, "unknown", ExistenceUnknown.String())
+	})
+}
+
+func TestLayeredCache_TTL(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("两层剩余过期时间", func(t *testing.T) {
+		c := createTestCache(t)
+		assert.NoError(t, c.Set(ctx, "key", "v", WithTTL(time.Minute, time.Hour)))
+
+		memoryKnown, remoteTTL, err := c.TTL(ctx, "key")
+		assert.NoError(t, err)
+		assert.True(t, memoryKnown)
+		assert.Equal(t, time.Hour, remoteTTL)
+	})
+
+	t.Run("只在 Remote 中", func(t *testing.T) {
+		c := createTestCache(t).(*LayeredCache)
+		assert.NoError(t, c.remote.Set(ctx, "key", []byte("v"), 0))
+
+		memoryKnown, remoteTTL, err := c.TTL(ctx, "key")
+		assert.NoError(t, err)
+		assert.False(t, memoryKnown)
+		assert.Equal(t, time.Duration(-1), remoteTTL)
+	})
+
+	t.Run("不存在或只有缺失值标记", func(t *testing.T) {
+		c := createTestCache(t)
+		_, _, err := c.TTL(ctx, "missing")
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		var result string
+		_ = c.Get(ctx, "absent", &result, WithCacheNotFound(true, time.Minute), WithLoader(func(ctx context.Context, key string) (any, error) {
+			return nil, nil
+		}))
+		_, _, err = c.TTL(ctx, "absent")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestLayeredCache_MemoryTTL(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("内存剩余过期时间", func(t *testing.T) {
+		c := createTestCache(t).(*LayeredCache)
+		assert.NoError(t, c.Set(ctx, "key", "v", WithTTL(time.Minute, time.Hour)))
+
+		ttl, err := c.MemoryTTL(ctx, "key")
+		assert.NoError(t, err)
+		assert.InDelta(t, time.Minute, ttl, float64(time.Second))
+
+		_, err = c.MemoryTTL(ctx, "missing")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("适配器不支持", func(t *testing.T) {
+		c, err := NewCache(WithConfigRemote(createRemoteAdapter(t)))
+		assert.NoError(t, err)
+		_, err = c.(*LayeredCache).MemoryTTL(ctx, "key")
+		assert.ErrorIs(t, err, errors.ErrOperationNotSupported)
+	})
+}
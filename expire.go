@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+)
+
+// Expire 延长单个键的过期时间，不重新写入值，适用于滑动过期的会话缓存；键不存在时忽略
+// 内存缓存以原有数据重新写入，Remote 使用 EXPIRE，TTL 的要求与 MExpire 相同
+func (c *LayeredCache) Expire(ctx context.Context, key string, memoryTTL, remoteTTL time.Duration) error {
+	return c.MExpire(ctx, []string{key}, memoryTTL, remoteTTL)
+}
+
+// MExpire 批量延长键的过期时间，适用于滑动过期的场景
+// 内存缓存逐个重新写入，Remote 通过 pipeline 一次往返完成；不存在的键忽略
+// 未配置的缓存层对应的 TTL 不生效，已配置的缓存层 TTL 必须大于 0
+func (c *LayeredCache) MExpire(ctx context.Context, keys []string, memoryTTL, remoteTTL time.Duration) error {
+	scope, ctx := c.takeKeyContext(ctx)
+	keys = scopeKeys(scope, keys)
+
+	if c.memory != nil {
+		if err := validMemoryTTL(memoryTTL, errors.TTLSourcePerCall); err != nil {
+			return c.misuse(err)
+		}
+	}
+
+	var expirer storage.Expirer
+	if c.remote != nil {
+		if err := validRemoteTTL(remoteTTL, errors.TTLSourcePerCall); err != nil {
+			return c.misuse(err)
+		}
+
+		var ok bool
+		if expirer, ok = c.remote.(storage.Expirer); !ok {
+			return errors.ErrOperationNotSupported
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if c.memory != nil {
+		for key, data := range c.memory.MGet(keys) {
+			c.memorySet(key, data, memoryTTL)
+		}
+	}
+
+	if expirer != nil {
+		return expirer.MExpire(ctx, keys, remoteTTL)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// ttlRecordingMemory 记录内存写入时的 TTL
+type ttlRecordingMemory struct {
+	storage.Memory
+	ttls map[string]time.Duration
+}
+
+func (m *ttlRecordingMemory) Set(key string, value []byte, expire time.Duration) int32 {
+	m.ttls[key] = expire
+	return m.Memory.Set(key, value, expire)
+}
+
+func (m *ttlRecordingMemory) MSet(values map[string][]byte, expire time.Duration) int32 {
+	for key := range values {
+		m.ttls[key] = expire
+	}
+	return m.Memory.MSet(values, expire)
+}
+
+func TestLayeredCache_MExpire(t *testing.T) {
+	ctx := context.Background()
+	memory := &ttlRecordingMemory{Memory: createOtterAdapter(t), ttls: make(map[string]time.Duration)}
+	cache, err := NewCache(
+		WithConfigMemory(memory),
+		WithConfigRemote(createRemoteAdapter(t)),
+	)
+	assert.NoError(t, err)
+	c := cache.(*LayeredCache)
+
+	assert.NoError(t, c.Set(ctx, "session:1", "a", WithTTL(time.Minute, time.Minute)))
+	assert.NoError(t, c.Set(ctx, "session:2", "b", WithTTL(time.Minute, time.Minute)))
+
+	t.Run("批量延长过期时间", func(t *testing.T) {
+		err := c.MExpire(ctx, []string{"session:1", "session:2", "session:404"}, time.Hour, 2*time.Hour)
+		assert.NoError(t, err)
+
+		assert.Equal(t, time.Hour, memory.ttls["session:1"])
+		assert.Equal(t, time.Hour, memory.ttls["session:2"])
+		assert.NotContains(t, memory.ttls, "session:404")
+
+		for _, key := range []string{"session:1", "session:2"} {
+			ttl, err := c.remote.TTL(ctx, key)
+			assert.NoError(t, err)
+			assert.Equal(t, 2*time.Hour, ttl)
+		}
+
+		_, err = c.remote.Get(ctx, "session:404")
+		assert.ErrorIs(t, err, errors.ErrNotFound)
+
+		var value string
+		assert.NoError(t, c.Get(ctx, "session:1", &value))
+		assert.Equal(t, "a", value)
+	})
+
+	t.Run("延长单个键的过期时间", func(t *testing.T) {
+		assert.NoError(t, c.Expire(ctx, "session:1", 3*time.Hour, 4*time.Hour))
+		assert.Equal(t, 3*time.Hour, memory.ttls["session:1"])
+
+		ttl, err := c.remote.TTL(ctx, "session:1")
+		assert.NoError(t, err)
+		assert.Equal(t, 4*time.Hour, ttl)
+		assert.NoError(t, c.Expire(ctx, "session:404", time.Hour, time.Hour))
+	})
+
+	t.Run("无效的TTL", func(t *testing.T) {
+		assert.ErrorIs(t, c.MExpire(ctx, []string{"session:1"}, 0, time.Hour), errors.ErrInvalidMemoryExpireTime)
+		assert.ErrorIs(t, c.MExpire(ctx, []string{"session:1"}, time.Hour, 0), errors.ErrInvalidRedisExpireTime)
+	})
+
+	t.Run("未配置的缓存层忽略TTL", func(t *testing.T) {
+		assert.NoError(t, createMemoryOnlyCache(t).MExpire(ctx, []string{"session:1"}, time.Hour, 0))
+	})
+
+	t.Run("Remote不支持", func(t *testing.T) {
+		cache, err := NewCache(WithConfigRemote(&countingRemote{Remote: createRemoteAdapter(t)}))
+		assert.NoError(t, err)
+		assert.ErrorIs(t, cache.MExpire(ctx, []string{"session:1"}, 0, time.Hour), errors.ErrOperationNotSupported)
+	})
+}
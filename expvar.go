@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"expvar"
+	"sync"
+
+	"github.com/biu7/layered-cache/errors"
+)
+
+var (
+	// expvarMu 保护 expvarSlots，保证同一名称只向 expvar 发布一次
+	expvarMu sync.Mutex
+
+	// expvarSlots 已发布的 expvar 名称及其计数来源
+	// expvar 不支持删除已发布的变量，Close 时清空来源，之后该名称可被新的缓存复用
+	expvarSlots = make(map[string]*expvarSlot)
+)
+
+// expvarSlot 发布到 expvar 的计数来源，为空时输出 null
+type expvarSlot struct {
+	mu    sync.Mutex
+	stats *stats
+}
+
+func (s *expvarSlot) value() any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stats == nil {
+		return nil
+	}
+	return s.stats.snapshot()
+}
+
+// expvarNameTaken 判断 name 是否已被其他变量或未关闭的缓存占用
+func expvarNameTaken(name string) bool {
+	expvarMu.Lock()
+	slot, ok := expvarSlots[name]
+	expvarMu.Unlock()
+	if !ok {
+		return expvar.Get(name) != nil
+	}
+
+	slot.mu.Lock()
+	defer slot.mu.Unlock()
+	return slot.stats != nil
+}
+
+// publishExpvar 将运行计数发布到 expvar，可通过 /debug/vars 查看
+// 检查和占用名称在 slot.mu 内完成，并发创建同名缓存时只有一个成功，其余返回 ErrExpvarNameExists
+func (c *LayeredCache) publishExpvar(name string) error {
+	expvarMu.Lock()
+	slot, ok := expvarSlots[name]
+	if !ok {
+		if expvar.Get(name) != nil {
+			expvarMu.Unlock()
+			return errors.ErrExpvarNameExists
+		}
+		slot = &expvarSlot{}
+		expvarSlots[name] = slot
+		expvar.Publish(name, expvar.Func(slot.value))
+	}
+	expvarMu.Unlock()
+
+	slot.mu.Lock()
+	defer slot.mu.Unlock()
+	if slot.stats != nil {
+		return errors.ErrExpvarNameExists
+	}
+	slot.stats = &c.stats
+	c.expvar = slot
+	return nil
+}
+
+// unpublishExpvar 停止发布运行计数
+func (c *LayeredCache) unpublishExpvar() {
+	if c.expvar == nil {
+		return
+	}
+	c.expvar.mu.Lock()
+	if c.expvar.stats == &c.stats {
+		c.expvar.stats = nil
+	}
+	c.expvar.mu.Unlock()
+}
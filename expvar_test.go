@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayeredCache_Expvar(t *testing.T) {
+	ctx := context.Background()
+	cache, err := NewCache(
+		WithConfigMemory(createMemoryAdapter(t)),
+		WithConfigExpvar("layeredcache_test"),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cache.Close(ctx) })
+
+	assert.NoError(t, cache.Set(ctx, "key", "value"))
+	var result string
+	assert.NoError(t, cache.Get(ctx, "key", &result))
+
+	t.Run("通过expvar读取计数", func(t *testing.T) {
+		v := expvar.Get("layeredcache_test")
+		assert.NotNil(t, v)
+
+		var counters map[string]int64
+		assert.NoError(t, json.Unmarshal([]byte(v.String()), &counters))
+		assert.Equal(t, int64(1), counters["sets"])
+		assert.Equal(t, int64(1), counters["memory_hits"])
+	})
+
+	t.Run("名称重复", func(t *testing.T) {
+		_, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigExpvar("layeredcache_test"),
+		)
+		assert.ErrorIs(t, err, errors.ErrExpvarNameExists)
+	})
+
+	t.Run("关闭后名称可复用", func(t *testing.T) {
+		first, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigExpvar("layeredcache_test_close"),
+		)
+		require.NoError(t, err)
+		assert.NoError(t, first.Close(ctx))
+		assert.Equal(t, "null", expvar.Get("layeredcache_test_close").String())
+
+		second, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigExpvar("layeredcache_test_close"),
+		)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = second.Close(ctx) })
+		assert.NoError(t, second.Set(ctx, "key", "value"))
+
+		var counters map[string]int64
+		assert.NoError(t, json.Unmarshal([]byte(expvar.Get("layeredcache_test_close").String()), &counters))
+		assert.Equal(t, int64(1), counters["sets"])
+
+		assert.NoError(t, first.Close(ctx))
+		assert.NotEqual(t, "null", expvar.Get("layeredcache_test_close").String())
+	})
+
+	t.Run("并发创建同名缓存只有一个成功", func(t *testing.T) {
+		var wg sync.WaitGroup
+		var created atomic.Int32
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				c, err := NewCache(
+					WithConfigMemory(createMemoryAdapter(t)),
+					WithConfigExpvar("layeredcache_test_concurrent"),
+				)
+				if err != nil {
+					assert.ErrorIs(t, err, errors.ErrExpvarNameExists)
+					return
+				}
+				created.Add(1)
+				t.Cleanup(func() { _ = c.Close(ctx) })
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1), created.Load())
+	})
+}
@@ -0,0 +1,17 @@
+package cache
+
+import "context"
+
+// Fetch 获取 key 对应的值并以 T 类型返回，未命中时调用 loader 加载并写入缓存
+// 适用于不需要构建 TypedCache 的一次性读取，loader 为 nil 时等同于 Get
+func Fetch[T any](ctx context.Context, c Cache, key string, loader func(ctx context.Context, key string) (T, error), opts ...GetOption) (T, error) {
+	if loader != nil {
+		opts = append(opts, WithLoader(func(ctx context.Context, key string) (any, error) {
+			return loader(ctx, key)
+		}))
+	}
+
+	var result T
+	err := c.Get(ctx, key, &result, opts...)
+	return result, err
+}
@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetch(t *testing.T) {
+	ctx := context.Background()
+	type user struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+
+	t.Run("未命中时加载并缓存", func(t *testing.T) {
+		c := createTestCache(t)
+		calls := 0
+		loader := func(ctx context.Context, key string) (user, error) {
+			calls++
+			return user{ID: 1, Name: key}, nil
+		}
+
+		for i := 0; i < 2; i++ {
+			u, err := Fetch(ctx, c, "alice", loader)
+			assert.NoError(t, err)
+			assert.Equal(t, user{ID: 1, Name: "alice"}, u)
+		}
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("没有loader", func(t *testing.T) {
+		c := createTestCache(t)
+		_, err := Fetch[user](ctx, c, "missing", nil)
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		assert.NoError(t, c.Set(ctx, "bob", user{ID: 2, Name: "bob"}))
+		u, err := Fetch[user](ctx, c, "bob", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), u.ID)
+	})
+
+	t.Run("loader返回错误", func(t *testing.T) {
+		loadErr := errors.New("db down")
+		_, err := Fetch(ctx, createTestCache(t), "key", func(ctx context.Context, key string) (string, error) {
+			return "", loadErr
+		})
+		assert.ErrorIs(t, err, loadErr)
+	})
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+	"slices"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+)
+
+// GetSet 将键的值替换为 value，并将替换前 Remote 中的值解码到 target（例如交换式的计数器和配置开关）
+// 替换在 Remote 中原子完成，成功后回填内存缓存；替换前不存在时仍写入新值并返回 ErrNotFound。需要 Remote 实现 storage.Swapper
+func (c *LayeredCache) GetSet(ctx context.Context, key string, value any, target any, opts ...SetOption) error {
+	scope, ctx := c.takeKeyContext(ctx)
+	key, opts = scope.key(key), scopeSetOptions(scope, slices.Values([]string{key}), opts)
+
+	config := newSetOptions()
+	if err := applySetOptions(config, opts...); err != nil {
+		return c.misuse(err)
+	}
+	c.checkLayerTTL(config.memoryTTL, config.remoteTTL)
+	if err := c.checkTags(config); err != nil {
+		return c.misuse(err)
+	}
+
+	swapper, ok := c.remote.(storage.Swapper)
+	if !ok {
+		return errors.ErrOperationNotSupported
+	}
+
+	data, err := c.encode(value, config.serializer)
+	if err != nil {
+		return err
+	}
+	if err = c.checkEntrySize(key, data); err != nil {
+		return err
+	}
+	if err = c.checkValueSize(key, data); err != nil {
+		return err
+	}
+	oversized := len(c.oversized(map[string][]byte{key: data})) > 0
+
+	var old []byte
+	memoryTTL, remoteTTL := c.jitterTTL(c.calculateSetValueTTL(config, key, value))
+	err = c.remoteCall(ctx, func(ctx context.Context) (err error) {
+		old, err = swapper.GetSet(ctx, key, data, remoteTTL)
+		return err
+	})
+	if err != nil && !errors.Is(err, errors.ErrNotFound) {
+		return err
+	}
+	// 旧版本直接写在原始键上的缺失值占位符按不存在处理
+	missing := err != nil || isNotFoundPlaceholder(old)
+
+	c.unshield(key)
+	c.stats.sets.Add(1)
+	if c.memory != nil && oversized {
+		c.memory.Delete(key)
+	} else if c.memory != nil {
+		c.memorySet(key, data, memoryTTL)
+	}
+	if err = c.clearNotFound(ctx, []string{key}, true); err != nil {
+		return err
+	}
+
+	c.broadcastInvalidation(ctx, []string{key})
+	if err = c.tagKeys(ctx, config, []string{key}, remoteTTL); err != nil {
+		return err
+	}
+	if err = c.writeThrough(ctx, map[string][]byte{key: data}); err != nil {
+		return err
+	}
+
+	if missing {
+		return errors.ErrNotFound
+	}
+	return c.decode(old, target, config.serializer)
+}
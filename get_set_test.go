@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_GetSet(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("返回替换前的值", func(t *testing.T) {
+		c := createTestCache(t).(*LayeredCache)
+
+		var old string
+		assert.ErrorIs(t, c.GetSet(ctx, "flag", "on", &old), ErrNotFound)
+
+		assert.NoError(t, c.GetSet(ctx, "flag", "off", &old))
+		assert.Equal(t, "on", old)
+
+		_, exists := c.memory.Get("flag")
+		assert.True(t, exists, "替换后回填内存")
+
+		var value string
+		assert.NoError(t, c.Get(ctx, "flag", &value))
+		assert.Equal(t, "off", value)
+	})
+
+	t.Run("以 Remote 为准", func(t *testing.T) {
+		c := createTestCache(t).(*LayeredCache)
+		assert.NoError(t, c.Set(ctx, "flag", "local", WithOnlyMemory()))
+
+		var old string
+		assert.ErrorIs(t, c.GetSet(ctx, "flag", "remote", &old), ErrNotFound)
+
+		var value string
+		assert.NoError(t, c.Get(ctx, "flag", &value))
+		assert.Equal(t, "remote", value)
+	})
+
+	t.Run("替换缺失值占位符返回ErrNotFound", func(t *testing.T) {
+		c := createTestCache(t).(*LayeredCache)
+		assert.NoError(t, c.remote.Set(ctx, "flag", legacyNotFoundPlaceholder, time.Minute))
+
+		var old string
+		assert.ErrorIs(t, c.GetSet(ctx, "flag", "on", &old), ErrNotFound)
+		assert.Empty(t, old)
+
+		var value string
+		assert.NoError(t, c.Get(ctx, "flag", &value))
+		assert.Equal(t, "on", value)
+	})
+
+	t.Run("Remote不支持", func(t *testing.T) {
+		var old string
+		err := createMemoryOnlyCache(t).GetSet(ctx, "flag", "on", &old)
+		assert.ErrorIs(t, err, errors.ErrOperationNotSupported)
+	})
+}
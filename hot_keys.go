@@ -0,0 +1,175 @@
+package cache
+
+import (
+	"cmp"
+	"hash/maphash"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// hotKeyDepth count-min sketch 的行数
+	hotKeyDepth = 4
+	// hotKeyWidth count-min sketch 每行的计数器数量
+	hotKeyWidth = 4096
+	// hotKeyCandidates 保留的候选热点键数量，TopKeys 最多返回这么多键
+	hotKeyCandidates = 128
+)
+
+// HotKey 热点键及其估算的访问次数
+type HotKey struct {
+	Key   string
+	Count int64
+}
+
+// hotKeyTracker 使用 count-min sketch 估算每个键的访问次数，并保留估算次数最多的一组候选键
+// 每个窗口结束时所有计数减半，估算次数近似最近几个窗口的访问频率
+type hotKeyTracker struct {
+	window    time.Duration
+	threshold int64
+	onHot     func(key string, count int64)
+	now       func() time.Time
+
+	seed maphash.Seed
+
+	mu       sync.Mutex
+	sketch   [hotKeyDepth][hotKeyWidth]int64
+	top      map[string]int64
+	minCount int64
+	fired    map[string]struct{}
+	rotateAt time.Time
+}
+
+func newHotKeyTracker(window time.Duration, threshold int64, onHot func(key string, count int64)) *hotKeyTracker {
+	t := &hotKeyTracker{
+		window:    window,
+		threshold: threshold,
+		onHot:     onHot,
+		now:       time.Now,
+		seed:      maphash.MakeSeed(),
+		top:       make(map[string]int64),
+		fired:     make(map[string]struct{}),
+	}
+	t.rotateAt = t.now().Add(window)
+	return t
+}
+
+// record 记录一次访问，估算次数首次达到阈值时调用 onHot（每个窗口每个键最多一次）
+func (t *hotKeyTracker) record(key string) {
+	t.mu.Lock()
+	if now := t.now(); !now.Before(t.rotateAt) {
+		t.rotate(now)
+	}
+
+	h := maphash.String(t.seed, key)
+	h1, h2 := h, h>>32|h<<32
+	count := int64(-1)
+	for i := range t.sketch {
+		slot := &t.sketch[i][(h1+uint64(i)*h2)%hotKeyWidth]
+		*slot++
+		if count < 0 || *slot < count {
+			count = *slot
+		}
+	}
+	t.offer(key, count)
+
+	hot := false
+	if t.onHot != nil && t.threshold > 0 && count >= t.threshold {
+		if _, ok := t.fired[key]; !ok {
+			t.fired[key] = struct{}{}
+			hot = true
+		}
+	}
+	t.mu.Unlock()
+
+	if hot {
+		t.onHot(key, count)
+	}
+}
+
+// offer 更新候选键，候选已满时替换估算次数最少的键
+func (t *hotKeyTracker) offer(key string, count int64) {
+	if _, ok := t.top[key]; ok || len(t.top) < hotKeyCandidates {
+		t.top[key] = count
+		return
+	}
+	if count <= t.minCount {
+		return
+	}
+
+	var minKey string
+	minCount := int64(-1)
+	for k, c := range t.top {
+		if minCount < 0 || c < minCount {
+			minKey, minCount = k, c
+		}
+	}
+	if count <= minCount {
+		t.minCount = minCount
+		return
+	}
+	delete(t.top, minKey)
+	t.top[key] = count
+
+	t.minCount = count
+	for _, c := range t.top {
+		t.minCount = min(t.minCount, c)
+	}
+}
+
+// rotate 开始新的窗口：所有计数减半，重置已触发的回调
+func (t *hotKeyTracker) rotate(now time.Time) {
+	for i := range t.sketch {
+		for j := range t.sketch[i] {
+			t.sketch[i][j] /= 2
+		}
+	}
+	for key, count := range t.top {
+		if count /= 2; count == 0 {
+			delete(t.top, key)
+		} else {
+			t.top[key] = count
+		}
+	}
+	t.minCount /= 2
+	clear(t.fired)
+	t.rotateAt = now.Add(t.window)
+}
+
+// topKeys 返回估算次数最多的 n 个候选键，按次数从多到少排列
+func (t *hotKeyTracker) topKeys(n int) []HotKey {
+	t.mu.Lock()
+	keys := make([]HotKey, 0, len(t.top))
+	for key, count := range t.top {
+		keys = append(keys, HotKey{Key: key, Count: count})
+	}
+	t.mu.Unlock()
+
+	slices.SortFunc(keys, func(a, b HotKey) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Key, b.Key)
+	})
+	return keys[:min(n, len(keys))]
+}
+
+// trackHotKeys 记录读取的键，未开启热点键统计时为空操作
+func (c *LayeredCache) trackHotKeys(keys ...string) {
+	if c.hotKeys == nil {
+		return
+	}
+	for _, key := range keys {
+		c.hotKeys.record(key)
+	}
+}
+
+// TopKeys 返回最近访问最多的 n 个键及其估算的访问次数，按次数从多到少排列，最多返回 128 个
+// 键为实际写入缓存层的键（包含命名空间前缀），估算次数可能偏大；未开启 WithConfigHotKeys 时返回 nil
+func (c *LayeredCache) TopKeys(n int) []HotKey {
+	if c.hotKeys == nil || n <= 0 {
+		return nil
+	}
+	return c.hotKeys.topKeys(n)
+}
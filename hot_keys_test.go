@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHotKeyTracker(t *testing.T) {
+	t.Run("估算次数和排序", func(t *testing.T) {
+		tracker := newHotKeyTracker(time.Minute, 0, nil)
+		for i := range 200 {
+			tracker.record(fmt.Sprintf("cold:%d", i))
+		}
+		for range 50 {
+			tracker.record("hot:1")
+		}
+		for range 20 {
+			tracker.record("hot:2")
+		}
+
+		top := tracker.topKeys(2)
+		assert.Len(t, top, 2)
+		assert.Equal(t, "hot:1", top[0].Key)
+		assert.GreaterOrEqual(t, top[0].Count, int64(50))
+		assert.Equal(t, "hot:2", top[1].Key)
+		assert.LessOrEqual(t, len(tracker.topKeys(1000)), hotKeyCandidates)
+	})
+
+	t.Run("达到阈值时每个窗口回调一次", func(t *testing.T) {
+		now := time.Unix(0, 0)
+		var hot []string
+		tracker := newHotKeyTracker(time.Second, 3, func(key string, count int64) {
+			hot = append(hot, key)
+		})
+		tracker.now = func() time.Time { return now }
+		tracker.rotateAt = now.Add(time.Second)
+
+		for range 5 {
+			tracker.record("k")
+		}
+		assert.Equal(t, []string{"k"}, hot)
+
+		// 新窗口计数减半后重新累计
+		now = now.Add(time.Second)
+		tracker.record("k")
+		assert.Equal(t, []string{"k", "k"}, hot)
+		assert.Equal(t, int64(3), tracker.topKeys(1)[0].Count)
+	})
+}
+
+func TestLayeredCache_TopKeys(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("配置校验", func(t *testing.T) {
+		_, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigHotKeys(0, 0, nil))
+		assert.ErrorIs(t, err, errors.ErrInvalidHotKeys)
+	})
+
+	t.Run("未开启时返回 nil", func(t *testing.T) {
+		assert.Nil(t, createTestCache(t).(*LayeredCache).TopKeys(10))
+	})
+
+	t.Run("统计 Get 和 MGet", func(t *testing.T) {
+		var hot []string
+		cache, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigRemote(createRemoteAdapter(t)),
+			WithConfigHotKeys(time.Minute, 4, func(key string, count int64) {
+				hot = append(hot, key)
+			}),
+		)
+		assert.NoError(t, err)
+		c := cache.(*LayeredCache)
+
+		var value string
+		for range 3 {
+			_ = c.Get(ctx, "user:1", &value)
+		}
+		values := make(map[string]string)
+		assert.NoError(t, c.MGet(ctx, []string{"user:1", "user:2"}, &values))
+
+		top := c.TopKeys(10)
+		assert.Equal(t, []HotKey{{Key: "user:1", Count: 4}, {Key: "user:2", Count: 1}}, top)
+		assert.Equal(t, []string{"user:1"}, hot)
+	})
+}
@@ -0,0 +1,88 @@
+//go:build integration
+
+package cache_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	cache "github.com/biu7/layered-cache"
+	"github.com/biu7/layered-cache/cachetest"
+	"github.com/biu7/layered-cache/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func newIntegrationCache(t *testing.T, opts ...cache.Option) cache.Cache {
+	t.Helper()
+
+	memory, err := storage.NewOtter(1 << 20)
+	assert.NoError(t, err)
+
+	opts = append([]cache.Option{
+		cache.WithConfigMemory(memory),
+		cache.WithConfigRemote(storage.NewRedisWithClient(cachetest.StartRedis(t))),
+	}, opts...)
+	c, err := cache.NewCache(opts...)
+	assert.NoError(t, err)
+	return c
+}
+
+func TestIntegration_SetGetDelete(t *testing.T) {
+	ctx := context.Background()
+	c := newIntegrationCache(t)
+
+	assert.NoError(t, c.Set(ctx, "user:1", map[string]any{"name": "Alice"}))
+
+	var user map[string]any
+	assert.NoError(t, c.Get(ctx, "user:1", &user))
+	assert.Equal(t, "Alice", user["name"])
+
+	assert.NoError(t, c.Delete(ctx, "user:1"))
+	assert.ErrorIs(t, c.Get(ctx, "user:1", &user), cache.ErrNotFound)
+}
+
+func TestIntegration_MGetWithBatchLoader(t *testing.T) {
+	ctx := context.Background()
+	c := newIntegrationCache(t, cache.WithConfigDefaultCacheNotFound(true, time.Minute))
+
+	assert.NoError(t, c.Set(ctx, "k1", "v1"))
+
+	values := make(map[string]string)
+	err := c.MGet(ctx, []string{"k1", "k2", "k3"}, &values, cache.WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+		return map[string]any{"k2": "v2"}, nil
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"k1": "v1", "k2": "v2"}, values)
+
+	var value string
+	assert.ErrorIs(t, c.Get(ctx, "k3", &value), cache.ErrNotFound)
+}
+
+func TestIntegration_RemoteKeysAndMExpire(t *testing.T) {
+	ctx := context.Background()
+	c := newIntegrationCache(t)
+
+	var keys []string
+	for i := 0; i < 50; i++ {
+		key := fmt.Sprintf("session:%d", i)
+		keys = append(keys, key)
+		assert.NoError(t, c.Set(ctx, key, i, cache.WithTTL(time.Minute, time.Minute)))
+	}
+
+	var scanned []string
+	var cursor uint64
+	for {
+		batch, next, err := c.RemoteKeys(ctx, "session:", cursor, 10)
+		assert.NoError(t, err)
+		scanned = append(scanned, batch...)
+		if next == 0 {
+			break
+		}
+		cursor = next
+	}
+	assert.ElementsMatch(t, keys, scanned)
+
+	assert.NoError(t, c.MExpire(ctx, keys, time.Hour, time.Hour))
+}
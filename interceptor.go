@@ -0,0 +1,34 @@
+package cache
+
+import "context"
+
+// Operation 被拦截的缓存调用
+type Operation struct {
+	// Name 操作名称：get、mget、set、mset、delete、mdelete，MultiFetch 为 mget
+	Name string
+
+	// Keys 调用涉及的键，拦截器不应修改
+	Keys []string
+}
+
+// Invoker 执行被拦截的缓存调用
+type Invoker func(ctx context.Context) error
+
+// Interceptor 缓存调用拦截器，调用 next 继续执行，可以替换传给 next 的 ctx；
+// 不调用 next 时直接以返回值作为调用结果
+type Interceptor func(ctx context.Context, op Operation, next Invoker) error
+
+// intercept 依次经过拦截器执行 call，没有拦截器时直接执行
+func (c *LayeredCache) intercept(ctx context.Context, op Operation, call Invoker) error {
+	for i := len(c.interceptors) - 1; i >= 0; i-- {
+		interceptor, next := c.interceptors[i], call
+		call = func(ctx context.Context) error {
+			return interceptor(ctx, op, next)
+		}
+	}
+	// span 位于所有拦截器外层，包含拦截器的耗时
+	if c.tracer != nil {
+		call = c.traceCall(op, call)
+	}
+	return call(ctx)
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_Interceptor(t *testing.T) {
+	ctx := context.Background()
+	newCache := func(t *testing.T, interceptors ...Interceptor) Cache {
+		c, err := NewCache(
+			WithConfigMemory(createOtterAdapter(t)),
+			WithConfigRemote(createRemoteAdapter(t)),
+			WithConfigInterceptor(interceptors...),
+		)
+		assert.NoError(t, err)
+		return c
+	}
+
+	t.Run("按添加顺序由外到内执行", func(t *testing.T) {
+		var calls []string
+		trace := func(name string) Interceptor {
+			return func(ctx context.Context, op Operation, next Invoker) error {
+				calls = append(calls, name+">"+op.Name)
+				err := next(ctx)
+				calls = append(calls, name+"<"+op.Name)
+				return err
+			}
+		}
+		c := newCache(t, trace("outer"), trace("inner"))
+
+		assert.NoError(t, c.Set(ctx, "key", "value"))
+		assert.Equal(t, []string{"outer>set", "inner>set", "inner<set", "outer<set"}, calls)
+	})
+
+	t.Run("覆盖所有读写操作并传递键", func(t *testing.T) {
+		ops := make(map[string][]string)
+		c := newCache(t, func(ctx context.Context, op Operation, next Invoker) error {
+			ops[op.Name] = op.Keys
+			return next(ctx)
+		})
+
+		var result string
+		values := make(map[string]string)
+		assert.NoError(t, c.Set(ctx, "a", "1"))
+		assert.NoError(t, c.MSet(ctx, map[string]any{"b": "2"}))
+		assert.NoError(t, c.Get(ctx, "a", &result))
+		assert.NoError(t, c.MGet(ctx, []string{"a", "b"}, &values))
+		assert.NoError(t, c.Delete(ctx, "a"))
+		assert.NoError(t, c.MDelete(ctx, []string{"b"}))
+
+		assert.Equal(t, map[string][]string{
+			"set":     {"a"},
+			"mset":    {"b"},
+			"get":     {"a"},
+			"mget":    {"a", "b"},
+			"delete":  {"a"},
+			"mdelete": {"b"},
+		}, ops)
+	})
+
+	t.Run("不调用next时直接返回拦截器的结果", func(t *testing.T) {
+		denied := errors.New("denied")
+		c := newCache(t, func(ctx context.Context, op Operation, next Invoker) error {
+			for _, key := range op.Keys {
+				if strings.HasPrefix(key, "admin:") {
+					return denied
+				}
+			}
+			return next(ctx)
+		})
+
+		assert.ErrorIs(t, c.Set(ctx, "admin:1", "value"), denied)
+		var result string
+		assert.ErrorIs(t, c.Get(ctx, "admin:1", &result), denied)
+
+		assert.NoError(t, c.Set(ctx, "user:1", "value"))
+		assert.NoError(t, c.Get(ctx, "user:1", &result))
+		assert.Equal(t, "value", result)
+	})
+
+	t.Run("替换传给下一层的ctx", func(t *testing.T) {
+		type ctxKey struct{}
+		var loaded any
+		c := newCache(t, func(ctx context.Context, op Operation, next Invoker) error {
+			return next(context.WithValue(ctx, ctxKey{}, op.Name))
+		})
+
+		var result string
+		err := c.Get(ctx, "loaded", &result, WithLoader(func(ctx context.Context, key string) (any, error) {
+			loaded = ctx.Value(ctxKey{})
+			return "value", nil
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, "get", loaded)
+	})
+
+	t.Run("nil拦截器", func(t *testing.T) {
+		_, err := NewCache(WithConfigMemory(createOtterAdapter(t)), WithConfigInterceptor(nil))
+		assert.ErrorIs(t, err, errors.ErrInvalidInterceptor)
+	})
+}
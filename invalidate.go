@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+)
+
+// Invalidate 将缓存标记为失效，与 Delete 不同，数据仍然保留直到过期
+// 失效后正常的 Get/MGet 视为未命中并调用 loader，开启 WithServeStale 时加载失败可降级返回失效的数据；
+// 需要开启封装格式（WithConfigEnvelope），未封装的旧数据无法标记，直接删除
+func (c *LayeredCache) Invalidate(ctx context.Context, key string) error {
+	if !c.envelope {
+		return c.misuse(errors.ErrEnvelopeRequired)
+	}
+	scope, ctx := c.takeKeyContext(ctx)
+	key = scope.key(key)
+
+	if err := c.invalidate(ctx, key); err != nil {
+		return err
+	}
+	c.broadcastInvalidation(ctx, []string{key})
+	return nil
+}
+
+// invalidate Invalidate 的实现，key 为已加上前缀的缓存键
+func (c *LayeredCache) invalidate(ctx context.Context, key string) error {
+	if c.memory != nil {
+		if data, exists := c.memory.Get(key); exists {
+			if staleData, ok := markStale(data); ok {
+				c.memorySet(key, staleData, c.defaultMemoryTTL)
+			} else {
+				c.memory.Delete(key)
+			}
+		}
+	}
+
+	if c.remote == nil {
+		return nil
+	}
+
+	data, err := c.remote.Get(ctx, key)
+	if err != nil {
+		if IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	staleData, ok := markStale(data)
+	if !ok {
+		return c.remote.Delete(ctx, key)
+	}
+
+	// 保留剩余的过期时间
+	ttl, err := c.remote.TTL(ctx, key)
+	if err != nil {
+		return err
+	}
+	if ttl <= 0 {
+		ttl = c.defaultRemoteTTL
+	}
+	return c.remote.Set(ctx, key, staleData, ttl)
+}
+
+// markStale 为封装格式的数据设置失效标记，不是封装格式时返回 false
+func markStale(data []byte) ([]byte, bool) {
+	if isNotFoundPlaceholder(data) {
+		return nil, false
+	}
+	if _, ok := decodeEnvelope(data); !ok {
+		return nil, false
+	}
+
+	staleData := make([]byte, len(data))
+	copy(staleData, data)
+	staleData[2] |= envelopeFlagStale
+	return staleData, true
+}
+
+// isStale 判断数据是否已被标记为失效，或写入时间超过了 maxAge
+func (c *LayeredCache) isStale(data []byte, maxAge time.Duration) bool {
+	if !c.envelope {
+		return false
+	}
+	env, ok := decodeEnvelope(data)
+	if !ok {
+		return false
+	}
+	return env.flags&envelopeFlagStale != 0 || (maxAge > 0 && time.Since(env.createdAt) > maxAge)
+}
+
+// serveStaleBatch 允许降级时用失效的数据补充未加载到的键，返回是否进行了降级
+func (c *LayeredCache) serveStaleBatch(result, stale map[string][]byte, missingKeys []string, config *getOptions) bool {
+	if !config.serveStale || len(stale) == 0 {
+		return false
+	}
+
+	served := false
+	for _, key := range missingKeys {
+		if data, ok := stale[key]; ok {
+			if _, loaded := result[key]; !loaded {
+				result[key] = data
+				served = true
+			}
+		}
+	}
+	return served
+}
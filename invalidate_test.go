@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func createEnvelopeCache(t *testing.T) *LayeredCache {
+	t.Helper()
+
+	cache, err := NewCache(
+		WithConfigMemory(createOtterAdapter(t)),
+		WithConfigRemote(createRemoteAdapter(t)),
+		WithConfigEnvelope(true),
+	)
+	assert.NoError(t, err)
+	return cache.(*LayeredCache)
+}
+
+func TestLayeredCache_Invalidate(t *testing.T) {
+	ctx := context.Background()
+	failingLoader := func(ctx context.Context, key string) (any, error) {
+		return nil, fmt.Errorf("database unavailable")
+	}
+
+	t.Run("失效后Get调用loader", func(t *testing.T) {
+		c := createEnvelopeCache(t)
+		assert.NoError(t, c.Set(ctx, "user:1", "old", WithRemoteTTL(time.Hour)))
+		assert.NoError(t, c.Invalidate(ctx, "user:1"))
+
+		var result string
+		assert.ErrorIs(t, c.Get(ctx, "user:1", &result), errors.ErrNotFound)
+
+		err := c.Get(ctx, "user:1", &result, WithLoader(func(ctx context.Context, key string) (any, error) {
+			return "new", nil
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, "new", result)
+
+		assert.NoError(t, c.Get(ctx, "user:1", &result))
+		assert.Equal(t, "new", result)
+	})
+
+	t.Run("保留剩余的过期时间", func(t *testing.T) {
+		c := createEnvelopeCache(t)
+		assert.NoError(t, c.Set(ctx, "user:1", "old", WithRemoteTTL(time.Hour)))
+		assert.NoError(t, c.Invalidate(ctx, "user:1"))
+
+		ttl, err := c.remote.TTL(ctx, "user:1")
+		assert.NoError(t, err)
+		assert.InDelta(t, time.Hour.Seconds(), ttl.Seconds(), 5)
+
+		data, err := c.remote.Get(ctx, "user:1")
+		assert.NoError(t, err)
+		assert.True(t, c.isStale(data, 0))
+	})
+
+	t.Run("加载失败时降级返回失效数据", func(t *testing.T) {
+		c := createEnvelopeCache(t)
+		assert.NoError(t, c.Set(ctx, "user:1", "old"))
+		assert.NoError(t, c.Invalidate(ctx, "user:1"))
+
+		var result string
+		err := c.Get(ctx, "user:1", &result, WithLoader(failingLoader))
+		assert.EqualError(t, err, "database unavailable")
+
+		err = c.Get(ctx, "user:1", &result, WithLoader(failingLoader), WithServeStale(true))
+		assert.NoError(t, err)
+		assert.Equal(t, "old", result)
+
+		// 只有 Remote 中存在失效数据
+		c.memory.Delete("user:1")
+		result = ""
+		assert.NoError(t, c.Get(ctx, "user:1", &result, WithServeStale(true)))
+		assert.Equal(t, "old", result)
+	})
+
+	t.Run("MGet降级返回失效数据", func(t *testing.T) {
+		c := createEnvelopeCache(t)
+		assert.NoError(t, c.MSet(ctx, map[string]any{"k1": "v1", "k2": "v2"}))
+		assert.NoError(t, c.Invalidate(ctx, "k1"))
+
+		result := make(map[string]string)
+		assert.NoError(t, c.MGet(ctx, []string{"k1", "k2"}, &result))
+		assert.Equal(t, map[string]string{"k2": "v2"}, result)
+
+		failingBatchLoader := func(ctx context.Context, keys []string) (map[string]any, error) {
+			return nil, fmt.Errorf("database unavailable")
+		}
+		result = make(map[string]string)
+		assert.Error(t, c.MGet(ctx, []string{"k1", "k2"}, &result, WithBatchLoader(failingBatchLoader)))
+
+		result = make(map[string]string)
+		assert.NoError(t, c.MGet(ctx, []string{"k1", "k2"}, &result, WithBatchLoader(failingBatchLoader), WithServeStale(true)))
+		assert.Equal(t, map[string]string{"k1": "v1", "k2": "v2"}, result)
+	})
+
+	t.Run("未封装的旧数据直接删除", func(t *testing.T) {
+		c := createEnvelopeCache(t)
+		assert.NoError(t, c.remote.Set(ctx, "legacy", []byte("old"), time.Hour))
+		assert.NoError(t, c.Invalidate(ctx, "legacy"))
+
+		_, err := c.remote.Get(ctx, "legacy")
+		assert.ErrorIs(t, err, errors.ErrNotFound)
+	})
+
+	t.Run("键不存在", func(t *testing.T) {
+		c := createEnvelopeCache(t)
+		assert.NoError(t, c.Invalidate(ctx, "missing"))
+	})
+
+	t.Run("未开启封装格式", func(t *testing.T) {
+		assert.ErrorIs(t, createTestCache(t).Invalidate(ctx, "key"), errors.ErrEnvelopeRequired)
+	})
+}
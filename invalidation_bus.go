@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/biu7/layered-cache/storage"
+)
+
+// invalidationRetryInterval 订阅出错后重新订阅的间隔
+const invalidationRetryInterval = time.Second
+
+// invalidationMessage 跨实例失效消息，Source 用于忽略自己广播的消息
+type invalidationMessage struct {
+	Source string   `json:"src"`
+	Keys   []string `json:"keys"`
+}
+
+// invalidationBus 通过 InvalidationTransport 广播和接收失效消息
+type invalidationBus struct {
+	transport storage.InvalidationTransport
+	source    string
+}
+
+func newInvalidationBus(transport storage.InvalidationTransport) *invalidationBus {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return &invalidationBus{transport: transport, source: hex.EncodeToString(b)}
+}
+
+// broadcastInvalidation 唤醒本实例 keys 的 Watch 并通知其他实例删除 keys 的内存缓存，广播失败只计入 Stats().InvalidationErrors
+func (c *LayeredCache) broadcastInvalidation(ctx context.Context, keys []string) {
+	c.watches.notify(keys)
+	if c.bus == nil || len(keys) == 0 {
+		return
+	}
+	payload, err := json.Marshal(invalidationMessage{Source: c.bus.source, Keys: keys})
+	if err == nil {
+		err = c.bus.transport.Publish(ctx, payload)
+	}
+	if err != nil {
+		c.stats.invalidationErrors.Add(1)
+	}
+}
+
+// subscribeInvalidations 订阅其他实例的失效消息直到 Close，订阅出错时间隔一段时间后重新订阅
+func (c *LayeredCache) subscribeInvalidations() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-c.life.done
+		cancel()
+	}()
+
+	for {
+		err := c.bus.transport.Subscribe(ctx, c.applyInvalidation)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			c.stats.invalidationErrors.Add(1)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(invalidationRetryInterval):
+		}
+	}
+}
+
+// applyInvalidation 删除其他实例广播的键在本实例内存中的缓存并唤醒这些键的 Watch，无法解析的消息忽略
+func (c *LayeredCache) applyInvalidation(payload []byte) {
+	var msg invalidationMessage
+	if err := json.Unmarshal(payload, &msg); err != nil || msg.Source == c.bus.source {
+		return
+	}
+	c.watches.notify(msg.Keys)
+	if c.memory == nil {
+		return
+	}
+	for _, key := range msg.Keys {
+		c.memory.Delete(key)
+		c.memory.Delete(notFoundKey(key))
+	}
+}
@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/biu7/layered-cache/storage"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_InvalidationTransport(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	remote := storage.NewRedisWithClient(client)
+
+	newInstance := func() *LayeredCache {
+		c, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigRemote(remote),
+			WithConfigInvalidationTransport(storage.NewRedisPubSub(client, "cache-invalidate")),
+		)
+		assert.NoError(t, err)
+		t.Cleanup(func() { _ = c.Close(context.Background()) })
+		return c.(*LayeredCache)
+	}
+	a, b := newInstance(), newInstance()
+
+	var value string
+	assert.NoError(t, a.Set(ctx, "k", "v1"))
+	assert.NoError(t, b.Get(ctx, "k", &value))
+	assert.Equal(t, "v1", value)
+
+	// b 的内存中已有 v1，a 更新后 b 收到广播删除内存中的旧值
+	assert.Eventually(t, func() bool {
+		assert.NoError(t, a.Set(ctx, "k", "v2"))
+		assert.NoError(t, b.Get(ctx, "k", &value))
+		return value == "v2"
+	}, time.Second, 20*time.Millisecond)
+
+	assert.NoError(t, a.Delete(ctx, "k"))
+	assert.Eventually(t, func() bool {
+		_, exists := b.memory.Get("k")
+		return !exists
+	}, time.Second, 10*time.Millisecond)
+
+	// SetNX 写入成功后同样广播，b 内存中的旧值被删除
+	b.memorySet("nx", []byte(`"stale"`), time.Minute)
+	ok, err := a.SetNX(ctx, "nx", "fresh")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Eventually(t, func() bool {
+		_, exists := b.memory.Get("nx")
+		return !exists
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Zero(t, a.Stats().InvalidationErrors)
+}
@@ -0,0 +1,194 @@
+package cache
+
+import (
+	"context"
+	"iter"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// keyContextKey context 中键前缀的 key
+type keyContextKey struct{}
+
+// WithKeyContext 返回携带键前缀的 context，之后使用该 context 的缓存调用都会自动在键前加上 prefix，
+// 适用于在中间件中按请求设置一次租户前缀，例如 WithKeyContext(ctx, "tenant-42:")；多次调用时前缀依次拼接。
+// 前缀对调用方透明：loader、TTLFunc 收到的键以及 MGet 结果中的键都不含前缀，WithTags 的标签同样按前缀隔离
+func WithKeyContext(ctx context.Context, prefix string) context.Context {
+	return context.WithValue(ctx, keyContextKey{}, keyPrefixOf(ctx)+prefix)
+}
+
+// keyPrefixOf 返回 ctx 中的键前缀
+func keyPrefixOf(ctx context.Context) string {
+	prefix, _ := ctx.Value(keyContextKey{}).(string)
+	return prefix
+}
+
+// takeKeyContext 取出 ctx 中的键前缀，返回的 context 不再携带前缀，避免内部调用重复添加
+func takeKeyContext(ctx context.Context) (string, context.Context) {
+	prefix := keyPrefixOf(ctx)
+	if prefix == "" {
+		return "", ctx
+	}
+	return prefix, context.WithValue(ctx, keyContextKey{}, "")
+}
+
+// keyScope 一次调用的键作用域，由缓存级命名空间、ctx 中的前缀和缓存级键哈希函数组成
+type keyScope struct {
+	// prefix 缓存级命名空间加上 ctx 中的前缀
+	prefix string
+
+	// reentry ctx 中的前缀，loader 收到的 context 重新带上
+	reentry string
+
+	// hasher 加上前缀后的键的哈希函数，为 nil 表示不哈希
+	hasher func(string) string
+}
+
+// empty 是否不需要改写键
+func (s keyScope) empty() bool {
+	return s.prefix == "" && s.hasher == nil
+}
+
+// key 返回 key 实际写入缓存层的键
+func (s keyScope) key(key string) string {
+	key = s.prefix + key
+	if s.hasher != nil {
+		key = s.hasher(key)
+	}
+	return key
+}
+
+// unscoper 返回将 keys 改写后的键还原为原始键的函数
+// 设置了哈希函数时只能还原 keys 中的键，其他键原样返回
+func (s keyScope) unscoper(keys []string) func(string) string {
+	if s.hasher == nil {
+		prefix := s.prefix
+		return func(key string) string {
+			return strings.TrimPrefix(key, prefix)
+		}
+	}
+	origin := make(map[string]string, len(keys))
+	for _, key := range keys {
+		origin[s.key(key)] = key
+	}
+	return func(key string) string {
+		if unscoped, ok := origin[key]; ok {
+			return unscoped
+		}
+		return key
+	}
+}
+
+// scopeKeys 改写 keys，scope 为空时原样返回
+func scopeKeys(scope keyScope, keys []string) []string {
+	if scope.empty() {
+		return keys
+	}
+	scoped := make([]string, len(keys))
+	for i, key := range keys {
+		scoped[i] = scope.key(key)
+	}
+	return scoped
+}
+
+// scopeMap 改写 m 的键，scope 为空时原样返回
+func scopeMap[V any](scope keyScope, m map[string]V) map[string]V {
+	if scope.empty() {
+		return m
+	}
+	scoped := make(map[string]V, len(m))
+	for key, value := range m {
+		scoped[scope.key(key)] = value
+	}
+	return scoped
+}
+
+// unscopeTarget 还原 MGet 结果 map 中的键，target 为指向 map[string]T 的指针
+func unscopeTarget(unscope func(string) string, target any) {
+	targetValue := reflect.ValueOf(target)
+	if targetValue.Kind() != reflect.Ptr || targetValue.Elem().Kind() != reflect.Map || targetValue.Elem().IsNil() {
+		return
+	}
+	targetValue = targetValue.Elem()
+	unscoped := reflect.MakeMapWithSize(targetValue.Type(), targetValue.Len())
+	iter := targetValue.MapRange()
+	for iter.Next() {
+		key := unscope(iter.Key().String())
+		unscoped.SetMapIndex(reflect.ValueOf(key).Convert(targetValue.Type().Key()), iter.Value())
+	}
+	targetValue.Set(unscoped)
+}
+
+// withKeyScope 将 loader、batchLoader 和 TTLFunc 包装为接收原始键，必须放在所有选项之后
+// loader 收到的 context 重新带上 ctx 中的前缀，loader 内部的缓存调用仍按同一前缀隔离
+type withKeyScope struct {
+	scope   keyScope
+	unscope func(string) string
+}
+
+func (w withKeyScope) applyGet(cfg *getOptions) {
+	scope, unscope := w.scope, w.unscope
+	if loader := cfg.loader; loader != nil {
+		cfg.loader = func(ctx context.Context, key string) (any, error) {
+			return loader(reenterKeyContext(ctx, scope), unscope(key))
+		}
+	}
+	if batchLoader := cfg.batchLoader; batchLoader != nil {
+		cfg.batchLoader = func(ctx context.Context, keys []string) (map[string]any, error) {
+			unscoped := make([]string, len(keys))
+			for i, key := range keys {
+				unscoped[i] = unscope(key)
+			}
+			values, err := batchLoader(reenterKeyContext(ctx, scope), unscoped)
+			return scopeMap(scope, values), err
+		}
+	}
+	if ttlFunc := cfg.ttlFunc; ttlFunc != nil {
+		cfg.ttlFunc = func(key string, value any) (time.Duration, time.Duration) {
+			return ttlFunc(unscope(key), value)
+		}
+	}
+	if notFoundCached := cfg.notFoundCached; notFoundCached != nil {
+		cfg.notFoundCached = func(key string) {
+			notFoundCached(unscope(key))
+		}
+	}
+}
+
+func (w withKeyScope) applySet(cfg *setOptions) {
+	cfg.tags = scopeKeys(w.scope, cfg.tags)
+	if ttlFunc, unscope := cfg.ttlFunc, w.unscope; ttlFunc != nil {
+		cfg.ttlFunc = func(key string, value any) (time.Duration, time.Duration) {
+			return ttlFunc(unscope(key), value)
+		}
+	}
+}
+
+// scopeGetOptions 在 opts 之后追加 withKeyScope，scope 为空时原样返回
+func scopeGetOptions(scope keyScope, unscope func(string) string, opts []GetOption) []GetOption {
+	if scope.empty() {
+		return opts
+	}
+	return append(opts[:len(opts):len(opts)], withKeyScope{scope: scope, unscope: unscope})
+}
+
+// scopeSetOptions 在 opts 之后追加 withKeyScope，keys 为改写前写入的键，scope 为空时原样返回
+func scopeSetOptions(scope keyScope, keys iter.Seq[string], opts []SetOption) []SetOption {
+	if scope.empty() {
+		return opts
+	}
+	unscope := scope.unscoper(slices.Collect(keys))
+	return append(opts[:len(opts):len(opts)], withKeyScope{scope: scope, unscope: unscope})
+}
+
+// scopedView 按键作用域读取的快照
+type scopedView struct {
+	View
+	scope keyScope
+}
+
+func (v scopedView) Get(key string, target any) error {
+	return v.View.Get(v.scope.key(key), target)
+}
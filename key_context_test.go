@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithKeyContext(t *testing.T) {
+	ctx := context.Background()
+	tenant1 := WithKeyContext(ctx, "t1:")
+	tenant2 := WithKeyContext(ctx, "t2:")
+
+	t.Run("按前缀隔离", func(t *testing.T) {
+		remote := createRemoteAdapter(t)
+		c, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigRemote(remote))
+		assert.NoError(t, err)
+
+		assert.NoError(t, c.Set(tenant1, "k", "v1"))
+		assert.NoError(t, c.Set(tenant2, "k", "v2"))
+
+		var value string
+		assert.NoError(t, c.Get(tenant1, "k", &value))
+		assert.Equal(t, "v1", value)
+		assert.ErrorIs(t, c.Get(ctx, "k", &value), ErrNotFound)
+
+		_, err = remote.Get(ctx, "t2:k")
+		assert.NoError(t, err)
+
+		keys, _, err := c.RemoteKeys(tenant1, "", 0, 100)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"k"}, keys)
+
+		assert.NoError(t, c.DeleteByPrefix(tenant1, "k"))
+		assert.ErrorIs(t, c.Get(tenant1, "k", &value), ErrNotFound)
+		assert.NoError(t, c.Get(tenant2, "k", &value))
+	})
+
+	t.Run("MGet 结果和 loader 使用不含前缀的键", func(t *testing.T) {
+		c, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigRemote(createRemoteAdapter(t)))
+		assert.NoError(t, err)
+		assert.NoError(t, c.Set(tenant1, "a", "cached"))
+
+		var loaded []string
+		values := make(map[string]string)
+		err = c.MGet(tenant1, []string{"a", "b"}, &values, WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+			loaded = append(loaded, keys...)
+			assert.Equal(t, "t1:", keyPrefixOf(ctx), "loader 的 context 保留前缀")
+			return map[string]any{"b": "loaded"}, nil
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"b"}, loaded)
+		assert.Equal(t, map[string]string{"a": "cached", "b": "loaded"}, values)
+
+		var value string
+		assert.NoError(t, c.Get(tenant1, "b", &value))
+		assert.ErrorIs(t, c.Get(tenant2, "b", &value), ErrNotFound)
+	})
+
+	t.Run("与 TypedCache 组合", func(t *testing.T) {
+		c, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigRemote(createRemoteAdapter(t)))
+		assert.NoError(t, err)
+		users := Typed[int64, string](c)
+
+		assert.NoError(t, users.MSet(tenant1, "user", map[int64]string{1: "alice", 2: "bob"}))
+		found, err := users.MGet(tenant1, "user", []int64{1, 2, 3}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, map[int64]string{1: "alice", 2: "bob"}, found)
+
+		found, err = users.MGet(tenant2, "user", []int64{1, 2}, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, found)
+
+		nested := WithKeyContext(tenant1, "eu:")
+		assert.NoError(t, users.Set(nested, "user", 1, "carol"))
+		keys, _, err := c.RemoteKeys(tenant1, "", 0, 100)
+		assert.NoError(t, err)
+		sort.Strings(keys)
+		assert.Equal(t, []string{"eu:user:1", "user:1", "user:2"}, keys)
+	})
+
+	t.Run("标签按前缀隔离", func(t *testing.T) {
+		c, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigRemote(createRemoteAdapter(t)))
+		assert.NoError(t, err)
+
+		assert.NoError(t, c.Set(tenant1, "k", "v", WithTags("group")))
+		assert.NoError(t, c.Set(tenant2, "k", "v", WithTags("group")))
+		assert.NoError(t, c.InvalidateTag(tenant1, "group"))
+
+		var value string
+		assert.ErrorIs(t, c.Get(tenant1, "k", &value), ErrNotFound)
+		assert.NoError(t, c.Get(tenant2, "k", &value))
+	})
+}
@@ -0,0 +1,26 @@
+package cache
+
+import "context"
+
+// namespacedKey context 中已改写为缓存级键空间的标记，值为对应的 *LayeredCache，避免内部调用重复改写
+type namespacedKey struct{}
+
+// takeKeyContext 取出 ctx 中的键前缀并加上缓存级命名空间和键哈希函数，返回的 context 不再携带前缀
+func (c *LayeredCache) takeKeyContext(ctx context.Context) (keyScope, context.Context) {
+	prefix, ctx := takeKeyContext(ctx)
+	scope := keyScope{prefix: prefix, reentry: prefix}
+	if (c.keyPrefix == "" && c.keyHasher == nil) || ctx.Value(namespacedKey{}) == c {
+		return scope, ctx
+	}
+	scope.prefix, scope.hasher = c.keyPrefix+prefix, c.keyHasher
+	return scope, context.WithValue(ctx, namespacedKey{}, c)
+}
+
+// reenterKeyContext 返回 loader 使用的 context：重新带上 ctx 中的前缀并清除缓存级键空间标记，
+// loader 内部的缓存调用按调用方的视角重新改写键
+func reenterKeyContext(ctx context.Context, scope keyScope) context.Context {
+	if ctx.Value(namespacedKey{}) != nil {
+		ctx = context.WithValue(ctx, namespacedKey{}, nil)
+	}
+	return WithKeyContext(ctx, scope.reentry)
+}
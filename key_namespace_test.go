@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_KeyNamespace(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("共用 Remote 时按命名空间隔离", func(t *testing.T) {
+		remote := createRemoteAdapter(t)
+		a, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigRemote(remote), WithConfigKeyPrefix("svc-a:"))
+		assert.NoError(t, err)
+		b, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigRemote(remote), WithConfigKeyPrefix("svc-b:"))
+		assert.NoError(t, err)
+
+		assert.NoError(t, a.Set(ctx, "k", "a"))
+		assert.NoError(t, b.Set(ctx, "k", "b"))
+		_, err = remote.Get(ctx, "svc-a:k")
+		assert.NoError(t, err)
+
+		var value string
+		assert.NoError(t, a.Get(ctx, "k", &value))
+		assert.Equal(t, "a", value)
+
+		keys, _, err := a.RemoteKeys(ctx, "", 0, 100)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"k"}, keys)
+
+		assert.NoError(t, a.DeleteByPrefix(ctx, "k"))
+		assert.ErrorIs(t, a.Get(ctx, "k", &value), ErrNotFound)
+		assert.NoError(t, b.Get(ctx, "k", &value))
+		assert.Equal(t, "b", value)
+	})
+
+	t.Run("MGet 写回和 loader 使用不含命名空间的键", func(t *testing.T) {
+		remote := createRemoteAdapter(t)
+		c, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigRemote(remote), WithConfigKeyPrefix("svc-a:"))
+		assert.NoError(t, err)
+		tenant := WithKeyContext(ctx, "t1:")
+
+		var loaded []string
+		values := make(map[string]string)
+		err = c.MGet(tenant, []string{"a", "b"}, &values, WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+			loaded = append(loaded, keys...)
+			assert.Equal(t, "t1:", keyPrefixOf(ctx))
+
+			var nested string
+			assert.NoError(t, c.Set(ctx, "nested", "n"))
+			assert.NoError(t, c.Get(ctx, "nested", &nested), "loader 内部的调用只加一次命名空间")
+			return map[string]any{"a": "1", "b": "2"}, nil
+		}))
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"a", "b"}, loaded)
+		assert.Equal(t, map[string]string{"a": "1", "b": "2"}, values)
+
+		for _, key := range []string{"svc-a:t1:a", "svc-a:t1:b", "svc-a:t1:nested"} {
+			_, err = remote.Get(ctx, key)
+			assert.NoError(t, err, key)
+		}
+	})
+
+	t.Run("键哈希", func(t *testing.T) {
+		remote := createRemoteAdapter(t)
+		hash := func(key string) string {
+			sum := sha1.Sum([]byte(key))
+			return hex.EncodeToString(sum[:])
+		}
+		c, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigRemote(remote), WithConfigKeyPrefix("svc-a:"), WithConfigKeyHasher(hash))
+		assert.NoError(t, err)
+
+		var ttlKeys []string
+		values := make(map[string]string)
+		err = c.MGet(ctx, []string{"a", "b"}, &values,
+			WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+				return map[string]any{"a": "1"}, nil
+			}),
+			WithCacheNotFound(true, time.Minute),
+			WithTTLFunc(func(key string, value any) (time.Duration, time.Duration) {
+				ttlKeys = append(ttlKeys, key)
+				return time.Minute, time.Hour
+			}),
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"a": "1"}, values)
+		assert.Equal(t, []string{"a"}, ttlKeys)
+
+		_, err = remote.Get(ctx, hash("svc-a:a"))
+		assert.NoError(t, err)
+		var value string
+		assert.NoError(t, c.Get(ctx, "a", &value))
+		assert.Equal(t, "1", value)
+
+		assert.ErrorIs(t, c.DeleteByPrefix(ctx, "a"), errors.ErrCustomKeyLayout)
+		_, _, err = c.RemoteKeys(ctx, "", 0, 100)
+		assert.ErrorIs(t, err, errors.ErrCustomKeyLayout)
+	})
+
+	t.Run("配置报告", func(t *testing.T) {
+		report, err := ValidateConfig(WithConfigMemory(createMemoryAdapter(t)), WithConfigKeyPrefix("svc-a:"))
+		assert.NoError(t, err)
+		assert.Contains(t, report.Features, `key-prefix("svc-a:")`)
+	})
+}
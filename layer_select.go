@@ -0,0 +1,67 @@
+package cache
+
+import "github.com/biu7/layered-cache/errors"
+
+// layerSelection 单次调用跳过的缓存层，被跳过的层既不读取也不写入
+type layerSelection struct {
+	skipMemory bool
+	skipRemote bool
+}
+
+// useMemory 本次调用是否使用内存缓存
+func (l layerSelection) useMemory(c *LayeredCache) bool {
+	return c.memory != nil && !l.skipMemory
+}
+
+// useRemote 本次调用是否使用 Remote
+func (l layerSelection) useRemote(c *LayeredCache) bool {
+	return c.remote != nil && !l.skipRemote
+}
+
+func (l layerSelection) validate() error {
+	if l.skipMemory && l.skipRemote {
+		return errors.ErrInvalidSkipLayers
+	}
+	return nil
+}
+
+// withSkipLayers 设置单次调用跳过的缓存层
+type withSkipLayers struct {
+	readOption
+
+	skipMemory bool
+	skipRemote bool
+}
+
+func (w withSkipLayers) applyGet(cfg *getOptions) {
+	cfg.skipMemory, cfg.skipRemote = w.skipMemory, w.skipRemote
+}
+
+func (w withSkipLayers) applySet(cfg *setOptions) {
+	cfg.skipMemory, cfg.skipRemote = w.skipMemory, w.skipRemote
+}
+
+// WithSkipLayers 设置单次调用跳过的缓存层（通用选项，可用于Get和Set操作），被跳过的层既不读取也不写入，
+// loader 加载的数据和缺失值标记也只写入未跳过的层；不能同时跳过两层
+func WithSkipLayers(skipMemory, skipRemote bool) interface {
+	ReadOption
+	SetOption
+} {
+	return withSkipLayers{skipMemory: skipMemory, skipRemote: skipRemote}
+}
+
+// WithOnlyMemory 只读写内存缓存，例如只在本实例缓存的计算结果
+func WithOnlyMemory() interface {
+	ReadOption
+	SetOption
+} {
+	return withSkipLayers{skipRemote: true}
+}
+
+// WithOnlyRedis 只读写 Remote，例如只写入 Redis 的会话令牌，或绕过内存缓存强制从 Remote 读取最新数据
+func WithOnlyRedis() interface {
+	ReadOption
+	SetOption
+} {
+	return withSkipLayers{skipMemory: true}
+}
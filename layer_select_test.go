@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_SkipLayers(t *testing.T) {
+	ctx := context.Background()
+
+	newCache := func(t *testing.T) *LayeredCache {
+		c, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigRemote(createRemoteAdapter(t)))
+		assert.NoError(t, err)
+		return c.(*LayeredCache)
+	}
+
+	t.Run("不能同时跳过两层", func(t *testing.T) {
+		c := newCache(t)
+		assert.ErrorIs(t, c.Set(ctx, "k", "v", WithSkipLayers(true, true)), errors.ErrInvalidSkipLayers)
+		var value string
+		assert.ErrorIs(t, c.Get(ctx, "k", &value, WithSkipLayers(true, true)), errors.ErrInvalidSkipLayers)
+	})
+
+	t.Run("只写入 Redis", func(t *testing.T) {
+		c := newCache(t)
+		assert.NoError(t, c.Set(ctx, "session", "token", WithOnlyRedis()))
+		assert.NoError(t, c.MSet(ctx, map[string]any{"s1": "a"}, WithOnlyRedis()))
+
+		_, ok := c.memory.Get("session")
+		assert.False(t, ok)
+		_, ok = c.memory.Get("s1")
+		assert.False(t, ok)
+
+		var value string
+		assert.NoError(t, c.Get(ctx, "session", &value, WithOnlyRedis()))
+		assert.Equal(t, "token", value)
+		_, ok = c.memory.Get("session")
+		assert.False(t, ok, "跳过内存时不写回")
+	})
+
+	t.Run("只写入内存", func(t *testing.T) {
+		c := newCache(t)
+		assert.NoError(t, c.Set(ctx, "k", "v", WithOnlyMemory()))
+		_, err := c.remote.Get(ctx, "k")
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		var value string
+		assert.NoError(t, c.Get(ctx, "k", &value))
+		assert.Equal(t, "v", value)
+	})
+
+	t.Run("绕过内存读取最新数据", func(t *testing.T) {
+		c := newCache(t)
+		assert.NoError(t, c.Set(ctx, "k", "old"))
+		data, err := c.encode("new", nil)
+		assert.NoError(t, err)
+		assert.NoError(t, c.remote.Set(ctx, "k", data, 0))
+
+		var value string
+		assert.NoError(t, c.Get(ctx, "k", &value))
+		assert.Equal(t, "old", value)
+		assert.NoError(t, c.Get(ctx, "k", &value, WithSkipLayers(true, false)))
+		assert.Equal(t, "new", value)
+
+		values := make(map[string]string)
+		assert.NoError(t, c.MGet(ctx, []string{"k"}, &values, WithOnlyRedis()))
+		assert.Equal(t, map[string]string{"k": "new"}, values)
+	})
+
+	t.Run("loader 结果只写入未跳过的层", func(t *testing.T) {
+		c := newCache(t)
+		var value string
+		err := c.Get(ctx, "k", &value, WithOnlyMemory(), WithLoader(func(ctx context.Context, key string) (any, error) {
+			return "loaded", nil
+		}))
+		assert.NoError(t, err)
+		_, ok := c.memory.Get("k")
+		assert.True(t, ok)
+		_, err = c.remote.Get(ctx, "k")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_Layers(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("配置校验", func(t *testing.T) {
+		_, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigLayers([]storage.Layer{}))
+		assert.ErrorIs(t, err, errors.ErrInvalidLayers)
+
+		_, err = NewCache(WithConfigRemote(createRemoteAdapter(t)), WithConfigLayers([]storage.Layer{{Remote: createRemoteAdapter(t)}}))
+		assert.ErrorIs(t, err, errors.ErrInvalidLayers)
+	})
+
+	t.Run("内存 → Redis → 磁盘", func(t *testing.T) {
+		redis := createRemoteAdapter(t)
+		disk, err := storage.NewBolt(filepath.Join(t.TempDir(), "cache.db"), 0)
+		assert.NoError(t, err)
+		defer disk.Close()
+
+		c, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigLayers([]storage.Layer{{Remote: redis, TTL: time.Minute}, {Remote: disk}}),
+		)
+		assert.NoError(t, err)
+
+		assert.NoError(t, c.Set(ctx, "k", "v"))
+		_, err = disk.Get(ctx, "k")
+		assert.NoError(t, err, "写入作用于所有层")
+
+		data, err := c.(*LayeredCache).encode("cold", nil)
+		assert.NoError(t, err)
+		assert.NoError(t, disk.Set(ctx, "cold", data, time.Hour))
+
+		var value string
+		assert.NoError(t, c.Get(ctx, "cold", &value))
+		assert.Equal(t, "cold", value)
+		_, err = redis.Get(ctx, "cold")
+		assert.NoError(t, err, "读取回填到 Redis")
+
+		report, err := ValidateConfig(WithConfigLayers([]storage.Layer{{Remote: redis}, {Remote: disk}}))
+		assert.NoError(t, err)
+		assert.Contains(t, report.Features, "layers(2)")
+	})
+}
@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"sync"
+)
+
+// lifecycle 跟踪后台任务和正在执行的加载，Close 时停止接收新的后台任务并等待已有的结束
+type lifecycle struct {
+	// mu 保护 closed，登记任务时持有读锁，保证关闭后不再登记
+	mu     sync.RWMutex
+	closed bool
+
+	// done 关闭时关闭，通知定时任务退出
+	done chan struct{}
+
+	tasks sync.WaitGroup
+}
+
+func newLifecycle() *lifecycle {
+	return &lifecycle{done: make(chan struct{})}
+}
+
+// enter 登记一个任务，已关闭时返回 false；返回 true 时结束后需要调用 exit
+func (l *lifecycle) enter() bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if l.closed {
+		return false
+	}
+	l.tasks.Add(1)
+	return true
+}
+
+func (l *lifecycle) exit() {
+	l.tasks.Done()
+}
+
+// close 停止登记新任务并等待已登记的任务结束，ctx 结束时不再等待
+func (l *lifecycle) close(ctx context.Context) error {
+	l.mu.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.done)
+	}
+	l.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		l.tasks.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// spawn 在后台执行 fn 并在 Close 时等待其结束，已关闭时不执行并返回 false
+func (c *LayeredCache) spawn(fn func()) bool {
+	if !c.life.enter() {
+		return false
+	}
+	go func() {
+		defer c.life.exit()
+		fn()
+	}()
+	return true
+}
+
+// Close 关闭缓存：停止定时任务，写完异步 Remote 写入的队列，等待后台预取、影子比对、异步写穿以及正在执行的 loader 结束；
+// 随后停止发布 expvar 计数，开启 WithConfigCloseAdapters 时最后关闭实现了 io.Closer 的内存和 Remote 适配器。
+// 关闭后读写仍可使用，但不再启动后台任务：Remote 写入和写穿改为同步执行，预取和影子比对跳过，ScheduleInvalidation 返回 ErrClosed。
+// ctx 结束时不再等待并返回 ctx.Err()，此时不关闭适配器；重复调用只等待尚未结束的任务
+func (c *LayeredCache) Close(ctx context.Context) error {
+	if c.async != nil {
+		if err := c.async.close(ctx); err != nil {
+			return err
+		}
+	}
+	if err := c.life.close(ctx); err != nil {
+		return err
+	}
+	c.unpublishExpvar()
+
+	if !c.closeAdapters {
+		return nil
+	}
+	var errs []error
+	c.closeOnce.Do(func() {
+		if closer, ok := c.memory.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+		if closer, ok := c.remote.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	})
+	return stderrors.Join(errs...)
+}
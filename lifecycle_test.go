@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_Close(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("等待正在执行的加载", func(t *testing.T) {
+		c, err := NewCache(WithConfigMemory(createMemoryAdapter(t)))
+		assert.NoError(t, err)
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		go func() {
+			var value string
+			_ = c.Get(ctx, "k", &value, WithLoader(func(ctx context.Context, key string) (any, error) {
+				close(started)
+				<-release
+				return "v", nil
+			}))
+		}()
+		<-started
+
+		timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, c.Close(timeout), context.DeadlineExceeded)
+
+		closed := make(chan error)
+		go func() { closed <- c.Close(ctx) }()
+		select {
+		case <-closed:
+			t.Fatal("加载结束前 Close 不应返回")
+		case <-time.After(20 * time.Millisecond):
+		}
+		close(release)
+		assert.NoError(t, <-closed)
+	})
+
+	t.Run("关闭后不再启动后台任务", func(t *testing.T) {
+		var called bool
+		c, err := NewCache(
+			WithConfigRemote(createRemoteAdapter(t)),
+			WithConfigWriteThrough(func(ctx context.Context, key string, value []byte) error {
+				called = true
+				return nil
+			}, WriteThroughAsync, nil),
+		)
+		assert.NoError(t, err)
+		assert.NoError(t, c.Close(ctx))
+
+		_, err = c.ScheduleInvalidation("price:", "@hourly")
+		assert.ErrorIs(t, err, errors.ErrClosed)
+
+		assert.NoError(t, c.Set(ctx, "k", "v"))
+		assert.True(t, called, "异步写穿改为同步执行")
+	})
+
+	t.Run("停止定时任务", func(t *testing.T) {
+		c, err := NewCache(WithConfigRemote(createRemoteAdapter(t)))
+		assert.NoError(t, err)
+
+		_, err = c.ScheduleInvalidation("price:", "@hourly")
+		assert.NoError(t, err)
+		assert.NoError(t, c.Close(ctx), "Close 不需要等待下一次定时执行")
+	})
+
+	t.Run("关闭适配器", func(t *testing.T) {
+		remote := createRemoteAdapter(t)
+		c, err := NewCache(WithConfigMemory(createOtterAdapter(t)), WithConfigRemote(remote), WithConfigCloseAdapters(true))
+		assert.NoError(t, err)
+		assert.NoError(t, c.Close(ctx))
+
+		assert.Error(t, remote.Set(ctx, "k", []byte("v"), time.Minute))
+	})
+}
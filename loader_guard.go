@@ -0,0 +1,170 @@
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+)
+
+// loaderGuard 按键前缀隔离的 loader 熔断和限流，一个前缀的下游故障不影响其他前缀
+type loaderGuard struct {
+	prefixes prefixAllowList
+
+	// 熔断配置，failures 为 0 表示不熔断
+	failures int
+	cooldown time.Duration
+
+	// 限流配置，rate 为 0 表示不限流
+	rate  float64
+	burst int
+
+	now func() time.Time
+
+	mu     sync.Mutex
+	states map[string]*prefixGuard
+}
+
+// prefixGuard 单个前缀的熔断和限流状态
+type prefixGuard struct {
+	// 连续失败次数
+	failures int
+
+	// 熔断打开的截止时间，零值表示关闭
+	openUntil time.Time
+
+	// 熔断冷却结束后是否已有探测请求在执行（半开状态）
+	probing bool
+
+	// 令牌桶
+	tokens float64
+	refill time.Time
+}
+
+func newLoaderGuard(prefixes []string, breaker *loaderBreakerOption, limit *loaderRateLimitOption) *loaderGuard {
+	g := &loaderGuard{
+		prefixes: newPrefixAllowList(prefixes),
+		now:      time.Now,
+		states:   make(map[string]*prefixGuard),
+	}
+	if breaker != nil {
+		g.failures, g.cooldown = breaker.failures, breaker.cooldown
+	}
+	if limit != nil {
+		g.rate, g.burst = limit.rate, limit.burst
+	}
+	return g
+}
+
+// acquire 判断 keys 所属的前缀是否允许调用 loader，返回的前缀需要在调用结束后传给 report
+// 涉及多个前缀时任一前缀被拒绝则整体拒绝，已占用的探测名额会被释放
+func (g *loaderGuard) acquire(keys []string) ([]string, error) {
+	labels := g.labels(keys)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	for i, label := range labels {
+		if err := g.state(label, now).allow(g, now); err != nil {
+			for _, acquired := range labels[:i] {
+				g.states[acquired].probing = false
+			}
+			return nil, err
+		}
+	}
+	return labels, nil
+}
+
+// report 记录 loader 的调用结果，err 为 nil 时关闭熔断，连续失败达到阈值时打开熔断
+func (g *loaderGuard) report(labels []string, err error) {
+	if g.failures <= 0 {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	for _, label := range labels {
+		s := g.state(label, now)
+		s.probing = false
+		if err == nil {
+			s.failures = 0
+			s.openUntil = time.Time{}
+			continue
+		}
+		s.failures++
+		if s.failures >= g.failures {
+			s.openUntil = now.Add(g.cooldown)
+		}
+	}
+}
+
+// labels 返回 keys 涉及的所有前缀，保持首次出现的顺序
+func (g *loaderGuard) labels(keys []string) []string {
+	var labels []string
+	seen := make(map[string]struct{})
+	for _, key := range keys {
+		label := g.prefixes.label(key)
+		if _, ok := seen[label]; ok {
+			continue
+		}
+		seen[label] = struct{}{}
+		labels = append(labels, label)
+	}
+	return labels
+}
+
+// state 返回前缀的状态，不存在时创建，调用方需持有锁
+func (g *loaderGuard) state(label string, now time.Time) *prefixGuard {
+	s, ok := g.states[label]
+	if !ok {
+		s = &prefixGuard{tokens: float64(g.burst), refill: now}
+		g.states[label] = s
+	}
+	return s
+}
+
+// allow 判断本次调用是否被熔断或限流，调用方需持有锁
+func (s *prefixGuard) allow(g *loaderGuard, now time.Time) error {
+	if g.failures > 0 && s.failures >= g.failures {
+		// 冷却期内直接拒绝，冷却结束后只放行一个探测请求
+		if now.Before(s.openUntil) || s.probing {
+			return errors.ErrCircuitOpen
+		}
+	}
+
+	if g.rate > 0 {
+		s.tokens = min(float64(g.burst), s.tokens+now.Sub(s.refill).Seconds()*g.rate)
+		s.refill = now
+		if s.tokens < 1 {
+			return errors.ErrRateLimited
+		}
+		s.tokens--
+	}
+
+	if g.failures > 0 && s.failures >= g.failures {
+		s.probing = true
+	}
+	return nil
+}
+
+// guardLoad 检查 loader 是否允许调用，未开启时返回的 done 为空操作
+// done 需要在 loader 返回后调用，缺失值不视为失败
+func (c *LayeredCache) guardLoad(keys []string) (done func(err error), err error) {
+	if c.guard == nil {
+		return func(error) {}, nil
+	}
+	labels, err := c.guard.acquire(keys)
+	if err != nil {
+		c.stats.loadRejects.Add(1)
+		return nil, err
+	}
+	return func(err error) {
+		if IsNotFound(err) {
+			err = nil
+		}
+		c.guard.report(labels, err)
+	}, nil
+}
@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoaderGuard(t *testing.T) {
+	now := time.Unix(0, 0)
+	failed := stderrors.New("db down")
+
+	t.Run("熔断按前缀隔离", func(t *testing.T) {
+		g := newLoaderGuard([]string{"rec:", "user:"}, &loaderBreakerOption{failures: 2, cooldown: time.Second}, nil)
+		g.now = func() time.Time { return now }
+
+		for range 2 {
+			labels, err := g.acquire([]string{"rec:1"})
+			assert.NoError(t, err)
+			g.report(labels, failed)
+		}
+
+		_, err := g.acquire([]string{"rec:2"})
+		assert.ErrorIs(t, err, errors.ErrCircuitOpen)
+
+		labels, err := g.acquire([]string{"user:1"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"user:"}, labels)
+
+		_, err = g.acquire([]string{"user:2", "rec:3"})
+		assert.ErrorIs(t, err, errors.ErrCircuitOpen)
+	})
+
+	t.Run("冷却后半开探测", func(t *testing.T) {
+		clock := now
+		g := newLoaderGuard(nil, &loaderBreakerOption{failures: 1, cooldown: time.Second}, nil)
+		g.now = func() time.Time { return clock }
+
+		labels, _ := g.acquire([]string{"k"})
+		g.report(labels, failed)
+
+		clock = clock.Add(time.Second)
+		probe, err := g.acquire([]string{"k"})
+		assert.NoError(t, err)
+		_, err = g.acquire([]string{"k"})
+		assert.ErrorIs(t, err, errors.ErrCircuitOpen, "探测期间其他请求仍被拒绝")
+
+		g.report(probe, nil)
+		_, err = g.acquire([]string{"k"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("按前缀限流", func(t *testing.T) {
+		clock := now
+		g := newLoaderGuard([]string{"rec:"}, nil, &loaderRateLimitOption{rate: 1, burst: 2})
+		g.now = func() time.Time { return clock }
+
+		for range 2 {
+			_, err := g.acquire([]string{"rec:1"})
+			assert.NoError(t, err)
+		}
+		_, err := g.acquire([]string{"rec:1"})
+		assert.ErrorIs(t, err, errors.ErrRateLimited)
+
+		_, err = g.acquire([]string{"other"})
+		assert.NoError(t, err)
+
+		clock = clock.Add(time.Second)
+		_, err = g.acquire([]string{"rec:1"})
+		assert.NoError(t, err)
+	})
+}
+
+func TestLayeredCache_LoaderBreaker(t *testing.T) {
+	ctx := context.Background()
+	failed := stderrors.New("db down")
+
+	t.Run("配置校验", func(t *testing.T) {
+		_, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigLoaderBreaker(0, time.Second))
+		assert.ErrorIs(t, err, errors.ErrInvalidLoaderBreaker)
+
+		_, err = NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigLoaderRateLimit(1, 0))
+		assert.ErrorIs(t, err, errors.ErrInvalidLoaderRateLimit)
+	})
+
+	t.Run("故障前缀熔断不影响其他前缀", func(t *testing.T) {
+		c, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigRemote(createRemoteAdapter(t)),
+			WithConfigLoaderPrefixes("rec:", "user:"),
+			WithConfigLoaderBreaker(1, time.Minute),
+		)
+		assert.NoError(t, err)
+
+		calls := 0
+		failing := func(ctx context.Context, key string) (any, error) {
+			calls++
+			return nil, failed
+		}
+		var value string
+		assert.ErrorIs(t, c.Get(ctx, "rec:1", &value, WithLoader(failing)), failed)
+		assert.ErrorIs(t, c.Get(ctx, "rec:2", &value, WithLoader(failing)), errors.ErrCircuitOpen)
+		assert.Equal(t, 1, calls)
+
+		values := make(map[string]string)
+		err = c.MGet(ctx, []string{"rec:3"}, &values, WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+			calls++
+			return nil, nil
+		}))
+		assert.ErrorIs(t, err, errors.ErrCircuitOpen)
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, int64(2), c.(*LayeredCache).Stats().LoadRejects)
+
+		err = c.Get(ctx, "user:1", &value, WithLoader(func(ctx context.Context, key string) (any, error) {
+			return "alice", nil
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", value)
+	})
+
+	t.Run("缺失值不视为失败", func(t *testing.T) {
+		c, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigLoaderBreaker(1, time.Minute))
+		assert.NoError(t, err)
+
+		notFound := func(ctx context.Context, key string) (any, error) {
+			return nil, ErrNotFound
+		}
+		var value string
+		assert.ErrorIs(t, c.Get(ctx, "k1", &value, WithLoader(notFound)), ErrNotFound)
+		assert.ErrorIs(t, c.Get(ctx, "k2", &value, WithLoader(notFound)), ErrNotFound)
+		assert.NotErrorIs(t, c.Get(ctx, "k3", &value, WithLoader(notFound)), errors.ErrCircuitOpen)
+	})
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+)
+
+// loaderBackgroundFactor 超时后在后台继续执行的 loader 最长执行超时时间的多少倍
+const loaderBackgroundFactor = 10
+
+// load 通过 singleflight 执行加载，设置了 loader 超时时间时以独立的 context 执行并限制等待时间
+// 单次调用未设置超时时间时使用 WithConfigDefaultLoaderTimeout 的默认值；
+// 超时时间不同的调用不共用同一次加载，避免超时时间较长的调用方继承其他调用方较短的截止时间
+func (c *LayeredCache) load(ctx context.Context, sfKey string, config *getOptions, fn func(ctx context.Context) (any, error)) (any, error) {
+	timeout := config.loaderTimeout
+	if timeout == 0 {
+		timeout = c.defaultLoaderTimeout
+	}
+
+	// 正在执行的加载在 Close 时等待结束
+	track := fn
+	fn = func(ctx context.Context) (any, error) {
+		if c.life.enter() {
+			defer c.life.exit()
+		}
+		return track(ctx)
+	}
+
+	// executed 表示本次请求是否实际执行了加载，未执行说明复用了其他并发请求的结果
+	executed := false
+	if timeout <= 0 {
+		result, err, _ := c.sf.Do(sfKey, func() (any, error) {
+			executed = true
+			return fn(ctx)
+		})
+		c.observeSingleflight(!executed)
+		return result, err
+	}
+
+	limit := timeout
+	if config.loaderFinishInBackground {
+		limit = timeout * loaderBackgroundFactor
+	}
+	loadCtx := context.WithoutCancel(ctx)
+	ch := c.sf.DoChan(fmt.Sprintf("%s\x00%s", sfKey, timeout), func() (any, error) {
+		executed = true
+		ctx, cancel := context.WithTimeout(loadCtx, limit)
+		defer cancel()
+		result, err := fn(ctx)
+		// loader 因自身的截止时间返回时，等待者同样视为超时
+		if err != nil && ctx.Err() != nil && errors.Is(err, context.DeadlineExceeded) {
+			return nil, errors.ErrLoaderTimeout
+		}
+		return result, err
+	})
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case result := <-ch:
+		c.observeSingleflight(!executed)
+		return result.Val, result.Err
+	case <-timer.C:
+		return nil, errors.ErrLoaderTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// observeSingleflight 统计并上报 singleflight 的合并情况，shared 表示复用了其他并发请求的结果
+func (c *LayeredCache) observeSingleflight(shared bool) {
+	c.stats.singleflightCalls.Add(1)
+	if shared {
+		c.stats.singleflightShared.Add(1)
+	}
+	if c.metrics != nil {
+		c.metrics.ObserveSingleflight(shared)
+	}
+}
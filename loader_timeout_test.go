@@ -0,0 +1,186 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_LoaderTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("超时后取消loader", func(t *testing.T) {
+		c := createTestCache(t)
+		loaderErr := make(chan error, 1)
+		loader := func(ctx context.Context, key string) (any, error) {
+			<-ctx.Done()
+			loaderErr <- ctx.Err()
+			return nil, ctx.Err()
+		}
+
+		var result string
+		err := c.Get(ctx, "slow", &result, WithLoader(loader), WithLoaderTimeout(50*time.Millisecond, false))
+		assert.ErrorIs(t, err, errors.ErrLoaderTimeout)
+		assert.ErrorIs(t, <-loaderErr, context.DeadlineExceeded)
+	})
+
+	t.Run("超时后在后台完成并写入缓存", func(t *testing.T) {
+		c := createTestCache(t)
+		release := make(chan struct{})
+		loader := func(ctx context.Context, key string) (any, error) {
+			<-release
+			return "loaded", ctx.Err()
+		}
+
+		var result string
+		err := c.Get(ctx, "slow", &result, WithLoader(loader), WithLoaderTimeout(50*time.Millisecond, true))
+		assert.ErrorIs(t, err, errors.ErrLoaderTimeout)
+
+		close(release)
+		assert.Eventually(t, func() bool {
+			return c.Get(ctx, "slow", &result) == nil && result == "loaded"
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("loader 自身超时返回 ErrLoaderTimeout", func(t *testing.T) {
+		c := createTestCache(t)
+		loader := func(ctx context.Context, key string) (any, error) {
+			<-ctx.Done()
+			return nil, fmt.Errorf("query: %w", ctx.Err())
+		}
+
+		var result string
+		for range 5 {
+			err := c.Get(ctx, "slow", &result, WithLoader(loader), WithLoaderTimeout(10*time.Millisecond, false))
+			assert.ErrorIs(t, err, errors.ErrLoaderTimeout)
+		}
+	})
+
+	t.Run("超时时间较长的调用不继承较短的截止时间", func(t *testing.T) {
+		c := createTestCache(t)
+		started := make(chan struct{})
+		var calls atomic.Int32
+		loader := func(ctx context.Context, key string) (any, error) {
+			if calls.Add(1) == 1 {
+				close(started)
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return "loaded", nil
+		}
+
+		done := make(chan error, 1)
+		go func() {
+			var result string
+			done <- c.Get(ctx, "key", &result, WithLoader(loader), WithLoaderTimeout(50*time.Millisecond, false))
+		}()
+		<-started
+
+		var result string
+		assert.NoError(t, c.Get(ctx, "key", &result, WithLoader(loader), WithLoaderTimeout(time.Second, false)))
+		assert.Equal(t, "loaded", result)
+		assert.ErrorIs(t, <-done, errors.ErrLoaderTimeout)
+	})
+
+	t.Run("未超时正常返回", func(t *testing.T) {
+		c := createTestCache(t)
+		loader := func(ctx context.Context, key string) (any, error) {
+			return "fast", nil
+		}
+
+		var result string
+		assert.NoError(t, c.Get(ctx, "fast", &result, WithLoader(loader), WithLoaderTimeout(time.Second, false)))
+		assert.Equal(t, "fast", result)
+	})
+
+	t.Run("调用方取消不影响其他等待者", func(t *testing.T) {
+		c := createTestCache(t)
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var once sync.Once
+		loader := func(ctx context.Context, key string) (any, error) {
+			once.Do(func() { close(started) })
+			select {
+			case <-release:
+				return "shared", nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		opts := []GetOption{WithLoader(loader), WithLoaderTimeout(time.Second, false)}
+
+		cancelCtx, cancel := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		var firstErr error
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var result string
+			firstErr = c.Get(cancelCtx, "shared", &result, opts...)
+		}()
+		<-started
+
+		var second string
+		var secondErr error
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			secondErr = c.Get(ctx, "shared", &second, opts...)
+		}()
+
+		cancel()
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.ErrorIs(t, firstErr, context.Canceled)
+		assert.NoError(t, secondErr)
+		assert.Equal(t, "shared", second)
+	})
+
+	t.Run("批量加载超时", func(t *testing.T) {
+		c := createTestCache(t)
+		batchLoader := func(ctx context.Context, keys []string) (map[string]any, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+
+		result := make(map[string]string)
+		err := c.MGet(ctx, []string{"a", "b"}, &result, WithBatchLoader(batchLoader), WithLoaderTimeout(50*time.Millisecond, false))
+		assert.ErrorIs(t, err, errors.ErrLoaderTimeout)
+	})
+
+	t.Run("默认超时时间", func(t *testing.T) {
+		c, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigDefaultLoaderTimeout(50*time.Millisecond))
+		assert.NoError(t, err)
+		loader := func(ctx context.Context, key string) (any, error) {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(200 * time.Millisecond):
+				return "slow", nil
+			}
+		}
+
+		// 使用不同的键，避免第二次调用复用第一次仍在执行的加载
+		var result string
+		assert.ErrorIs(t, c.Get(ctx, "slow:default", &result, WithLoader(loader)), errors.ErrLoaderTimeout)
+		assert.NoError(t, c.Get(ctx, "slow:call", &result, WithLoader(loader), WithLoaderTimeout(time.Second, false)), "单次调用的超时时间优先")
+		assert.Equal(t, "slow", result)
+	})
+
+	t.Run("无效的超时时间", func(t *testing.T) {
+		c := createTestCache(t)
+		var result string
+		assert.ErrorIs(t, c.Get(ctx, "key", &result, WithLoaderTimeout(-time.Second, false)), errors.ErrInvalidLoaderTimeout)
+
+		_, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigDefaultLoaderTimeout(-time.Second))
+		assert.ErrorIs(t, err, errors.ErrInvalidLoaderTimeout)
+	})
+}
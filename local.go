@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/maypok86/otter"
+	"golang.org/x/sync/singleflight"
+)
+
+// LocalCache 只在进程内缓存的对象缓存，直接保存对象本身，读写都不经过序列化
+// 适用于读取频繁、构造代价高且允许各实例短暂不一致的对象（例如配置、路由表）
+// 保存的对象在所有调用方之间共享，可变对象需要通过 WithClone 设置拷贝函数，避免调用方修改缓存中的对象
+type LocalCache[ID comparable, T any] struct {
+	client otter.Cache[ID, T]
+
+	// 对象的拷贝函数，为 nil 表示直接返回缓存中的对象
+	clone func(T) T
+
+	sf singleflight.Group
+}
+
+// LocalOption LocalCache 的配置
+type LocalOption[T any] interface {
+	applyLocal(*localOptions[T])
+}
+
+// localOptions LocalCache 配置
+type localOptions[T any] struct {
+	clone func(T) T
+}
+
+// withClone 设置对象的拷贝函数
+type withClone[T any] struct {
+	clone func(T) T
+}
+
+func (w withClone[T]) applyLocal(opts *localOptions[T]) {
+	opts.clone = w.clone
+}
+
+// WithClone 设置对象的拷贝函数，Set 写入和 Get 返回时都会拷贝，调用方持有的对象与缓存中的对象互不影响
+// 拷贝只发生在内存中，不经过序列化，适用于缓存 map、slice 或含指针字段的结构体
+func WithClone[T any](clone func(T) T) LocalOption[T] {
+	return withClone[T]{clone: clone}
+}
+
+// NewLocal 创建进程内对象缓存，capacity 为最多缓存的对象数量，ttl 为对象的过期时间
+func NewLocal[ID comparable, T any](capacity int, ttl time.Duration, opts ...LocalOption[T]) (*LocalCache[ID, T], error) {
+	if capacity <= 0 || ttl <= 0 {
+		return nil, errors.ErrInvalidLocalConfig
+	}
+
+	config := &localOptions[T]{}
+	for _, opt := range opts {
+		opt.applyLocal(config)
+	}
+
+	client, err := otter.MustBuilder[ID, T](capacity).WithTTL(ttl).Build()
+	if err != nil {
+		return nil, fmt.Errorf("local create: capacity %d: %w", capacity, err)
+	}
+	return &LocalCache[ID, T]{client: client, clone: config.clone}, nil
+}
+
+// Get 获取对象，未命中时调用 loader 加载并缓存，loader 为 nil 时返回 ErrNotFound
+// 同一 ID 的并发加载通过 singleflight 合并
+func (c *LocalCache[ID, T]) Get(ctx context.Context, id ID, loader TypedLoaderFunc[ID, T]) (T, error) {
+	if value, ok := c.client.Get(id); ok {
+		return c.copy(value), nil
+	}
+
+	var zero T
+	if loader == nil {
+		return zero, errors.ErrNotFound
+	}
+
+	result, err, _ := c.sf.Do(fmt.Sprint(id), func() (any, error) {
+		value, err := loader(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		value = c.copy(value)
+		c.client.Set(id, value)
+		return value, nil
+	})
+	if err != nil {
+		return zero, err
+	}
+	return c.copy(result.(T)), nil
+}
+
+// Set 缓存对象，设置了拷贝函数时缓存的是 value 的拷贝
+func (c *LocalCache[ID, T]) Set(id ID, value T) {
+	c.client.Set(id, c.copy(value))
+}
+
+// Delete 删除对象
+func (c *LocalCache[ID, T]) Delete(id ID) {
+	c.client.Delete(id)
+}
+
+// Close 停止后台清理协程并清空缓存
+func (c *LocalCache[ID, T]) Close() {
+	c.client.Close()
+}
+
+// copy 使用拷贝函数复制对象，未设置时原样返回
+func (c *LocalCache[ID, T]) copy(value T) T {
+	if c.clone == nil {
+		return value
+	}
+	return c.clone(value)
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("配置校验", func(t *testing.T) {
+		_, err := NewLocal[string, int](0, time.Minute)
+		assert.ErrorIs(t, err, errors.ErrInvalidLocalConfig)
+	})
+
+	t.Run("loader 加载并缓存", func(t *testing.T) {
+		c, err := NewLocal[int64, string](100, time.Minute)
+		assert.NoError(t, err)
+		defer c.Close()
+
+		_, err = c.Get(ctx, 1, nil)
+		assert.ErrorIs(t, err, errors.ErrNotFound)
+
+		calls := 0
+		loader := func(ctx context.Context, id int64) (string, error) {
+			calls++
+			return "alice", nil
+		}
+		for range 2 {
+			value, err := c.Get(ctx, 1, loader)
+			assert.NoError(t, err)
+			assert.Equal(t, "alice", value)
+		}
+		assert.Equal(t, 1, calls)
+
+		c.Delete(1)
+		_, err = c.Get(ctx, 1, nil)
+		assert.ErrorIs(t, err, errors.ErrNotFound)
+	})
+
+	t.Run("未设置拷贝时共享对象", func(t *testing.T) {
+		c, err := NewLocal[string, map[string]int](100, time.Minute)
+		assert.NoError(t, err)
+		defer c.Close()
+
+		c.Set("k", map[string]int{"a": 1})
+		value, _ := c.Get(ctx, "k", nil)
+		value["a"] = 2
+
+		value, _ = c.Get(ctx, "k", nil)
+		assert.Equal(t, 2, value["a"])
+	})
+
+	t.Run("WithClone 防止修改缓存中的对象", func(t *testing.T) {
+		c, err := NewLocal[string, map[string]int](100, time.Minute, WithClone(maps.Clone[map[string]int]))
+		assert.NoError(t, err)
+		defer c.Close()
+
+		original := map[string]int{"a": 1}
+		c.Set("k", original)
+		original["a"] = 2
+
+		value, _ := c.Get(ctx, "k", nil)
+		assert.Equal(t, 1, value["a"])
+		value["a"] = 3
+
+		value, _ = c.Get(ctx, "k", nil)
+		assert.Equal(t, 1, value["a"])
+
+		loaded, err := c.Get(ctx, "l", func(ctx context.Context, id string) (map[string]int, error) {
+			return map[string]int{"b": 1}, nil
+		})
+		assert.NoError(t, err)
+		loaded["b"] = 2
+		value, _ = c.Get(ctx, "l", nil)
+		assert.Equal(t, 1, value["b"])
+	})
+}
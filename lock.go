@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+)
+
+// lockKeySuffix 分布式锁键的后缀，与同名的缓存值分开存储
+const lockKeySuffix = "\x00lock"
+
+// lockKey 返回 key 对应的锁键
+func lockKey(key string) string {
+	return key + lockKeySuffix
+}
+
+// Unlock 释放锁，锁已过期或被其他持有者占用时返回 ErrLockLost
+type Unlock func(ctx context.Context) error
+
+// Lock 基于 Remote 获取分布式锁（SET NX PX），锁已被占用时立即返回 ErrLockHeld，不等待
+// 用于保证跨实例不能并发执行的 loader 等逻辑；锁在 ttl 后自动过期，持有时间应小于 ttl。
+// 释放时校验持有者令牌，不会误删已过期后被其他实例重新获取的锁。需要 Remote 实现 storage.ConditionalSetter 和 storage.CompareDeleter
+func (c *LayeredCache) Lock(ctx context.Context, key string, ttl time.Duration) (Unlock, error) {
+	scope, ctx := c.takeKeyContext(ctx)
+	if ttl <= 0 {
+		return nil, c.misuse(errors.ErrInvalidLockTTL)
+	}
+	return c.lock(ctx, scope.key(key), ttl)
+}
+
+// lock Lock 的实现，key 为已加上前缀的缓存键
+func (c *LayeredCache) lock(ctx context.Context, key string, ttl time.Duration) (Unlock, error) {
+	key = lockKey(key)
+
+	setter, ok := c.remote.(storage.ConditionalSetter)
+	if !ok {
+		return nil, errors.ErrOperationNotSupported
+	}
+	deleter, ok := c.remote.(storage.CompareDeleter)
+	if !ok {
+		return nil, errors.ErrOperationNotSupported
+	}
+
+	token, err := lockToken()
+	if err != nil {
+		return nil, err
+	}
+	err = c.remoteCall(ctx, func(ctx context.Context) (err error) {
+		ok, err = setter.SetNX(ctx, key, token, ttl)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.ErrLockHeld
+	}
+
+	return func(ctx context.Context) error {
+		var deleted bool
+		err := c.remoteCall(ctx, func(ctx context.Context) (err error) {
+			deleted, err = deleter.CompareAndDelete(ctx, key, token)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			return errors.ErrLockLost
+		}
+		return nil
+	}, nil
+}
+
+// lockToken 生成锁持有者的随机令牌
+func lockToken() ([]byte, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return []byte(hex.EncodeToString(b)), nil
+}
@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_Lock(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("互斥与释放", func(t *testing.T) {
+		c := createTestCache(t)
+
+		unlock, err := c.Lock(ctx, "job", time.Minute)
+		assert.NoError(t, err)
+
+		_, err = c.Lock(ctx, "job", time.Minute)
+		assert.ErrorIs(t, err, errors.ErrLockHeld)
+
+		assert.NoError(t, unlock(ctx))
+		assert.ErrorIs(t, unlock(ctx), errors.ErrLockLost)
+
+		unlock, err = c.Lock(ctx, "job", time.Minute)
+		assert.NoError(t, err)
+		assert.NoError(t, unlock(ctx))
+	})
+
+	t.Run("不影响同名缓存值", func(t *testing.T) {
+		c := createTestCache(t)
+		assert.NoError(t, c.Set(ctx, "job", "v"))
+
+		unlock, err := c.Lock(ctx, "job", time.Minute)
+		assert.NoError(t, err)
+		defer unlock(ctx)
+
+		var value string
+		assert.NoError(t, c.Get(ctx, "job", &value))
+		assert.Equal(t, "v", value)
+	})
+
+	t.Run("过期后被他人获取时不误删", func(t *testing.T) {
+		c := createTestCache(t).(*LayeredCache)
+		unlock, err := c.Lock(ctx, "job", time.Minute)
+		assert.NoError(t, err)
+
+		assert.NoError(t, c.remote.Delete(ctx, lockKey("job")))
+		other, err := c.Lock(ctx, "job", time.Minute)
+		assert.NoError(t, err)
+
+		assert.ErrorIs(t, unlock(ctx), errors.ErrLockLost)
+		assert.NoError(t, other(ctx))
+	})
+
+	t.Run("参数和适配器校验", func(t *testing.T) {
+		_, err := createTestCache(t).Lock(ctx, "job", 0)
+		assert.ErrorIs(t, err, errors.ErrInvalidLockTTL)
+
+		_, err = createMemoryOnlyCache(t).Lock(ctx, "job", time.Minute)
+		assert.ErrorIs(t, err, errors.ErrOperationNotSupported)
+	})
+}
@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+)
+
+// memorySet 写入内存缓存，返回被适配器拒绝的键数
+func (c *LayeredCache) memorySet(key string, data []byte, ttl time.Duration) int {
+	if c.memory.Set(key, data, ttl) > 0 {
+		return 0
+	}
+	c.stats.memoryRejects.Add(1)
+	return 1
+}
+
+// memoryMSet 批量写入内存缓存，返回被适配器拒绝的键数
+func (c *LayeredCache) memoryMSet(values map[string][]byte, ttl time.Duration) int {
+	rejected := len(values) - int(c.memory.MSet(values, ttl))
+	if rejected <= 0 {
+		return 0
+	}
+	c.stats.memoryRejects.Add(int64(rejected))
+	return rejected
+}
+
+// checkMemoryAdmission 开启 WithConfigStrictMemoryWrites 且有键被内存适配器拒绝时返回错误
+func (c *LayeredCache) checkMemoryAdmission(rejected int) error {
+	if rejected == 0 || !c.strictMemoryWrites {
+		return nil
+	}
+	return fmt.Errorf("%w: %d keys not admitted", errors.ErrMemoryRejected, rejected)
+}
@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_MemoryAdmission(t *testing.T) {
+	ctx := context.Background()
+	large := strings.Repeat("x", 100)
+
+	newCache := func(t *testing.T, opts ...Option) *LayeredCache {
+		memory, err := storage.NewShardedMap(64, storage.WithShards(1))
+		assert.NoError(t, err)
+		c, err := NewCache(append([]Option{WithConfigMemory(memory), WithConfigRemote(createRemoteAdapter(t))}, opts...)...)
+		assert.NoError(t, err)
+		return c.(*LayeredCache)
+	}
+
+	t.Run("默认只记录拒绝次数", func(t *testing.T) {
+		c := newCache(t)
+		assert.NoError(t, c.Set(ctx, "a", large))
+		assert.NoError(t, c.MSet(ctx, map[string]any{"b": large, "c": "small"}))
+		assert.Equal(t, int64(2), c.Stats().MemoryRejects)
+
+		var value string
+		assert.NoError(t, c.Get(ctx, "a", &value), "Remote 中仍有值")
+		assert.Equal(t, large, value)
+	})
+
+	t.Run("严格模式返回错误", func(t *testing.T) {
+		c := newCache(t, WithConfigStrictMemoryWrites(true))
+		assert.ErrorIs(t, c.Set(ctx, "a", large), errors.ErrMemoryRejected)
+		assert.ErrorIs(t, c.MSet(ctx, map[string]any{"b": large, "c": "small"}), errors.ErrMemoryRejected)
+		assert.NoError(t, c.Set(ctx, "d", "small"))
+
+		var value string
+		assert.NoError(t, c.Get(ctx, "b", &value))
+		assert.Equal(t, large, value)
+	})
+}
@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_MemoryCostFunc(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("配置校验", func(t *testing.T) {
+		freecache, err := storage.NewFreecache(1 << 20)
+		assert.NoError(t, err)
+		_, err = NewCache(WithConfigMemory(freecache), WithConfigMemoryCostFunc(func(key string, value []byte) int64 { return 1 }))
+		assert.ErrorIs(t, err, errors.ErrOperationNotSupported)
+	})
+
+	t.Run("按条目数限制内存容量", func(t *testing.T) {
+		memory, err := storage.NewShardedMap(2, storage.WithShards(1))
+		assert.NoError(t, err)
+		c, err := NewCache(WithConfigMemory(memory), WithConfigMemoryCostFunc(func(key string, value []byte) int64 { return 1 }))
+		assert.NoError(t, err)
+
+		for _, key := range []string{"a", "b", "c"} {
+			assert.NoError(t, c.Set(ctx, key, "value"))
+		}
+		assert.Equal(t, 2, memory.Stats().Entries)
+
+		var value string
+		assert.NoError(t, c.Get(ctx, "c", &value))
+		assert.Equal(t, "value", value)
+	})
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// MetricsCollector 缓存指标采集接口，用于对接 Prometheus 等监控系统
+// 方法在缓存操作的调用链路上同步执行，实现需要并发安全且尽量轻量
+type MetricsCollector interface {
+	// ObserveGet 记录一次单键读取，layer 为 LayerMemory 或 LayerRemote，命中缺失值标记也视为命中
+	ObserveGet(layer Layer, hit bool, dur time.Duration, err error)
+
+	// ObserveMGet 记录一次批量读取，keys 为读取的键数量，hits 为命中的键数量
+	ObserveMGet(layer Layer, keys, hits int, dur time.Duration, err error)
+
+	// ObserveLoad 记录一次 loader / batchLoader 调用，keys 为加载的键数量
+	ObserveLoad(keys int, dur time.Duration, err error)
+
+	// ObserveWrite 记录一次 Remote 写入，op 为 set、mset、delete 或 mdelete
+	ObserveWrite(op string, layer Layer, keys int, dur time.Duration, err error)
+
+	// ObserveSingleflight 记录一次经过 singleflight 的加载，shared 表示本次请求没有执行加载，复用了其他并发请求的结果
+	ObserveSingleflight(shared bool)
+}
+
+// observer 将缓存交互同时上报给请求级的 Recorder 和实例级的 MetricsCollector
+type observer struct {
+	rec      *Recorder
+	metrics  MetricsCollector
+	labeled  LabeledCollector
+	prefixes prefixAllowList
+
+	// span 当前操作的 span，未开启追踪时为 nil
+	span trace.Span
+}
+
+// observe 返回当前请求的 observer
+func (c *LayeredCache) observe(ctx context.Context) observer {
+	o := observer{rec: recorderFrom(ctx), metrics: c.metrics, labeled: c.labeled, prefixes: c.metricsPrefixes}
+	if c.tracer != nil {
+		o.span = trace.SpanFromContext(ctx)
+	}
+	return o
+}
+
+// active 是否需要记录交互
+func (o observer) active() bool {
+	return o.rec != nil || o.metrics != nil || o.span != nil
+}
+
+// now 返回交互开始时间，不需要记录时返回零值以避免多余的开销
+func (o observer) now() time.Time {
+	if !o.active() {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// record 记录一次交互
+func (o observer) record(op string, layer Layer, keys []string, hits int, start time.Time, err error) {
+	o.rec.record(op, layer, keys, hits, start, err)
+	if o.span != nil {
+		traceLayer(o.span, op, layer, len(keys), hits, err)
+	}
+	if o.metrics == nil {
+		return
+	}
+
+	dur := time.Since(start)
+	if o.labeled != nil {
+		o.labeled.Observe(MetricLabels{Layer: layer, Op: op, Prefix: o.prefixes.labelKeys(keys)}, len(keys), hits, dur, err)
+		return
+	}
+
+	switch {
+	case layer == LayerLoader:
+		o.metrics.ObserveLoad(len(keys), dur, err)
+	case op == "get":
+		o.metrics.ObserveGet(layer, hits > 0, dur, err)
+	case op == "mget":
+		o.metrics.ObserveMGet(layer, len(keys), hits, dur, err)
+	default:
+		o.metrics.ObserveWrite(op, layer, len(keys), dur, err)
+	}
+}
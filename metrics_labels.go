@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// 标准化的指标名称，对接 Prometheus 等监控系统时使用，与 dashboards 目录中的看板保持一致
+const (
+	// MetricOperations 缓存交互次数（counter）
+	MetricOperations = "layered_cache_operations_total"
+
+	// MetricKeys 缓存交互涉及的键数量（counter）
+	MetricKeys = "layered_cache_keys_total"
+
+	// MetricHits 命中的键数量（counter），写入操作不计入
+	MetricHits = "layered_cache_hits_total"
+
+	// MetricErrors 缓存交互出错的次数（counter），ErrNotFound 不计入
+	MetricErrors = "layered_cache_errors_total"
+
+	// MetricDuration 缓存交互耗时（histogram，单位秒）
+	MetricDuration = "layered_cache_operation_duration_seconds"
+
+	// MetricSingleflight 经过 singleflight 的加载次数（counter），按 LabelShared 区分
+	MetricSingleflight = "layered_cache_singleflight_total"
+)
+
+// 标准化的指标标签
+const (
+	// LabelLayer 交互发生的层：memory、remote、loader
+	LabelLayer = "layer"
+
+	// LabelOp 发起交互的操作：get、mget、set、mset、delete、mdelete
+	LabelOp = "op"
+
+	// LabelPrefix 键前缀，只会是 WithConfigMetricsPrefixes 中的前缀、PrefixOther 或 PrefixMixed
+	LabelPrefix = "prefix"
+
+	// LabelShared singleflight 是否复用了其他请求的结果：true、false
+	LabelShared = "shared"
+)
+
+const (
+	// PrefixOther 键不匹配任何允许的前缀，或没有配置前缀
+	PrefixOther = "other"
+
+	// PrefixMixed 批量操作中的键属于不同的前缀
+	PrefixMixed = "mixed"
+)
+
+// MetricLabels 一次缓存交互的标准化标签
+type MetricLabels struct {
+	Layer  Layer
+	Op     string
+	Prefix string
+}
+
+// LabeledCollector 按标准化标签上报的指标采集接口
+// MetricsCollector 同时实现该接口时，缓存交互只通过 Observe 上报，不再调用 ObserveGet 等方法
+type LabeledCollector interface {
+	// Observe 记录一次缓存交互，keys 为涉及的键数量，hits 为命中的键数量，写入操作为 0
+	Observe(labels MetricLabels, keys, hits int, dur time.Duration, err error)
+}
+
+// prefixAllowList 指标前缀白名单，防止按原始键生成标签导致基数爆炸
+type prefixAllowList []string
+
+// newPrefixAllowList 创建前缀白名单，按长度降序排列以便优先匹配最长的前缀
+func newPrefixAllowList(prefixes []string) prefixAllowList {
+	list := make(prefixAllowList, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		if prefix != "" {
+			list = append(list, prefix)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return len(list[i]) > len(list[j])
+	})
+	return list
+}
+
+// label 返回 key 匹配的最长前缀，不匹配时返回 PrefixOther
+func (l prefixAllowList) label(key string) string {
+	for _, prefix := range l {
+		if strings.HasPrefix(key, prefix) {
+			return prefix
+		}
+	}
+	return PrefixOther
+}
+
+// labelKeys 返回一组键的前缀标签，属于不同前缀时返回 PrefixMixed
+func (l prefixAllowList) labelKeys(keys []string) string {
+	if len(keys) == 0 || len(l) == 0 {
+		return PrefixOther
+	}
+
+	label := l.label(keys[0])
+	for _, key := range keys[1:] {
+		if l.label(key) != label {
+			return PrefixMixed
+		}
+	}
+	return label
+}
@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// labeledRecordingCollector 同时实现 LabeledCollector 的 recordingCollector
+type labeledRecordingCollector struct {
+	recordingCollector
+}
+
+func (r *labeledRecordingCollector) Observe(labels MetricLabels, keys, hits int, dur time.Duration, err error) {
+	r.add("%s %s prefix=%s keys=%d hits=%d err=%v", labels.Op, labels.Layer, labels.Prefix, keys, hits, err)
+}
+
+func TestPrefixAllowList(t *testing.T) {
+	list := newPrefixAllowList([]string{"user:", "", "user:vip:", "order:"})
+	assert.Equal(t, prefixAllowList{"user:vip:", "order:", "user:"}, list)
+
+	assert.Equal(t, "user:", list.label("user:1"))
+	assert.Equal(t, "user:vip:", list.label("user:vip:1"))
+	assert.Equal(t, PrefixOther, list.label("video:1"))
+
+	assert.Equal(t, "user:", list.labelKeys([]string{"user:1", "user:2"}))
+	assert.Equal(t, PrefixMixed, list.labelKeys([]string{"user:1", "order:1"}))
+	assert.Equal(t, PrefixOther, list.labelKeys(nil))
+	assert.Equal(t, PrefixOther, newPrefixAllowList(nil).labelKeys([]string{"user:1"}))
+}
+
+func TestLayeredCache_LabeledCollector(t *testing.T) {
+	ctx := context.Background()
+	collector := &labeledRecordingCollector{}
+	c, err := NewCache(
+		WithConfigMemory(createMemoryAdapter(t)),
+		WithConfigRemote(createRemoteAdapter(t)),
+		WithConfigMetricsCollector(collector),
+		WithConfigMetricsPrefixes("user:", "order:"),
+	)
+	assert.NoError(t, err)
+
+	var result string
+	assert.NoError(t, c.Set(ctx, "user:1", "v"))
+	assert.NoError(t, c.Get(ctx, "user:1", &result))
+	values := make(map[string]string)
+	assert.NoError(t, c.MGet(ctx, []string{"user:1", "order:1"}, &values))
+	assert.NoError(t, c.Delete(ctx, fmt.Sprintf("session:%d", 1)))
+
+	assert.Equal(t, []string{
+		"set remote prefix=user: keys=1 hits=0 err=<nil>",
+		"get memory prefix=user: keys=1 hits=1 err=<nil>",
+		"mget memory prefix=mixed keys=2 hits=1 err=<nil>",
+		"mget remote prefix=order: keys=1 hits=0 err=<nil>",
+		"delete remote prefix=other keys=1 hits=0 err=<nil>",
+	}, collector.take())
+}
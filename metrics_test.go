@@ -0,0 +1,101 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingCollector 以文本记录上报的指标
+type recordingCollector struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recordingCollector) add(format string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingCollector) ObserveGet(layer Layer, hit bool, dur time.Duration, err error) {
+	r.add("get %s hit=%v err=%v", layer, hit, err)
+}
+
+func (r *recordingCollector) ObserveMGet(layer Layer, keys, hits int, dur time.Duration, err error) {
+	r.add("mget %s keys=%d hits=%d err=%v", layer, keys, hits, err)
+}
+
+func (r *recordingCollector) ObserveLoad(keys int, dur time.Duration, err error) {
+	r.add("load keys=%d err=%v", keys, err)
+}
+
+func (r *recordingCollector) ObserveWrite(op string, layer Layer, keys int, dur time.Duration, err error) {
+	r.add("%s %s keys=%d err=%v", op, layer, keys, err)
+}
+
+func (r *recordingCollector) ObserveSingleflight(shared bool) {
+	r.add("singleflight shared=%v", shared)
+}
+
+func (r *recordingCollector) take() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := r.events
+	r.events = nil
+	return events
+}
+
+func TestLayeredCache_MetricsCollector(t *testing.T) {
+	ctx := context.Background()
+	collector := &recordingCollector{}
+	c, err := NewCache(
+		WithConfigMemory(createMemoryAdapter(t)),
+		WithConfigRemote(createRemoteAdapter(t)),
+		WithConfigMetricsCollector(collector),
+	)
+	assert.NoError(t, err)
+
+	loader := func(ctx context.Context, key string) (any, error) {
+		return "loaded", nil
+	}
+	batchLoader := func(ctx context.Context, keys []string) (map[string]any, error) {
+		return map[string]any{"b": "loaded"}, nil
+	}
+
+	t.Run("单键读写", func(t *testing.T) {
+		var result string
+		assert.NoError(t, c.Set(ctx, "a", "v"))
+		assert.NoError(t, c.Get(ctx, "a", &result))
+		assert.NoError(t, c.Get(ctx, "x", &result, WithLoader(loader)))
+		assert.NoError(t, c.Delete(ctx, "a"))
+
+		assert.Equal(t, []string{
+			"set remote keys=1 err=<nil>",
+			"get memory hit=true err=<nil>",
+			"get memory hit=false err=<nil>",
+			"get remote hit=false err=<nil>",
+			"load keys=1 err=<nil>",
+			"singleflight shared=false",
+			"delete remote keys=1 err=<nil>",
+		}, collector.take())
+	})
+
+	t.Run("批量读写", func(t *testing.T) {
+		assert.NoError(t, c.MSet(ctx, map[string]any{"m1": "v", "m2": "v"}))
+		values := make(map[string]string)
+		assert.NoError(t, c.MGet(ctx, []string{"m1", "b"}, &values, WithBatchLoader(batchLoader)))
+
+		assert.Equal(t, []string{
+			"mset remote keys=2 err=<nil>",
+			"mget memory keys=2 hits=1 err=<nil>",
+			"mget remote keys=1 hits=0 err=<nil>",
+			"load keys=1 err=<nil>",
+			"singleflight shared=false",
+		}, collector.take())
+	})
+}
@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"context"
+	"reflect"
+)
+
+// MGetWithMissing 与 MGet 相同，同时按请求的顺序返回所有缓存层和 batchLoader 都没有值的键（包括命中缺失值标记的键），
+// 调用方不再需要自行比对请求的键和结果；重复的键只返回一次
+func (c *LayeredCache) MGetWithMissing(ctx context.Context, keys []string, target any, opts ...GetOption) ([]string, error) {
+	if err := c.MGet(ctx, keys, target, opts...); err != nil {
+		return nil, err
+	}
+	return missingKeys(keys, target), nil
+}
+
+// missingKeys 返回 keys 中不在 target 指向的 map 里的键
+func missingKeys(keys []string, target any) []string {
+	values := reflect.ValueOf(target).Elem()
+	keyType := values.Type().Key()
+
+	var missing []string
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		if !values.MapIndex(reflect.ValueOf(key).Convert(keyType)).IsValid() {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_MGetWithMissing(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("返回所有层和 loader 都没有值的键", func(t *testing.T) {
+		c := createTestCache(t)
+		assert.NoError(t, c.Set(ctx, "a", "1"))
+
+		values := make(map[string]string)
+		missing, err := c.MGetWithMissing(ctx, []string{"a", "b", "c", "b"}, &values, WithCacheNotFound(true, time.Minute), WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+			return map[string]any{"c": "3"}, nil
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"a": "1", "c": "3"}, values)
+		assert.Equal(t, []string{"b"}, missing)
+
+		// 命中缺失值标记的键同样返回
+		values = make(map[string]string)
+		missing, err = c.MGetWithMissing(ctx, []string{"b"}, &values)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"b"}, missing)
+	})
+
+	t.Run("键前缀隔离", func(t *testing.T) {
+		c := createTestCache(t)
+		scoped := WithKeyContext(ctx, "tenant:")
+		assert.NoError(t, c.Set(scoped, "a", "1"))
+
+		values := make(map[string]string)
+		missing, err := c.MGetWithMissing(scoped, []string{"a", "b"}, &values)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"b"}, missing)
+	})
+}
@@ -0,0 +1,203 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+)
+
+// FetchRequest MultiFetch 中的单个批量读取请求
+type FetchRequest struct {
+	// Keys 需要读取的键
+	Keys []string
+
+	// Target 结果写入的目标，必须是指向 map[string]T 的指针
+	Target any
+
+	// Options 该请求的读取选项，batchLoader、TTL 等仅对该请求生效
+	Options []GetOption
+}
+
+// FetchResult MultiFetch 中单个请求的结果，与请求一一对应
+type FetchResult struct {
+	// Err 该请求自身的错误，例如选项无效、target 类型错误、batchLoader 或反序列化失败
+	Err error
+}
+
+// fetchLookup 影响缓存层读取的选项，选项相同的请求共用一次内存遍历和一次 Remote MGET
+type fetchLookup struct {
+	layerSelection
+	maxAge         time.Duration
+	reloadNotFound bool
+	memoryTTL      time.Duration
+}
+
+// fetchGroup 一组共用读取的请求及其读取结果
+type fetchGroup struct {
+	lookup fetchLookup
+	keys   []string
+	seen   map[string]struct{}
+
+	found    map[string][]byte
+	stale    map[string][]byte
+	missing  map[string]struct{}
+	notFound map[string]struct{}
+}
+
+// MultiFetch 合并多个批量读取请求，读取选项（跳过的缓存层、WithMaxAge、WithReloadNotFound、写回内存的 TTL）相同的请求
+// 共用一次内存遍历和一次 Remote MGET；与 MGet 一样经过拦截器和追踪，操作名为 mget
+// 仅当缓存层本身出错时返回 error，单个请求的错误记录在对应的 FetchResult 中
+func (c *LayeredCache) MultiFetch(ctx context.Context, requests []FetchRequest) ([]FetchResult, error) {
+	if scope, ctx := c.takeKeyContext(ctx); !scope.empty() {
+		scoped := make([]FetchRequest, len(requests))
+		unscopes := make([]func(string) string, len(requests))
+		for i, request := range requests {
+			unscopes[i] = scope.unscoper(request.Keys)
+			scoped[i] = FetchRequest{Keys: scopeKeys(scope, request.Keys), Target: request.Target, Options: scopeGetOptions(scope, unscopes[i], request.Options)}
+		}
+		results, err := c.interceptFetch(ctx, scoped)
+		for i, result := range results {
+			if result.Err == nil {
+				unscopeTarget(unscopes[i], requests[i].Target)
+			}
+		}
+		return results, err
+	}
+	return c.interceptFetch(ctx, requests)
+}
+
+// interceptFetch 经过拦截器执行 multiFetch
+func (c *LayeredCache) interceptFetch(ctx context.Context, requests []FetchRequest) ([]FetchResult, error) {
+	var keys []string
+	for _, request := range requests {
+		keys = append(keys, request.Keys...)
+	}
+
+	var results []FetchResult
+	err := c.intercept(ctx, Operation{Name: "mget", Keys: keys}, func(ctx context.Context) (err error) {
+		results, err = c.multiFetch(ctx, requests)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// multiFetch MultiFetch 的实现，不经过拦截器
+func (c *LayeredCache) multiFetch(ctx context.Context, requests []FetchRequest) ([]FetchResult, error) {
+	results := make([]FetchResult, len(requests))
+	configs := make([]*getOptions, len(requests))
+	groups := make([]*fetchGroup, len(requests))
+
+	var lookups []*fetchGroup
+	byLookup := make(map[fetchLookup]*fetchGroup)
+	for i, request := range requests {
+		config := newGetOptions()
+		if err := applyGetOptions(config, request.Options...); err != nil {
+			results[i].Err = c.misuse(err)
+			continue
+		}
+		c.checkLayerTTL(config.memoryTTL, config.remoteTTL)
+		if err := c.validateMGetTarget(request.Target); err != nil {
+			results[i].Err = c.misuse(err)
+			continue
+		}
+		if config.maxAge > 0 && !c.envelope {
+			results[i].Err = c.misuse(errors.ErrEnvelopeRequired)
+			continue
+		}
+		configs[i] = config
+
+		memoryTTL, _ := c.calculateLoaderTTL(config)
+		lookup := fetchLookup{layerSelection: config.layerSelection, maxAge: config.maxAge, reloadNotFound: config.reloadNotFound, memoryTTL: memoryTTL}
+		group := byLookup[lookup]
+		if group == nil {
+			group = &fetchGroup{lookup: lookup, seen: make(map[string]struct{})}
+			byLookup[lookup] = group
+			lookups = append(lookups, group)
+		}
+		groups[i] = group
+
+		for _, key := range request.Keys {
+			if _, ok := group.seen[key]; ok {
+				continue
+			}
+			group.seen[key] = struct{}{}
+			group.keys = append(group.keys, key)
+		}
+	}
+
+	for _, group := range lookups {
+		if err := c.fetchGroup(ctx, group); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, request := range requests {
+		config, group := configs[i], groups[i]
+		if config == nil {
+			continue
+		}
+
+		result := make(map[string][]byte, len(request.Keys))
+		var missingKeys []string
+		for _, key := range request.Keys {
+			if data, ok := group.found[key]; ok {
+				result[key] = data
+			} else if _, ok = group.missing[key]; ok {
+				missingKeys = append(missingKeys, key)
+			} else if _, ok = group.notFound[key]; ok {
+				config.reportNotFoundCached(key)
+			}
+		}
+
+		loadedData, err := c.batchLoad(ctx, missingKeys, config)
+		if err != nil && !c.serveStaleBatch(result, group.stale, missingKeys, config) {
+			results[i].Err = err
+			continue
+		}
+		for key, data := range loadedData {
+			result[key] = data
+		}
+		if config.batchLoader == nil {
+			c.serveStaleBatch(result, group.stale, missingKeys, config)
+		}
+
+		if len(result) == 0 {
+			continue
+		}
+		results[i].Err = c.unmarshalBatch(result, request.Target, config.serializer)
+	}
+
+	return results, nil
+}
+
+// fetchGroup 按组的读取选项读取组内所有键
+func (c *LayeredCache) fetchGroup(ctx context.Context, group *fetchGroup) error {
+	group.notFound = make(map[string]struct{})
+	if len(group.keys) == 0 {
+		return nil
+	}
+
+	config := newGetOptions()
+	config.layerSelection = group.lookup.layerSelection
+	config.maxAge = group.lookup.maxAge
+	config.reloadNotFound = group.lookup.reloadNotFound
+	config.memoryTTL = &group.lookup.memoryTTL
+	config.notFoundCached = func(key string) {
+		group.notFound[key] = struct{}{}
+	}
+
+	found, stale, missingKeys, err := c.batchLookup(ctx, group.keys, config)
+	if err != nil {
+		return err
+	}
+	group.found, group.stale = found, stale
+	group.missing = make(map[string]struct{}, len(missingKeys))
+	for _, key := range missingKeys {
+		group.missing[key] = struct{}{}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingRemote 统计 Remote 调用次数
+type countingRemote struct {
+	storage.Remote
+	mgetCalls atomic.Int32
+}
+
+func (r *countingRemote) MGet(ctx context.Context, keys []string) (map[string][]byte, error) {
+	r.mgetCalls.Add(1)
+	return r.Remote.MGet(ctx, keys)
+}
+
+func TestLayeredCache_MultiFetch(t *testing.T) {
+	ctx := context.Background()
+
+	remote := &countingRemote{Remote: createRemoteAdapter(t)}
+	cache, err := NewCache(
+		WithConfigMemory(createOtterAdapter(t)),
+		WithConfigRemote(remote),
+	)
+	assert.NoError(t, err)
+	c := cache.(*LayeredCache)
+
+	assert.NoError(t, c.remote.Set(ctx, "user:1", []byte(`{"id":1,"name":"Alice"}`), 0))
+	assert.NoError(t, c.remote.Set(ctx, "video:1", []byte(`{"id":1,"title":"Intro"}`), 0))
+	assert.NoError(t, c.Set(ctx, "video:2", Video{ID: 2, Title: "Memory"}))
+
+	users := make(map[string]TestUser)
+	videos := make(map[string]Video)
+	results, err := c.MultiFetch(ctx, []FetchRequest{
+		{Keys: []string{"user:1", "user:2"}, Target: &users},
+		{
+			Keys:   []string{"video:1", "video:2", "video:3"},
+			Target: &videos,
+			Options: []GetOption{WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+				assert.Equal(t, []string{"video:3"}, keys)
+				return map[string]any{"video:3": Video{ID: 3, Title: "Loaded"}}, nil
+			})},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.NoError(t, results[0].Err)
+	assert.NoError(t, results[1].Err)
+
+	assert.Equal(t, map[string]TestUser{"user:1": {ID: 1, Name: "Alice"}}, users)
+	assert.Equal(t, map[string]Video{
+		"video:1": {ID: 1, Title: "Intro"},
+		"video:2": {ID: 2, Title: "Memory"},
+		"video:3": {ID: 3, Title: "Loaded"},
+	}, videos)
+
+	// 所有请求的键合并为一次 Remote MGET
+	assert.Equal(t, int32(1), remote.mgetCalls.Load())
+}
+
+func TestLayeredCache_MultiFetch_Options(t *testing.T) {
+	ctx := context.Background()
+
+	var ops []string
+	remote := &countingRemote{Remote: createRemoteAdapter(t)}
+	cache, err := NewCache(
+		WithConfigMemory(createOtterAdapter(t)),
+		WithConfigRemote(remote),
+		WithConfigInterceptor(func(ctx context.Context, op Operation, next Invoker) error {
+			ops = append(ops, op.Name)
+			return next(ctx)
+		}),
+	)
+	assert.NoError(t, err)
+	c := cache.(*LayeredCache)
+
+	// 内存中的值比 Remote 旧，跳过内存的请求应读到 Remote 中的值
+	c.memory.Set("user:1", []byte(`{"id":1,"name":"Old"}`), time.Hour)
+	assert.NoError(t, c.remote.Set(ctx, "user:1", []byte(`{"id":1,"name":"Alice"}`), 0))
+
+	cached := make(map[string]TestUser)
+	fresh := make(map[string]TestUser)
+	results, err := c.MultiFetch(ctx, []FetchRequest{
+		{Keys: []string{"user:1"}, Target: &cached},
+		{Keys: []string{"user:1"}, Target: &fresh, Options: []GetOption{WithSkipLayers(true, false)}},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, results[0].Err)
+	assert.NoError(t, results[1].Err)
+	assert.Equal(t, "Old", cached["user:1"].Name)
+	assert.Equal(t, "Alice", fresh["user:1"].Name)
+	assert.Equal(t, int32(1), remote.mgetCalls.Load(), "跳过内存的请求单独读取 Remote")
+	assert.Equal(t, []string{"mget"}, ops, "经过拦截器")
+}
+
+func TestLayeredCache_MultiFetch_RequestErrors(t *testing.T) {
+	ctx := context.Background()
+	c := createTestCache(t)
+	assert.NoError(t, c.Set(ctx, "k1", "v1"))
+
+	values := make(map[string]string)
+	results, err := c.MultiFetch(ctx, []FetchRequest{
+		{Keys: []string{"k1"}, Target: values},
+		{Keys: []string{"k1"}, Target: &values},
+		{Keys: []string{"k2"}, Target: &values, Options: []GetOption{WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+			return nil, errors.New("loader failed")
+		})}},
+	})
+	assert.NoError(t, err)
+	assert.ErrorIs(t, results[0].Err, errors.ErrInvalidMGetTarget)
+	assert.NoError(t, results[1].Err)
+	assert.EqualError(t, results[2].Err, "loader failed")
+	assert.Equal(t, map[string]string{"k1": "v1"}, values)
+}
+
+func TestTypedCache_Fetch(t *testing.T) {
+	ctx := context.Background()
+	c := createTestCache(t)
+
+	userCache := Typed[int, TestUser](c)
+	videoCache := Typed[int64, Video](c)
+	assert.NoError(t, userCache.Set(ctx, "user", 1, TestUser{ID: 1, Name: "Alice"}))
+	assert.NoError(t, videoCache.Set(ctx, "video", 10, Video{ID: 10, Title: "Intro"}))
+
+	users := userCache.Fetch("user", []int{1, 2}, nil)
+	videos := videoCache.Fetch("video", []int64{10, 11}, func(ctx context.Context, ids []int64) (map[int64]Video, error) {
+		assert.Equal(t, []int64{11}, ids)
+		return map[int64]Video{11: {ID: 11, Title: "Loaded"}}, nil
+	})
+
+	results, err := c.MultiFetch(ctx, []FetchRequest{users.Request(), videos.Request()})
+	assert.NoError(t, err)
+	for _, result := range results {
+		assert.NoError(t, result.Err)
+	}
+
+	assert.Equal(t, map[int]TestUser{1: {ID: 1, Name: "Alice"}}, users.Result())
+	assert.Equal(t, map[int64]Video{
+		10: {ID: 10, Title: "Intro"},
+		11: {ID: 11, Title: "Loaded"},
+	}, videos.Result())
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+)
+
+// notFoundKeySuffix 缺失值标记键的后缀
+// 缺失值标记与正常值分开存储，直接读取同名键的使用方不会把占位符当作数据
+const notFoundKeySuffix = "\x00nf"
+
+// notFoundPlaceholder 缺失值占位符
+// 以封装魔数开头且短于封装头，不会与序列化后的数据、字符串值或封装数据混淆
+var notFoundPlaceholder = []byte{envelopeMagic, envelopeVersion, envelopeFlagNotFound}
+
+// legacyNotFoundPlaceholder 旧版本写入的缺失值占位符
+// 滚动升级期间仍按缺失值处理，至少保留一个版本；在此期间恰好等于该字符串的值也会按缺失值处理
+var legacyNotFoundPlaceholder = []byte("__CACHE_NOT_FOUND__")
+
+// notFoundKey 返回 key 对应的缺失值标记键
+func notFoundKey(key string) string {
+	return key + notFoundKeySuffix
+}
+
+// notFoundKeys 返回 keys 对应的缺失值标记键
+func notFoundKeys(keys []string) []string {
+	markers := make([]string, len(keys))
+	for i, key := range keys {
+		markers[i] = notFoundKey(key)
+	}
+	return markers
+}
+
+// withNotFoundKeys 返回 keys 及其对应的缺失值标记键
+func withNotFoundKeys(keys []string) []string {
+	ret := make([]string, 0, len(keys)*2)
+	ret = append(ret, keys...)
+	for _, key := range keys {
+		ret = append(ret, notFoundKey(key))
+	}
+	return ret
+}
+
+// clearNotFound 写入新值后删除 keys 的缺失值标记，避免新值从内存淘汰后读取仍命中存活更久的标记
+// remote 为 false 时只删除内存中的标记
+func (c *LayeredCache) clearNotFound(ctx context.Context, keys []string, remote bool) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	markers := notFoundKeys(keys)
+	if c.memory != nil {
+		for _, marker := range markers {
+			c.memory.Delete(marker)
+		}
+	}
+	if !remote || c.remote == nil {
+		return nil
+	}
+	err := c.remoteCall(ctx, func(ctx context.Context) error {
+		return c.deleteRemote(ctx, markers)
+	})
+	return c.remoteFailed(err)
+}
+
+// isNotFoundPlaceholder 判断数据是否为缺失值占位符
+// 兼容旧版本写入的占位符
+func isNotFoundPlaceholder(data []byte) bool {
+	return bytes.Equal(data, notFoundPlaceholder) || bytes.Equal(data, legacyNotFoundPlaceholder)
+}
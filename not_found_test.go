@@ -0,0 +1,207 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotFoundKey(t *testing.T) {
+	assert.Equal(t, "user:1\x00nf", notFoundKey("user:1"))
+	assert.Equal(t, []string{"a", "b", "a\x00nf", "b\x00nf"}, withNotFoundKeys([]string{"a", "b"}))
+}
+
+func TestLayeredCache_NotFoundNamespace(t *testing.T) {
+	ctx := context.Background()
+	notFoundLoader := WithLoader(func(ctx context.Context, key string) (any, error) {
+		return nil, errors.ErrNotFound
+	})
+
+	newCache := func(t *testing.T) *LayeredCache {
+		c, err := NewCache(
+			WithConfigMemory(createOtterAdapter(t)),
+			WithConfigRemote(createRemoteAdapter(t)),
+			WithConfigDefaultCacheNotFound(true, time.Minute),
+		)
+		assert.NoError(t, err)
+		return c.(*LayeredCache)
+	}
+
+	t.Run("缺失值标记写入派生键，原始键保持为空", func(t *testing.T) {
+		c := newCache(t)
+		var result string
+		assert.ErrorIs(t, c.Get(ctx, "missing", &result, notFoundLoader), errors.ErrNotFound)
+
+		_, exists := c.memory.Get("missing")
+		assert.False(t, exists)
+		_, err := c.remote.Get(ctx, "missing")
+		assert.ErrorIs(t, err, errors.ErrNotFound)
+
+		data, exists := c.memory.Get(notFoundKey("missing"))
+		assert.True(t, exists)
+		assert.Equal(t, notFoundPlaceholder, data)
+		data, err = c.remote.Get(ctx, notFoundKey("missing"))
+		assert.NoError(t, err)
+		assert.Equal(t, notFoundPlaceholder, data)
+	})
+
+	t.Run("命中Redis中的缺失值标记不再调用loader", func(t *testing.T) {
+		c := newCache(t)
+		assert.NoError(t, c.remote.Set(ctx, notFoundKey("remote-missing"), notFoundPlaceholder, time.Minute))
+
+		called := false
+		var result string
+		err := c.Get(ctx, "remote-missing", &result, WithLoader(func(ctx context.Context, key string) (any, error) {
+			called = true
+			return "value", nil
+		}))
+		assert.ErrorIs(t, err, errors.ErrNotFound)
+		assert.False(t, called)
+	})
+
+	t.Run("兼容旧版本写在原始键上的占位符", func(t *testing.T) {
+		c := newCache(t)
+		assert.NoError(t, c.remote.Set(ctx, "legacy-missing", legacyNotFoundPlaceholder, time.Minute))
+
+		var result string
+		assert.ErrorIs(t, c.Get(ctx, "legacy-missing", &result), errors.ErrNotFound)
+
+		values := make(map[string]string)
+		assert.NoError(t, c.MGet(ctx, []string{"legacy-missing"}, &values))
+		assert.Empty(t, values)
+	})
+
+	t.Run("缓存缺失值后写入新值删除标记", func(t *testing.T) {
+		c := newCache(t)
+		var result string
+		assert.ErrorIs(t, c.Get(ctx, "later-set", &result, notFoundLoader), errors.ErrNotFound)
+		assert.ErrorIs(t, c.Get(ctx, "later-mset", &result, notFoundLoader), errors.ErrNotFound)
+
+		assert.NoError(t, c.Set(ctx, "later-set", "v"))
+		assert.NoError(t, c.MSet(ctx, map[string]any{"later-mset": "v"}))
+		for _, key := range []string{"later-set", "later-mset"} {
+			_, exists := c.memory.Get(notFoundKey(key))
+			assert.False(t, exists)
+			_, err := c.remote.Get(ctx, notFoundKey(key))
+			assert.ErrorIs(t, err, errors.ErrNotFound)
+
+			// 新值从内存淘汰后仍从 Remote 读取到新值
+			c.memory.Delete(key)
+			result = ""
+			assert.NoError(t, c.Get(ctx, key, &result))
+			assert.Equal(t, "v", result)
+		}
+	})
+
+	t.Run("WithReloadNotFound加载后删除标记", func(t *testing.T) {
+		c := newCache(t)
+		var result string
+		assert.ErrorIs(t, c.Get(ctx, "reloaded", &result, notFoundLoader), errors.ErrNotFound)
+
+		assert.NoError(t, c.Get(ctx, "reloaded", &result, WithReloadNotFound(true), WithLoader(func(ctx context.Context, key string) (any, error) {
+			return "v", nil
+		})))
+		_, err := c.remote.Get(ctx, notFoundKey("reloaded"))
+		assert.ErrorIs(t, err, errors.ErrNotFound)
+	})
+
+	t.Run("写入正常值后优先返回正常值", func(t *testing.T) {
+		c := newCache(t)
+		var result string
+		assert.ErrorIs(t, c.Get(ctx, "later-set", &result, notFoundLoader), errors.ErrNotFound)
+
+		assert.NoError(t, c.Set(ctx, "later-set", "value"))
+		assert.NoError(t, c.Get(ctx, "later-set", &result))
+		assert.Equal(t, "value", result)
+	})
+
+	t.Run("Delete同时删除缺失值标记", func(t *testing.T) {
+		c := newCache(t)
+		var result string
+		assert.ErrorIs(t, c.Get(ctx, "deleted", &result, notFoundLoader), errors.ErrNotFound)
+
+		assert.NoError(t, c.Delete(ctx, "deleted"))
+		_, exists := c.memory.Get(notFoundKey("deleted"))
+		assert.False(t, exists)
+		_, err := c.remote.Get(ctx, notFoundKey("deleted"))
+		assert.ErrorIs(t, err, errors.ErrNotFound)
+	})
+
+	t.Run("MGet识别派生键上的缺失值标记", func(t *testing.T) {
+		c := newCache(t)
+		assert.NoError(t, c.Set(ctx, "present", "value"))
+		assert.NoError(t, c.remote.Set(ctx, notFoundKey("absent"), notFoundPlaceholder, time.Minute))
+
+		called := false
+		values := make(map[string]string)
+		err := c.MGet(ctx, []string{"present", "absent"}, &values, WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+			called = true
+			return nil, nil
+		}))
+		assert.NoError(t, err)
+		assert.False(t, called)
+		assert.Equal(t, map[string]string{"present": "value"}, values)
+	})
+
+	t.Run("兼容旧版本写在标记键上的占位符", func(t *testing.T) {
+		c := newCache(t)
+		assert.NoError(t, c.remote.Set(ctx, notFoundKey("legacy-marker"), legacyNotFoundPlaceholder, time.Minute))
+
+		var result string
+		assert.ErrorIs(t, c.Get(ctx, "legacy-marker", &result), errors.ErrNotFoundCached)
+
+		values := make(map[string]string)
+		assert.NoError(t, c.MGet(ctx, []string{"legacy-marker"}, &values))
+		assert.Empty(t, values)
+	})
+
+	t.Run("命中缺失值标记返回ErrNotFoundCached", func(t *testing.T) {
+		c := newCache(t)
+		var result string
+		err := c.Get(ctx, "cached-miss", &result, notFoundLoader)
+		assert.ErrorIs(t, err, errors.ErrNotFound)
+		assert.NotErrorIs(t, err, errors.ErrNotFoundCached)
+
+		err = c.Get(ctx, "cached-miss", &result, notFoundLoader)
+		assert.ErrorIs(t, err, errors.ErrNotFoundCached)
+		assert.True(t, IsNotFound(err))
+
+		c.memory.Delete(notFoundKey("cached-miss"))
+		assert.ErrorIs(t, c.Get(ctx, "cached-miss", &result), errors.ErrNotFoundCached)
+	})
+
+	t.Run("WithReloadNotFound忽略缺失值标记重新加载", func(t *testing.T) {
+		c := newCache(t)
+		var result string
+		assert.ErrorIs(t, c.Get(ctx, "reload", &result, notFoundLoader), errors.ErrNotFound)
+
+		loader := WithLoader(func(ctx context.Context, key string) (any, error) {
+			return "loaded", nil
+		})
+		assert.ErrorIs(t, c.Get(ctx, "reload", &result, loader), errors.ErrNotFoundCached)
+		assert.NoError(t, c.Get(ctx, "reload", &result, loader, WithReloadNotFound(true)))
+		assert.Equal(t, "loaded", result)
+
+		result = ""
+		assert.NoError(t, c.Get(ctx, "reload", &result))
+		assert.Equal(t, "loaded", result)
+	})
+
+	t.Run("MGet使用WithReloadNotFound重新加载缺失值标记", func(t *testing.T) {
+		c := newCache(t)
+		assert.NoError(t, c.remote.Set(ctx, notFoundKey("absent"), notFoundPlaceholder, time.Minute))
+
+		var loaded []string
+		values := make(map[string]string)
+		err := c.MGet(ctx, []string{"absent"}, &values, WithReloadNotFound(true), WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+			loaded = keys
+			return map[string]any{"absent": "value"}, nil
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"absent"}, loaded)
+		assert.Equal(t, map[string]string{"absent": "value"}, values)
+	})
+}
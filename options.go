@@ -1,11 +1,13 @@
 package cache
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/biu7/layered-cache/errors"
 	"github.com/biu7/layered-cache/serializer"
 	"github.com/biu7/layered-cache/storage"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Option interface {
@@ -20,6 +22,12 @@ type options struct {
 	// Remote 缓存适配器
 	remoteAdapter storage.Remote
 
+	// invalidationTransport 跨实例失效消息的传输层，为 nil 表示不广播
+	invalidationTransport storage.InvalidationTransport
+
+	// layers 多级 Remote，创建时组合为 storage.Chain 作为 remoteAdapter
+	layers []storage.Layer
+
 	// serializer 序列化器
 	serializer serializer.Serializer
 
@@ -34,6 +42,123 @@ type options struct {
 
 	// defaultCacheNotFoundTTL 默认缺失值的缓存过期时间
 	defaultCacheNotFoundTTL time.Duration
+
+	// readRepairRate 内存命中后与 Remote 交叉校验的采样比例
+	readRepairRate float64
+
+	// deleteShieldTTL 删除后在本地视为不存在的窗口期
+	deleteShieldTTL time.Duration
+
+	// envelope 是否使用封装格式存储（记录写入时间等元数据）
+	envelope bool
+
+	// envelopeLegacyWrite 开启封装格式读取时，是否仍以未封装的旧格式写入（滚动升级兼容）
+	envelopeLegacyWrite bool
+
+	// demoteOnEvict 内存淘汰时是否将比 Remote 更新的条目写回 Remote
+	demoteOnEvict bool
+
+	// coalesceWrites 是否合并同一键的并发 Remote 写入
+	coalesceWrites bool
+
+	// expvarName 发布运行计数的 expvar 名称，为空表示不发布
+	expvarName string
+
+	// devMode 开发模式
+	devMode bool
+
+	// strictMemorySize Set 时是否拒绝超过内存条目大小上限的值
+	strictMemorySize bool
+
+	// strictMemoryWrites Set/MSet 时内存适配器拒绝写入是否返回错误
+	strictMemoryWrites bool
+
+	// memoryCostFunc 内存条目的成本函数，为 nil 表示使用适配器默认的键长度 + 值长度
+	memoryCostFunc func(key string, value []byte) int64
+
+	// dependencies 是否开启键依赖（级联删除）
+	dependencies bool
+
+	// metrics 指标采集，为 nil 表示不采集
+	metrics MetricsCollector
+
+	// metricsPrefixes 指标中允许作为 prefix 标签的键前缀
+	metricsPrefixes []string
+
+	// adaptiveBatch 自适应批量读取配置，为 nil 表示不拆分
+	adaptiveBatch *adaptiveBatchOption
+
+	// batchChunkSize 批量操作单次访问 Remote 和调用 batchLoader 的最大键数量，为 0 表示不拆分
+	batchChunkSize int
+
+	// remoteConcurrency 拆分后的 Remote 读写的最大并发数
+	remoteConcurrency int
+
+	// keyPrefix 缓存级键命名空间
+	keyPrefix string
+
+	// keyHasher 缓存级键哈希函数，为 nil 表示不哈希
+	keyHasher func(string) string
+
+	// siblingPrefetch 相邻键预取配置，为 nil 表示关闭
+	siblingPrefetch *siblingPrefetchOption
+
+	// hotKeys 热点键统计配置，为 nil 表示关闭
+	hotKeys *hotKeysOption
+
+	// watchInterval Watch 轮询 Remote 的间隔
+	watchInterval time.Duration
+
+	// maxValueSize 值大小上限，为 nil 表示不限制
+	maxValueSize *valueSizeLimit
+
+	// ttlJitter 写入时 TTL 的随机抖动比例
+	ttlJitter float64
+
+	// memoryTTLJitter 内存 TTL 是否也随机抖动
+	memoryTTLJitter bool
+
+	// valueMiddlewares 值字节的编解码中间件
+	valueMiddlewares []ValueMiddleware
+
+	// interceptors 调用拦截器
+	interceptors []Interceptor
+
+	// loaderPrefixes loader 熔断和限流按前缀隔离时使用的键前缀
+	loaderPrefixes []string
+
+	// loaderBreaker loader 熔断配置，为 nil 表示关闭
+	loaderBreaker *loaderBreakerOption
+
+	// remoteBreaker Remote 熔断配置，为 nil 表示关闭
+	remoteBreaker *loaderBreakerOption
+
+	// loaderRateLimit loader 限流配置，为 nil 表示关闭
+	loaderRateLimit *loaderRateLimitOption
+
+	// writeThrough Set/MSet 成功后的写穿回调，为 nil 表示关闭
+	writeThrough *writeThrough
+
+	// poisonThreshold 同一键连续反序列化失败多少次后删除，为 0 表示关闭
+	poisonThreshold int
+
+	// defaultLoaderTimeout 默认的 loader 超时时间，为 0 表示不限制
+	defaultLoaderTimeout time.Duration
+
+	// asyncRemoteWrites 异步 Remote 写入配置，为 nil 表示同步写入
+	asyncRemoteWrites *asyncRemoteWritesOption
+
+	// closeAdapters Close 时是否关闭内存和 Remote 适配器
+	closeAdapters bool
+
+	// refreshAhead 提前刷新配置，为 nil 表示关闭
+	refreshAhead *refreshAheadOption
+
+	// tracerProvider OpenTelemetry TracerProvider，为 nil 表示不追踪
+	tracerProvider trace.TracerProvider
+
+	// remoteFailurePolicy Remote 出错时的处理策略
+	remoteFailurePolicy RemoteFailurePolicy
 }
 
 type memoryAdapterOption struct {
@@ -60,6 +185,39 @@ func WithConfigRemote(adp storage.Remote) Option {
 	return remoteAdapterOption{adapter: adp}
 }
 
+// invalidationTransportOption 设置跨实例失效消息的传输层
+type invalidationTransportOption struct {
+	transport storage.InvalidationTransport
+}
+
+func (i invalidationTransportOption) apply(opts *options) {
+	opts.invalidationTransport = i.transport
+}
+
+// WithConfigInvalidationTransport 设置跨实例失效消息的传输层，Set/MSet/SetNX/GetSet/Delete/MDelete/Invalidate 成功后广播涉及的键，
+// 其他实例收到后删除这些键的内存缓存，下次读取时从 Remote 获取最新的值，避免各实例内存中的旧值保留到内存 TTL 到期。
+// 内置 storage.NewRedisPubSub 和 storage.NewNATS；使用 Kafka 等其他消息系统时实现 storage.InvalidationTransport 即可接入。
+// 广播失败不影响写入结果，只计入 Stats().InvalidationErrors；Close 时停止订阅
+func WithConfigInvalidationTransport(transport storage.InvalidationTransport) Option {
+	return invalidationTransportOption{transport: transport}
+}
+
+// layersOption 设置多级 Remote
+type layersOption struct {
+	layers []storage.Layer
+}
+
+func (l layersOption) apply(opts *options) {
+	opts.layers = l.layers
+}
+
+// WithConfigLayers 设置多级 Remote，例如内存 → Redis → S3/磁盘，内存层仍通过 WithConfigMemory 设置
+// 各层按顺序组合为 storage.Chain 作为 Remote：读取逐层查找并回填到之前的层，出错的层跳过，写入和删除作用于所有层，
+// 每层可以通过 Layer.TTL 设置自己的过期时间；不能与 WithConfigRemote 同时使用
+func WithConfigLayers(layers []storage.Layer) Option {
+	return layersOption{layers: layers}
+}
+
 type serializerOption struct {
 	serializer serializer.Serializer
 }
@@ -102,14 +260,659 @@ func WithConfigDefaultCacheNotFound(cacheNotFound bool, cacheNotFoundTTL time.Du
 	return defaultCacheNotFoundOption{cacheNotFound: cacheNotFound, cacheNotFoundTTL: cacheNotFoundTTL}
 }
 
+// readRepairOption 设置读修复采样比例
+type readRepairOption struct {
+	rate float64
+}
+
+func (r readRepairOption) apply(opts *options) {
+	opts.readRepairRate = r.rate
+}
+
+// WithConfigReadRepair 设置读修复采样比例
+// rate: 内存命中时以该比例回源 Remote 校验，取值范围 [0, 1]，0 表示关闭
+// 校验发现不一致时以 Remote 为准修复内存缓存
+func WithConfigReadRepair(rate float64) Option {
+	return readRepairOption{rate: rate}
+}
+
+// deleteShieldOption 设置删除保护窗口
+type deleteShieldOption struct {
+	ttl time.Duration
+}
+
+func (d deleteShieldOption) apply(opts *options) {
+	opts.deleteShieldTTL = d.ttl
+}
+
+// WithConfigDeleteShield 设置删除保护窗口
+// Delete 后的 ttl 时间内，该键在本地直接视为不存在，不再读取内存和 Remote 缓存，
+// 防止其他节点并发写回的旧数据在失效传播前被读到；0 表示关闭
+func WithConfigDeleteShield(ttl time.Duration) Option {
+	return deleteShieldOption{ttl: ttl}
+}
+
+// envelopeOption 设置是否使用封装格式存储
+type envelopeOption struct {
+	enabled bool
+}
+
+func (e envelopeOption) apply(opts *options) {
+	opts.envelope = e.enabled
+	opts.envelopeLegacyWrite = false
+}
+
+// WithConfigEnvelope 设置是否使用封装格式存储
+// 开启后缓存值带有写入时间等元数据，读取时兼容未封装的旧数据；
+// 未开启封装的实例无法读取封装格式的数据，同一份缓存的所有读写方需要保持一致，
+// 滚动升级时先通过 WithConfigEnvelopeCompat 过渡
+func WithConfigEnvelope(enabled bool) Option {
+	return envelopeOption{enabled: enabled}
+}
+
+// envelopeCompatOption 设置封装格式的滚动升级兼容模式
+type envelopeCompatOption struct {
+	writeLegacy bool
+}
+
+func (e envelopeCompatOption) apply(opts *options) {
+	opts.envelope = true
+	opts.envelopeLegacyWrite = e.writeLegacy
+}
+
+// WithConfigEnvelopeCompat 设置封装格式的滚动升级兼容模式，读取时同时识别封装格式和旧格式
+// writeLegacy: 是否仍以旧格式写入；新旧版本混合部署期间设为 true，保证旧版本实例能读取新版本写入的数据，
+// 所有实例升级完成后改为 false（等同于 WithConfigEnvelope(true)）开始写入封装格式
+// 以旧格式写入时没有写入时间等元数据，依赖这些元数据的功能（如 WithConfigDemoteOnEvict）不可用
+func WithConfigEnvelopeCompat(writeLegacy bool) Option {
+	return envelopeCompatOption{writeLegacy: writeLegacy}
+}
+
+// demoteOnEvictOption 设置内存淘汰时是否降级写回 Remote
+type demoteOnEvictOption struct {
+	enabled bool
+}
+
+func (d demoteOnEvictOption) apply(opts *options) {
+	opts.demoteOnEvict = d.enabled
+}
+
+// WithConfigDemoteOnEvict 设置内存因容量不足淘汰条目时，若条目比 Remote 中的副本更新则重新写回 Remote
+// 需要内存适配器实现 storage.EvictionNotifier，并开启封装格式（WithConfigEnvelope）用于比较写入时间；
+// 淘汰的条目放入有界队列由后台协程写回，队列已满时丢弃并计入 Stats().DemoteDrops
+func WithConfigDemoteOnEvict(enabled bool) Option {
+	return demoteOnEvictOption{enabled: enabled}
+}
+
+// coalesceWritesOption 设置是否合并同一键的并发写入
+type coalesceWritesOption struct {
+	enabled bool
+}
+
+func (o coalesceWritesOption) apply(opts *options) {
+	opts.coalesceWrites = o.enabled
+}
+
+// WithConfigCoalesceWrites 设置是否合并同一键的并发 Remote 写入
+// 开启后同一键同时只有一个 Set 写入 Remote，执行期间到达的写入只保留最后一个（latest-wins），
+// 被覆盖的 Set 返回最终写入的结果
+func WithConfigCoalesceWrites(enabled bool) Option {
+	return coalesceWritesOption{enabled: enabled}
+}
+
+// expvarOption 设置 expvar 发布名称
+type expvarOption struct {
+	name string
+}
+
+func (e expvarOption) apply(opts *options) {
+	opts.expvarName = e.name
+}
+
+// WithConfigExpvar 将运行计数（命中、未命中、加载次数等）以 name 发布到 expvar，
+// 引入 expvar 后即可通过 /debug/vars 查看；name 在进程内必须唯一。
+// expvar 不支持删除变量，Close 后该名称输出 null，并可由新的缓存复用
+func WithConfigExpvar(name string) Option {
+	return expvarOption{name: name}
+}
+
+// devModeOption 设置开发模式
+type devModeOption struct {
+	enabled bool
+}
+
+func (d devModeOption) apply(opts *options) {
+	opts.devMode = d.enabled
+}
+
+// WithConfigDevMode 设置开发模式，仅建议在开发和测试环境开启
+// 开启后误用（Get 的 target 不是指针、为未配置的缓存层设置 TTL、TypedCache 的 ID 包含分隔符、
+// 无效的选项等）会立即 panic 并给出说明；关闭时保持返回错误或忽略
+func WithConfigDevMode(enabled bool) Option {
+	return devModeOption{enabled: enabled}
+}
+
+// strictMemorySizeOption 设置是否拒绝超过内存条目大小上限的值
+type strictMemorySizeOption struct {
+	enabled bool
+}
+
+func (s strictMemorySizeOption) apply(opts *options) {
+	opts.strictMemorySize = s.enabled
+}
+
+// WithConfigStrictMemorySize 设置 Set/MSet 时是否拒绝超过内存条目大小上限的值
+// 内存适配器对单个条目有大小上限（例如 Otter 为容量的 10%），超过的值会被静默丢弃，只能读 Remote；
+// 开启后 Set/MSet 直接返回 ErrValueTooLarge 且不写入任何缓存层，需要内存适配器实现 storage.EntrySizeLimiter
+func WithConfigStrictMemorySize(enabled bool) Option {
+	return strictMemorySizeOption{enabled: enabled}
+}
+
+// strictMemoryWritesOption 设置内存适配器拒绝写入时是否返回错误
+type strictMemoryWritesOption struct {
+	enabled bool
+}
+
+func (s strictMemoryWritesOption) apply(opts *options) {
+	opts.strictMemoryWrites = s.enabled
+}
+
+// WithConfigStrictMemoryWrites 设置 Set/MSet 时内存适配器拒绝写入是否返回错误
+// Otter/Ristretto 在容量不足或准入策略拒绝时静默丢弃条目，热点值可能始终无法进入内存；
+// 开启后其他缓存层照常写入，最后返回包装 ErrMemoryRejected 的错误。未开启时只记录在 Stats().MemoryRejects 中
+func WithConfigStrictMemoryWrites(enabled bool) Option {
+	return strictMemoryWritesOption{enabled: enabled}
+}
+
+// memoryCostFuncOption 设置内存条目的成本函数
+type memoryCostFuncOption struct {
+	fn func(key string, value []byte) int64
+}
+
+func (m memoryCostFuncOption) apply(opts *options) {
+	opts.memoryCostFunc = m.fn
+}
+
+// WithConfigMemoryCostFunc 设置内存条目的成本函数，内存容量按成本之和限制，替代默认的键长度 + 值长度，
+// 例如加上反序列化后对象的额外开销，或返回 1 按条目数限制（容量即为条目数）；需要内存适配器实现 storage.CostSetter
+func WithConfigMemoryCostFunc(fn func(key string, value []byte) int64) Option {
+	return memoryCostFuncOption{fn: fn}
+}
+
+// dependenciesOption 设置是否开启键依赖
+type dependenciesOption struct {
+	enabled bool
+}
+
+func (d dependenciesOption) apply(opts *options) {
+	opts.dependencies = d.enabled
+}
+
+// WithConfigDependencies 设置是否开启键依赖
+// 开启后可以通过 DependOn 声明父子键的依赖关系（存储在 Remote 中），Delete 父键时级联删除所有子键；
+// 每次 Delete 会额外读取一次依赖集合，需要 Remote 适配器实现 storage.SetStore
+func WithConfigDependencies(enabled bool) Option {
+	return dependenciesOption{enabled: enabled}
+}
+
+// metricsCollectorOption 设置指标采集
+type metricsCollectorOption struct {
+	collector MetricsCollector
+}
+
+func (m metricsCollectorOption) apply(opts *options) {
+	opts.metrics = m.collector
+}
+
+// WithConfigMetricsCollector 设置指标采集，内存、Remote、loader 和 singleflight 的交互都会上报给 collector
+func WithConfigMetricsCollector(collector MetricsCollector) Option {
+	return metricsCollectorOption{collector: collector}
+}
+
+// metricsPrefixesOption 设置指标前缀白名单
+type metricsPrefixesOption struct {
+	prefixes []string
+}
+
+func (m metricsPrefixesOption) apply(opts *options) {
+	opts.metricsPrefixes = append(opts.metricsPrefixes, m.prefixes...)
+}
+
+// WithConfigMetricsPrefixes 设置 LabeledCollector 中允许作为 prefix 标签的键前缀，可多次调用
+// 键匹配最长的前缀，不匹配任何前缀时标签为 PrefixOther；未设置时所有键都为 PrefixOther，避免按原始键生成标签
+func WithConfigMetricsPrefixes(prefixes ...string) Option {
+	return metricsPrefixesOption{prefixes: prefixes}
+}
+
+// loaderPrefixesOption 设置 loader 熔断和限流的隔离前缀
+type loaderPrefixesOption struct {
+	prefixes []string
+}
+
+func (l loaderPrefixesOption) apply(opts *options) {
+	opts.loaderPrefixes = append(opts.loaderPrefixes, l.prefixes...)
+}
+
+// WithConfigLoaderPrefixes 设置 loader 熔断和限流按键前缀隔离，每个前缀有独立的熔断和限流状态，可多次调用
+// 键匹配最长的前缀，不匹配任何前缀的键共用一组状态；未设置时所有键共用一组状态
+func WithConfigLoaderPrefixes(prefixes ...string) Option {
+	return loaderPrefixesOption{prefixes: prefixes}
+}
+
+// loaderBreakerOption 设置 loader 熔断
+type loaderBreakerOption struct {
+	failures int
+	cooldown time.Duration
+}
+
+func (l loaderBreakerOption) apply(opts *options) {
+	opts.loaderBreaker = &l
+}
+
+// WithConfigLoaderBreaker 设置 loader / batchLoader 的熔断
+// 同一前缀的 loader 连续失败 failures 次后熔断 cooldown，期间该前缀的加载直接返回 ErrCircuitOpen（有旧值时按 WithServeStale 返回旧值）；
+// 冷却结束后只放行一个探测请求，成功则恢复，失败则继续熔断。ErrNotFound 不视为失败
+func WithConfigLoaderBreaker(failures int, cooldown time.Duration) Option {
+	return loaderBreakerOption{failures: failures, cooldown: cooldown}
+}
+
+// remoteBreakerOption 设置 Remote 熔断
+type remoteBreakerOption struct {
+	failures int
+	cooldown time.Duration
+}
+
+func (r remoteBreakerOption) apply(opts *options) {
+	opts.remoteBreaker = &loaderBreakerOption{failures: r.failures, cooldown: r.cooldown}
+}
+
+// WithConfigRemoteBreaker 设置 Remote 的熔断
+// Remote 连续出错或超时 failures 次后熔断 cooldown，期间 Get、MGet、Set、MSet 不再访问 Remote，直接按 ErrCircuitOpen 处理；
+// 配合 WithConfigRemoteFailurePolicy(FailOpen) 时绕过 Remote 只使用内存和 loader，否则直接返回 ErrCircuitOpen。
+// 冷却结束后只放行一个探测请求，成功则恢复，失败则继续熔断。ErrNotFound 不视为失败
+func WithConfigRemoteBreaker(failures int, cooldown time.Duration) Option {
+	return remoteBreakerOption{failures: failures, cooldown: cooldown}
+}
+
+// loaderRateLimitOption 设置 loader 限流
+type loaderRateLimitOption struct {
+	rate  float64
+	burst int
+}
+
+func (l loaderRateLimitOption) apply(opts *options) {
+	opts.loaderRateLimit = &l
+}
+
+// WithConfigLoaderRateLimit 设置每个前缀每秒最多实际调用 rate 次 loader / batchLoader，允许 burst 次突发
+// 超出的加载直接返回 ErrRateLimited，singleflight 合并的请求只计一次
+func WithConfigLoaderRateLimit(rate float64, burst int) Option {
+	return loaderRateLimitOption{rate: rate, burst: burst}
+}
+
+// writeThroughOption 设置写穿回调
+type writeThroughOption struct {
+	hook writeThrough
+}
+
+func (w writeThroughOption) apply(opts *options) {
+	opts.writeThrough = &w.hook
+}
+
+// WithConfigWriteThrough 设置 Set/MSet 成功写入缓存后调用的写穿回调，用于在同一代码路径中同步更新派生存储
+// policy 为 WriteThroughFailCall 时回调失败 Set/MSet 返回错误；为 WriteThroughAsync 时在后台执行，失败通知 reporter（可以为 nil）
+// loader 加载后写入缓存的数据不会触发回调
+func WithConfigWriteThrough(fn WriteThroughFunc, policy WriteThroughPolicy, reporter WriteThroughReporter) Option {
+	return writeThroughOption{hook: writeThrough{fn: fn, policy: policy, reporter: reporter}}
+}
+
+// defaultLoaderTimeoutOption 设置默认的 loader 超时时间
+type defaultLoaderTimeoutOption struct {
+	timeout time.Duration
+}
+
+func (d defaultLoaderTimeoutOption) apply(opts *options) {
+	opts.defaultLoaderTimeout = d.timeout
+}
+
+// WithConfigDefaultLoaderTimeout 设置所有 loader / batchLoader 默认的超时时间，行为与 WithLoaderTimeout(timeout, false) 相同，
+// 避免某次调用忘记设置超时导致一个慢查询让 singleflight 的所有等待者一直阻塞；单次调用的 WithLoaderTimeout 优先
+func WithConfigDefaultLoaderTimeout(timeout time.Duration) Option {
+	return defaultLoaderTimeoutOption{timeout: timeout}
+}
+
+// asyncRemoteWritesOption 设置异步 Remote 写入
+type asyncRemoteWritesOption struct {
+	queueSize int
+	workers   int
+}
+
+func (a asyncRemoteWritesOption) apply(opts *options) {
+	opts.asyncRemoteWrites = &a
+}
+
+// WithConfigAsyncRemoteWrites 开启异步 Remote 写入：Set/MSet 写入内存缓存后将 Remote 写入放入队列即返回，由 workers 个后台 worker 执行，
+// 失败时退避重试，重试耗尽后丢弃并计入 Stats.AsyncWriteDrops；队列已满时 Set/MSet 阻塞等待。
+// 同一键的写入按提交顺序执行，但 Delete 不等待排队的写入，需要立即生效时先调用 Flush；关闭前调用 Close 等待队列写完
+func WithConfigAsyncRemoteWrites(queueSize, workers int) Option {
+	return asyncRemoteWritesOption{queueSize: queueSize, workers: workers}
+}
+
+// closeAdaptersOption 设置 Close 时是否关闭适配器
+type closeAdaptersOption struct {
+	enabled bool
+}
+
+func (o closeAdaptersOption) apply(opts *options) {
+	opts.closeAdapters = o.enabled
+}
+
+// WithConfigCloseAdapters 设置 Close 时是否关闭实现了 io.Closer 的内存和 Remote 适配器，
+// Remote 适配器会关闭底层的 Redis 客户端，客户端与其他组件共用时不要开启
+func WithConfigCloseAdapters(enabled bool) Option {
+	return closeAdaptersOption{enabled: enabled}
+}
+
+// remoteFailurePolicyOption 设置 Remote 出错时的处理策略
+type remoteFailurePolicyOption struct {
+	policy RemoteFailurePolicy
+}
+
+func (r remoteFailurePolicyOption) apply(opts *options) {
+	opts.remoteFailurePolicy = r.policy
+}
+
+// WithConfigRemoteFailurePolicy 设置 Remote 出错时的处理策略，默认 FailClosed
+// FailOpen 只作用于 Get、MGet、Set、MSet 以及加载后的缓存写入；Delete、标签和失效等操作出错时仍返回错误，
+// 避免删除失败后 Remote 中留下已失效的数据
+func WithConfigRemoteFailurePolicy(policy RemoteFailurePolicy) Option {
+	return remoteFailurePolicyOption{policy: policy}
+}
+
+// tracerProviderOption 设置 OpenTelemetry TracerProvider
+type tracerProviderOption struct {
+	provider trace.TracerProvider
+}
+
+func (t tracerProviderOption) apply(opts *options) {
+	opts.tracerProvider = t.provider
+}
+
+// WithConfigTracerProvider 开启 OpenTelemetry 追踪：Get、MGet、Set、MSet、Delete、MDelete 各创建一个 span，
+// 记录键的数量、值的字节数以及内存和 Remote 各层的命中与未命中数量，loader / batchLoader 调用创建子 span。
+// 属性中不包含键本身，避免在追踪系统中暴露业务数据；provider 为 nil 表示不追踪
+func WithConfigTracerProvider(provider trace.TracerProvider) Option {
+	return tracerProviderOption{provider: provider}
+}
+
+// refreshAheadOption 设置提前刷新
+type refreshAheadOption struct {
+	interval time.Duration
+	maxKeys  int
+	keys     RefreshKeysFunc
+}
+
+func (r refreshAheadOption) apply(opts *options) {
+	opts.refreshAhead = &r
+}
+
+// WithConfigRefreshAhead 开启提前刷新：每隔 interval 在后台重新调用 loader 加载一批键并写入内存和 Remote，
+// 让延迟敏感接口的热点键在内存过期前保持命中，interval 应小于内存 TTL。
+// keys 为 nil 时选取上一轮访问次数最多的 maxKeys 个键；不为 nil 时刷新 keys 返回的键，最多 maxKeys 个。
+// 只有通过带 WithLoader 的 Get 访问过的键才能刷新，刷新使用该键最近一次 Get 的 loader 和选项，缓存关闭时停止
+func WithConfigRefreshAhead(interval time.Duration, maxKeys int, keys RefreshKeysFunc) Option {
+	return refreshAheadOption{interval: interval, maxKeys: maxKeys, keys: keys}
+}
+
+// poisonThresholdOption 设置毒丸数据的删除阈值
+type poisonThresholdOption struct {
+	threshold int
+}
+
+func (p poisonThresholdOption) apply(opts *options) {
+	opts.poisonThreshold = p.threshold
+}
+
+// WithConfigPoisonThreshold 设置同一键缓存的数据连续 threshold 次反序列化失败后，自动从两层缓存中删除并按未命中调用 loader 加载，
+// 避免一条损坏的数据让接口一直报错直到人工处理；0 表示关闭。
+// target 类型与缓存数据不匹配同样会计为失败，阈值不宜过小
+func WithConfigPoisonThreshold(threshold int) Option {
+	return poisonThresholdOption{threshold: threshold}
+}
+
+// batchChunkSizeOption 设置批量操作的拆分大小
+type batchChunkSizeOption struct {
+	size int
+}
+
+func (b batchChunkSizeOption) apply(opts *options) {
+	opts.batchChunkSize = b.size
+}
+
+// WithConfigBatchChunkSize 设置批量操作的拆分大小，键数量很多的 MGet/MSet 按 size 拆分为多次 Remote MGET/MSET，
+// 未命中的键按 size 拆分为多次 batchLoader 调用，每组使用独立的 singleflight 键；为 0 表示不拆分（默认）。
+// 同时开启 WithConfigAdaptiveBatch 时 Remote 读取按自适应批大小拆分
+func WithConfigBatchChunkSize(size int) Option {
+	return batchChunkSizeOption{size: size}
+}
+
+// remoteConcurrencyOption 设置拆分后的 Remote 读写的并发数
+type remoteConcurrencyOption struct {
+	concurrency int
+}
+
+func (r remoteConcurrencyOption) apply(opts *options) {
+	opts.remoteConcurrency = r.concurrency
+}
+
+// WithConfigRemoteConcurrency 设置按 WithConfigBatchChunkSize 拆分后的 Remote MGET/MSET 的最大并发数，默认 1 即按顺序执行；
+// 任一分组出错时取消其他分组并返回错误。开启 WithConfigAdaptiveBatch 时 Remote 读取仍按顺序执行
+func WithConfigRemoteConcurrency(concurrency int) Option {
+	return remoteConcurrencyOption{concurrency: concurrency}
+}
+
+// keyPrefixOption 设置缓存级键命名空间
+type keyPrefixOption struct {
+	prefix string
+}
+
+func (k keyPrefixOption) apply(opts *options) {
+	opts.keyPrefix = k.prefix
+}
+
+// WithConfigKeyPrefix 设置缓存级键命名空间，所有键在两层缓存中都自动加上 prefix，例如 WithConfigKeyPrefix("svc-a:")，
+// 多个服务或环境共用同一个 Redis 时互不影响。与 WithKeyContext 一样对调用方透明，缓存级前缀在 context 前缀之前
+func WithConfigKeyPrefix(prefix string) Option {
+	return keyPrefixOption{prefix: prefix}
+}
+
+// keyHasherOption 设置缓存级键哈希函数
+type keyHasherOption struct {
+	hasher func(string) string
+}
+
+func (k keyHasherOption) apply(opts *options) {
+	opts.keyHasher = k.hasher
+}
+
+// WithConfigKeyHasher 设置缓存级键哈希函数，加上命名空间和 context 前缀后的键经过 hasher 改写后再写入两层缓存，
+// 例如将过长的键替换为摘要；hasher 必须是确定性的，且不同的键不能得到相同的结果。
+// 改写后的键无法按前缀遍历，DeleteByPrefix 和 RemoteKeys 返回 ErrCustomKeyLayout
+func WithConfigKeyHasher(hasher func(string) string) Option {
+	return keyHasherOption{hasher: hasher}
+}
+
+// adaptiveBatchOption 设置自适应批量读取
+type adaptiveBatchOption struct {
+	target  time.Duration
+	minSize int
+	maxSize int
+}
+
+func (a adaptiveBatchOption) apply(opts *options) {
+	opts.adaptiveBatch = &a
+}
+
+// WithConfigAdaptiveBatch 设置 Remote 批量读取的自适应拆分
+// 批量读取按当前批大小拆分为多次 MGET，单次耗时不超过 target 时批大小逐步增加，超过时减半（AIMD），
+// 批大小在 [minSize, maxSize] 之间调整；小值的场景自动使用大批次，大值的场景自动缩小批次以平滑尾延迟
+func WithConfigAdaptiveBatch(target time.Duration, minSize, maxSize int) Option {
+	return adaptiveBatchOption{target: target, minSize: minSize, maxSize: maxSize}
+}
+
+// siblingPrefetchOption 设置相邻键预取
+type siblingPrefetchOption struct {
+	window int
+	rate   int
+}
+
+func (s siblingPrefetchOption) apply(opts *options) {
+	opts.siblingPrefetch = &s
+}
+
+// WithConfigSiblingPrefetch 设置 MGet 部分键只在 Remote 命中时，异步预取相邻键到内存缓存
+// 键的最后一段为整数 ID 时（例如 user:100），以同一前缀下本次未命中内存的最大 ID 为起点预取后续 window 个 ID，
+// 适用于顺序翻页的场景；rate 为每秒最多触发的预取次数，超出的预取直接丢弃
+func WithConfigSiblingPrefetch(window, rate int) Option {
+	return siblingPrefetchOption{window: window, rate: rate}
+}
+
+// hotKeysOption 设置热点键统计
+type hotKeysOption struct {
+	window    time.Duration
+	threshold int64
+	onHot     func(key string, count int64)
+}
+
+func (h hotKeysOption) apply(opts *options) {
+	opts.hotKeys = &h
+}
+
+// WithConfigHotKeys 开启热点键统计，Get / MGet 读取的键使用 count-min sketch 估算访问次数，通过 TopKeys 查看，
+// 用于找出值得固定在内存或拆分的热点键；每个 window 结束时计数减半，估算次数近似每个窗口的访问次数。
+// threshold 大于 0 且 onHot 不为 nil 时，键的估算次数达到 threshold 时调用 onHot，每个窗口每个键最多一次；onHot 在读取路径中同步执行，应尽快返回
+func WithConfigHotKeys(window time.Duration, threshold int64, onHot func(key string, count int64)) Option {
+	return hotKeysOption{window: window, threshold: threshold, onHot: onHot}
+}
+
+// maxValueSizeOption 设置值大小上限
+type maxValueSizeOption struct {
+	limit valueSizeLimit
+}
+
+func (m maxValueSizeOption) apply(opts *options) {
+	opts.maxValueSize = &m.limit
+}
+
+// WithConfigMaxValueSize 设置单个值序列化后的大小上限（字节），超过上限的值按 policy 处理：
+// ValueSizeReject 时 Set/MSet 返回 ErrValueTooLarge；ValueSizeRemoteOnly 时只写入 Remote；ValueSizeSkip 时不写入任何缓存层。
+// 未设置时过大的值只会被内存适配器静默丢弃，仍然写入 Remote；超过上限的值计入 Stats().OversizedValues
+func WithConfigMaxValueSize(size int, policy ValueSizePolicy) Option {
+	return maxValueSizeOption{limit: valueSizeLimit{size: size, policy: policy}}
+}
+
+// watchIntervalOption 设置 Watch 的轮询间隔
+type watchIntervalOption struct {
+	interval time.Duration
+}
+
+func (w watchIntervalOption) apply(opts *options) {
+	opts.watchInterval = w.interval
+}
+
+// WithConfigWatchInterval 设置 Watch 轮询的间隔（默认 30 秒），轮询用于兜底没有收到的写入和失效消息，
+// 配置了 WithConfigInvalidationTransport 时其他实例的写入通常在轮询之前就会被感知
+func WithConfigWatchInterval(interval time.Duration) Option {
+	return watchIntervalOption{interval: interval}
+}
+
+// ttlJitterOption 设置 TTL 抖动比例
+type ttlJitterOption struct {
+	fraction float64
+}
+
+func (t ttlJitterOption) apply(opts *options) {
+	opts.ttlJitter = t.fraction
+}
+
+// WithConfigTTLJitter 设置每次写入 Remote 时 TTL 在 ±fraction 范围内随机抖动，取值范围 [0, 1)，0 表示关闭
+// 避免 MSet 或批量加载同时写入的大量键在同一时刻过期，集中回源击穿数据库；
+// 批量写入时键被随机分为若干组，每组使用不同的 TTL
+func WithConfigTTLJitter(fraction float64) Option {
+	return ttlJitterOption{fraction: fraction}
+}
+
+// memoryTTLJitterOption 设置内存 TTL 是否抖动
+type memoryTTLJitterOption struct {
+	enabled bool
+}
+
+func (m memoryTTLJitterOption) apply(opts *options) {
+	opts.memoryTTLJitter = m.enabled
+}
+
+// WithConfigMemoryTTLJitter 设置内存 TTL 是否也按 WithConfigTTLJitter 的比例抖动
+func WithConfigMemoryTTLJitter(enabled bool) Option {
+	return memoryTTLJitterOption{enabled: enabled}
+}
+
+// valueMiddlewareOption 添加值中间件
+type valueMiddlewareOption struct {
+	middlewares []ValueMiddleware
+}
+
+func (v valueMiddlewareOption) apply(opts *options) {
+	opts.valueMiddlewares = append(opts.valueMiddlewares, v.middlewares...)
+}
+
+// WithConfigValueMiddleware 添加值字节的编解码中间件，可多次调用，按添加顺序编码、相反顺序解码
+// 中间件改变了存储格式，读写同一份缓存的实例必须使用相同的中间件
+func WithConfigValueMiddleware(m ...ValueMiddleware) Option {
+	return valueMiddlewareOption{middlewares: m}
+}
+
+// interceptorOption 添加调用拦截器
+type interceptorOption struct {
+	interceptors []Interceptor
+}
+
+func (i interceptorOption) apply(opts *options) {
+	opts.interceptors = append(opts.interceptors, i.interceptors...)
+}
+
+// WithConfigInterceptor 添加 Get、MGet、Set、MSet、Delete、MDelete 的调用拦截器，可多次调用
+// 先添加的拦截器位于外层，可用于日志、按键前缀鉴权、故障注入等
+func WithConfigInterceptor(i ...Interceptor) Option {
+	return interceptorOption{interceptors: i}
+}
+
 // applyOptions 应用选项到配置
 func applyOptions(opts *options, options ...Option) error {
 	for _, option := range options {
 		option.apply(opts)
 	}
+	if err := resolveLayers(opts); err != nil {
+		return err
+	}
 	return validateOptions(opts)
 }
 
+// resolveLayers 将 WithConfigLayers 设置的多级 Remote 组合为 remoteAdapter
+func resolveLayers(cfg *options) error {
+	if cfg.layers == nil {
+		return nil
+	}
+	if cfg.remoteAdapter != nil {
+		return errors.ErrInvalidLayers
+	}
+	chain, err := storage.NewChain(cfg.layers...)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errors.ErrInvalidLayers, err)
+	}
+	cfg.remoteAdapter = chain
+	return nil
+}
+
 // newOptions 创建默认配置
 func newOptions() *options {
 	return &options{
@@ -118,6 +921,8 @@ func newOptions() *options {
 		defaultRemoteTTL:        14 * 24 * time.Hour,       // 默认Remote缓存14天
 		defaultCacheNotFound:    false,                     // 默认不缓存缺失值
 		defaultCacheNotFoundTTL: time.Minute,               // 默认缺失值缓存1分钟
+		remoteConcurrency:       1,                         // 默认按顺序执行拆分后的 Remote 读写
+		watchInterval:           30 * time.Second,          // 默认 Watch 每30秒轮询一次
 	}
 }
 
@@ -127,22 +932,157 @@ func validateOptions(cfg *options) error {
 	}
 
 	if cfg.memoryAdapter != nil {
-		if err := validMemoryTTL(cfg.defaultMemoryTTL); err != nil {
+		if err := validMemoryTTL(cfg.defaultMemoryTTL, errors.TTLSourceDefault); err != nil {
 			return err
 		}
 	}
 
 	if cfg.remoteAdapter != nil {
-		if err := validRemoteTTL(cfg.defaultRemoteTTL); err != nil {
+		if err := validRemoteTTL(cfg.defaultRemoteTTL, errors.TTLSourceDefault); err != nil {
 			return err
 		}
 	}
 
 	if cfg.defaultCacheNotFound {
-		if err := validCacheMissTTL(cfg.defaultCacheNotFoundTTL); err != nil {
+		if err := validCacheMissTTL(cfg.defaultCacheNotFoundTTL, errors.TTLSourceDefault); err != nil {
 			return err
 		}
 	}
 
+	if cfg.ttlJitter < 0 || cfg.ttlJitter >= 1 {
+		return errors.ErrInvalidTTLJitter
+	}
+
+	for _, m := range cfg.valueMiddlewares {
+		if m == nil {
+			return errors.ErrInvalidValueMiddleware
+		}
+	}
+
+	for _, i := range cfg.interceptors {
+		if i == nil {
+			return errors.ErrInvalidInterceptor
+		}
+	}
+
+	if cfg.readRepairRate < 0 || cfg.readRepairRate > 1 {
+		return errors.ErrInvalidReadRepairRate
+	}
+
+	if cfg.deleteShieldTTL < 0 {
+		return &errors.TTLError{Field: "deleteShieldTTL", Value: cfg.deleteShieldTTL, Source: errors.TTLSourceConfig, Err: errors.ErrInvalidDeleteShieldTTL}
+	}
+
+	if cfg.batchChunkSize < 0 {
+		return errors.ErrInvalidBatchChunkSize
+	}
+
+	if cfg.remoteConcurrency <= 0 {
+		return errors.ErrInvalidRemoteConcurrency
+	}
+
+	if a := cfg.adaptiveBatch; a != nil {
+		if a.target <= 0 || a.minSize <= 0 || a.maxSize < a.minSize {
+			return errors.ErrInvalidAdaptiveBatch
+		}
+		if cfg.remoteAdapter == nil {
+			return errors.ErrRemoteRequired
+		}
+	}
+
+	if p := cfg.siblingPrefetch; p != nil {
+		if p.window <= 0 || p.rate <= 0 {
+			return errors.ErrInvalidSiblingPrefetch
+		}
+		if cfg.memoryAdapter == nil || cfg.remoteAdapter == nil {
+			return errors.ErrBothLayersRequired
+		}
+	}
+
+	if h := cfg.hotKeys; h != nil && (h.window <= 0 || h.threshold < 0) {
+		return errors.ErrInvalidHotKeys
+	}
+
+	if m := cfg.maxValueSize; m != nil && (m.size <= 0 || m.policy < ValueSizeReject || m.policy > ValueSizeSkip) {
+		return errors.ErrInvalidMaxValueSize
+	}
+
+	if b := cfg.loaderBreaker; b != nil && (b.failures <= 0 || b.cooldown <= 0) {
+		return errors.ErrInvalidLoaderBreaker
+	}
+
+	if b := cfg.remoteBreaker; b != nil && (b.failures <= 0 || b.cooldown <= 0) {
+		return errors.ErrInvalidRemoteBreaker
+	}
+
+	if l := cfg.loaderRateLimit; l != nil && (l.rate <= 0 || l.burst <= 0) {
+		return errors.ErrInvalidLoaderRateLimit
+	}
+
+	if cfg.defaultLoaderTimeout < 0 {
+		return errors.ErrInvalidLoaderTimeout
+	}
+
+	if cfg.watchInterval <= 0 {
+		return errors.ErrInvalidWatchInterval
+	}
+
+	if cfg.poisonThreshold < 0 {
+		return errors.ErrInvalidPoisonThreshold
+	}
+
+	if a := cfg.asyncRemoteWrites; a != nil && (a.queueSize <= 0 || a.workers <= 0) {
+		return errors.ErrInvalidAsyncRemoteWrites
+	}
+
+	if cfg.remoteFailurePolicy != FailClosed && cfg.remoteFailurePolicy != FailOpen {
+		return errors.ErrInvalidRemoteFailurePolicy
+	}
+
+	if r := cfg.refreshAhead; r != nil && (r.interval <= 0 || r.maxKeys <= 0) {
+		return errors.ErrInvalidRefreshAhead
+	}
+
+	if w := cfg.writeThrough; w != nil && (w.fn == nil || w.policy < WriteThroughFailCall || w.policy > WriteThroughAsync) {
+		return errors.ErrInvalidWriteThrough
+	}
+
+	if cfg.expvarName != "" && expvarNameTaken(cfg.expvarName) {
+		return errors.ErrExpvarNameExists
+	}
+
+	if cfg.dependencies {
+		if cfg.remoteAdapter == nil {
+			return errors.ErrRemoteRequired
+		}
+		if _, ok := cfg.remoteAdapter.(storage.SetStore); !ok {
+			return errors.ErrOperationNotSupported
+		}
+	}
+
+	if cfg.strictMemorySize && cfg.memoryAdapter != nil {
+		if _, ok := cfg.memoryAdapter.(storage.EntrySizeLimiter); !ok {
+			return errors.ErrOperationNotSupported
+		}
+	}
+
+	if cfg.memoryCostFunc != nil && cfg.memoryAdapter != nil {
+		if _, ok := cfg.memoryAdapter.(storage.CostSetter); !ok {
+			return errors.ErrOperationNotSupported
+		}
+	}
+
+	if cfg.demoteOnEvict {
+		if cfg.memoryAdapter == nil || cfg.remoteAdapter == nil {
+			return errors.ErrDemoteRequiresBothLayers
+		}
+		if _, ok := cfg.memoryAdapter.(storage.EvictionNotifier); !ok {
+			return errors.ErrEvictionNotSupported
+		}
+		if !cfg.envelope || cfg.envelopeLegacyWrite {
+			return errors.ErrEnvelopeRequired
+		}
+	}
+
 	return nil
 }
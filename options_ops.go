@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/serializer"
 )
 
 // LoaderFunc 单个键的加载函数
@@ -37,6 +38,48 @@ type getOptions struct {
 
 	// cacheNotFoundTTL 缺失值的缓存过期时间
 	cacheNotFoundTTL *time.Duration
+
+	// shadowRate 影子比对的采样比例
+	shadowRate float64
+
+	// shadowReporter 影子比对发现不一致时的回调
+	shadowReporter ShadowReporter
+
+	// cacheExtra batchLoader 返回的未请求的键是否也写入缓存
+	cacheExtra bool
+
+	// loaderTimeout loader 执行的超时时间，为 0 表示不限制
+	loaderTimeout time.Duration
+
+	// loaderFinishInBackground 超时后是否让 loader 在后台继续执行并写入缓存
+	loaderFinishInBackground bool
+
+	// serveStale 加载失败或没有 loader 时是否返回已失效的数据
+	serveStale bool
+
+	// maxAge 数据写入后超过该时长视为已失效，为 0 表示不限制
+	maxAge time.Duration
+
+	// ttlFunc 按键和值计算加载后写入缓存的过期时间
+	ttlFunc TTLFunc
+
+	// serializer 本次读取使用的序列化器，为 nil 表示按类型或默认选择
+	serializer serializer.Serializer
+
+	// reloadNotFound 是否忽略缓存的缺失值标记，重新调用 loader 加载
+	reloadNotFound bool
+
+	// 本次读取跳过的缓存层
+	layerSelection
+
+	// lockTTL 跨实例合并加载时锁的过期时间，为 0 表示不开启
+	lockTTL time.Duration
+
+	// lockWait 未获取到锁时等待其他实例加载结果的最长时间
+	lockWait time.Duration
+
+	// notFoundCached 批量读取命中缺失值标记时的回调
+	notFoundCached func(key string)
 }
 
 // withLoader 设置缓存未命中时的加载函数
@@ -69,6 +112,8 @@ func WithBatchLoader(batchLoader BatchLoaderFunc) GetOption {
 
 // withTTL TTL选项的通用实现
 type withTTL struct {
+	readOption
+
 	memoryTTL time.Duration
 	remoteTTL time.Duration
 }
@@ -85,13 +130,15 @@ func (w withTTL) applySet(cfg *setOptions) {
 
 // WithTTL 设置缓存过期时间（通用选项，可用于Get和Set操作）
 func WithTTL(memoryTTL, remoteTTL time.Duration) interface {
-	GetOption
+	ReadOption
 	SetOption
 } {
 	return withTTL{memoryTTL: memoryTTL, remoteTTL: remoteTTL}
 }
 
 type withMemoryTTL struct {
+	readOption
+
 	memoryTTL time.Duration
 }
 
@@ -105,13 +152,15 @@ func (w withMemoryTTL) applySet(cfg *setOptions) {
 
 // WithMemoryTTL 设置缓存过期时间（通用选项，可用于Get和Set操作）
 func WithMemoryTTL(memoryTTL time.Duration) interface {
-	GetOption
+	ReadOption
 	SetOption
 } {
 	return withMemoryTTL{memoryTTL: memoryTTL}
 }
 
 type withRedisTTL struct {
+	readOption
+
 	remoteTTL time.Duration
 }
 
@@ -125,7 +174,7 @@ func (w withRedisTTL) applySet(cfg *setOptions) {
 
 // WithRemoteTTL 设置缓存过期时间（通用选项，可用于Get和Set操作）
 func WithRemoteTTL(remoteTTL time.Duration) interface {
-	GetOption
+	ReadOption
 	SetOption
 } {
 	return withRedisTTL{remoteTTL: remoteTTL}
@@ -133,6 +182,8 @@ func WithRemoteTTL(remoteTTL time.Duration) interface {
 
 // withCacheNotFound 设置是否缓存缺失值
 type withCacheNotFound struct {
+	readOption
+
 	cacheNotFound    bool
 	cacheNotFoundTTL time.Duration
 }
@@ -145,10 +196,100 @@ func (w withCacheNotFound) applyGet(cfg *getOptions) {
 // WithCacheNotFound 设置是否缓存缺失值（防止缓存穿透）
 // cacheNotFound: 是否启用缺失值缓存
 // cacheNotFoundTTL: 缺失值的缓存过期时间，如果小于0则使用默认值
-func WithCacheNotFound(cacheNotFound bool, cacheNotFoundTTL time.Duration) GetOption {
+func WithCacheNotFound(cacheNotFound bool, cacheNotFoundTTL time.Duration) ReadOption {
 	return withCacheNotFound{cacheNotFound: cacheNotFound, cacheNotFoundTTL: cacheNotFoundTTL}
 }
 
+// withCacheExtra 设置是否缓存 batchLoader 额外返回的键
+type withCacheExtra struct {
+	batchOption
+
+	cacheExtra bool
+}
+
+func (w withCacheExtra) applyGet(cfg *getOptions) {
+	cfg.cacheExtra = w.cacheExtra
+}
+
+// WithCacheExtra 设置是否缓存 batchLoader 额外返回的键
+// 开启后 batchLoader 返回的不在请求范围内的键（例如加载父对象时一并返回的子对象）也会写入缓存，
+// 但不会出现在本次 MGet 的结果中；关闭时丢弃
+func WithCacheExtra(cacheExtra bool) TypedMGetOption {
+	return withCacheExtra{cacheExtra: cacheExtra}
+}
+
+// withLoaderTimeout 设置 loader 执行的超时时间
+type withLoaderTimeout struct {
+	readOption
+
+	timeout            time.Duration
+	finishInBackground bool
+}
+
+func (w withLoaderTimeout) applyGet(cfg *getOptions) {
+	cfg.loaderTimeout = w.timeout
+	cfg.loaderFinishInBackground = w.finishInBackground
+}
+
+// WithLoaderTimeout 设置 loader / batchLoader 执行的超时时间，超时后所有等待的调用方返回 ErrLoaderTimeout
+// loader 使用独立于调用方的 context 执行，单个调用方取消不会影响 singleflight 中的其他等待者
+// finishInBackground: 超时后是否让 loader 在后台继续执行，完成后照常写入缓存供后续请求使用，
+// 后台执行的 loader 的 context 在 10 倍超时时间后取消；为 false 时 loader 的 context 在超时时取消
+func WithLoaderTimeout(timeout time.Duration, finishInBackground bool) ReadOption {
+	return withLoaderTimeout{timeout: timeout, finishInBackground: finishInBackground}
+}
+
+// withServeStale 设置是否允许返回已失效的数据
+type withServeStale struct {
+	readOption
+
+	serveStale bool
+}
+
+func (w withServeStale) applyGet(cfg *getOptions) {
+	cfg.serveStale = w.serveStale
+}
+
+// WithServeStale 设置是否允许降级返回被 Invalidate 标记为失效的数据
+// 开启后 loader 返回错误（ErrNotFound 除外）或没有 loader 时，返回失效前的数据而不是错误
+func WithServeStale(serveStale bool) ReadOption {
+	return withServeStale{serveStale: serveStale}
+}
+
+// withMaxAge 设置数据的最大年龄
+type withMaxAge struct {
+	readOption
+
+	maxAge time.Duration
+}
+
+func (w withMaxAge) applyGet(cfg *getOptions) {
+	cfg.maxAge = w.maxAge
+}
+
+// WithMaxAge 设置数据的最大年龄，按封装头中的写入时间判断，写入超过 maxAge 的数据视为已失效，
+// 与 Invalidate 标记的数据一样重新加载（开启 WithServeStale 时可降级返回）；需要开启封装格式，未封装的旧数据不受限制
+func WithMaxAge(maxAge time.Duration) ReadOption {
+	return withMaxAge{maxAge: maxAge}
+}
+
+// withReloadNotFound 设置是否忽略缓存的缺失值标记
+type withReloadNotFound struct {
+	readOption
+
+	reloadNotFound bool
+}
+
+func (w withReloadNotFound) applyGet(cfg *getOptions) {
+	cfg.reloadNotFound = w.reloadNotFound
+}
+
+// WithReloadNotFound 设置是否忽略缓存的缺失值标记
+// 开启后命中缺失值标记的键按未命中处理，继续调用 loader / batchLoader 加载，加载到数据后正常值优先于缺失值标记
+func WithReloadNotFound(reloadNotFound bool) ReadOption {
+	return withReloadNotFound{reloadNotFound: reloadNotFound}
+}
+
 // applyGetOptions 应用Get选项到配置
 func applyGetOptions(cfg *getOptions, opts ...GetOption) error {
 	for _, opt := range opts {
@@ -163,18 +304,40 @@ func newGetOptions() *getOptions {
 }
 
 func validateGetOptions(cfg *getOptions) error {
-	if cfg.memoryTTL != nil && *cfg.memoryTTL <= 0 {
-		return errors.ErrInvalidMemoryExpireTime
+	if cfg.memoryTTL != nil {
+		if err := validMemoryTTL(*cfg.memoryTTL, errors.TTLSourcePerCall); err != nil {
+			return err
+		}
+	}
+
+	if cfg.remoteTTL != nil {
+		if err := validRemoteTTL(*cfg.remoteTTL, errors.TTLSourcePerCall); err != nil {
+			return err
+		}
+	}
+
+	if cfg.cacheNotFoundTTL != nil {
+		if err := validCacheMissTTL(*cfg.cacheNotFoundTTL, errors.TTLSourcePerCall); err != nil {
+			return err
+		}
 	}
 
-	if cfg.remoteTTL != nil && *cfg.remoteTTL <= 0 {
-		return errors.ErrInvalidRedisExpireTime
+	if cfg.shadowRate < 0 || cfg.shadowRate > 1 {
+		return errors.ErrInvalidShadowCompareRate
 	}
 
-	if cfg.cacheNotFoundTTL != nil && *cfg.cacheNotFoundTTL <= 0 {
-		return errors.ErrInvalidCacheNotFondTTL
+	if cfg.loaderTimeout < 0 {
+		return errors.ErrInvalidLoaderTimeout
 	}
-	return nil
+
+	if cfg.maxAge < 0 {
+		return errors.ErrInvalidMaxAge
+	}
+
+	if cfg.lockTTL < 0 || cfg.lockWait < 0 {
+		return errors.ErrInvalidDistributedSingleflight
+	}
+	return cfg.layerSelection.validate()
 }
 
 // SetOption Set操作的选项配置
@@ -189,6 +352,18 @@ type setOptions struct {
 
 	// remoteTTL Redis缓存过期时间
 	remoteTTL *time.Duration
+
+	// tags 写入的键携带的标签
+	tags []string
+
+	// ttlFunc 按键和值计算写入的过期时间
+	ttlFunc TTLFunc
+
+	// serializer 本次写入使用的序列化器，为 nil 表示按类型或默认选择
+	serializer serializer.Serializer
+
+	// 本次写入跳过的缓存层
+	layerSelection
 }
 
 // applySetOptions 应用Set选项到配置
@@ -205,12 +380,16 @@ func newSetOptions() *setOptions {
 }
 
 func validateSetOptions(cfg *setOptions) error {
-	if cfg.memoryTTL != nil && *cfg.memoryTTL <= 0 {
-		return errors.ErrInvalidMemoryExpireTime
+	if cfg.memoryTTL != nil {
+		if err := validMemoryTTL(*cfg.memoryTTL, errors.TTLSourcePerCall); err != nil {
+			return err
+		}
 	}
 
-	if cfg.remoteTTL != nil && *cfg.remoteTTL <= 0 {
-		return errors.ErrInvalidRedisExpireTime
+	if cfg.remoteTTL != nil {
+		if err := validRemoteTTL(*cfg.remoteTTL, errors.TTLSourcePerCall); err != nil {
+			return err
+		}
 	}
-	return nil
+	return cfg.layerSelection.validate()
 }
@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+)
+
+const (
+	// pinCheckInterval 检查固定的键是否需要重新加载的间隔
+	pinCheckInterval = time.Second
+	// pinRefreshAhead 内存中剩余过期时间小于该值时提前重新加载，需要内存适配器实现 storage.TTLGetter
+	pinRefreshAhead = 2 * pinCheckInterval
+)
+
+// pinner 记录固定的键及其加载选项
+type pinner struct {
+	once sync.Once
+
+	mu   sync.Mutex
+	keys map[string]*getOptions
+}
+
+// Pin 固定键：内存中没有该键时立即通过 opts 中的 loader 加载，之后后台每秒检查一次，
+// 内存中的条目即将过期、已过期或被删除时重新调用 loader 加载，使其始终保留在内存中，适用于体积小、不可缺失的配置对象。
+// 内存适配器实现 storage.Pinner 时（例如 storage.ShardedMap）固定的条目不会因容量不足被淘汰。
+// opts 必须包含 WithLoader，否则返回 ErrLoaderRequired；没有内存缓存时返回 ErrOperationNotSupported
+func (c *LayeredCache) Pin(ctx context.Context, key string, opts ...GetOption) error {
+	scope, ctx := c.takeKeyContext(ctx)
+	if !scope.empty() {
+		key, opts = scope.key(key), scopeGetOptions(scope, scope.unscoper([]string{key}), opts)
+	}
+
+	config := newGetOptions()
+	if err := applyGetOptions(config, opts...); err != nil {
+		return c.misuse(err)
+	}
+	if config.loader == nil {
+		return c.misuse(errors.ErrLoaderRequired)
+	}
+	if c.memory == nil {
+		return errors.ErrOperationNotSupported
+	}
+
+	c.pins.mu.Lock()
+	if c.pins.keys == nil {
+		c.pins.keys = make(map[string]*getOptions)
+	}
+	c.pins.keys[key] = config
+	c.pins.mu.Unlock()
+	if pinner, ok := c.memory.(storage.Pinner); ok {
+		pinner.Pin(key)
+	}
+	c.pins.once.Do(func() {
+		c.schedule(intervalSchedule(pinCheckInterval), func() {
+			c.refreshPinned(context.Background())
+		})
+	})
+
+	if _, ok := c.memory.Get(key); ok {
+		return nil
+	}
+	return c.reloadPinned(ctx, key, config)
+}
+
+// Unpin 取消固定，内存中的条目保留到过期
+func (c *LayeredCache) Unpin(ctx context.Context, key string) {
+	scope, _ := c.takeKeyContext(ctx)
+	key = scope.key(key)
+
+	c.pins.mu.Lock()
+	delete(c.pins.keys, key)
+	c.pins.mu.Unlock()
+	if pinner, ok := c.memory.(storage.Pinner); ok {
+		pinner.Unpin(key)
+	}
+}
+
+// refreshPinned 重新加载内存中即将过期或已经不存在的固定键
+func (c *LayeredCache) refreshPinned(ctx context.Context) {
+	c.pins.mu.Lock()
+	keys := make(map[string]*getOptions, len(c.pins.keys))
+	for key, config := range c.pins.keys {
+		keys[key] = config
+	}
+	c.pins.mu.Unlock()
+
+	getter, _ := c.memory.(storage.TTLGetter)
+	for key, config := range keys {
+		if getter != nil {
+			if _, ttl, ok := getter.GetWithTTL(key); ok && (ttl < 0 || ttl > pinRefreshAhead) {
+				continue
+			}
+		} else if _, ok := c.memory.Get(key); ok {
+			continue
+		}
+		_ = c.reloadPinned(ctx, key, config)
+	}
+}
+
+// reloadPinned 调用 loader 重新加载固定的键，与同一键的 Get 共用 singleflight
+func (c *LayeredCache) reloadPinned(ctx context.Context, key string, config *getOptions) error {
+	_, err := c.load(ctx, key, config, func(ctx context.Context) (any, error) {
+		return c.loadAndCache(ctx, key, config)
+	})
+	return err
+}
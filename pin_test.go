@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_Pin(t *testing.T) {
+	ctx := context.Background()
+
+	newCache := func(t *testing.T) *LayeredCache {
+		memory, err := storage.NewShardedMap(1024, storage.WithShards(1))
+		assert.NoError(t, err)
+		cache, err := NewCache(WithConfigMemory(memory), WithConfigRemote(createRemoteAdapter(t)))
+		assert.NoError(t, err)
+		return cache.(*LayeredCache)
+	}
+
+	t.Run("需要 loader", func(t *testing.T) {
+		assert.ErrorIs(t, newCache(t).Pin(ctx, "config"), errors.ErrLoaderRequired)
+	})
+
+	t.Run("删除或即将过期时重新加载", func(t *testing.T) {
+		c := newCache(t)
+		calls := 0
+		loader := WithLoader(func(ctx context.Context, key string) (any, error) {
+			calls++
+			return "v", nil
+		})
+
+		assert.NoError(t, c.Pin(ctx, "config", loader, WithTTL(time.Hour, time.Hour)))
+		assert.Equal(t, 1, calls)
+		assert.NoError(t, c.Pin(ctx, "config", loader, WithTTL(time.Hour, time.Hour)))
+		assert.Equal(t, 1, calls, "已在内存中时不重新加载")
+
+		c.refreshPinned(ctx)
+		assert.Equal(t, 1, calls)
+
+		c.memory.Delete("config")
+		c.refreshPinned(ctx)
+		assert.Equal(t, 2, calls)
+		_, ok := c.memory.Get("config")
+		assert.True(t, ok)
+
+		assert.NoError(t, c.Pin(ctx, "config", loader, WithTTL(time.Second, time.Hour)))
+		c.memory.Delete("config")
+		c.refreshPinned(ctx)
+		assert.Equal(t, 3, calls)
+		c.refreshPinned(ctx)
+		assert.Equal(t, 4, calls, "剩余过期时间过短时提前加载")
+
+		c.Unpin(ctx, "config")
+		c.memory.Delete("config")
+		c.refreshPinned(ctx)
+		assert.Equal(t, 4, calls)
+	})
+
+	t.Run("固定的条目不被淘汰", func(t *testing.T) {
+		c := newCache(t)
+		assert.NoError(t, c.Pin(ctx, "config", WithLoader(func(ctx context.Context, key string) (any, error) {
+			return "v", nil
+		})))
+		for i := range 100 {
+			assert.NoError(t, c.Set(ctx, fmt.Sprintf("k%d", i), "0123456789"))
+		}
+		_, ok := c.memory.Get("config")
+		assert.True(t, ok)
+	})
+}
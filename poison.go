@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/biu7/layered-cache/serializer"
+)
+
+// poisonTrackCapacity 最多同时跟踪的反序列化失败的键数量，超过时清空重新计数
+const poisonTrackCapacity = 10000
+
+// poisonTracker 按键统计连续反序列化失败的次数
+type poisonTracker struct {
+	threshold int
+
+	// tracked 当前跟踪的键数量，为 0 时成功读取不需要加锁
+	tracked atomic.Int32
+
+	mu       sync.Mutex
+	failures map[string]int
+}
+
+func newPoisonTracker(threshold int) *poisonTracker {
+	return &poisonTracker{threshold: threshold, failures: make(map[string]int)}
+}
+
+// fail 记录一次失败，达到阈值时清除计数并返回 true
+func (p *poisonTracker) fail(key string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.failures[key]; !ok && len(p.failures) >= poisonTrackCapacity {
+		clear(p.failures)
+	}
+	p.failures[key]++
+	if p.failures[key] < p.threshold {
+		p.tracked.Store(int32(len(p.failures)))
+		return false
+	}
+	delete(p.failures, key)
+	p.tracked.Store(int32(len(p.failures)))
+	return true
+}
+
+// succeed 成功读取后清除键的失败计数
+func (p *poisonTracker) succeed(key string) {
+	if p.tracked.Load() == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.failures, key)
+	p.tracked.Store(int32(len(p.failures)))
+}
+
+// poisoned 根据缓存数据的反序列化结果判断 key 是否为毒丸数据，连续失败达到阈值时从两层缓存中删除并返回 true，
+// 调用方应按未命中继续处理；未开启或未达到阈值时返回 false，由调用方原样返回 err
+func (c *LayeredCache) poisoned(ctx context.Context, key string, err error) bool {
+	if c.poison == nil {
+		return false
+	}
+	if err == nil {
+		c.poison.succeed(key)
+		return false
+	}
+	if !c.poison.fail(key) {
+		return false
+	}
+
+	if c.deleteKey(ctx, key) != nil {
+		return false
+	}
+	c.stats.poisonDeletes.Add(1)
+	return true
+}
+
+// dropPoisoned 逐个反序列化 data 中的数据，删除达到阈值的毒丸数据并返回这些键
+func (c *LayeredCache) dropPoisoned(ctx context.Context, data map[string][]byte, target any, s serializer.Serializer) []string {
+	valueType := reflect.TypeOf(target).Elem().Elem()
+
+	var keys []string
+	for key, value := range data {
+		if c.poisoned(ctx, key, c.decode(value, reflect.New(valueType).Interface(), s)) {
+			delete(data, key)
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
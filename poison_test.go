@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoisonTracker(t *testing.T) {
+	p := newPoisonTracker(3)
+
+	assert.False(t, p.fail("k"))
+	assert.False(t, p.fail("k"))
+	p.succeed("k")
+	assert.False(t, p.fail("k"), "成功读取后重新计数")
+	assert.False(t, p.fail("k"))
+	assert.True(t, p.fail("k"))
+	assert.Equal(t, int32(0), p.tracked.Load())
+}
+
+func TestLayeredCache_PoisonThreshold(t *testing.T) {
+	ctx := context.Background()
+	type product struct {
+		Name string `json:"name"`
+	}
+
+	createPoisonCache := func(t *testing.T) *LayeredCache {
+		t.Helper()
+		c, err := NewCache(
+			WithConfigMemory(createOtterAdapter(t)),
+			WithConfigRemote(createRemoteAdapter(t)),
+			WithConfigPoisonThreshold(2),
+		)
+		assert.NoError(t, err)
+		return c.(*LayeredCache)
+	}
+	corrupt := func(t *testing.T, c *LayeredCache, key string) {
+		t.Helper()
+		c.memory.Set(key, []byte("{corrupt"), time.Hour)
+		assert.NoError(t, c.remote.Set(ctx, key, []byte("{corrupt"), time.Hour))
+	}
+	loader := WithLoader(func(ctx context.Context, key string) (any, error) {
+		return product{Name: "fresh"}, nil
+	})
+
+	t.Run("配置校验", func(t *testing.T) {
+		_, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigPoisonThreshold(-1))
+		assert.ErrorIs(t, err, errors.ErrInvalidPoisonThreshold)
+	})
+
+	t.Run("Get连续失败后删除并回源", func(t *testing.T) {
+		c := createPoisonCache(t)
+		corrupt(t, c, "product:1")
+
+		var value product
+		assert.Error(t, c.Get(ctx, "product:1", &value, loader))
+
+		assert.NoError(t, c.Get(ctx, "product:1", &value, loader))
+		assert.Equal(t, "fresh", value.Name)
+		assert.Equal(t, int64(1), c.Stats().PoisonDeletes)
+
+		value = product{}
+		assert.NoError(t, c.Get(ctx, "product:1", &value))
+		assert.Equal(t, "fresh", value.Name)
+	})
+
+	t.Run("MGet连续失败后删除并回源", func(t *testing.T) {
+		c := createPoisonCache(t)
+		corrupt(t, c, "product:1")
+		assert.NoError(t, c.Set(ctx, "product:2", product{Name: "cached"}))
+
+		batchLoader := WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+			values := make(map[string]any)
+			for _, key := range keys {
+				values[key] = product{Name: "fresh"}
+			}
+			return values, nil
+		})
+
+		values := make(map[string]product)
+		assert.Error(t, c.MGet(ctx, []string{"product:1", "product:2"}, &values, batchLoader))
+
+		assert.NoError(t, c.MGet(ctx, []string{"product:1", "product:2"}, &values, batchLoader))
+		assert.Equal(t, map[string]product{"product:1": {Name: "fresh"}, "product:2": {Name: "cached"}}, values)
+	})
+
+	t.Run("未开启时一直返回错误", func(t *testing.T) {
+		c := createEnvelopeCache(t)
+		corrupt(t, c, "product:1")
+
+		var value product
+		for range 3 {
+			assert.Error(t, c.Get(ctx, "product:1", &value, loader))
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// prefetchTimeout 相邻键预取的超时时间
+const prefetchTimeout = 5 * time.Second
+
+// siblingPrefetcher 相邻键预取的配置和限流
+type siblingPrefetcher struct {
+	window int
+	rate   int
+
+	mu          sync.Mutex
+	windowStart time.Time
+	count       int
+}
+
+func newSiblingPrefetcher(window, rate int) *siblingPrefetcher {
+	return &siblingPrefetcher{window: window, rate: rate}
+}
+
+// allow 判断本秒内是否还能触发预取
+func (p *siblingPrefetcher) allow() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(p.windowStart) >= time.Second {
+		p.windowStart = now
+		p.count = 0
+	}
+	if p.count >= p.rate {
+		return false
+	}
+	p.count++
+	return true
+}
+
+// siblings 计算 missKeys 的相邻键：同一前缀下以最大 ID 为起点的后续 window 个 ID，排除 requested 中的键
+func (p *siblingPrefetcher) siblings(missKeys, requested []string) []string {
+	maxIDs := make(map[string]int64)
+	for _, key := range missKeys {
+		prefix, id, ok := splitSequentialKey(key)
+		if !ok {
+			continue
+		}
+		if maxID, exists := maxIDs[prefix]; !exists || id > maxID {
+			maxIDs[prefix] = id
+		}
+	}
+	if len(maxIDs) == 0 {
+		return nil
+	}
+
+	skip := make(map[string]struct{}, len(requested))
+	for _, key := range requested {
+		skip[key] = struct{}{}
+	}
+
+	var siblings []string
+	for prefix, maxID := range maxIDs {
+		for i := int64(1); i <= int64(p.window); i++ {
+			key := prefix + strconv.FormatInt(maxID+i, 10)
+			if _, ok := skip[key]; !ok {
+				siblings = append(siblings, key)
+			}
+		}
+	}
+	return siblings
+}
+
+// splitSequentialKey 将最后一段为整数 ID 的键拆分为前缀（含分隔符）和 ID
+func splitSequentialKey(key string) (string, int64, bool) {
+	i := strings.LastIndex(key, separator)
+	if i < 0 {
+		return "", 0, false
+	}
+
+	suffix := key[i+len(separator):]
+	id, err := strconv.ParseInt(suffix, 10, 64)
+	if err != nil || strconv.FormatInt(id, 10) != suffix {
+		return "", 0, false
+	}
+	return key[:i+len(separator)], id, true
+}
+
+// prefetchSiblings 异步从 Remote 预取 missKeys 的相邻键写入内存缓存
+// 已在内存中的键、删除保护窗口内的键跳过，缺失值标记和已失效的数据不预取
+func (c *LayeredCache) prefetchSiblings(ctx context.Context, missKeys, requested []string) {
+	if c.prefetcher == nil {
+		return
+	}
+
+	siblings := c.prefetcher.siblings(missKeys, requested)
+	if len(siblings) == 0 || !c.prefetcher.allow() {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	c.spawn(func() {
+		ctx, cancel := context.WithTimeout(ctx, prefetchTimeout)
+		defer cancel()
+
+		cached := c.memory.MGet(siblings)
+		keys := make([]string, 0, len(siblings))
+		for _, key := range siblings {
+			if _, ok := cached[key]; !ok && !c.isShielded(key) {
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) == 0 {
+			return
+		}
+
+		remoteData, err := c.mgetRemote(ctx, keys)
+		if c.stats.remoteError(err) != nil && !IsNotFound(err) {
+			return
+		}
+
+		prefetched := make(map[string][]byte)
+		for _, key := range keys {
+			if data, ok := remoteData[key]; ok && !c.isStale(data, 0) {
+				prefetched[key] = data
+			}
+		}
+		if len(prefetched) > 0 {
+			c.memoryMSet(prefetched, c.defaultMemoryTTL)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitSequentialKey(t *testing.T) {
+	tests := []struct {
+		key    string
+		prefix string
+		id     int64
+		ok     bool
+	}{
+		{key: "user:100", prefix: "user:", id: 100, ok: true},
+		{key: "org:1:member:7", prefix: "org:1:member:", id: 7, ok: true},
+		{key: "user:alice", ok: false},
+		{key: "user:007", ok: false},
+		{key: "100", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			prefix, id, ok := splitSequentialKey(tt.key)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.prefix, prefix)
+				assert.Equal(t, tt.id, id)
+			}
+		})
+	}
+}
+
+func TestSiblingPrefetcher(t *testing.T) {
+	p := newSiblingPrefetcher(3, 2)
+
+	t.Run("相邻键", func(t *testing.T) {
+		siblings := p.siblings([]string{"user:1", "user:2", "order:9", "tag:go"}, []string{"user:1", "user:2", "user:3"})
+		assert.ElementsMatch(t, []string{"user:4", "user:5", "order:10", "order:11", "order:12"}, siblings)
+	})
+
+	t.Run("限流", func(t *testing.T) {
+		assert.True(t, p.allow())
+		assert.True(t, p.allow())
+		assert.False(t, p.allow())
+	})
+}
+
+func TestLayeredCache_SiblingPrefetch(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("配置校验", func(t *testing.T) {
+		_, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigRemote(createRemoteAdapter(t)), WithConfigSiblingPrefetch(0, 1))
+		assert.ErrorIs(t, err, errors.ErrInvalidSiblingPrefetch)
+
+		_, err = NewCache(WithConfigRemote(createRemoteAdapter(t)), WithConfigSiblingPrefetch(10, 1))
+		assert.ErrorIs(t, err, errors.ErrBothLayersRequired)
+	})
+
+	t.Run("Remote命中后预取下一页", func(t *testing.T) {
+		cache, err := NewCache(
+			WithConfigMemory(createOtterAdapter(t)),
+			WithConfigRemote(createRemoteAdapter(t)),
+			WithConfigSiblingPrefetch(3, 10),
+		)
+		assert.NoError(t, err)
+		c := cache.(*LayeredCache)
+
+		values := make(map[string]any)
+		for i := 1; i <= 10; i++ {
+			values[fmt.Sprintf("item:%d", i)] = fmt.Sprintf("v%d", i)
+		}
+		assert.NoError(t, c.MSet(ctx, values))
+		for key := range values {
+			c.memory.Delete(key)
+		}
+
+		result := make(map[string]string)
+		assert.NoError(t, c.MGet(ctx, []string{"item:1", "item:2", "item:3"}, &result))
+		assert.Len(t, result, 3)
+
+		assert.Eventually(t, func() bool {
+			return len(c.memory.MGet([]string{"item:4", "item:5", "item:6"})) == 3
+		}, time.Second, 10*time.Millisecond)
+
+		_, exists := c.memory.Get("item:7")
+		assert.False(t, exists)
+	})
+
+	t.Run("默认关闭", func(t *testing.T) {
+		c := createTestCache(t).(*LayeredCache)
+		assert.NoError(t, c.MSet(ctx, map[string]any{"item:1": "v1", "item:2": "v2"}))
+		c.memory.Delete("item:1")
+		c.memory.Delete("item:2")
+
+		result := make(map[string]string)
+		assert.NoError(t, c.MGet(ctx, []string{"item:1"}, &result))
+		time.Sleep(20 * time.Millisecond)
+
+		_, exists := c.memory.Get("item:2")
+		assert.False(t, exists)
+	})
+}
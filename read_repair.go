@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"math/rand/v2"
+)
+
+// shouldReadRepair 判断本次内存命中是否需要回源校验
+func (c *LayeredCache) shouldReadRepair() bool {
+	if c.readRepairRate <= 0 || c.memory == nil || c.remote == nil {
+		return false
+	}
+	return c.readRepairRate >= 1 || rand.Float64() < c.readRepairRate
+}
+
+// repairMemory 以 Remote 为准校验内存命中的数据
+// 返回 false 表示 Remote 中已不存在该键，内存中的数据已被删除
+func (c *LayeredCache) repairMemory(ctx context.Context, key string, data []byte, config *getOptions) ([]byte, bool) {
+	var remoteData []byte
+	err := c.remoteCall(ctx, func(ctx context.Context) (err error) {
+		remoteData, err = c.remote.Get(ctx, key)
+		return err
+	})
+	if IsNotFound(err) {
+		c.memory.Delete(key)
+		return nil, false
+	}
+	if err != nil {
+		// Remote 异常时不影响读取，继续使用内存数据，FailOpen 时计入降级次数
+		_ = c.remoteFailed(err)
+		return data, true
+	}
+
+	if isNotFoundPlaceholder(remoteData) {
+		c.memory.Delete(key)
+		return nil, false
+	}
+
+	if !bytes.Equal(remoteData, data) {
+		memoryTTL, _ := c.calculateLoaderTTL(config)
+		c.memorySet(key, remoteData, memoryTTL)
+	}
+	return remoteData, true
+}
+
+// repairMemoryBatch 批量以 Remote 为准校验内存命中的数据
+// 返回校验后仍然有效的数据，Remote 中已不存在的键会从内存中删除
+func (c *LayeredCache) repairMemoryBatch(ctx context.Context, data map[string][]byte, config *getOptions) map[string][]byte {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+
+	var remoteData map[string][]byte
+	err := c.remoteCall(ctx, func(ctx context.Context) (err error) {
+		remoteData, err = c.remote.MGet(ctx, keys)
+		return err
+	})
+	if err != nil {
+		// Remote 异常时不影响读取，继续使用内存数据，FailOpen 时计入降级次数
+		_ = c.remoteFailed(err)
+		return data
+	}
+
+	result := make(map[string][]byte, len(data))
+	staleData := make(map[string][]byte)
+	for _, key := range keys {
+		value, exists := remoteData[key]
+		if !exists || isNotFoundPlaceholder(value) {
+			c.memory.Delete(key)
+			continue
+		}
+
+		if !bytes.Equal(value, data[key]) {
+			staleData[key] = value
+		}
+		result[key] = value
+	}
+
+	if len(staleData) > 0 {
+		memoryTTL, _ := c.calculateLoaderTTL(config)
+		c.memoryMSet(staleData, memoryTTL)
+	}
+	return result
+}
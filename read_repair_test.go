@@ -0,0 +1,154 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCache_ReadRepairRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rate    float64
+		wantErr error
+	}{
+		{name: "关闭读修复", rate: 0},
+		{name: "部分采样", rate: 0.1},
+		{name: "全量校验", rate: 1},
+		{name: "失败 - 负数比例", rate: -0.1, wantErr: errors.ErrInvalidReadRepairRate},
+		{name: "失败 - 比例大于1", rate: 1.5, wantErr: errors.ErrInvalidReadRepairRate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCache(
+				WithConfigMemory(createOtterAdapter(t)),
+				WithConfigRemote(createRemoteAdapter(t)),
+				WithConfigReadRepair(tt.rate),
+			)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLayeredCache_Get_ReadRepair(t *testing.T) {
+	ctx := context.Background()
+
+	newCache := func(t *testing.T, rate float64) *LayeredCache {
+		c, err := NewCache(
+			WithConfigMemory(createOtterAdapter(t)),
+			WithConfigRemote(createRemoteAdapter(t)),
+			WithConfigReadRepair(rate),
+		)
+		assert.NoError(t, err)
+		return c.(*LayeredCache)
+	}
+
+	t.Run("内存数据过期时以Redis为准并修复内存", func(t *testing.T) {
+		c := newCache(t, 1)
+		assert.NoError(t, c.Set(ctx, "repair-key", "old"))
+		assert.NoError(t, c.remote.Set(ctx, "repair-key", []byte("new"), time.Hour))
+
+		var result string
+		assert.NoError(t, c.Get(ctx, "repair-key", &result))
+		assert.Equal(t, "new", result)
+
+		data, exists := c.memory.Get("repair-key")
+		assert.True(t, exists)
+		assert.Equal(t, []byte("new"), data)
+	})
+
+	t.Run("Redis中已删除时清理内存并回源", func(t *testing.T) {
+		c := newCache(t, 1)
+		assert.NoError(t, c.Set(ctx, "deleted-key", "old"))
+		assert.NoError(t, c.remote.Delete(ctx, "deleted-key"))
+
+		var result string
+		err := c.Get(ctx, "deleted-key", &result)
+		assert.ErrorIs(t, err, errors.ErrNotFound)
+
+		_, exists := c.memory.Get("deleted-key")
+		assert.False(t, exists)
+
+		err = c.Get(ctx, "deleted-key", &result, WithLoader(func(ctx context.Context, key string) (any, error) {
+			return "loaded", nil
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, "loaded", result)
+	})
+
+	t.Run("关闭读修复时直接使用内存数据", func(t *testing.T) {
+		c := newCache(t, 0)
+		assert.NoError(t, c.Set(ctx, "no-repair-key", "old"))
+		assert.NoError(t, c.remote.Set(ctx, "no-repair-key", []byte("new"), time.Hour))
+
+		var result string
+		assert.NoError(t, c.Get(ctx, "no-repair-key", &result))
+		assert.Equal(t, "old", result)
+	})
+
+	t.Run("Redis异常时使用内存数据并计入熔断", func(t *testing.T) {
+		cache, err := NewCache(
+			WithConfigMemory(createOtterAdapter(t)),
+			WithConfigRemote(downRemote{failingRemote{Remote: createRemoteAdapter(t)}}),
+			WithConfigReadRepair(1),
+			WithConfigRemoteBreaker(1, time.Minute),
+		)
+		assert.NoError(t, err)
+		c := cache.(*LayeredCache)
+		c.memory.Set("down-key", []byte("old"), time.Hour)
+
+		var result string
+		for range 2 {
+			assert.NoError(t, c.Get(ctx, "down-key", &result))
+			assert.Equal(t, "old", result)
+		}
+		stats := c.Stats()
+		assert.Equal(t, int64(1), stats.RemoteErrors)
+		assert.Equal(t, int64(1), stats.RemoteRejects)
+	})
+
+	t.Run("仅内存适配器时忽略读修复", func(t *testing.T) {
+		cache, err := NewCache(WithConfigMemory(createOtterAdapter(t)), WithConfigReadRepair(1))
+		assert.NoError(t, err)
+		assert.NoError(t, cache.Set(ctx, "memory-only-key", "value"))
+
+		var result string
+		assert.NoError(t, cache.Get(ctx, "memory-only-key", &result))
+		assert.Equal(t, "value", result)
+	})
+}
+
+func TestLayeredCache_MGet_ReadRepair(t *testing.T) {
+	ctx := context.Background()
+
+	cache, err := NewCache(
+		WithConfigMemory(createOtterAdapter(t)),
+		WithConfigRemote(createRemoteAdapter(t)),
+		WithConfigReadRepair(1),
+	)
+	assert.NoError(t, err)
+	c := cache.(*LayeredCache)
+
+	assert.NoError(t, c.MSet(ctx, map[string]any{
+		"k1": "v1",
+		"k2": "v2",
+		"k3": "v3",
+	}))
+	assert.NoError(t, c.remote.Set(ctx, "k1", []byte("v1-new"), time.Hour))
+	assert.NoError(t, c.remote.Delete(ctx, "k3"))
+
+	result := make(map[string]string)
+	assert.NoError(t, c.MGet(ctx, []string{"k1", "k2", "k3"}, &result))
+	assert.Equal(t, map[string]string{"k1": "v1-new", "k2": "v2"}, result)
+
+	data, exists := c.memory.Get("k1")
+	assert.True(t, exists)
+	assert.Equal(t, []byte("v1-new"), data)
+
+	_, exists = c.memory.Get("k3")
+	assert.False(t, exists)
+}
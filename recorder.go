@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Layer 缓存交互发生的层
+type Layer string
+
+const (
+	LayerMemory Layer = "memory"
+	LayerRemote Layer = "remote"
+	LayerLoader Layer = "loader"
+)
+
+// Interaction 一次与缓存层的交互
+type Interaction struct {
+	// Op 发起交互的操作：get、mget、set、mset、delete、mdelete
+	Op    string
+	Layer Layer
+	Keys  []string
+	// Hits 在该层命中的键数量（含缺失值标记），写入操作为 0
+	Hits     int
+	Duration time.Duration
+	Err      error
+}
+
+// Recorder 记录单个请求链路上的缓存交互，可在请求结束时写入访问日志或调试信息
+// 并发安全；singleflight 合并的加载只记录在实际执行 loader 的请求中
+type Recorder struct {
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+type recorderKey struct{}
+
+// WithRecorder 返回携带 Recorder 的 context，使用该 context 的缓存操作都会被记录
+func WithRecorder(ctx context.Context) (context.Context, *Recorder) {
+	rec := &Recorder{}
+	return context.WithValue(ctx, recorderKey{}, rec), rec
+}
+
+// recorderFrom 返回 context 中的 Recorder，不存在时返回 nil
+func recorderFrom(ctx context.Context) *Recorder {
+	rec, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return rec
+}
+
+// Interactions 返回已记录的交互
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ret := make([]Interaction, len(r.interactions))
+	copy(ret, r.interactions)
+	return ret
+}
+
+// now 返回交互开始时间，未开启记录时返回零值以避免多余的开销
+func (r *Recorder) now() time.Time {
+	if r == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// record 记录一次交互，未开启记录时忽略
+func (r *Recorder) record(op string, layer Layer, keys []string, hits int, start time.Time, err error) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, Interaction{
+		Op:       op,
+		Layer:    layer,
+		Keys:     keys,
+		Hits:     hits,
+		Duration: time.Since(start),
+		Err:      err,
+	})
+}
+
+// countFound 统计在 data 中找到值或缺失值标记的键数量
+func countFound(keys []string, data map[string][]byte) int {
+	count := 0
+	for _, key := range keys {
+		if _, ok := data[key]; ok {
+			count++
+		} else if _, ok = data[notFoundKey(key)]; ok {
+			count++
+		}
+	}
+	return count
+}
+
+func mapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// layersOf 返回交互的操作和层，便于断言
+func layersOf(interactions []Interaction) []string {
+	ret := make([]string, 0, len(interactions))
+	for _, interaction := range interactions {
+		ret = append(ret, interaction.Op+":"+string(interaction.Layer))
+	}
+	return ret
+}
+
+func TestWithRecorder(t *testing.T) {
+	c := createTestCache(t).(*LayeredCache)
+
+	t.Run("记录Get在各层的交互", func(t *testing.T) {
+		ctx, rec := WithRecorder(context.Background())
+
+		var result string
+		err := c.Get(ctx, "user:1", &result, WithLoader(func(ctx context.Context, key string) (any, error) {
+			return "Alice", nil
+		}))
+		assert.NoError(t, err)
+		assert.NoError(t, c.Get(ctx, "user:1", &result))
+
+		interactions := rec.Interactions()
+		assert.Equal(t, []string{"get:memory", "get:remote", "get:loader", "get:memory"}, layersOf(interactions))
+		assert.Equal(t, 0, interactions[0].Hits)
+		assert.Equal(t, 1, interactions[2].Hits)
+		assert.Equal(t, 1, interactions[3].Hits)
+		assert.Equal(t, []string{"user:1"}, interactions[3].Keys)
+	})
+
+	t.Run("记录MGet和写入", func(t *testing.T) {
+		ctx, rec := WithRecorder(context.Background())
+
+		assert.NoError(t, c.MSet(ctx, map[string]any{"k1": "v1"}))
+		c.memory.Delete("k1")
+
+		result := make(map[string]string)
+		err := c.MGet(ctx, []string{"k1", "k2"}, &result, WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+			return map[string]any{"k2": "v2"}, nil
+		}))
+		assert.NoError(t, err)
+		assert.NoError(t, c.Delete(ctx, "k1"))
+
+		interactions := rec.Interactions()
+		assert.Equal(t, []string{"mset:remote", "mget:memory", "mget:remote", "mget:loader", "delete:remote"}, layersOf(interactions))
+		assert.Equal(t, 0, interactions[1].Hits)
+		assert.Equal(t, 1, interactions[2].Hits)
+		assert.Equal(t, []string{"k2"}, interactions[3].Keys)
+	})
+
+	t.Run("未开启时不记录", func(t *testing.T) {
+		assert.Nil(t, recorderFrom(context.Background()))
+
+		var result string
+		assert.NotPanics(t, func() {
+			_ = c.Get(context.Background(), "user:1", &result)
+		})
+	})
+}
@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// refreshTrackFactor 记录 loader 的键数上限为 maxKeys 的倍数，避免大量冷键占用内存
+const refreshTrackFactor = 8
+
+// RefreshKeysFunc 返回本轮需要提前刷新的键
+type RefreshKeysFunc func(ctx context.Context) []string
+
+// refresher 记录带 loader 的 Get 调用，定期重新调用 loader 刷新热点键，使内存缓存在过期前保持最新
+type refresher struct {
+	maxKeys int
+
+	// keys 为 nil 时按访问次数选出热点键
+	keys RefreshKeysFunc
+
+	mu      sync.Mutex
+	tracked map[string]*refreshEntry
+}
+
+// refreshEntry 一个键最近一次 Get 使用的选项和本轮访问次数
+type refreshEntry struct {
+	config *getOptions
+	hits   int
+}
+
+func newRefresher(maxKeys int, keys RefreshKeysFunc) *refresher {
+	return &refresher{maxKeys: maxKeys, keys: keys, tracked: make(map[string]*refreshEntry)}
+}
+
+// track 记录一次带 loader 的 Get，已达到记录上限时忽略新键
+func (r *refresher) track(key string, config *getOptions) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.tracked[key]
+	if !ok {
+		if len(r.tracked) >= r.maxKeys*refreshTrackFactor {
+			return
+		}
+		entry = &refreshEntry{}
+		r.tracked[key] = entry
+	}
+	entry.config = config
+	entry.hits++
+}
+
+// due 返回本轮需要刷新的键及其选项，并开始新一轮计数
+// 按访问次数选取时，本轮没有访问过的键不再记录
+func (r *refresher) due(ctx context.Context) map[string]*getOptions {
+	var wanted []string
+	if r.keys != nil {
+		wanted = r.keys(ctx)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ret := make(map[string]*getOptions)
+	if r.keys != nil {
+		for _, key := range wanted {
+			if entry, ok := r.tracked[key]; ok && len(ret) < r.maxKeys {
+				ret[key] = entry.config
+			}
+		}
+		for _, entry := range r.tracked {
+			entry.hits = 0
+		}
+		return ret
+	}
+
+	hot := make([]string, 0, len(r.tracked))
+	for key, entry := range r.tracked {
+		if entry.hits == 0 {
+			delete(r.tracked, key)
+			continue
+		}
+		hot = append(hot, key)
+	}
+	slices.SortFunc(hot, func(a, b string) int {
+		return cmp.Compare(r.tracked[b].hits, r.tracked[a].hits)
+	})
+	for _, key := range hot[:min(len(hot), r.maxKeys)] {
+		ret[key] = r.tracked[key].config
+	}
+	for _, entry := range r.tracked {
+		entry.hits = 0
+	}
+	return ret
+}
+
+// trackRefresh 记录带 loader 的 Get，未开启提前刷新时为空操作
+func (c *LayeredCache) trackRefresh(key string, config *getOptions) {
+	if c.refresher == nil || config.loader == nil {
+		return
+	}
+	c.refresher.track(key, config)
+}
+
+// refreshAhead 重新调用 loader 加载本轮需要刷新的键并写入缓存，与同一键的 Get 共用 singleflight
+func (c *LayeredCache) refreshAhead(ctx context.Context) {
+	for key, config := range c.refresher.due(ctx) {
+		if ctx.Err() != nil {
+			return
+		}
+		_, err := c.load(ctx, key, config, func(ctx context.Context) (any, error) {
+			return c.loadAndCache(ctx, key, config)
+		})
+		if err == nil || IsNotFound(err) {
+			c.stats.refreshes.Add(1)
+		}
+	}
+}
+
+// startRefreshAhead 启动提前刷新的定时任务，缓存关闭时停止
+func (c *LayeredCache) startRefreshAhead(interval time.Duration) {
+	c.schedule(intervalSchedule(interval), func() {
+		c.refreshAhead(context.Background())
+	})
+}
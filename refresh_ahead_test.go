@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_RefreshAhead(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("配置校验", func(t *testing.T) {
+		_, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigRefreshAhead(0, 10, nil))
+		assert.ErrorIs(t, err, errors.ErrInvalidRefreshAhead)
+
+		_, err = NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigRefreshAhead(time.Second, 0, nil))
+		assert.ErrorIs(t, err, errors.ErrInvalidRefreshAhead)
+	})
+
+	t.Run("刷新访问最多的键", func(t *testing.T) {
+		c, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigRemote(createRemoteAdapter(t)),
+			WithConfigRefreshAhead(time.Hour, 1, nil),
+		)
+		assert.NoError(t, err)
+		lc := c.(*LayeredCache)
+
+		calls := make(map[string]int)
+		loader := func(ctx context.Context, key string) (any, error) {
+			calls[key]++
+			return fmt.Sprintf("%s-%d", key, calls[key]), nil
+		}
+		var value string
+		for range 3 {
+			assert.NoError(t, c.Get(ctx, "hot", &value, WithLoader(loader)))
+		}
+		assert.NoError(t, c.Get(ctx, "cold", &value, WithLoader(loader)))
+		assert.ErrorIs(t, c.Get(ctx, "plain", &value), errors.ErrNotFound)
+
+		lc.refreshAhead(ctx)
+		assert.Equal(t, map[string]int{"hot": 2, "cold": 1}, calls)
+		assert.Equal(t, int64(1), lc.Stats().Refreshes)
+
+		// 内存和 Remote 都已更新为新值
+		assert.NoError(t, c.Get(ctx, "hot", &value))
+		assert.Equal(t, "hot-2", value)
+		lc.memory.Delete("hot")
+		assert.NoError(t, c.Get(ctx, "hot", &value))
+		assert.Equal(t, "hot-2", value)
+
+		// 上一轮没有访问的键不再刷新
+		lc.refreshAhead(ctx)
+		lc.refreshAhead(ctx)
+		assert.Equal(t, map[string]int{"hot": 2, "cold": 1}, calls)
+	})
+
+	t.Run("刷新指定的键", func(t *testing.T) {
+		c, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigRefreshAhead(time.Hour, 10, func(ctx context.Context) []string {
+				return []string{"a", "unknown"}
+			}),
+		)
+		assert.NoError(t, err)
+		lc := c.(*LayeredCache)
+
+		var calls atomic.Int64
+		loader := func(ctx context.Context, key string) (any, error) {
+			calls.Add(1)
+			return key, nil
+		}
+		var value string
+		assert.NoError(t, c.Get(ctx, "a", &value, WithLoader(loader)))
+		assert.NoError(t, c.Get(ctx, "b", &value, WithLoader(loader)))
+
+		// 未访问的指定键仍然刷新
+		lc.refreshAhead(ctx)
+		lc.refreshAhead(ctx)
+		assert.Equal(t, int64(4), calls.Load())
+		assert.Equal(t, int64(2), lc.Stats().Refreshes)
+	})
+
+	t.Run("后台定时刷新", func(t *testing.T) {
+		c, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigRefreshAhead(10*time.Millisecond, 10, nil),
+		)
+		assert.NoError(t, err)
+
+		var calls atomic.Int64
+		var value string
+		assert.NoError(t, c.Get(ctx, "k", &value, WithLoader(func(ctx context.Context, key string) (any, error) {
+			calls.Add(1)
+			return "v", nil
+		})))
+		assert.Eventually(t, func() bool { return calls.Load() >= 2 }, time.Second, 5*time.Millisecond)
+		assert.NoError(t, c.Close(ctx))
+	})
+}
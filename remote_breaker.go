@@ -0,0 +1,25 @@
+package cache
+
+import "context"
+
+// remoteCall 调用 Remote 并统计错误
+// 开启 Remote 熔断时，熔断打开期间直接返回 ErrCircuitOpen 而不访问 Remote，避免每个请求都等待 Remote 超时；
+// 配合 WithConfigRemoteFailurePolicy(FailOpen) 时按 Remote 出错处理，即绕过 Remote 只使用内存和 loader
+func (c *LayeredCache) remoteCall(ctx context.Context, fn func(ctx context.Context) error) error {
+	if c.remoteGuard == nil {
+		return c.stats.remoteError(fn(ctx))
+	}
+
+	labels, err := c.remoteGuard.acquire([]string{""})
+	if err != nil {
+		c.stats.remoteRejects.Add(1)
+		return err
+	}
+	err = c.stats.remoteError(fn(ctx))
+	if IsNotFound(err) {
+		c.remoteGuard.report(labels, nil)
+	} else {
+		c.remoteGuard.report(labels, err)
+	}
+	return err
+}
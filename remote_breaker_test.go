@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"context"
+	stderrors "errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// probedRemote 统计 Remote 读取次数
+type probedRemote struct {
+	downRemote
+	calls *atomic.Int64
+}
+
+func (r probedRemote) MGet(ctx context.Context, keys []string) (map[string][]byte, error) {
+	r.calls.Add(1)
+	return r.downRemote.MGet(ctx, keys)
+}
+
+func TestLayeredCache_RemoteBreaker(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("配置校验", func(t *testing.T) {
+		_, err := NewCache(WithConfigRemote(createRemoteAdapter(t)), WithConfigRemoteBreaker(0, time.Second))
+		assert.ErrorIs(t, err, errors.ErrInvalidRemoteBreaker)
+
+		_, err = NewCache(WithConfigRemote(createRemoteAdapter(t)), WithConfigRemoteBreaker(1, 0))
+		assert.ErrorIs(t, err, errors.ErrInvalidRemoteBreaker)
+	})
+
+	t.Run("熔断后不再访问 Remote", func(t *testing.T) {
+		var calls atomic.Int64
+		c, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigRemote(probedRemote{downRemote: downRemote{failingRemote{Remote: createRemoteAdapter(t)}}, calls: &calls}),
+			WithConfigRemoteBreaker(2, time.Minute),
+		)
+		assert.NoError(t, err)
+
+		var value string
+		for range 2 {
+			assert.Error(t, c.Get(ctx, "k", &value))
+		}
+		assert.ErrorIs(t, c.Get(ctx, "k", &value), errors.ErrCircuitOpen)
+		assert.ErrorIs(t, c.MGet(ctx, []string{"k"}, &map[string]string{}), errors.ErrCircuitOpen)
+		assert.Equal(t, int64(2), calls.Load())
+		assert.Equal(t, int64(2), c.(*LayeredCache).Stats().RemoteRejects)
+	})
+
+	t.Run("FailOpen 时绕过 Remote", func(t *testing.T) {
+		var calls atomic.Int64
+		c, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigRemote(probedRemote{downRemote: downRemote{failingRemote{Remote: createRemoteAdapter(t)}}, calls: &calls}),
+			WithConfigRemoteBreaker(1, time.Minute),
+			WithConfigRemoteFailurePolicy(FailOpen),
+		)
+		assert.NoError(t, err)
+
+		loader := WithLoader(func(ctx context.Context, key string) (any, error) {
+			return "loaded", nil
+		})
+		var value string
+		for _, key := range []string{"k1", "k2", "k3"} {
+			assert.NoError(t, c.Get(ctx, key, &value, loader))
+			assert.Equal(t, "loaded", value)
+		}
+		assert.Equal(t, int64(1), calls.Load())
+	})
+
+	t.Run("冷却后探测成功恢复", func(t *testing.T) {
+		remote := &switchRemote{Remote: createRemoteAdapter(t)}
+		remote.down.Store(true)
+		c, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigRemote(remote),
+			WithConfigRemoteBreaker(1, time.Second),
+		)
+		assert.NoError(t, err)
+		clock := time.Unix(0, 0)
+		c.(*LayeredCache).remoteGuard.now = func() time.Time { return clock }
+
+		assert.Error(t, c.Set(ctx, "k", "v"))
+		assert.ErrorIs(t, c.Set(ctx, "k", "v"), errors.ErrCircuitOpen)
+
+		remote.down.Store(false)
+		clock = clock.Add(time.Second)
+		assert.NoError(t, c.Set(ctx, "k", "v"))
+		assert.NoError(t, c.Set(ctx, "k", "v2"))
+	})
+}
+
+// switchRemote down 为 true 时写入失败
+type switchRemote struct {
+	storage.Remote
+	down atomic.Bool
+}
+
+func (r *switchRemote) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if r.down.Load() {
+		return stderrors.New("remote down")
+	}
+	return r.Remote.Set(ctx, key, value, ttl)
+}
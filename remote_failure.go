@@ -0,0 +1,22 @@
+package cache
+
+// RemoteFailurePolicy Remote 读写出错时的处理策略
+type RemoteFailurePolicy int
+
+const (
+	// FailClosed Remote 出错时返回错误，默认策略
+	FailClosed RemoteFailurePolicy = iota
+
+	// FailOpen Remote 出错时降级为只使用内存缓存和 loader：写入只保留在内存中，读取视为未命中继续回源，
+	// 错误计入 Stats.RemoteFallbacks；Remote 恢复前其他实例可能读到旧值
+	FailOpen
+)
+
+// remoteFailed 按降级策略处理 Remote 读写返回的错误，FailOpen 时忽略错误并返回 nil，ErrNotFound 原样返回
+func (c *LayeredCache) remoteFailed(err error) error {
+	if err == nil || IsNotFound(err) || c.remoteFailurePolicy != FailOpen {
+		return err
+	}
+	c.stats.remoteFallbacks.Add(1)
+	return nil
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// downRemote 读写都失败
+type downRemote struct {
+	failingRemote
+}
+
+func (r downRemote) Get(ctx context.Context, key string) ([]byte, error) {
+	return nil, stderrors.New("remote down")
+}
+
+func (r downRemote) MGet(ctx context.Context, keys []string) (map[string][]byte, error) {
+	return nil, stderrors.New("remote down")
+}
+
+func TestLayeredCache_RemoteFailurePolicy(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("配置校验", func(t *testing.T) {
+		_, err := NewCache(WithConfigRemote(createRemoteAdapter(t)), WithConfigRemoteFailurePolicy(RemoteFailurePolicy(9)))
+		assert.ErrorIs(t, err, errors.ErrInvalidRemoteFailurePolicy)
+	})
+
+	t.Run("默认返回 Remote 错误", func(t *testing.T) {
+		c, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigRemote(downRemote{failingRemote{Remote: createRemoteAdapter(t)}}),
+		)
+		assert.NoError(t, err)
+
+		assert.Error(t, c.Set(ctx, "k", "v"))
+		var value string
+		err = c.Get(ctx, "missing", &value, WithLoader(func(ctx context.Context, key string) (any, error) {
+			return "loaded", nil
+		}))
+		assert.Error(t, err)
+	})
+
+	t.Run("FailOpen 降级为内存和 loader", func(t *testing.T) {
+		c, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigRemote(downRemote{failingRemote{Remote: createRemoteAdapter(t)}}),
+			WithConfigRemoteFailurePolicy(FailOpen),
+		)
+		assert.NoError(t, err)
+
+		assert.NoError(t, c.Set(ctx, "k", "v"))
+		assert.NoError(t, c.MSet(ctx, map[string]any{"m1": "a", "m2": "b"}))
+		var value string
+		assert.NoError(t, c.Get(ctx, "k", &value))
+		assert.Equal(t, "v", value)
+
+		calls := 0
+		err = c.Get(ctx, "missing", &value, WithLoader(func(ctx context.Context, key string) (any, error) {
+			calls++
+			return "loaded", nil
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, "loaded", value)
+
+		values := make(map[string]string)
+		err = c.MGet(ctx, []string{"m1", "b1"}, &values, WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+			calls++
+			return map[string]any{"b1": "batch"}, nil
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"m1": "a", "b1": "batch"}, values)
+		assert.Equal(t, 2, calls)
+
+		err = c.Get(ctx, "none", &value)
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Positive(t, c.(*LayeredCache).Stats().RemoteFallbacks)
+	})
+}
@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"context"
+	"strings"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+)
+
+// RemoteKeys 以游标分页遍历 Remote 中以 prefix 开头的键，基于 SCAN 实现，不会像 KEYS 一样阻塞 Redis
+// cursor 首次传 0，返回的游标为 0 表示遍历结束；count 为单次遍历的建议数量
+// 遍历期间新增或删除的键可能不会返回，同一个键也可能返回多次；缺失值标记、依赖集合和标签集合不会返回
+func (c *LayeredCache) RemoteKeys(ctx context.Context, prefix string, cursor uint64, count int) ([]string, uint64, error) {
+	if c.remote == nil {
+		return nil, 0, errors.ErrRemoteRequired
+	}
+	scanner, ok := c.remote.(storage.Scanner)
+	if !ok {
+		return nil, 0, errors.ErrOperationNotSupported
+	}
+
+	scope, ctx := c.takeKeyContext(ctx)
+	if scope.hasher != nil {
+		return nil, 0, errors.ErrCustomKeyLayout
+	}
+	keys, next, err := scanner.Scan(ctx, escapeGlob(scope.prefix+prefix)+"*", cursor, count)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := keys[:0]
+	for _, key := range keys {
+		if !strings.HasSuffix(key, notFoundKeySuffix) && !strings.HasSuffix(key, dependentsKeySuffix) && !strings.HasSuffix(key, tagKeySuffix) {
+			result = append(result, strings.TrimPrefix(key, scope.prefix))
+		}
+	}
+	return result, next, nil
+}
+
+// escapeGlob 转义 Redis glob 模式中的特殊字符
+func escapeGlob(s string) string {
+	var builder strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			builder.WriteByte('\\')
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
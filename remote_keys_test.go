@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_RemoteKeys(t *testing.T) {
+	ctx := context.Background()
+	cache := createTestCache(t)
+	c := cache.(*LayeredCache)
+
+	for i := 0; i < 25; i++ {
+		assert.NoError(t, c.Set(ctx, fmt.Sprintf("user:%d", i), i))
+	}
+	assert.NoError(t, c.Set(ctx, "video:1", 1))
+	assert.NoError(t, c.Set(ctx, "user*:1", 1))
+	assert.NoError(t, c.remote.Set(ctx, notFoundKey("user:404"), notFoundPlaceholder, time.Minute))
+
+	t.Run("分页遍历前缀", func(t *testing.T) {
+		var keys []string
+		var cursor uint64
+		pages := 0
+		for {
+			batch, next, err := c.RemoteKeys(ctx, "user:", cursor, 10)
+			assert.NoError(t, err)
+			keys = append(keys, batch...)
+			pages++
+			if next == 0 {
+				break
+			}
+			cursor = next
+		}
+
+		sort.Strings(keys)
+		assert.Len(t, keys, 25)
+		assert.Equal(t, "user:0", keys[0])
+		assert.Greater(t, pages, 1)
+	})
+
+	t.Run("前缀中的通配符按字面匹配", func(t *testing.T) {
+		keys, next, err := c.RemoteKeys(ctx, "user*", 0, 100)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), next)
+		assert.Equal(t, []string{"user*:1"}, keys)
+	})
+
+	t.Run("未配置Remote", func(t *testing.T) {
+		_, _, err := createMemoryOnlyCache(t).RemoteKeys(ctx, "user:", 0, 10)
+		assert.ErrorIs(t, err, errors.ErrRemoteRequired)
+	})
+
+	t.Run("Remote不支持遍历", func(t *testing.T) {
+		cache, err := NewCache(WithConfigRemote(&countingRemote{Remote: createRemoteAdapter(t)}))
+		assert.NoError(t, err)
+		_, _, err = cache.RemoteKeys(ctx, "user:", 0, 10)
+		assert.ErrorIs(t, err, errors.ErrOperationNotSupported)
+	})
+}
+
+func TestEscapeGlob(t *testing.T) {
+	assert.Equal(t, "user:", escapeGlob("user:"))
+	assert.Equal(t, `a\*b\?c\[d\]e\\f`, escapeGlob(`a*b?c[d]e\f`))
+}
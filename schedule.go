@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+)
+
+// schedule 计算下一次执行的时间，返回零值表示不再执行
+type schedule interface {
+	next(t time.Time) time.Time
+}
+
+// intervalSchedule 固定间隔执行
+type intervalSchedule time.Duration
+
+func (s intervalSchedule) next(t time.Time) time.Time {
+	return t.Add(time.Duration(s))
+}
+
+// ScheduleInvalidation 按 cron 表达式定时删除 Remote 中以 prefix 开头的键，例如每晚刷新价格缓存
+// cron 为标准 5 段表达式（分 时 日 月 周，本地时区），也支持 @hourly、@daily 等预定义表达式
+// 删除通过 RemoteKeys 遍历后 MDelete 完成，同时删除这些键的内存缓存；只存在于内存中的键不会被清理
+// 返回的 stop 用于停止定时任务，不会中断正在进行的清理
+func (c *LayeredCache) ScheduleInvalidation(prefix string, cron string) (stop func(), err error) {
+	if c.remote == nil {
+		return nil, errors.ErrRemoteRequired
+	}
+	scanner, ok := c.remote.(storage.Scanner)
+	if !ok {
+		return nil, errors.ErrOperationNotSupported
+	}
+	// Redis 集群等配置在 Scan 时才返回 ErrOperationNotSupported，注册时提前检查，避免定时任务每次静默失败
+	if _, _, err := scanner.Scan(context.Background(), "", 0, 1); errors.Is(err, errors.ErrOperationNotSupported) {
+		return nil, err
+	}
+
+	s, err := parseCron(cron)
+	if err != nil {
+		return nil, err
+	}
+	stop = c.schedule(s, func() {
+		_ = c.deleteRemotePrefix(context.Background(), prefix)
+	})
+	if stop == nil {
+		return nil, errors.ErrClosed
+	}
+	return stop, nil
+}
+
+// schedule 在后台按 s 定时执行 fn，上一次执行结束后才计算下一次的时间，缓存关闭时停止；已关闭时返回 nil
+func (c *LayeredCache) schedule(s schedule, fn func()) (stop func()) {
+	done := make(chan struct{})
+	started := c.spawn(func() {
+		for {
+			next := s.next(time.Now())
+			if next.IsZero() {
+				return
+			}
+
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-done:
+				timer.Stop()
+				return
+			case <-c.life.done:
+				timer.Stop()
+				return
+			case <-timer.C:
+				fn()
+			}
+		}
+	})
+	if !started {
+		return nil
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
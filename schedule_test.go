@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_ScheduleInvalidation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("删除前缀下的键", func(t *testing.T) {
+		c := createTestCache(t).(*LayeredCache)
+		for i := 0; i < 25; i++ {
+			assert.NoError(t, c.Set(ctx, fmt.Sprintf("price:%d", i), i))
+		}
+		assert.NoError(t, c.Set(ctx, "user:1", 1))
+
+		assert.NoError(t, c.deleteRemotePrefix(ctx, "price:"))
+
+		var value int
+		for i := 0; i < 25; i++ {
+			assert.ErrorIs(t, c.Get(ctx, fmt.Sprintf("price:%d", i), &value), errors.ErrNotFound)
+		}
+		assert.NoError(t, c.Get(ctx, "user:1", &value))
+	})
+
+	t.Run("按调度重复执行直到stop", func(t *testing.T) {
+		c := createTestCache(t).(*LayeredCache)
+		var runs atomic.Int32
+		stop := c.schedule(intervalSchedule(10*time.Millisecond), func() {
+			runs.Add(1)
+		})
+
+		assert.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, 5*time.Millisecond)
+		stop()
+		stop()
+		n := runs.Load()
+		time.Sleep(50 * time.Millisecond)
+		assert.LessOrEqual(t, runs.Load(), n+1)
+	})
+
+	t.Run("参数校验", func(t *testing.T) {
+		c := createTestCache(t)
+		_, err := c.ScheduleInvalidation("price:", "bad")
+		assert.ErrorIs(t, err, errors.ErrInvalidCron)
+
+		stop, err := c.ScheduleInvalidation("price:", "@daily")
+		assert.NoError(t, err)
+		stop()
+
+		memoryOnly, err := NewCache(WithConfigMemory(createOtterAdapter(t)))
+		assert.NoError(t, err)
+		_, err = memoryOnly.ScheduleInvalidation("price:", "@daily")
+		assert.ErrorIs(t, err, errors.ErrRemoteRequired)
+
+		plain, err := NewCache(WithConfigRemote(plainRemote{createRemoteAdapter(t)}))
+		assert.NoError(t, err)
+		_, err = plain.ScheduleInvalidation("price:", "@daily")
+		assert.ErrorIs(t, err, errors.ErrOperationNotSupported)
+	})
+}
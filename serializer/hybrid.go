@@ -0,0 +1,72 @@
+package serializer
+
+import "strings"
+
+// Format 序列化格式
+type Format byte
+
+const (
+	FormatMsgPack Format = iota
+	FormatJSON
+)
+
+// FormatSerializer 可以识别多种格式的序列化器
+type FormatSerializer interface {
+	Serializer
+
+	// Format 返回 Marshal 写入的格式
+	Format() Format
+
+	// UnmarshalFormat 按指定格式反序列化
+	UnmarshalFormat(data []byte, format Format, v any) error
+}
+
+var _ FormatSerializer = (*hybrid)(nil)
+
+// hybrid 按指定格式写入，读取时同时兼容 JSON 和 MessagePack
+type hybrid struct {
+	format  Format
+	json    Serializer
+	msgpack Serializer
+}
+
+// Auto 根据运行环境选择写入格式：dev、development、local、test 使用 JSON，便于通过 redis-cli 直接查看；
+// 其他环境使用压缩后的 MessagePack。读取时自动识别两种格式，切换环境后已有的数据仍可读取
+func Auto(env string) Serializer {
+	format := FormatMsgPack
+	switch strings.ToLower(env) {
+	case "dev", "development", "local", "test":
+		format = FormatJSON
+	}
+	return &hybrid{format: format, json: NewSonicJson(), msgpack: NewMsgPackCompress()}
+}
+
+// Format implements FormatSerializer.
+func (h *hybrid) Format() Format {
+	return h.format
+}
+
+// Marshal implements Serializer.
+func (h *hybrid) Marshal(v any) ([]byte, error) {
+	if h.format == FormatJSON {
+		return h.json.Marshal(v)
+	}
+	return h.msgpack.Marshal(v)
+}
+
+// Unmarshal implements Serializer.
+// MessagePack 数据的最后一个字节是压缩标记（0x0 或 0x1），合法的 JSON 不会以这两个字节结尾
+func (h *hybrid) Unmarshal(data []byte, v any) error {
+	if len(data) > 0 && (data[len(data)-1] == noCompression || data[len(data)-1] == s2Compression) {
+		return h.msgpack.Unmarshal(data, v)
+	}
+	return h.json.Unmarshal(data, v)
+}
+
+// UnmarshalFormat implements FormatSerializer.
+func (h *hybrid) UnmarshalFormat(data []byte, format Format, v any) error {
+	if format == FormatJSON {
+		return h.json.Unmarshal(data, v)
+	}
+	return h.msgpack.Unmarshal(data, v)
+}
@@ -0,0 +1,25 @@
+package serializer
+
+import (
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+var _ Serializer = (*msgpackSerializer)(nil)
+
+// MessagePack，结构体较多的数据比 JSON 更小，减少 Redis 的内存和带宽占用
+// 需要压缩时使用 NewMsgPackCompress
+type msgpackSerializer struct{}
+
+func NewMsgpack() Serializer {
+	return &msgpackSerializer{}
+}
+
+// Marshal implements Serializer.
+func (s *msgpackSerializer) Marshal(v any) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+// Unmarshal implements Serializer.
+func (s *msgpackSerializer) Unmarshal(data []byte, v any) error {
+	return msgpack.Unmarshal(data, v)
+}
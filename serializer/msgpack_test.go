@@ -0,0 +1,39 @@
+package serializer
+
+import (
+	"reflect"
+	"testing"
+)
+
+type msgpackUser struct {
+	ID    int64             `json:"id" msgpack:"id"`
+	Name  string            `json:"name" msgpack:"name"`
+	Tags  []string          `json:"tags" msgpack:"tags"`
+	Attrs map[string]string `json:"attrs" msgpack:"attrs"`
+}
+
+func TestMsgpack(t *testing.T) {
+	s := NewMsgpack()
+	user := msgpackUser{ID: 1, Name: "Alice", Tags: []string{"a", "b"}, Attrs: map[string]string{"k": "v"}}
+
+	data, err := s.Marshal(user)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var got msgpackUser
+	if err = s.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(user, got) {
+		t.Errorf("Unmarshal() = %+v, want %+v", got, user)
+	}
+
+	jsonData, err := NewStdJson().Marshal(user)
+	if err != nil {
+		t.Fatalf("json Marshal() error = %v", err)
+	}
+	if len(data) >= len(jsonData) {
+		t.Errorf("msgpack 应该比 JSON 更小: msgpack %d, json %d", len(data), len(jsonData))
+	}
+}
@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/biu7/layered-cache/serializer"
+)
+
+// serializerRegistry 按类型注册的序列化器
+var serializerRegistry = struct {
+	sync.RWMutex
+	byType map[reflect.Type]serializer.Serializer
+}{
+	byType: make(map[reflect.Type]serializer.Serializer),
+}
+
+// RegisterSerializer 为类型 T 注册序列化器，所有缓存实例写入 T 或 *T 的值、读取到 *T 的 target 时使用 s，
+// 例如二进制数据使用 MessagePack、结构体仍使用默认的 JSON，无需创建多个缓存实例；WithSerializer 指定的序列化器优先。
+// 读写同一个键的类型必须注册相同的序列化器；s 为 nil 时取消注册
+func RegisterSerializer[T any](s serializer.Serializer) {
+	t := reflect.TypeFor[T]()
+
+	serializerRegistry.Lock()
+	defer serializerRegistry.Unlock()
+	if s == nil {
+		delete(serializerRegistry.byType, t)
+		return
+	}
+	serializerRegistry.byType[t] = s
+}
+
+// registeredSerializer 返回类型 t 注册的序列化器，t 为指针时也查找其指向的类型
+func registeredSerializer(t reflect.Type) serializer.Serializer {
+	if t == nil {
+		return nil
+	}
+
+	serializerRegistry.RLock()
+	defer serializerRegistry.RUnlock()
+	if len(serializerRegistry.byType) == 0 {
+		return nil
+	}
+	if s, ok := serializerRegistry.byType[t]; ok {
+		return s
+	}
+	if t.Kind() == reflect.Pointer {
+		return serializerRegistry.byType[t.Elem()]
+	}
+	return nil
+}
+
+// withSerializer 设置本次调用的序列化器
+type withSerializer struct {
+	readOption
+
+	serializer serializer.Serializer
+}
+
+func (w withSerializer) applyGet(cfg *getOptions) {
+	cfg.serializer = w.serializer
+}
+
+func (w withSerializer) applySet(cfg *setOptions) {
+	cfg.serializer = w.serializer
+}
+
+// WithSerializer 设置本次读写使用的序列化器，优先于 RegisterSerializer 和 WithConfigSerializer，
+// 读写同一个键时必须使用相同的序列化器
+func WithSerializer(s serializer.Serializer) interface {
+	ReadOption
+	SetOption
+} {
+	return withSerializer{serializer: s}
+}
+
+// serializerFor 返回值 v 使用的序列化器：s 不为 nil 时使用 s，其次是 v 的类型注册的序列化器，最后是缓存的默认序列化器
+// 读取时 v 为 target，按其指向的类型查找
+func (c *LayeredCache) serializerFor(v any, s serializer.Serializer) serializer.Serializer {
+	if s != nil {
+		return s
+	}
+	if s = registeredSerializer(reflect.TypeOf(v)); s != nil {
+		return s
+	}
+	return c.serializer
+}
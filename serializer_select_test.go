@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/biu7/layered-cache/serializer"
+	"github.com/stretchr/testify/assert"
+)
+
+// blob 按类型注册序列化器的测试类型
+type blob struct {
+	Data []byte
+}
+
+func TestLayeredCache_SerializerSelection(t *testing.T) {
+	ctx := context.Background()
+	msgpack := serializer.NewMsgpack()
+
+	t.Run("按类型注册", func(t *testing.T) {
+		RegisterSerializer[blob](msgpack)
+		defer RegisterSerializer[blob](nil)
+
+		c := createTestCache(t).(*LayeredCache)
+		assert.NoError(t, c.Set(ctx, "blob", blob{Data: []byte{1, 2, 3}}))
+		assert.NoError(t, c.Set(ctx, "user", TestUser{ID: 1, Name: "Alice"}))
+
+		data, err := c.remote.Get(ctx, "blob")
+		assert.NoError(t, err)
+		expected, _ := msgpack.Marshal(blob{Data: []byte{1, 2, 3}})
+		assert.Equal(t, expected, data)
+
+		data, err = c.remote.Get(ctx, "user")
+		assert.NoError(t, err)
+		assert.Equal(t, byte('{'), data[0], "未注册的类型使用默认序列化器")
+
+		var b blob
+		assert.NoError(t, c.Get(ctx, "blob", &b))
+		assert.Equal(t, []byte{1, 2, 3}, b.Data)
+
+		values := make(map[string]blob)
+		assert.NoError(t, c.MGet(ctx, []string{"blob"}, &values))
+		assert.Equal(t, []byte{1, 2, 3}, values["blob"].Data)
+	})
+
+	t.Run("本次调用指定", func(t *testing.T) {
+		c := createTestCache(t).(*LayeredCache)
+		user := TestUser{ID: 1, Name: "Alice"}
+		assert.NoError(t, c.Set(ctx, "user", user, WithSerializer(msgpack)))
+
+		data, err := c.remote.Get(ctx, "user")
+		assert.NoError(t, err)
+		expected, _ := msgpack.Marshal(user)
+		assert.Equal(t, expected, data)
+
+		c.memory.Delete("user")
+		var result TestUser
+		assert.NoError(t, c.Get(ctx, "user", &result, WithSerializer(msgpack)))
+		assert.Equal(t, user, result)
+
+		loaded := TestUser{ID: 2, Name: "Bob"}
+		assert.NoError(t, c.Get(ctx, "user:2", &result, WithSerializer(msgpack), WithLoader(func(ctx context.Context, key string) (any, error) {
+			return loaded, nil
+		})))
+		assert.Equal(t, loaded, result)
+		data, err = c.remote.Get(ctx, "user:2")
+		assert.NoError(t, err)
+		expected, _ = msgpack.Marshal(loaded)
+		assert.Equal(t, expected, data)
+	})
+}
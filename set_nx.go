@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+	"slices"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+)
+
+// SetNX 仅在 Remote 中不存在该键时写入，返回是否写入成功，多个实例并发写入同一个键时只有第一个成功（例如幂等令牌）
+// 是否存在以 Remote 为准，写入成功后再回填内存缓存；内存缓存中已有的同名键不影响判断。需要 Remote 实现 storage.ConditionalSetter
+func (c *LayeredCache) SetNX(ctx context.Context, key string, value any, opts ...SetOption) (bool, error) {
+	scope, ctx := c.takeKeyContext(ctx)
+	key, opts = scope.key(key), scopeSetOptions(scope, slices.Values([]string{key}), opts)
+
+	config := newSetOptions()
+	if err := applySetOptions(config, opts...); err != nil {
+		return false, c.misuse(err)
+	}
+	c.checkLayerTTL(config.memoryTTL, config.remoteTTL)
+	if err := c.checkTags(config); err != nil {
+		return false, c.misuse(err)
+	}
+
+	setter, ok := c.remote.(storage.ConditionalSetter)
+	if !ok {
+		return false, errors.ErrOperationNotSupported
+	}
+
+	data, err := c.encode(value, config.serializer)
+	if err != nil {
+		return false, err
+	}
+	if err = c.checkEntrySize(key, data); err != nil {
+		return false, err
+	}
+	if err = c.checkValueSize(key, data); err != nil {
+		return false, err
+	}
+	oversized := len(c.oversized(map[string][]byte{key: data})) > 0
+
+	memoryTTL, remoteTTL := c.jitterTTL(c.calculateSetValueTTL(config, key, value))
+	err = c.remoteCall(ctx, func(ctx context.Context) (err error) {
+		ok, err = setter.SetNX(ctx, key, data, remoteTTL)
+		return err
+	})
+	if err != nil || !ok {
+		return false, err
+	}
+
+	c.unshield(key)
+	c.stats.sets.Add(1)
+	if c.memory != nil && oversized {
+		c.memory.Delete(key)
+	} else if c.memory != nil {
+		c.memorySet(key, data, memoryTTL)
+	}
+	if err = c.clearNotFound(ctx, []string{key}, true); err != nil {
+		return true, err
+	}
+
+	c.broadcastInvalidation(ctx, []string{key})
+	if err = c.tagKeys(ctx, config, []string{key}, remoteTTL); err != nil {
+		return true, err
+	}
+	return true, c.writeThrough(ctx, map[string][]byte{key: data})
+}
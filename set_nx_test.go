@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_SetNX(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("只有第一个写入成功", func(t *testing.T) {
+		c := createTestCache(t).(*LayeredCache)
+
+		ok, err := c.SetNX(ctx, "token", "first")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = c.SetNX(ctx, "token", "second")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		_, exists := c.memory.Get("token")
+		assert.True(t, exists, "写入成功后回填内存")
+
+		var value string
+		assert.NoError(t, c.Get(ctx, "token", &value))
+		assert.Equal(t, "first", value)
+	})
+
+	t.Run("以 Remote 为准", func(t *testing.T) {
+		c := createTestCache(t).(*LayeredCache)
+		assert.NoError(t, c.Set(ctx, "token", "local", WithOnlyMemory()))
+
+		ok, err := c.SetNX(ctx, "token", "remote")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		var value string
+		assert.NoError(t, c.Get(ctx, "token", &value))
+		assert.Equal(t, "remote", value)
+	})
+
+	t.Run("Remote不支持", func(t *testing.T) {
+		_, err := createMemoryOnlyCache(t).SetNX(ctx, "token", "v")
+		assert.ErrorIs(t, err, errors.ErrOperationNotSupported)
+	})
+}
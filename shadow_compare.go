@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// shadowCompareTimeout 影子比对后台回源的超时时间
+const shadowCompareTimeout = 5 * time.Second
+
+// ShadowReporter 影子比对发现缓存数据与回源数据不一致时的回调
+// fresh 为 nil 表示数据源中已不存在该键
+type ShadowReporter func(key string, cached, fresh []byte)
+
+// withShadowCompare 设置影子比对
+type withShadowCompare struct {
+	readOption
+
+	rate     float64
+	reporter ShadowReporter
+}
+
+func (w withShadowCompare) applyGet(cfg *getOptions) {
+	cfg.shadowRate = w.rate
+	cfg.shadowReporter = w.reporter
+}
+
+// WithShadowCompare 设置影子比对（用于统计缓存数据的实际陈旧率）
+// rate: 缓存命中时以该比例在后台额外调用 loader/batchLoader，取值范围 [0, 1]
+// reporter: 回源数据与缓存数据不一致时的回调，回源结果不会写入缓存，不影响本次返回
+func WithShadowCompare(rate float64, reporter func(key string, cached, fresh []byte)) ReadOption {
+	return withShadowCompare{rate: rate, reporter: reporter}
+}
+
+// shouldShadowCompare 判断本次缓存命中是否需要影子比对
+func (c *LayeredCache) shouldShadowCompare(config *getOptions) bool {
+	if config.shadowRate <= 0 || config.shadowReporter == nil {
+		return false
+	}
+	return config.shadowRate >= 1 || rand.Float64() < config.shadowRate
+}
+
+// shadowCompare 在后台调用 loader 并与缓存数据比对
+func (c *LayeredCache) shadowCompare(ctx context.Context, key string, cached []byte, config *getOptions) {
+	if config.loader == nil || !c.shouldShadowCompare(config) {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	c.spawn(func() {
+		ctx, cancel := context.WithTimeout(ctx, shadowCompareTimeout)
+		defer cancel()
+
+		value, err := config.loader(ctx, key)
+		if err != nil && !IsNotFound(err) {
+			return
+		}
+		c.reportShadow(key, cached, value, config)
+	})
+}
+
+// shadowCompareBatch 在后台调用 batchLoader 并与缓存数据比对，每个命中的键独立采样
+func (c *LayeredCache) shadowCompareBatch(ctx context.Context, hits map[string][]byte, config *getOptions) {
+	if config.batchLoader == nil || len(hits) == 0 {
+		return
+	}
+
+	sampled := make(map[string][]byte)
+	for key, data := range hits {
+		if c.shouldShadowCompare(config) {
+			sampled[key] = data
+		}
+	}
+	if len(sampled) == 0 {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	c.spawn(func() {
+		ctx, cancel := context.WithTimeout(ctx, shadowCompareTimeout)
+		defer cancel()
+
+		keys := make([]string, 0, len(sampled))
+		for key := range sampled {
+			keys = append(keys, key)
+		}
+		values, err := config.batchLoader(ctx, keys)
+		if err != nil && !IsNotFound(err) {
+			return
+		}
+		for key, cached := range sampled {
+			c.reportShadow(key, cached, values[key], config)
+		}
+	})
+}
+
+// reportShadow 序列化回源结果，与缓存数据不一致时回调 reporter
+func (c *LayeredCache) reportShadow(key string, cached []byte, value any, config *getOptions) {
+	var fresh []byte
+	if value != nil {
+		var err error
+		if fresh, err = c.marshal(value, config.serializer); err != nil {
+			return
+		}
+	}
+
+	cached = c.payload(cached)
+	if fresh == nil || !bytes.Equal(cached, fresh) {
+		config.shadowReporter(key, cached, fresh)
+	}
+}
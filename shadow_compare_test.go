@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// shadowRecorder 收集影子比对的上报结果
+type shadowRecorder struct {
+	mu      sync.Mutex
+	reports map[string][2][]byte
+}
+
+func newShadowRecorder() *shadowRecorder {
+	return &shadowRecorder{reports: make(map[string][2][]byte)}
+}
+
+func (r *shadowRecorder) report(key string, cached, fresh []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reports[key] = [2][]byte{cached, fresh}
+}
+
+func (r *shadowRecorder) get(key string) ([2][]byte, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	report, ok := r.reports[key]
+	return report, ok
+}
+
+func TestLayeredCache_Get_ShadowCompare(t *testing.T) {
+	ctx := context.Background()
+	c := createTestCache(t)
+	assert.NoError(t, c.Set(ctx, "same", "value"))
+	assert.NoError(t, c.Set(ctx, "changed", "old"))
+	assert.NoError(t, c.Set(ctx, "removed", "old"))
+
+	recorder := newShadowRecorder()
+	loaded := make(chan string, 3)
+	loader := WithLoader(func(ctx context.Context, key string) (any, error) {
+		defer func() { loaded <- key }()
+		switch key {
+		case "same":
+			return "value", nil
+		case "changed":
+			return "new", nil
+		default:
+			return nil, errors.ErrNotFound
+		}
+	})
+
+	for _, key := range []string{"same", "changed", "removed"} {
+		var result string
+		assert.NoError(t, c.Get(ctx, key, &result, loader, WithShadowCompare(1, recorder.report)))
+		assert.Equal(t, map[string]string{"same": "value", "changed": "old", "removed": "old"}[key], result)
+	}
+	for i := 0; i < 3; i++ {
+		select {
+		case <-loaded:
+		case <-time.After(time.Second):
+			t.Fatal("影子比对未调用loader")
+		}
+	}
+
+	assert.Eventually(t, func() bool {
+		_, changed := recorder.get("changed")
+		_, removed := recorder.get("removed")
+		return changed && removed
+	}, time.Second, 5*time.Millisecond)
+
+	_, ok := recorder.get("same")
+	assert.False(t, ok)
+	report, _ := recorder.get("changed")
+	assert.Equal(t, [2][]byte{[]byte("old"), []byte("new")}, report)
+	report, _ = recorder.get("removed")
+	assert.Nil(t, report[1])
+
+	// 回源结果不写入缓存
+	var result string
+	assert.NoError(t, c.Get(ctx, "changed", &result))
+	assert.Equal(t, "old", result)
+}
+
+func TestLayeredCache_MGet_ShadowCompare(t *testing.T) {
+	ctx := context.Background()
+	c := createTestCache(t)
+	assert.NoError(t, c.MSet(ctx, map[string]any{"a": "va", "b": "vb"}))
+
+	recorder := newShadowRecorder()
+	values := make(map[string]string)
+	err := c.MGet(ctx, []string{"a", "b"}, &values,
+		WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+			return map[string]any{"a": "va", "b": "vb-new"}, nil
+		}),
+		WithShadowCompare(1, recorder.report),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "va", "b": "vb"}, values)
+
+	assert.Eventually(t, func() bool {
+		_, ok := recorder.get("b")
+		return ok
+	}, time.Second, 5*time.Millisecond)
+	_, ok := recorder.get("a")
+	assert.False(t, ok)
+}
+
+func TestWithShadowCompare_Validate(t *testing.T) {
+	c := createTestCache(t)
+	var result string
+	err := c.Get(context.Background(), "k", &result, WithShadowCompare(2, func(string, []byte, []byte) {}))
+	assert.ErrorIs(t, err, errors.ErrInvalidShadowCompareRate)
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+	"maps"
+
+	"github.com/biu7/layered-cache/errors"
+)
+
+// View 一组键在某一时刻的只读快照
+type View interface {
+	// Get 从快照中读取 key，快照时不存在返回 ErrNotFound，key 不在快照的键集合中返回 ErrKeyNotInSnapshot
+	Get(key string, target any) error
+}
+
+// snapshotView Snapshot 返回的快照
+type snapshotView struct {
+	c *LayeredCache
+
+	// 快照时存在的键及其数据
+	data map[string][]byte
+
+	// 快照的键集合
+	keys map[string]struct{}
+}
+
+// Snapshot 一次性读取 keys 的当前值（一次内存遍历和一次 Remote MGET），之后通过返回的 View 读取这些键
+// 同一个请求内多次读取相关联的键时都以快照为准，避免中途被其他写入修改导致读到不一致的组合；
+// 快照不调用 loader，已失效的数据和缺失值标记都视为不存在，快照本身不会过期也不会随缓存更新
+func (c *LayeredCache) Snapshot(ctx context.Context, keys []string) (View, error) {
+	if scope, ctx := c.takeKeyContext(ctx); !scope.empty() {
+		view, err := c.Snapshot(ctx, scopeKeys(scope, keys))
+		if err != nil {
+			return nil, err
+		}
+		return scopedView{View: view, scope: scope}, nil
+	}
+
+	view := &snapshotView{c: c, data: make(map[string][]byte), keys: make(map[string]struct{}, len(keys))}
+	for _, key := range keys {
+		view.keys[key] = struct{}{}
+	}
+	if len(keys) == 0 {
+		return view, nil
+	}
+
+	found, _, _, err := c.batchLookup(ctx, keys, newGetOptions())
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(view.data, found)
+	return view, nil
+}
+
+func (v *snapshotView) Get(key string, target any) error {
+	if _, ok := v.keys[key]; !ok {
+		return v.c.misuse(errors.ErrKeyNotInSnapshot)
+	}
+	data, ok := v.data[key]
+	if !ok {
+		return errors.ErrNotFound
+	}
+	return v.c.decode(data, target, nil)
+}
@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_Snapshot(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("快照不受后续写入影响", func(t *testing.T) {
+		c := createTestCache(t)
+		assert.NoError(t, c.MSet(ctx, map[string]any{"order:1": "pending", "order:1:items": 3}))
+
+		view, err := c.Snapshot(ctx, []string{"order:1", "order:1:items", "order:2"})
+		assert.NoError(t, err)
+
+		assert.NoError(t, c.MSet(ctx, map[string]any{"order:1": "paid", "order:1:items": 4}))
+
+		var status string
+		var items int
+		assert.NoError(t, view.Get("order:1", &status))
+		assert.NoError(t, view.Get("order:1:items", &items))
+		assert.Equal(t, "pending", status)
+		assert.Equal(t, 3, items)
+
+		assert.ErrorIs(t, view.Get("order:2", &status), errors.ErrNotFound)
+	})
+
+	t.Run("读取不在快照中的键", func(t *testing.T) {
+		c := createTestCache(t)
+		view, err := c.Snapshot(ctx, []string{"order:1"})
+		assert.NoError(t, err)
+
+		var status string
+		assert.ErrorIs(t, view.Get("order:3", &status), errors.ErrKeyNotInSnapshot)
+	})
+}
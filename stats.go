@@ -0,0 +1,198 @@
+package cache
+
+import "sync/atomic"
+
+// Stats 缓存运行计数的快照，所有计数自实例创建起累加
+type Stats struct {
+	// MemoryHits 内存缓存命中次数
+	MemoryHits int64
+	// MemoryMisses 内存缓存未命中次数
+	MemoryMisses int64
+	// MemoryWriteBacks Remote 命中后写回内存缓存的次数
+	MemoryWriteBacks int64
+	// MemoryRejects 内存适配器拒绝写入（容量不足或超过单个条目上限）的键数，持续增长说明内存容量过小
+	MemoryRejects int64
+
+	// RemoteHits Remote 缓存命中次数
+	RemoteHits int64
+	// RemoteMisses Remote 缓存未命中次数
+	RemoteMisses int64
+	// RemoteErrors Remote 缓存读写出错的次数
+	RemoteErrors int64
+
+	// NotFoundHits 命中缺失值标记的次数
+	NotFoundHits int64
+
+	// Loads 实际调用 loader / batchLoader 的次数（不含 singleflight 合并的请求）
+	Loads int64
+	// LoadErrors loader / batchLoader 返回错误的次数，ErrNotFound 不计入
+	LoadErrors int64
+	// LoadRejects loader / batchLoader 被按前缀熔断或限流拒绝的次数
+	LoadRejects int64
+
+	// PoisonDeletes 连续反序列化失败被自动删除的键数
+	PoisonDeletes int64
+
+	// AsyncWriteDrops 异步 Remote 写入重试耗尽后丢弃的键数
+	AsyncWriteDrops int64
+
+	// Refreshes 提前刷新成功重新加载的键数
+	Refreshes int64
+
+	// RemoteFallbacks FailOpen 策略下 Remote 出错后降级为只使用内存和 loader 的次数
+	RemoteFallbacks int64
+
+	// RemoteRejects Remote 熔断打开期间未访问 Remote 的次数
+	RemoteRejects int64
+
+	// LockWaits 跨实例合并加载时未获取到锁、等待其他实例加载结果的次数
+	LockWaits int64
+
+	// AutoBatches AutoBatcher 合并后执行的批量读取次数，与合并前的 Get 调用次数相比可估算节省的往返
+	AutoBatches int64
+
+	// OversizedValues 超过 WithConfigMaxValueSize 上限的值的个数，按策略被拒绝、只写入 Remote 或不缓存
+	OversizedValues int64
+
+	// DemoteDrops 淘汰降级队列已满而丢弃、没有写回 Remote 的条目数
+	DemoteDrops int64
+
+	// InvalidationErrors 广播或订阅跨实例失效消息出错的次数
+	InvalidationErrors int64
+
+	// SingleflightCalls 经过 singleflight 的加载请求数（含被合并的请求）
+	SingleflightCalls int64
+	// SingleflightShared 没有执行加载、复用其他并发请求结果的请求数
+	SingleflightShared int64
+
+	// Sets 通过 Set / MSet 写入的键数
+	Sets int64
+	// Deletes 删除的键数
+	Deletes int64
+}
+
+// stats 缓存运行计数
+type stats struct {
+	memoryHits       atomic.Int64
+	memoryMisses     atomic.Int64
+	memoryWriteBacks atomic.Int64
+	memoryRejects    atomic.Int64
+
+	remoteHits   atomic.Int64
+	remoteMisses atomic.Int64
+	remoteErrors atomic.Int64
+
+	notFoundHits atomic.Int64
+
+	loads       atomic.Int64
+	loadErrors  atomic.Int64
+	loadRejects atomic.Int64
+
+	poisonDeletes atomic.Int64
+
+	asyncWriteDrops atomic.Int64
+
+	refreshes atomic.Int64
+
+	remoteFallbacks atomic.Int64
+	remoteRejects   atomic.Int64
+
+	lockWaits atomic.Int64
+
+	autoBatches atomic.Int64
+
+	oversizedValues atomic.Int64
+
+	demoteDrops atomic.Int64
+
+	invalidationErrors atomic.Int64
+
+	singleflightCalls  atomic.Int64
+	singleflightShared atomic.Int64
+
+	sets    atomic.Int64
+	deletes atomic.Int64
+}
+
+// Stats 返回当前运行计数的快照，可用于评估内存容量和 Remote TTL 的配置
+func (c *LayeredCache) Stats() Stats {
+	return Stats{
+		MemoryHits:       c.stats.memoryHits.Load(),
+		MemoryMisses:     c.stats.memoryMisses.Load(),
+		MemoryWriteBacks: c.stats.memoryWriteBacks.Load(),
+		MemoryRejects:    c.stats.memoryRejects.Load(),
+		RemoteHits:       c.stats.remoteHits.Load(),
+		RemoteMisses:     c.stats.remoteMisses.Load(),
+		RemoteErrors:     c.stats.remoteErrors.Load(),
+		NotFoundHits:     c.stats.notFoundHits.Load(),
+		Loads:            c.stats.loads.Load(),
+		LoadErrors:       c.stats.loadErrors.Load(),
+		LoadRejects:      c.stats.loadRejects.Load(),
+		PoisonDeletes:    c.stats.poisonDeletes.Load(),
+		AsyncWriteDrops:  c.stats.asyncWriteDrops.Load(),
+		Refreshes:        c.stats.refreshes.Load(),
+		RemoteFallbacks:  c.stats.remoteFallbacks.Load(),
+		RemoteRejects:    c.stats.remoteRejects.Load(),
+		LockWaits:        c.stats.lockWaits.Load(),
+		AutoBatches:      c.stats.autoBatches.Load(),
+
+		OversizedValues: c.stats.oversizedValues.Load(),
+		DemoteDrops:     c.stats.demoteDrops.Load(),
+
+		InvalidationErrors: c.stats.invalidationErrors.Load(),
+
+		SingleflightCalls:  c.stats.singleflightCalls.Load(),
+		SingleflightShared: c.stats.singleflightShared.Load(),
+
+		Sets:    c.stats.sets.Load(),
+		Deletes: c.stats.deletes.Load(),
+	}
+}
+
+// DedupRatio 返回 singleflight 的合并比例，即复用其他请求加载结果的请求占比，没有加载请求时返回 0
+// 比例持续接近 0 说明并发请求很少命中同一个键，防击穿没有实际发挥作用
+func (s Stats) DedupRatio() float64 {
+	if s.SingleflightCalls == 0 {
+		return 0
+	}
+	return float64(s.SingleflightShared) / float64(s.SingleflightCalls)
+}
+
+// snapshot 返回当前计数的快照
+func (s *stats) snapshot() map[string]int64 {
+	return map[string]int64{
+		"memory_hits":         s.memoryHits.Load(),
+		"memory_misses":       s.memoryMisses.Load(),
+		"memory_write_backs":  s.memoryWriteBacks.Load(),
+		"memory_rejects":      s.memoryRejects.Load(),
+		"remote_hits":         s.remoteHits.Load(),
+		"remote_misses":       s.remoteMisses.Load(),
+		"remote_errors":       s.remoteErrors.Load(),
+		"not_found_hits":      s.notFoundHits.Load(),
+		"loads":               s.loads.Load(),
+		"load_errors":         s.loadErrors.Load(),
+		"load_rejects":        s.loadRejects.Load(),
+		"poison_deletes":      s.poisonDeletes.Load(),
+		"async_write_drops":   s.asyncWriteDrops.Load(),
+		"refreshes":           s.refreshes.Load(),
+		"remote_fallbacks":    s.remoteFallbacks.Load(),
+		"remote_rejects":      s.remoteRejects.Load(),
+		"lock_waits":          s.lockWaits.Load(),
+		"auto_batches":        s.autoBatches.Load(),
+		"oversized_values":    s.oversizedValues.Load(),
+		"demote_drops":        s.demoteDrops.Load(),
+		"invalidation_errors": s.invalidationErrors.Load(),
+		"singleflight_calls":  s.singleflightCalls.Load(),
+		"singleflight_shared": s.singleflightShared.Load(),
+		"sets":                s.sets.Load(),
+		"deletes":             s.deletes.Load(),
+	}
+}
+
+// remoteError 统计 Remote 返回的错误，ErrNotFound 不计入，原样返回 err
+func (s *stats) remoteError(err error) error {
+	if err != nil && !IsNotFound(err) {
+		s.remoteErrors.Add(1)
+	}
+	return err
+}
@@ -0,0 +1,145 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_Stats(t *testing.T) {
+	ctx := context.Background()
+	cache := createTestCache(t)
+	c := cache.(*LayeredCache)
+
+	loader := func(ctx context.Context, key string) (any, error) {
+		if key == "missing" {
+			return nil, nil
+		}
+		return "loaded", nil
+	}
+
+	var result string
+	assert.NoError(t, c.Set(ctx, "k1", "v1"))
+	assert.NoError(t, c.Get(ctx, "k1", &result))                     // 内存命中
+	assert.NoError(t, c.Get(ctx, "k2", &result, WithLoader(loader))) // 两层未命中，加载
+	assert.ErrorIs(t, c.Get(ctx, "missing", &result, WithLoader(loader), WithCacheNotFound(true, time.Minute)), ErrNotFound)
+	assert.ErrorIs(t, c.Get(ctx, "missing", &result), ErrNotFound) // 命中缺失值标记
+	assert.NoError(t, c.Delete(ctx, "k1"))
+
+	c.memory.Delete("k2")
+	assert.NoError(t, c.Get(ctx, "k2", &result)) // Remote 命中
+
+	values := make(map[string]string)
+	assert.NoError(t, c.MGet(ctx, []string{"k2", "k3"}, &values))
+
+	assert.Equal(t, map[string]int64{
+		"memory_hits":         2,
+		"memory_misses":       4,
+		"memory_write_backs":  1,
+		"memory_rejects":      0,
+		"remote_hits":         1,
+		"remote_misses":       3,
+		"remote_errors":       0,
+		"not_found_hits":      1,
+		"loads":               2,
+		"load_errors":         0,
+		"load_rejects":        0,
+		"poison_deletes":      0,
+		"async_write_drops":   0,
+		"refreshes":           0,
+		"remote_fallbacks":    0,
+		"remote_rejects":      0,
+		"lock_waits":          0,
+		"auto_batches":        0,
+		"oversized_values":    0,
+		"demote_drops":        0,
+		"invalidation_errors": 0,
+		"singleflight_calls":  2,
+		"singleflight_shared": 0,
+		"sets":                1,
+		"deletes":             1,
+	}, c.stats.snapshot())
+
+	assert.Equal(t, Stats{
+		MemoryHits:        2,
+		MemoryMisses:      4,
+		MemoryWriteBacks:  1,
+		RemoteHits:        1,
+		RemoteMisses:      3,
+		NotFoundHits:      1,
+		Loads:             2,
+		SingleflightCalls: 2,
+		Sets:              1,
+		Deletes:           1,
+	}, c.Stats())
+}
+
+func TestLayeredCache_Stats_Errors(t *testing.T) {
+	ctx := context.Background()
+	s := miniredis.RunT(t)
+	cache, err := NewCache(
+		WithConfigMemory(createMemoryAdapter(t)),
+		WithConfigRemote(storage.NewRedisWithClient(redis.NewClient(&redis.Options{Addr: s.Addr()}))),
+	)
+	assert.NoError(t, err)
+
+	s.SetError("READONLY")
+	var result string
+	assert.Error(t, cache.Set(ctx, "k1", "v1"))
+	assert.Error(t, cache.Get(ctx, "k2", &result))
+	assert.Error(t, cache.Delete(ctx, "k1"))
+
+	loadErr := errors.New("db down")
+	s.SetError("")
+	assert.ErrorIs(t, cache.Get(ctx, "k3", &result, WithLoader(func(ctx context.Context, key string) (any, error) {
+		return nil, loadErr
+	})), loadErr)
+
+	stats := cache.Stats()
+	assert.Equal(t, int64(3), stats.RemoteErrors)
+	assert.Equal(t, int64(1), stats.Loads)
+	assert.Equal(t, int64(1), stats.LoadErrors)
+}
+
+func TestLayeredCache_Stats_DedupRatio(t *testing.T) {
+	ctx := context.Background()
+	c := createTestCache(t)
+	assert.Equal(t, float64(0), c.Stats().DedupRatio())
+
+	const callers = 10
+	var started sync.WaitGroup
+	started.Add(callers)
+	release := make(chan struct{})
+	loader := func(ctx context.Context, key string) (any, error) {
+		<-release
+		return "loaded", nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			started.Done()
+			var result string
+			assert.NoError(t, c.Get(ctx, "hot", &result, WithLoader(loader)))
+		}()
+	}
+	started.Wait()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	stats := c.Stats()
+	assert.Equal(t, int64(1), stats.Loads)
+	assert.Equal(t, int64(callers), stats.SingleflightCalls)
+	assert.Equal(t, int64(callers-1), stats.SingleflightShared)
+	assert.InDelta(t, 0.9, stats.DedupRatio(), 1e-9)
+}
@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/allegro/bigcache/v3"
+)
+
+var _ Memory = (*BigCache)(nil)
+
+var _ EntrySizeLimiter = (*BigCache)(nil)
+
+var _ PrefixDeleter = (*BigCache)(nil)
+
+const (
+	// bigCacheLifeWindow 条目的最长存活时间，bigcache 只支持全局过期时间，单个条目的过期时间记录在值中
+	bigCacheLifeWindow = 24 * time.Hour
+
+	// bigCacheShards 分片数量
+	bigCacheShards = 1024
+
+	// bigCacheExpireSize 值前记录过期时间的字节数
+	bigCacheExpireSize = 8
+
+	// bigCacheEntryOverhead 每个条目的额外开销：bigcache 条目头 18 字节、队列长度头最多 5 字节和过期时间
+	bigCacheEntryOverhead = 18 + 5 + bigCacheExpireSize
+)
+
+// BigCache 基于 bigcache 的内存适配器
+// 数据存放在按分片预先分配的字节队列中，不产生指针，对 GC 友好且严格限制内存占用；
+// 条目最长保留 24 小时，更长的过期时间会被提前淘汰；使用完毕后需要调用 Close 停止后台清理
+type BigCache struct {
+	client       *bigcache.BigCache
+	maxEntrySize int
+}
+
+// NewBigCache 创建 bigcache 内存适配器，maxMemory 为内存上限，按 MB 取整且不能小于 1MB
+func NewBigCache(maxMemory int) (*BigCache, error) {
+	if maxMemory < 1<<20 {
+		return nil, fmt.Errorf("bigcache create: invalid maxMemory: %d, must be at least 1MB", maxMemory)
+	}
+
+	// If you need to customize the Config, please use NewBigCacheWithClient instead.
+	config := bigcache.DefaultConfig(bigCacheLifeWindow)
+	config.Shards = bigCacheShards
+	config.HardMaxCacheSize = maxMemory >> 20
+	config.Verbose = false
+
+	client, err := bigcache.New(context.Background(), config)
+	if err != nil {
+		return nil, fmt.Errorf("bigcache create: maxMemory %d: %w", maxMemory, err)
+	}
+	return &BigCache{
+		client:       client,
+		maxEntrySize: config.HardMaxCacheSize<<20/config.Shards - bigCacheEntryOverhead,
+	}, nil
+}
+
+// NewBigCacheWithClient 使用已有的客户端创建适配器，不限制单个条目的大小
+func NewBigCacheWithClient(client *bigcache.BigCache) *BigCache {
+	return &BigCache{
+		client:       client,
+		maxEntrySize: math.MaxInt,
+	}
+}
+
+func (b *BigCache) Set(key string, value []byte, expire time.Duration) int32 {
+	if b.client.Set(key, b.wrap(value, expire)) != nil {
+		return 0
+	}
+	return 1
+}
+
+func (b *BigCache) MSet(values map[string][]byte, expire time.Duration) int32 {
+	var count int32
+	for key, value := range values {
+		if b.client.Set(key, b.wrap(value, expire)) == nil {
+			count++
+		}
+	}
+	return count
+}
+
+func (b *BigCache) Get(key string) ([]byte, bool) {
+	entry, err := b.client.Get(key)
+	if err != nil || len(entry) < bigCacheExpireSize {
+		return nil, false
+	}
+
+	expireAt := int64(binary.BigEndian.Uint64(entry))
+	if expireAt > 0 && time.Now().UnixNano() >= expireAt {
+		_ = b.client.Delete(key)
+		return nil, false
+	}
+	return entry[bigCacheExpireSize:], true
+}
+
+func (b *BigCache) MGet(keys []string) map[string][]byte {
+	ret := make(map[string][]byte)
+	for _, key := range keys {
+		if val, ok := b.Get(key); ok {
+			ret[key] = val
+		}
+	}
+	return ret
+}
+
+func (b *BigCache) Delete(key string) {
+	_ = b.client.Delete(key)
+}
+
+func (b *BigCache) DeletePrefix(prefix string) int {
+	var keys []string
+	it := b.client.Iterator()
+	for it.SetNext() {
+		entry, err := it.Value()
+		if err == nil && strings.HasPrefix(entry.Key(), prefix) {
+			keys = append(keys, entry.Key())
+		}
+	}
+
+	var count int
+	for _, key := range keys {
+		if b.client.Delete(key) == nil {
+			count++
+		}
+	}
+	return count
+}
+
+// MaxEntrySize 返回单个条目的大小上限，bigcache 拒绝超过单个分片容量的条目
+func (b *BigCache) MaxEntrySize() int {
+	return b.maxEntrySize
+}
+
+// Close 停止后台清理
+func (b *BigCache) Close() error {
+	return b.client.Close()
+}
+
+// wrap 在值前记录过期时间，小于等于 0 表示不过期
+func (b *BigCache) wrap(value []byte, expire time.Duration) []byte {
+	var expireAt int64
+	if expire > 0 {
+		expireAt = time.Now().Add(expire).UnixNano()
+	}
+	entry := make([]byte, bigCacheExpireSize+len(value))
+	binary.BigEndian.PutUint64(entry, uint64(expireAt))
+	copy(entry[bigCacheExpireSize:], value)
+	return entry
+}
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupBigCache(t *testing.T) *BigCache {
+	t.Helper()
+
+	bc, err := NewBigCache(16 << 20)
+	if err != nil {
+		t.Fatalf("创建 BigCache 失败: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = bc.Close()
+	})
+	return bc
+}
+
+func TestNewBigCache(t *testing.T) {
+	if _, err := NewBigCache(1 << 19); err == nil {
+		t.Error("小于 1MB 时应该返回错误")
+	}
+
+	bc := setupBigCache(t)
+	if got, want := bc.MaxEntrySize(), 16<<20/bigCacheShards-bigCacheEntryOverhead; got != want {
+		t.Errorf("MaxEntrySize() = %d, want %d", got, want)
+	}
+}
+
+func TestBigCache_SetGet(t *testing.T) {
+	bc := setupBigCache(t)
+
+	if n := bc.Set("key", []byte("value"), time.Minute); n != 1 {
+		t.Errorf("Set() = %d, want 1", n)
+	}
+	val, ok := bc.Get("key")
+	if !ok || !bytes.Equal(val, []byte("value")) {
+		t.Errorf("Get() = %q, %v, want value, true", val, ok)
+	}
+
+	bc.Delete("key")
+	if _, ok = bc.Get("key"); ok {
+		t.Error("Delete 后 Get 应该返回 false")
+	}
+
+	if n := bc.Set("fit", []byte(strings.Repeat("x", bc.MaxEntrySize()-len("fit"))), time.Minute); n != 1 {
+		t.Errorf("等于条目大小上限时 Set() = %d, want 1", n)
+	}
+	if n := bc.Set("large", []byte(strings.Repeat("x", 2*bc.MaxEntrySize())), time.Minute); n != 0 {
+		t.Errorf("超过条目大小上限时 Set() = %d, want 0", n)
+	}
+}
+
+func TestBigCache_MSetMGet(t *testing.T) {
+	bc := setupBigCache(t)
+
+	values := map[string][]byte{"a": []byte("1"), "b": []byte("2")}
+	if n := bc.MSet(values, time.Minute); n != 2 {
+		t.Errorf("MSet() = %d, want 2", n)
+	}
+
+	got := bc.MGet([]string{"a", "b", "c"})
+	if len(got) != 2 || string(got["a"]) != "1" || string(got["b"]) != "2" {
+		t.Errorf("MGet() = %v", got)
+	}
+}
+
+func TestBigCache_Expire(t *testing.T) {
+	bc := setupBigCache(t)
+
+	bc.Set("short", []byte("v"), 20*time.Millisecond)
+	bc.Set("forever", []byte("v"), 0)
+	if _, ok := bc.Get("short"); !ok {
+		t.Error("过期前 Get 应该返回 true")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := bc.Get("short"); ok {
+		t.Error("过期后 Get 应该返回 false")
+	}
+	if got := bc.MGet([]string{"short", "forever"}); len(got) != 1 {
+		t.Errorf("MGet() 应该只返回未过期的键, got %v", got)
+	}
+}
+
+func TestBigCache_DeletePrefix(t *testing.T) {
+	testDeletePrefix(t, setupBigCache(t))
+}
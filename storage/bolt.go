@@ -0,0 +1,348 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	_ Remote       = (*Bolt)(nil)
+	_ Scanner      = (*Bolt)(nil)
+	_ MultiDeleter = (*Bolt)(nil)
+	_ Expirer      = (*Bolt)(nil)
+)
+
+const (
+	// boltHeaderSize 值前记录过期时间的字节数
+	boltHeaderSize = 8
+
+	// boltCompactBatch 后台清理时单个事务最多删除的键数，避免长时间持有写锁
+	boltCompactBatch = 1000
+)
+
+// boltBucket 缓存数据所在的 bucket
+var boltBucket = []byte("layered-cache")
+
+// Bolt 基于 bbolt 的本地磁盘缓存，可以在没有 Redis 的边缘部署中作为 Remote 使用，进程重启后数据仍然保留
+// 过期时间记录在值前，读取时跳过已过期的键，由后台清理协程定期删除
+type Bolt struct {
+	db *bolt.DB
+
+	// 是否由适配器打开数据库，Close 时只关闭自己打开的数据库
+	owned bool
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewBolt 打开或创建 path 处的 bbolt 数据库，compactInterval 为清理过期键的间隔，小于等于 0 表示不启动后台清理
+// 同一个数据库文件同时只能被一个进程打开
+func NewBolt(path string, compactInterval time.Duration) (*Bolt, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("bolt open %s: %w", path, err)
+	}
+	b, err := NewBoltWithDB(db, compactInterval)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	b.owned = true
+	return b, nil
+}
+
+// NewBoltWithDB 使用已打开的数据库创建适配器，数据保存在独立的 bucket 中，Close 不会关闭数据库
+func NewBoltWithDB(db *bolt.DB, compactInterval time.Duration) (*Bolt, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("bolt create bucket: %w", err)
+	}
+
+	b := &Bolt{db: db, stop: make(chan struct{}), done: make(chan struct{})}
+	if compactInterval > 0 {
+		go b.compactLoop(compactInterval)
+	} else {
+		close(b.done)
+	}
+	return b, nil
+}
+
+func (b *Bolt) Set(ctx context.Context, key string, value []byte, expire time.Duration) error {
+	return b.MSet(ctx, map[string][]byte{key: value}, expire)
+}
+
+func (b *Bolt) MSet(ctx context.Context, values map[string][]byte, expire time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+	expireAt := boltExpireAt(expire)
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucket)
+		for key, value := range values {
+			if err := bucket.Put([]byte(key), boltWrap(value, expireAt)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("bolt mset: %w", err)
+	}
+	return nil
+}
+
+func (b *Bolt) Get(ctx context.Context, key string) ([]byte, error) {
+	var ret []byte
+	err := b.db.View(func(tx *bolt.Tx) error {
+		value, ok := boltUnwrap(tx.Bucket(boltBucket).Get([]byte(key)), time.Now())
+		if !ok {
+			return errors.ErrNotFound
+		}
+		// bbolt 返回的切片只在事务内有效
+		ret = bytes.Clone(value)
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errors.ErrNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("bolt get %s: %w", key, err)
+	}
+	return ret, nil
+}
+
+func (b *Bolt) MGet(ctx context.Context, keys []string) (map[string][]byte, error) {
+	ret := make(map[string][]byte, len(keys))
+	now := time.Now()
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucket)
+		for _, key := range keys {
+			if value, ok := boltUnwrap(bucket.Get([]byte(key)), now); ok {
+				ret[key] = bytes.Clone(value)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("bolt mget: %w", err)
+	}
+	return ret, nil
+}
+
+func (b *Bolt) Delete(ctx context.Context, key string) error {
+	return b.MDelete(ctx, []string{key})
+}
+
+func (b *Bolt) MDelete(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucket)
+		for _, key := range keys {
+			if err := bucket.Delete([]byte(key)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("bolt mdelete: %w", err)
+	}
+	return nil
+}
+
+// TTL 返回键的剩余过期时间，与 Redis 一致：键不存在返回 -2，没有过期时间返回 -1
+func (b *Bolt) TTL(ctx context.Context, key string) (time.Duration, error) {
+	ttl := time.Duration(-2)
+	now := time.Now()
+	err := b.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(boltBucket).Get([]byte(key))
+		if _, ok := boltUnwrap(data, now); !ok {
+			return nil
+		}
+		if expireAt := boltExpireAtOf(data); expireAt == 0 {
+			ttl = -1
+		} else {
+			ttl = time.Unix(0, expireAt).Sub(now)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("bolt ttl %s: %w", key, err)
+	}
+	return ttl, nil
+}
+
+func (b *Bolt) MExpire(ctx context.Context, keys []string, expire time.Duration) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	expireAt := boltExpireAt(expire)
+	now := time.Now()
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucket)
+		for _, key := range keys {
+			value, ok := boltUnwrap(bucket.Get([]byte(key)), now)
+			if !ok {
+				continue
+			}
+			if err := bucket.Put([]byte(key), boltWrap(value, expireAt)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("bolt mexpire: %w", err)
+	}
+	return nil
+}
+
+// Scan 按键的字典序遍历匹配 match（Redis glob 语法）的键，游标为已遍历的键数；
+// 模式以字面量开头时从该前缀开始遍历，遍历期间写入或删除键可能导致重复或遗漏
+func (b *Bolt) Scan(ctx context.Context, match string, cursor uint64, count int) ([]string, uint64, error) {
+	if count <= 0 {
+		count = 10
+	}
+	prefix := []byte(globLiteralPrefix(match))
+	now := time.Now()
+
+	var keys []string
+	next := cursor
+	err := b.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(boltBucket).Cursor()
+		var skipped uint64
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			if skipped < cursor {
+				skipped++
+				continue
+			}
+			next++
+			if _, ok := boltUnwrap(v, now); ok && globMatch(match, string(k)) {
+				keys = append(keys, string(k))
+			}
+			if next-cursor >= uint64(count) {
+				if k, _ = c.Next(); k == nil || !bytes.HasPrefix(k, prefix) {
+					next = 0
+				}
+				return nil
+			}
+		}
+		next = 0
+		return nil
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("bolt scan %s: %w", match, err)
+	}
+	return keys, next, nil
+}
+
+// Compact 删除已过期的键并返回删除的数量，每个事务最多删除 boltCompactBatch 个键
+func (b *Bolt) Compact(ctx context.Context) (int, error) {
+	var total int
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		var deleted int
+		now := time.Now()
+		err := b.db.Update(func(tx *bolt.Tx) error {
+			c := tx.Bucket(boltBucket).Cursor()
+			for k, v := c.First(); k != nil && deleted < boltCompactBatch; {
+				if _, ok := boltUnwrap(v, now); ok {
+					k, v = c.Next()
+					continue
+				}
+				// 删除后游标位置不可靠，重新定位到被删除键之后
+				key := bytes.Clone(k)
+				if err := c.Delete(); err != nil {
+					return err
+				}
+				deleted++
+				k, v = c.Seek(key)
+			}
+			return nil
+		})
+		total += deleted
+		if err != nil {
+			return total, fmt.Errorf("bolt compact: %w", err)
+		}
+		if deleted < boltCompactBatch {
+			return total, nil
+		}
+	}
+}
+
+// Close 停止后台清理，数据库由适配器打开时同时关闭数据库
+func (b *Bolt) Close() error {
+	var err error
+	b.closeOnce.Do(func() {
+		close(b.stop)
+		<-b.done
+		if b.owned {
+			err = b.db.Close()
+		}
+	})
+	return err
+}
+
+// compactLoop 定期清理过期的键
+func (b *Bolt) compactLoop(interval time.Duration) {
+	defer close(b.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+			_, _ = b.Compact(context.Background())
+		}
+	}
+}
+
+// boltExpireAt 计算过期时间戳，小于等于 0 表示不过期
+func boltExpireAt(expire time.Duration) int64 {
+	if expire <= 0 {
+		return 0
+	}
+	return time.Now().Add(expire).UnixNano()
+}
+
+// boltWrap 在值前记录过期时间
+func boltWrap(value []byte, expireAt int64) []byte {
+	data := make([]byte, boltHeaderSize+len(value))
+	binary.BigEndian.PutUint64(data, uint64(expireAt))
+	copy(data[boltHeaderSize:], value)
+	return data
+}
+
+// boltExpireAtOf 返回记录的过期时间戳
+func boltExpireAtOf(data []byte) int64 {
+	return int64(binary.BigEndian.Uint64(data))
+}
+
+// boltUnwrap 返回未过期的值，不存在、格式错误或已过期时返回 false
+func boltUnwrap(data []byte, now time.Time) ([]byte, bool) {
+	if len(data) < boltHeaderSize {
+		return nil, false
+	}
+	if expireAt := boltExpireAtOf(data); expireAt > 0 && now.UnixNano() >= expireAt {
+		return nil, false
+	}
+	return data[boltHeaderSize:], true
+}
@@ -0,0 +1,211 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+func setupBolt(t *testing.T, path string) *Bolt {
+	t.Helper()
+
+	b, err := NewBolt(path, 0)
+	if err != nil {
+		t.Fatalf("创建 Bolt 失败: %v", err)
+	}
+	t.Cleanup(func() { _ = b.Close() })
+
+	return b
+}
+
+func TestBolt_SetGetDelete(t *testing.T) {
+	ctx := context.Background()
+	b := setupBolt(t, filepath.Join(t.TempDir(), "cache.db"))
+
+	if err := b.MSet(ctx, map[string][]byte{"a": []byte("1"), "b": []byte("2")}, time.Hour); err != nil {
+		t.Fatalf("MSet() error = %v", err)
+	}
+	if err := b.Set(ctx, "a", []byte("3"), 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	if value, err := b.Get(ctx, "a"); err != nil || string(value) != "3" {
+		t.Errorf("Get(a) = %q, %v, want 3", value, err)
+	}
+	values, err := b.MGet(ctx, []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("MGet() error = %v", err)
+	}
+	if len(values) != 2 || string(values["b"]) != "2" {
+		t.Errorf("MGet() = %v, want a 和 b", values)
+	}
+
+	if err := b.MDelete(ctx, []string{"a", "b"}); err != nil {
+		t.Fatalf("MDelete() error = %v", err)
+	}
+	if _, err := b.Get(ctx, "a"); !errors.Is(err, errors.ErrNotFound) {
+		t.Errorf("删除后 Get(a) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestBolt_TTL(t *testing.T) {
+	ctx := context.Background()
+	b := setupBolt(t, filepath.Join(t.TempDir(), "cache.db"))
+
+	_ = b.Set(ctx, "short", []byte("1"), 20*time.Millisecond)
+	_ = b.Set(ctx, "long", []byte("1"), time.Hour)
+	_ = b.Set(ctx, "forever", []byte("1"), 0)
+
+	if ttl, _ := b.TTL(ctx, "long"); ttl <= 59*time.Minute || ttl > time.Hour {
+		t.Errorf("TTL(long) = %v, want 约 1h", ttl)
+	}
+	if ttl, _ := b.TTL(ctx, "forever"); ttl != -1 {
+		t.Errorf("TTL(forever) = %v, want -1", ttl)
+	}
+	if ttl, _ := b.TTL(ctx, "missing"); ttl != -2 {
+		t.Errorf("TTL(missing) = %v, want -2", ttl)
+	}
+
+	if err := b.MExpire(ctx, []string{"forever", "missing"}, time.Minute); err != nil {
+		t.Fatalf("MExpire() error = %v", err)
+	}
+	if ttl, _ := b.TTL(ctx, "forever"); ttl <= 0 || ttl > time.Minute {
+		t.Errorf("MExpire 后 TTL(forever) = %v, want 约 1m", ttl)
+	}
+	if _, err := b.Get(ctx, "missing"); !errors.Is(err, errors.ErrNotFound) {
+		t.Errorf("MExpire 不应创建不存在的键, error = %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, err := b.Get(ctx, "short"); !errors.Is(err, errors.ErrNotFound) {
+		t.Errorf("过期后 Get(short) error = %v, want ErrNotFound", err)
+	}
+	if ttl, _ := b.TTL(ctx, "short"); ttl != -2 {
+		t.Errorf("过期后 TTL(short) = %v, want -2", ttl)
+	}
+
+	deleted, err := b.Compact(ctx)
+	if err != nil || deleted != 1 {
+		t.Errorf("Compact() = %d, %v, want 1", deleted, err)
+	}
+}
+
+func TestBolt_CompactLoop(t *testing.T) {
+	ctx := context.Background()
+	b, err := NewBolt(filepath.Join(t.TempDir(), "cache.db"), 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("创建 Bolt 失败: %v", err)
+	}
+	defer b.Close()
+
+	values := make(map[string][]byte, boltCompactBatch+10)
+	for i := range boltCompactBatch + 10 {
+		values[fmt.Sprintf("k%d", i)] = []byte("v")
+	}
+	_ = b.MSet(ctx, values, time.Millisecond)
+	_ = b.Set(ctx, "keep", []byte("v"), time.Hour)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		keys, _, _ := b.Scan(ctx, "*", 0, 0)
+		var n int
+		_ = b.db.View(func(tx *bolt.Tx) error {
+			n = tx.Bucket(boltBucket).Stats().KeyN
+			return nil
+		})
+		if n == 1 && slices.Equal(keys, []string{"keep"}) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("后台清理后剩余 %d 个键, want 1", n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestBolt_Scan(t *testing.T) {
+	ctx := context.Background()
+	b := setupBolt(t, filepath.Join(t.TempDir(), "cache.db"))
+
+	_ = b.MSet(ctx, map[string][]byte{
+		"user:1": []byte("1"), "user:2": []byte("2"), "user:3": []byte("3"),
+		"order:1": []byte("1"), "users": []byte("x"),
+	}, time.Hour)
+
+	var keys []string
+	var cursor uint64
+	for range 10 {
+		page, next, err := b.Scan(ctx, "user:*", cursor, 2)
+		if err != nil {
+			t.Fatalf("Scan() error = %v", err)
+		}
+		keys = append(keys, page...)
+		if cursor = next; cursor == 0 {
+			break
+		}
+	}
+	if cursor != 0 {
+		t.Fatal("Scan 没有结束")
+	}
+	if !slices.Equal(keys, []string{"user:1", "user:2", "user:3"}) {
+		t.Errorf("Scan(user:*) = %v", keys)
+	}
+
+	keys, _, _ = b.Scan(ctx, "*:1", 0, 100)
+	if !slices.Equal(keys, []string{"order:1", "user:1"}) {
+		t.Errorf("Scan(*:1) = %v", keys)
+	}
+}
+
+func TestBolt_Persistence(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cache.db")
+
+	b, err := NewBolt(path, 0)
+	if err != nil {
+		t.Fatalf("创建 Bolt 失败: %v", err)
+	}
+	_ = b.Set(ctx, "k", []byte("v"), time.Hour)
+	if err := b.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	b = setupBolt(t, path)
+	if value, err := b.Get(ctx, "k"); err != nil || string(value) != "v" {
+		t.Errorf("重新打开后 Get(k) = %q, %v, want v", value, err)
+	}
+}
+
+func TestGlobMatch(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"user:*", "user:1", true},
+		{"user:*", "users", false},
+		{"*", "", true},
+		{"h?llo", "hello", true},
+		{"h?llo", "hllo", false},
+		{"h[ae]llo", "hallo", true},
+		{"h[^e]llo", "hello", false},
+		{"h[a-c]llo", "hbllo", true},
+		{`a\*b`, "a*b", true},
+		{`a\*b`, "axb", false},
+		{"*:1", "order:1", true},
+	}
+
+	for _, tt := range tests {
+		if got := globMatch(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("globMatch(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+	if got := globLiteralPrefix(`user\*:*`); got != "user*:" {
+		t.Errorf("globLiteralPrefix() = %q, want user*:", got)
+	}
+}
@@ -0,0 +1,239 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+)
+
+// promoteTTL 回填到未设置 TTL 的层、且命中层中的键没有过期时间或读取失败时使用的过期时间
+const promoteTTL = time.Hour
+
+var (
+	_ Remote       = (*Chain)(nil)
+	_ MultiDeleter = (*Chain)(nil)
+	_ io.Closer    = (*Chain)(nil)
+)
+
+// Layer 多级 Remote 中的一层
+type Layer struct {
+	// Remote 该层的适配器
+	Remote Remote
+
+	// TTL 写入该层时使用的过期时间，为 0 表示使用调用方传入的过期时间
+	TTL time.Duration
+}
+
+// Chain 将多个 Remote 按顺序串联为一个 Remote，例如 Redis → S3
+// 读取从第一层开始逐层查找，出错的层跳过，命中后回填到之前未命中的层；写入和删除依次作用于所有层，任一层出错时返回错误。
+// 只实现 Remote 和 MultiDeleter，SetNX、IncrBy、Scan 等依赖单一存储语义的可选接口不支持
+type Chain struct {
+	layers []Layer
+}
+
+// NewChain 创建多级 Remote，至少需要一层，每层的 Remote 不能为 nil，TTL 不能为负数
+func NewChain(layers ...Layer) (*Chain, error) {
+	if len(layers) == 0 {
+		return nil, fmt.Errorf("chain create: no layers")
+	}
+	for i, layer := range layers {
+		if layer.Remote == nil || layer.TTL < 0 {
+			return nil, fmt.Errorf("chain create: invalid layer %d", i)
+		}
+	}
+	return &Chain{layers: layers}, nil
+}
+
+// Layers 返回各层的配置
+func (c *Chain) Layers() []Layer {
+	return c.layers
+}
+
+func (c *Chain) Set(ctx context.Context, key string, value []byte, expire time.Duration) error {
+	for i, layer := range c.layers {
+		if err := layer.Remote.Set(ctx, key, value, layer.expire(expire)); err != nil {
+			return fmt.Errorf("chain layer %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (c *Chain) MSet(ctx context.Context, values map[string][]byte, expire time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+	for i, layer := range c.layers {
+		if err := layer.Remote.MSet(ctx, values, layer.expire(expire)); err != nil {
+			return fmt.Errorf("chain layer %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Get 逐层读取，命中后回填到之前的层，回填失败不影响读取结果
+// 出错的层跳过，所有层都未命中且有层出错时返回第一个错误
+func (c *Chain) Get(ctx context.Context, key string) ([]byte, error) {
+	var first error
+	for i, layer := range c.layers {
+		value, err := layer.Remote.Get(ctx, key)
+		if errors.Is(err, errors.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			if first == nil {
+				first = fmt.Errorf("chain layer %d: %w", i, err)
+			}
+			continue
+		}
+		if i > 0 {
+			c.promote(ctx, i, map[string][]byte{key: value})
+		}
+		return value, nil
+	}
+	if first != nil {
+		return nil, first
+	}
+	return nil, errors.ErrNotFound
+}
+
+// MGet 逐层读取上一层未命中的键，命中的键回填到之前的层
+// 出错的层跳过，仍有键未命中且有层出错时返回第一个错误，无法区分这些键是否存在
+func (c *Chain) MGet(ctx context.Context, keys []string) (map[string][]byte, error) {
+	ret := make(map[string][]byte, len(keys))
+	missing := keys
+	var first error
+	for i, layer := range c.layers {
+		if len(missing) == 0 {
+			break
+		}
+		found, err := layer.Remote.MGet(ctx, missing)
+		if err != nil {
+			if first == nil {
+				first = fmt.Errorf("chain layer %d: %w", i, err)
+			}
+			continue
+		}
+		if len(found) == 0 {
+			continue
+		}
+		if i > 0 {
+			c.promote(ctx, i, found)
+		}
+
+		next := missing[:0:0]
+		for _, key := range missing {
+			if value, ok := found[key]; ok {
+				ret[key] = value
+			} else {
+				next = append(next, key)
+			}
+		}
+		missing = next
+	}
+	if len(missing) > 0 && first != nil {
+		return nil, first
+	}
+	return ret, nil
+}
+
+// promote 将第 hit 层命中的值回填到之前的层
+// 上层未设置 TTL 时使用命中层的剩余过期时间，读取失败或没有过期时间时使用 promoteTTL
+func (c *Chain) promote(ctx context.Context, hit int, values map[string][]byte) {
+	var remaining time.Duration
+	for _, layer := range c.layers[:hit] {
+		if layer.TTL > 0 {
+			_ = layer.Remote.MSet(ctx, values, layer.TTL)
+			continue
+		}
+		if remaining == 0 {
+			remaining = c.remainingTTL(ctx, hit, values)
+			if remaining <= 0 {
+				remaining = promoteTTL
+			}
+		}
+		_ = layer.Remote.MSet(ctx, values, remaining)
+	}
+}
+
+// remainingTTL 返回第 hit 层中 values 的最短剩余过期时间，没有过期时间或读取失败时返回 -1
+func (c *Chain) remainingTTL(ctx context.Context, hit int, values map[string][]byte) time.Duration {
+	remaining := time.Duration(-1)
+	for key := range values {
+		ttl, err := c.layers[hit].Remote.TTL(ctx, key)
+		if err != nil || ttl <= 0 {
+			continue
+		}
+		if remaining < 0 || ttl < remaining {
+			remaining = ttl
+		}
+	}
+	return remaining
+}
+
+func (c *Chain) Delete(ctx context.Context, key string) error {
+	for i, layer := range c.layers {
+		if err := layer.Remote.Delete(ctx, key); err != nil {
+			return fmt.Errorf("chain layer %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// MDelete 依次删除所有层中的键，未实现 MultiDeleter 的层逐个删除
+func (c *Chain) MDelete(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	for i, layer := range c.layers {
+		if deleter, ok := layer.Remote.(MultiDeleter); ok {
+			if err := deleter.MDelete(ctx, keys); err != nil {
+				return fmt.Errorf("chain layer %d: %w", i, err)
+			}
+			continue
+		}
+		for _, key := range keys {
+			if err := layer.Remote.Delete(ctx, key); err != nil {
+				return fmt.Errorf("chain layer %d: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// TTL 返回第一个存在该键的层中的剩余过期时间，所有层都不存在时返回 -2
+func (c *Chain) TTL(ctx context.Context, key string) (time.Duration, error) {
+	for i, layer := range c.layers {
+		ttl, err := layer.Remote.TTL(ctx, key)
+		if err != nil {
+			return 0, fmt.Errorf("chain layer %d: %w", i, err)
+		}
+		if ttl != -2 {
+			return ttl, nil
+		}
+	}
+	return -2, nil
+}
+
+// Close 关闭所有实现了 io.Closer 的层，返回第一个错误
+func (c *Chain) Close() error {
+	var first error
+	for _, layer := range c.layers {
+		if closer, ok := layer.Remote.(io.Closer); ok {
+			if err := closer.Close(); err != nil && first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
+
+// expire 返回写入该层时使用的过期时间
+func (l Layer) expire(expire time.Duration) time.Duration {
+	if l.TTL > 0 {
+		return l.TTL
+	}
+	return expire
+}
@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	stderrors "errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+)
+
+func TestNewChain(t *testing.T) {
+	if _, err := NewChain(); err == nil {
+		t.Error("没有任何层时应该返回错误")
+	}
+	if _, err := NewChain(Layer{}); err == nil {
+		t.Error("Remote 为 nil 时应该返回错误")
+	}
+}
+
+// brokenRemote 读取总是失败的 Remote
+type brokenRemote struct {
+	Remote
+}
+
+func (r brokenRemote) Get(ctx context.Context, key string) ([]byte, error) {
+	return nil, stderrors.New("layer down")
+}
+
+func (r brokenRemote) MGet(ctx context.Context, keys []string) (map[string][]byte, error) {
+	return nil, stderrors.New("layer down")
+}
+
+func TestChain_SkipsFailingLayer(t *testing.T) {
+	ctx := context.Background()
+	rdb, _ := setupRedis(t)
+	disk := setupBolt(t, filepath.Join(t.TempDir(), "cache.db"))
+	chain, err := NewChain(Layer{Remote: brokenRemote{rdb}}, Layer{Remote: disk})
+	if err != nil {
+		t.Fatalf("NewChain() error = %v", err)
+	}
+	if err = disk.Set(ctx, "k", []byte("v"), time.Hour); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	if value, err := chain.Get(ctx, "k"); err != nil || string(value) != "v" {
+		t.Errorf("Get(k) = %q, %v, want v", value, err)
+	}
+	if got, err := chain.MGet(ctx, []string{"k"}); err != nil || string(got["k"]) != "v" {
+		t.Errorf("MGet(k) = %v, %v, want v", got, err)
+	}
+
+	// 出错的层可能存在未命中的键，不能按不存在处理
+	if _, err := chain.Get(ctx, "missing"); err == nil || errors.Is(err, errors.ErrNotFound) {
+		t.Errorf("Get(missing) error = %v, want layer error", err)
+	}
+	if _, err := chain.MGet(ctx, []string{"k", "missing"}); err == nil {
+		t.Error("MGet(k, missing) 应该返回出错层的错误")
+	}
+}
+
+func TestChain_PromoteWithoutTTL(t *testing.T) {
+	ctx := context.Background()
+	rdb, _ := setupRedis(t)
+	disk := setupBolt(t, filepath.Join(t.TempDir(), "cache.db"))
+	chain, err := NewChain(Layer{Remote: rdb}, Layer{Remote: disk})
+	if err != nil {
+		t.Fatalf("NewChain() error = %v", err)
+	}
+	if err = disk.Set(ctx, "k", []byte("v"), 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	if _, err = chain.Get(ctx, "k"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if ttl, _ := rdb.TTL(ctx, "k"); ttl <= 0 || ttl > promoteTTL {
+		t.Errorf("回填后第一层 TTL = %v, want (0, %v]", ttl, promoteTTL)
+	}
+}
+
+func TestChain_ReadPromotesAndWriteFansOut(t *testing.T) {
+	ctx := context.Background()
+	rdb, _ := setupRedis(t)
+	disk := setupBolt(t, filepath.Join(t.TempDir(), "cache.db"))
+	chain, err := NewChain(Layer{Remote: rdb, TTL: time.Minute}, Layer{Remote: disk})
+	if err != nil {
+		t.Fatalf("NewChain() error = %v", err)
+	}
+
+	if err = chain.Set(ctx, "k", []byte("v"), time.Hour); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if ttl, _ := rdb.TTL(ctx, "k"); ttl != time.Minute {
+		t.Errorf("第一层 TTL = %v, want 1m", ttl)
+	}
+	if ttl, _ := disk.TTL(ctx, "k"); ttl <= 59*time.Minute {
+		t.Errorf("第二层 TTL = %v, want about 1h", ttl)
+	}
+
+	// 只在第二层中的键读取后回填到第一层
+	if err = disk.MSet(ctx, map[string][]byte{"a": []byte("1"), "b": []byte("2")}, time.Hour); err != nil {
+		t.Fatalf("MSet() error = %v", err)
+	}
+	got, err := chain.MGet(ctx, []string{"k", "a", "b", "missing"})
+	if err != nil || len(got) != 3 || string(got["a"]) != "1" {
+		t.Errorf("MGet() = %v, %v, want 3 values", got, err)
+	}
+	if value, err := rdb.Get(ctx, "a"); err != nil || string(value) != "1" {
+		t.Errorf("回填后第一层 Get(a) = %q, %v", value, err)
+	}
+
+	if _, err = chain.Get(ctx, "missing"); !errors.Is(err, errors.ErrNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrNotFound", err)
+	}
+
+	if err = chain.MDelete(ctx, []string{"k", "a"}); err != nil {
+		t.Fatalf("MDelete() error = %v", err)
+	}
+	if _, err = disk.Get(ctx, "a"); !errors.Is(err, errors.ErrNotFound) {
+		t.Errorf("删除后第二层 Get(a) error = %v, want ErrNotFound", err)
+	}
+	if ttl, _ := chain.TTL(ctx, "k"); ttl != -2 {
+		t.Errorf("TTL(k) = %v, want -2", ttl)
+	}
+}
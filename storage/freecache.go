@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/coocood/freecache"
+)
+
+var _ Memory = (*Freecache)(nil)
+
+var _ EntrySizeLimiter = (*Freecache)(nil)
+
+var _ PrefixDeleter = (*Freecache)(nil)
+
+var _ TTLGetter = (*Freecache)(nil)
+
+// freecacheMinSize freecache 的最小容量，小于该值时按该值分配
+const freecacheMinSize = 512 * 1024
+
+// Freecache 基于 freecache 的内存适配器
+// 数据存放在预先分配的少量大块内存中，不产生指针，对 GC 友好且严格限制内存占用；
+// 过期时间精度为秒，不足 1 秒的过期时间按 1 秒处理
+type Freecache struct {
+	client *freecache.Cache
+	size   int
+}
+
+// NewFreecache 创建 freecache 内存适配器，maxMemory 为预先分配的字节数
+func NewFreecache(maxMemory int) (*Freecache, error) {
+	if maxMemory <= 0 {
+		return nil, fmt.Errorf("freecache create: invalid maxMemory: %d", maxMemory)
+	}
+	return &Freecache{
+		client: freecache.NewCache(maxMemory),
+		size:   max(maxMemory, freecacheMinSize),
+	}, nil
+}
+
+// NewFreecacheWithClient 使用已有的客户端创建适配器，maxMemory 为创建客户端时使用的容量
+func NewFreecacheWithClient(client *freecache.Cache, maxMemory int) *Freecache {
+	return &Freecache{
+		client: client,
+		size:   max(maxMemory, freecacheMinSize),
+	}
+}
+
+func (f *Freecache) Set(key string, value []byte, expire time.Duration) int32 {
+	if f.client.Set([]byte(key), value, expireSeconds(expire)) != nil {
+		return 0
+	}
+	return 1
+}
+
+func (f *Freecache) MSet(values map[string][]byte, expire time.Duration) int32 {
+	var count int32
+	seconds := expireSeconds(expire)
+	for key, value := range values {
+		if f.client.Set([]byte(key), value, seconds) == nil {
+			count++
+		}
+	}
+	return count
+}
+
+func (f *Freecache) Get(key string) ([]byte, bool) {
+	val, err := f.client.Get([]byte(key))
+	if err != nil {
+		return nil, false
+	}
+	return val, true
+}
+
+// GetWithTTL 返回值和剩余过期时间，精度为秒
+func (f *Freecache) GetWithTTL(key string) ([]byte, time.Duration, bool) {
+	val, expireAt, err := f.client.GetWithExpiration([]byte(key))
+	if err != nil {
+		return nil, 0, false
+	}
+	if expireAt == 0 {
+		return val, -1, true
+	}
+	return val, max(time.Until(time.Unix(int64(expireAt), 0)), 0), true
+}
+
+func (f *Freecache) MGet(keys []string) map[string][]byte {
+	ret := make(map[string][]byte)
+	for _, key := range keys {
+		if val, err := f.client.Get([]byte(key)); err == nil {
+			ret[key] = val
+		}
+	}
+	return ret
+}
+
+func (f *Freecache) Delete(key string) {
+	f.client.Del([]byte(key))
+}
+
+func (f *Freecache) DeletePrefix(prefix string) int {
+	var keys [][]byte
+	it := f.client.NewIterator()
+	for entry := it.Next(); entry != nil; entry = it.Next() {
+		if bytes.HasPrefix(entry.Key, []byte(prefix)) {
+			keys = append(keys, entry.Key)
+		}
+	}
+
+	var count int
+	for _, key := range keys {
+		if f.client.Del(key) {
+			count++
+		}
+	}
+	return count
+}
+
+// MaxEntrySize 返回单个条目的大小上限，freecache 拒绝超过容量 1/1024 的条目（含 24 字节的条目头）
+func (f *Freecache) MaxEntrySize() int {
+	return f.size/1024 - 24
+}
+
+// expireSeconds 将过期时间转换为秒，不足 1 秒的向上取整，小于等于 0 表示不过期
+func expireSeconds(expire time.Duration) int {
+	if expire <= 0 {
+		return 0
+	}
+	return int((expire + time.Second - 1) / time.Second)
+}
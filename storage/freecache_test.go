@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupFreecache(t *testing.T) *Freecache {
+	t.Helper()
+
+	fc, err := NewFreecache(1 << 20)
+	if err != nil {
+		t.Fatalf("创建 Freecache 失败: %v", err)
+	}
+	return fc
+}
+
+func TestNewFreecache(t *testing.T) {
+	if _, err := NewFreecache(0); err == nil {
+		t.Error("NewFreecache(0) 应该返回错误")
+	}
+
+	fc, err := NewFreecache(1024)
+	if err != nil {
+		t.Fatalf("NewFreecache() error = %v", err)
+	}
+	if got, want := fc.MaxEntrySize(), freecacheMinSize/1024-24; got != want {
+		t.Errorf("小于最小容量时按最小容量计算上限: got %d, want %d", got, want)
+	}
+}
+
+func TestFreecache_SetGet(t *testing.T) {
+	fc := setupFreecache(t)
+
+	if n := fc.Set("key", []byte("value"), time.Minute); n != 1 {
+		t.Errorf("Set() = %d, want 1", n)
+	}
+	val, ok := fc.Get("key")
+	if !ok || !bytes.Equal(val, []byte("value")) {
+		t.Errorf("Get() = %q, %v, want value, true", val, ok)
+	}
+
+	fc.Delete("key")
+	if _, ok = fc.Get("key"); ok {
+		t.Error("Delete 后 Get 应该返回 false")
+	}
+
+	if n := fc.Set("large", []byte(strings.Repeat("x", fc.MaxEntrySize())), time.Minute); n != 0 {
+		t.Errorf("超过条目大小上限时 Set() = %d, want 0", n)
+	}
+	if n := fc.Set("fit", []byte(strings.Repeat("x", fc.MaxEntrySize()-len("fit"))), time.Minute); n != 1 {
+		t.Errorf("等于条目大小上限时 Set() = %d, want 1", n)
+	}
+}
+
+func TestFreecache_MSetMGet(t *testing.T) {
+	fc := setupFreecache(t)
+
+	values := map[string][]byte{"a": []byte("1"), "b": []byte("2")}
+	if n := fc.MSet(values, time.Minute); n != 2 {
+		t.Errorf("MSet() = %d, want 2", n)
+	}
+
+	got := fc.MGet([]string{"a", "b", "c"})
+	if len(got) != 2 || string(got["a"]) != "1" || string(got["b"]) != "2" {
+		t.Errorf("MGet() = %v", got)
+	}
+}
+
+func TestFreecache_Expire(t *testing.T) {
+	fc := setupFreecache(t)
+
+	fc.Set("short", []byte("v"), 10*time.Millisecond)
+	if _, ok := fc.Get("short"); !ok {
+		t.Error("不足 1 秒的过期时间应按 1 秒处理")
+	}
+
+	fc.Set("forever", []byte("v"), 0)
+	time.Sleep(1100 * time.Millisecond)
+	if _, ok := fc.Get("short"); ok {
+		t.Error("过期后 Get 应该返回 false")
+	}
+	if _, ok := fc.Get("forever"); !ok {
+		t.Error("过期时间为 0 时不应过期")
+	}
+}
+
+func TestExpireSeconds(t *testing.T) {
+	tests := []struct {
+		expire time.Duration
+		want   int
+	}{
+		{0, 0},
+		{-time.Second, 0},
+		{time.Millisecond, 1},
+		{time.Second, 1},
+		{1500 * time.Millisecond, 2},
+		{time.Minute, 60},
+	}
+	for _, tt := range tests {
+		if got := expireSeconds(tt.expire); got != tt.want {
+			t.Errorf("expireSeconds(%v) = %d, want %d", tt.expire, got, tt.want)
+		}
+	}
+}
+
+func TestFreecache_DeletePrefix(t *testing.T) {
+	testDeletePrefix(t, setupFreecache(t))
+}
+
+func TestFreecache_GetWithTTL(t *testing.T) {
+	testGetWithTTL(t, setupFreecache(t), true)
+}
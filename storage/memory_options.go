@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// MemoryOption 内存适配器的可选配置
+type MemoryOption interface {
+	apply(*memoryOptions)
+}
+
+// memoryOptions 内存适配器配置
+type memoryOptions struct {
+	// maxEntries 最多缓存的条目数，为 0 表示只按字节数限制
+	maxEntries int
+
+	// policy 淘汰策略，仅 ShardedMap 支持
+	policy EvictionPolicy
+
+	// shards 分片数，为 0 表示使用默认值，仅 ShardedMap 支持
+	shards int
+}
+
+// maxEntriesOption 设置条目数上限
+type maxEntriesOption struct {
+	maxEntries int
+}
+
+func (m maxEntriesOption) apply(opts *memoryOptions) {
+	opts.maxEntries = m.maxEntries
+}
+
+// WithMaxEntries 在字节容量之外限制最多缓存的条目数
+// 大量极小的键值按字节计算会容纳过多条目，每个条目固定的元数据开销可能远超预期的内存预算；
+// 设置后每个条目至少按 maxMemory/maxEntries 计算成本，因此条目数不会超过 maxEntries，总字节数也不会超过 maxMemory
+func WithMaxEntries(maxEntries int) MemoryOption {
+	return maxEntriesOption{maxEntries: maxEntries}
+}
+
+// evictionPolicyOption 设置淘汰策略
+type evictionPolicyOption struct {
+	policy EvictionPolicy
+}
+
+func (e evictionPolicyOption) apply(opts *memoryOptions) {
+	opts.policy = e.policy
+}
+
+// WithEvictionPolicy 设置容量不足时的淘汰策略，仅 ShardedMap 支持，Otter 和 Ristretto 使用各自内置的策略
+func WithEvictionPolicy(policy EvictionPolicy) MemoryOption {
+	return evictionPolicyOption{policy: policy}
+}
+
+// shardsOption 设置分片数
+type shardsOption struct {
+	shards int
+}
+
+func (s shardsOption) apply(opts *memoryOptions) {
+	opts.shards = s.shards
+}
+
+// WithShards 设置分片数，仅 ShardedMap 支持；分片越多锁竞争越少，但容量平均分配到各分片，单个条目不能超过一个分片的容量
+func WithShards(shards int) MemoryOption {
+	return shardsOption{shards: shards}
+}
+
+// newMemoryOptions 应用配置并计算单个条目的最小成本，未限制条目数时为 0
+func newMemoryOptions(name string, maxMemory int, opts []MemoryOption) (memoryOptions, int64, error) {
+	var cfg memoryOptions
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	if cfg.maxEntries < 0 {
+		return cfg, 0, fmt.Errorf("%s create: invalid maxEntries: %d", name, cfg.maxEntries)
+	}
+	if cfg.maxEntries == 0 {
+		return cfg, 0, nil
+	}
+	return cfg, int64((maxMemory + cfg.maxEntries - 1) / cfg.maxEntries), nil
+}
+
+// entryCost 返回条目的成本：键和值的字节数，不低于 minCost
+func entryCost(key string, value []byte, minCost int64) int64 {
+	return max(int64(len(key)+len(value)), minCost)
+}
+
+// costFunc 可替换的条目成本函数，未设置时使用 entryCost
+type costFunc struct {
+	fn      atomic.Pointer[func(key string, value []byte) int64]
+	minCost int64
+
+	// perEntry 按条目数限制容量，每个条目的成本固定为 1
+	perEntry bool
+}
+
+// set 设置成本函数，fn 为 nil 时恢复默认
+func (c *costFunc) set(fn func(key string, value []byte) int64) {
+	if fn == nil {
+		c.fn.Store(nil)
+		return
+	}
+	c.fn.Store(&fn)
+}
+
+// cost 返回条目的成本，不低于 minCost 且不为负数；按条目数限制容量时为 1
+func (c *costFunc) cost(key string, value []byte) int64 {
+	if c.perEntry {
+		return 1
+	}
+	fn := c.fn.Load()
+	if fn == nil {
+		return entryCost(key, value, c.minCost)
+	}
+	return max((*fn)(key, value), c.minCost, 0)
+}
+
+// rejectShardedOptions 检查是否设置了只有 ShardedMap 支持的配置
+func (cfg memoryOptions) rejectShardedOptions(name string) error {
+	if cfg.policy != EvictLRU || cfg.shards != 0 {
+		return fmt.Errorf("%s create: eviction policy and shards are only supported by sharded map", name)
+	}
+	return nil
+}
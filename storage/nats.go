@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+var _ InvalidationTransport = (*NATS)(nil)
+
+// natsFlushTimeout 等待服务端确认订阅的超时时间
+const natsFlushTimeout = 5 * time.Second
+
+// NATS 基于 NATS 核心发布订阅的失效消息传输，适用于已经部署 NATS 的环境，不占用 Redis 的连接和带宽；
+// 消息不持久化，断线重连期间的消息会丢失
+type NATS struct {
+	conn    *nats.Conn
+	subject string
+}
+
+// NewNATS 使用已创建的连接创建传输层，所有实例需要使用相同的 subject
+func NewNATS(conn *nats.Conn, subject string) *NATS {
+	return &NATS{conn: conn, subject: subject}
+}
+
+func (n *NATS) Publish(ctx context.Context, payload []byte) error {
+	if err := n.conn.Publish(n.subject, payload); err != nil {
+		return fmt.Errorf("nats publish %s: %w", n.subject, err)
+	}
+	return nil
+}
+
+func (n *NATS) Subscribe(ctx context.Context, handler func(payload []byte)) error {
+	sub, err := n.conn.SubscribeSync(n.subject)
+	if err != nil {
+		return fmt.Errorf("nats subscribe %s: %w", n.subject, err)
+	}
+	defer func() {
+		_ = sub.Unsubscribe()
+	}()
+
+	// 等待服务端确认订阅，保证返回前出错时能够感知
+	if err = n.conn.FlushTimeout(natsFlushTimeout); err != nil {
+		return fmt.Errorf("nats subscribe %s: %w", n.subject, err)
+	}
+
+	for {
+		msg, err := sub.NextMsgWithContext(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("nats subscribe %s: %w", n.subject, err)
+		}
+		handler(msg.Data)
+	}
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+)
+
+func TestNATS(t *testing.T) {
+	server := natsserver.RunRandClientPortServer()
+	defer server.Shutdown()
+	conn, err := nats.Connect(server.ClientURL())
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer conn.Close()
+	bus := NewNATS(conn, "invalidate")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	received := make(chan string, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- bus.Subscribe(ctx, func(payload []byte) {
+			received <- string(payload)
+		})
+	}()
+
+	// 订阅建立前发布的消息会丢失，重复发布直到收到
+	deadline := time.After(time.Second)
+	for got := false; !got; {
+		if err := bus.Publish(context.Background(), []byte("k1")); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+		select {
+		case payload := <-received:
+			if payload != "k1" {
+				t.Errorf("收到 %q, want k1", payload)
+			}
+			got = true
+		case <-time.After(10 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("订阅没有收到消息")
+		}
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Subscribe() error = %v, want context.Canceled", err)
+	}
+
+	// 连接关闭后订阅返回错误，由调用方重新订阅
+	go func() {
+		done <- bus.Subscribe(context.Background(), func([]byte) {})
+	}()
+	time.Sleep(20 * time.Millisecond)
+	conn.Close()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("连接关闭后 Subscribe() 应该返回错误")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("连接关闭后 Subscribe() 没有返回")
+	}
+}
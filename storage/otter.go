@@ -2,6 +2,9 @@ package storage
 
 import (
 	"fmt"
+	"math"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/maypok86/otter"
@@ -9,28 +12,76 @@ import (
 
 var _ Memory = (*Otter)(nil)
 
+var _ EvictionNotifier = (*Otter)(nil)
+
+var _ EntrySizeLimiter = (*Otter)(nil)
+
+var _ PrefixDeleter = (*Otter)(nil)
+
+var _ CostSetter = (*Otter)(nil)
+
+var _ TTLGetter = (*Otter)(nil)
+
+var _ Sizer = (*Otter)(nil)
+
 type Otter struct {
-	client *otter.CacheWithVariableTTL[string, []byte]
+	client  *otter.CacheWithVariableTTL[string, []byte]
+	onEvict atomic.Pointer[func(key string, value []byte)]
+	costs   costFunc
 }
 
-func NewOtter(maxMemory int) (*Otter, error) {
+// NewOtter 创建 Otter 内存适配器，maxMemory 为字节容量，可以通过 WithMaxEntries 同时限制条目数；
+// Otter 拒绝成本超过容量 10% 的条目，因此 maxEntries 不能小于 10
+func NewOtter(maxMemory int, opts ...MemoryOption) (*Otter, error) {
 	if maxMemory <= 0 {
 		return nil, fmt.Errorf("otter create: invalid maxMemory: %d", maxMemory)
 	}
-	cache, err := otter.MustBuilder[string, []byte](maxMemory).
+	cfg, minCost, err := newMemoryOptions("otter", maxMemory, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err = cfg.rejectShardedOptions("otter"); err != nil {
+		return nil, err
+	}
+	if cfg.maxEntries > 0 && cfg.maxEntries < 10 {
+		return nil, fmt.Errorf("otter create: maxEntries %d is less than 10", cfg.maxEntries)
+	}
+
+	o := &Otter{}
+	o.costs.minCost = minCost
+	return o.build(maxMemory)
+}
+
+// NewOtterWithEntries 创建按条目数限制容量的 Otter 内存适配器，每个条目的成本固定为 1，与值的大小无关，
+// 适合值大小均匀且较小的场景；SetCostFunc 设置的成本函数不生效。Otter 拒绝成本超过容量 10% 的条目，因此 maxEntries 不能小于 10
+func NewOtterWithEntries(maxEntries int) (*Otter, error) {
+	if maxEntries < 10 {
+		return nil, fmt.Errorf("otter create: maxEntries %d is less than 10", maxEntries)
+	}
+
+	o := &Otter{}
+	o.costs.perEntry = true
+	return o.build(maxEntries)
+}
+
+// build 按容量构建客户端
+func (o *Otter) build(capacity int) (*Otter, error) {
+	cache, err := otter.MustBuilder[string, []byte](capacity).
 		WithVariableTTL().
 		Cost(func(key string, value []byte) uint32 {
-			return uint32(len(key) + len(value))
+			return uint32(min(o.costs.cost(key, value), math.MaxUint32))
 		}).
+		DeletionListener(o.notifyDeletion).
 		Build()
 	if err != nil {
-		return nil, fmt.Errorf("otter create: capacity %d: %w", maxMemory, err)
+		return nil, fmt.Errorf("otter create: capacity %d: %w", capacity, err)
 	}
-	return &Otter{
-		client: &cache,
-	}, nil
+	o.client = &cache
+	return o, nil
 }
 
+// NewOtterWithClient 使用已有的客户端创建适配器
+// 客户端由调用方构建，OnEvict 设置的回调和 SetCostFunc 设置的成本函数都不会生效，需要时请在构建时自行设置 DeletionListener
 func NewOtterWithClient(client *otter.CacheWithVariableTTL[string, []byte]) *Otter {
 	return &Otter{
 		client: client,
@@ -67,6 +118,15 @@ func (o *Otter) Get(key string) ([]byte, bool) {
 	return o.client.Get(key)
 }
 
+// GetWithTTL 返回值和剩余过期时间，精度为秒
+func (o *Otter) GetWithTTL(key string) ([]byte, time.Duration, bool) {
+	entry, ok := o.client.Extension().GetEntry(key)
+	if !ok {
+		return nil, 0, false
+	}
+	return entry.Value(), entry.TTL(), true
+}
+
 func (o *Otter) MGet(keys []string) map[string][]byte {
 	ret := make(map[string][]byte)
 	for _, key := range keys {
@@ -82,3 +142,63 @@ func (o *Otter) MGet(keys []string) map[string][]byte {
 func (o *Otter) Delete(key string) {
 	o.client.Delete(key)
 }
+
+func (o *Otter) DeletePrefix(prefix string) int {
+	var count int
+	o.client.DeleteByFunc(func(key string, _ []byte) bool {
+		if strings.HasPrefix(key, prefix) {
+			count++
+			return true
+		}
+		return false
+	})
+	return count
+}
+
+// MaxEntrySize 返回单个条目的大小上限，Otter 拒绝超过容量 10% 的条目；按条目数限制容量时不限制大小
+func (o *Otter) MaxEntrySize() int {
+	if o.costs.perEntry {
+		return math.MaxInt
+	}
+	return o.client.Capacity() / 10
+}
+
+// Len 返回当前缓存的条目数
+func (o *Otter) Len() int {
+	return o.client.Size()
+}
+
+// Bytes 返回当前缓存条目的总字节数，需要遍历所有条目
+func (o *Otter) Bytes() int64 {
+	var n int64
+	o.client.Range(func(key string, value []byte) bool {
+		n += int64(len(key) + len(value))
+		return true
+	})
+	return n
+}
+
+// SetCostFunc 设置条目的成本函数，Otter 拒绝成本超过容量 10% 的条目
+func (o *Otter) SetCostFunc(fn func(key string, value []byte) int64) {
+	o.costs.set(fn)
+}
+
+// OnEvict 设置因容量不足淘汰条目时的回调，回调在 Otter 的淘汰协程中持有淘汰锁时同步执行，阻塞会使所有写入停顿
+func (o *Otter) OnEvict(fn func(key string, value []byte)) {
+	o.onEvict.Store(&fn)
+}
+
+func (o *Otter) notifyDeletion(key string, value []byte, cause otter.DeletionCause) {
+	if cause != otter.Size {
+		return
+	}
+	if fn := o.onEvict.Load(); fn != nil {
+		(*fn)(key, value)
+	}
+}
+
+// Close 停止后台清理协程并清空缓存
+func (o *Otter) Close() error {
+	o.client.Close()
+	return nil
+}
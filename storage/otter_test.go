@@ -3,6 +3,7 @@ package storage
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"testing"
 	"time"
 )
@@ -583,3 +584,153 @@ func containsOtter(slice []string, item string) bool {
 	}
 	return false
 }
+
+func TestOtter_OnEvict(t *testing.T) {
+	ot := setupOtter(t, 1000)
+
+	evicted := make(chan string, 100)
+	ot.OnEvict(func(key string, value []byte) {
+		evicted <- key
+	})
+
+	ot.Set("explicit", []byte("value"), time.Hour)
+	ot.Delete("explicit")
+
+	for i := 0; i < 100; i++ {
+		ot.Set(fmt.Sprintf("key-%d", i), make([]byte, 50), time.Hour)
+	}
+
+	select {
+	case key := <-evicted:
+		if key == "explicit" {
+			t.Error("显式删除不应触发淘汰回调")
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("容量不足时应触发淘汰回调")
+	}
+}
+
+func TestOtter_MaxEntrySize(t *testing.T) {
+	ot := setupOtter(t, 1000)
+
+	if got := ot.MaxEntrySize(); got != 100 {
+		t.Errorf("expected max entry size 100, got %d", got)
+	}
+
+	ot.Set("key", make([]byte, 200), time.Hour)
+	if _, ok := ot.Get("key"); ok {
+		t.Error("entry larger than max entry size should be rejected")
+	}
+}
+
+func TestOtter_DeletePrefix(t *testing.T) {
+	testDeletePrefix(t, setupOtter(t, 10000))
+}
+
+// testDeletePrefix 校验 PrefixDeleter 只删除匹配前缀的键
+func testDeletePrefix(t *testing.T, m interface {
+	Memory
+	PrefixDeleter
+}) {
+	t.Helper()
+
+	m.MSet(map[string][]byte{
+		"product:1": []byte("a"),
+		"product:2": []byte("b"),
+		"order:1":   []byte("c"),
+	}, time.Hour)
+	time.Sleep(10 * time.Millisecond)
+
+	if n := m.DeletePrefix("product:"); n != 2 {
+		t.Errorf("DeletePrefix() = %d, want 2", n)
+	}
+	if got := m.MGet([]string{"product:1", "product:2", "order:1"}); len(got) != 1 || got["order:1"] == nil {
+		t.Errorf("DeletePrefix() 后剩余 %v, want only order:1", got)
+	}
+}
+
+func TestOtter_GetWithTTL(t *testing.T) {
+	testGetWithTTL(t, setupOtter(t, 10000), false)
+}
+
+// testGetWithTTL 校验 TTLGetter 返回的剩余过期时间，noExpiry 表示适配器支持以 0 写入不过期的条目，此时应返回 -1
+func testGetWithTTL(t *testing.T, m interface {
+	Memory
+	TTLGetter
+}, noExpiry bool) {
+	t.Helper()
+
+	m.Set("expiring", []byte("a"), time.Hour)
+	m.Set("forever", []byte("b"), 0)
+	time.Sleep(10 * time.Millisecond)
+
+	value, ttl, ok := m.GetWithTTL("expiring")
+	if !ok || string(value) != "a" || ttl <= 59*time.Minute || ttl > time.Hour {
+		t.Errorf("GetWithTTL(expiring) = %q, %v, %v, want a with about 1h", value, ttl, ok)
+	}
+	if value, ttl, ok = m.GetWithTTL("forever"); noExpiry && (!ok || string(value) != "b" || ttl != -1) {
+		t.Errorf("GetWithTTL(forever) = %q, %v, %v, want b with -1", value, ttl, ok)
+	}
+	if _, _, ok = m.GetWithTTL("missing"); ok {
+		t.Error("GetWithTTL(missing) 不应该返回值")
+	}
+}
+
+func TestOtter_MaxEntries(t *testing.T) {
+	if _, err := NewOtter(1000, WithMaxEntries(5)); err == nil {
+		t.Error("maxEntries 小于 10 时应该返回错误")
+	}
+
+	ot, err := NewOtter(1<<20, WithMaxEntries(100))
+	if err != nil {
+		t.Fatalf("NewOtter() error = %v", err)
+	}
+	for i := 0; i < 1000; i++ {
+		ot.Set(fmt.Sprintf("key-%d", i), []byte("v"), time.Hour)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	if size := ot.client.Size(); size > 100 {
+		t.Errorf("条目数 %d 超过上限 100", size)
+	}
+}
+
+func TestNewOtterWithEntries(t *testing.T) {
+	if _, err := NewOtterWithEntries(5); err == nil {
+		t.Error("maxEntries 小于 10 时应该返回错误")
+	}
+
+	ot, err := NewOtterWithEntries(100)
+	if err != nil {
+		t.Fatalf("NewOtterWithEntries() error = %v", err)
+	}
+	t.Cleanup(func() { _ = ot.Close() })
+
+	// 条目的成本与值的大小无关
+	ot.Set("large", make([]byte, 1<<20), time.Hour)
+	for i := 0; i < 1000; i++ {
+		ot.Set(fmt.Sprintf("key-%d", i), []byte("v"), time.Hour)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	if n := ot.Len(); n == 0 || n > 100 {
+		t.Errorf("Len() = %d, want 1-100", n)
+	}
+	if got := ot.MaxEntrySize(); got != math.MaxInt {
+		t.Errorf("MaxEntrySize() = %d, want math.MaxInt", got)
+	}
+}
+
+func TestOtter_Sizer(t *testing.T) {
+	ot := setupOtter(t, 10000)
+	ot.Set("a", []byte("12345"), time.Hour)
+	ot.Set("bb", []byte("123"), time.Hour)
+	time.Sleep(10 * time.Millisecond)
+
+	if n := ot.Len(); n != 2 {
+		t.Errorf("Len() = %d, want 2", n)
+	}
+	if n := ot.Bytes(); n != 11 {
+		t.Errorf("Bytes() = %d, want 11", n)
+	}
+}
@@ -3,13 +3,60 @@ package storage
 import (
 	"context"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/biu7/layered-cache/errors"
 	"github.com/redis/go-redis/v9"
 )
 
-var _ Remote = (*Redis)(nil)
+var (
+	_ Remote         = (*Redis)(nil)
+	_ Scanner        = (*Redis)(nil)
+	_ Expirer        = (*Redis)(nil)
+	_ SetStore       = (*Redis)(nil)
+	_ MultiDeleter   = (*Redis)(nil)
+	_ MultiTTLSetter = (*Redis)(nil)
+	_ Counter        = (*Redis)(nil)
+
+	_ ConditionalSetter = (*Redis)(nil)
+	_ Swapper           = (*Redis)(nil)
+	_ CompareDeleter    = (*Redis)(nil)
+	_ CompareSetter     = (*Redis)(nil)
+)
+
+// compareAndDeleteScript 值相等时才删除，用于释放锁时确认锁仍由自己持有
+var compareAndDeleteScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// compareAndSetScript 值相等时才写入新值，保留原有的过期时间
+var compareAndSetScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	redis.call('SET', KEYS[1], ARGV[2], 'KEEPTTL')
+	return 1
+end
+return 0
+`)
+
+// incrByScript 增加计数并为没有过期时间的键设置过期时间，保证新建的计数器不会永久保留
+var incrByScript = redis.NewScript(`
+local value = redis.call('INCRBY', KEYS[1], ARGV[1])
+if tonumber(ARGV[2]) > 0 and redis.call('PTTL', KEYS[1]) == -1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return value
+`)
+
+// popAllScript 读取集合的所有成员后删除集合，避免读取和删除之间新加入的成员丢失
+var popAllScript = redis.NewScript(`
+local members = redis.call('SMEMBERS', KEYS[1])
+redis.call('DEL', KEYS[1])
+return members
+`)
 
 type Redis struct {
 	client redis.Cmdable
@@ -24,6 +71,7 @@ func NewRedis(redisURL string) (*Redis, error) {
 	return NewRedisWithClient(client), nil
 }
 
+// NewRedisWithClient 使用已创建的客户端创建 Redis 适配器，支持 *redis.Client、*redis.ClusterClient 和 *redis.Ring
 func NewRedisWithClient(client redis.Cmdable) *Redis {
 	return &Redis{client: client}
 }
@@ -36,7 +84,12 @@ func (r *Redis) Set(ctx context.Context, key string, value []byte, expire time.D
 	return nil
 }
 
+// MSet 通过 pipeline 批量写入，集群模式下按 hash slot 拆分并发写入
 func (r *Redis) MSet(ctx context.Context, values map[string][]byte, expire time.Duration) error {
+	if r.isCluster() {
+		return r.msetBySlot(ctx, values, expire)
+	}
+
 	pipeline := r.client.Pipeline()
 
 	for key, val := range values {
@@ -49,6 +102,23 @@ func (r *Redis) MSet(ctx context.Context, values map[string][]byte, expire time.
 	return nil
 }
 
+// MSetWithTTLs 使用 pipeline 逐个 SET ... PX 写入，集群模式下由客户端按节点拆分
+func (r *Redis) MSetWithTTLs(ctx context.Context, entries map[string]ValueWithTTL) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	pipeline := r.client.Pipeline()
+	for key, entry := range entries {
+		pipeline.Set(ctx, key, entry.Value, entry.TTL)
+	}
+	_, err := pipeline.Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("redis msetwithttls: %w", err)
+	}
+	return nil
+}
+
 func (r *Redis) Get(ctx context.Context, key string) ([]byte, error) {
 	val, err := r.client.Get(ctx, key).Bytes()
 	if err != nil {
@@ -60,11 +130,19 @@ func (r *Redis) Get(ctx context.Context, key string) ([]byte, error) {
 	return val, nil
 }
 
+// MGet 批量读取，集群模式下按 hash slot 拆分为多个 MGET，分片模式下使用 pipeline 逐个 GET
 func (r *Redis) MGet(ctx context.Context, keys []string) (map[string][]byte, error) {
 	ret := make(map[string][]byte, len(keys))
 	if len(keys) == 0 {
 		return ret, nil
 	}
+	switch {
+	case r.isCluster():
+		return r.mgetBySlot(ctx, keys)
+	case r.isRing():
+		return r.mgetPipelined(ctx, keys)
+	}
+
 	vals, err := r.client.MGet(ctx, keys...).Result()
 	if err != nil {
 		return nil, fmt.Errorf("redis mget: %w", err)
@@ -86,6 +164,131 @@ func (r *Redis) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// MDelete 通过 pipeline 逐个删除，兼容集群模式下键分布在不同 slot 的情况
+func (r *Redis) MDelete(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	pipeline := r.client.Pipeline()
+	for _, key := range keys {
+		pipeline.Del(ctx, key)
+	}
+	_, err := pipeline.Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("redis mdelete: %w", err)
+	}
+	return nil
+}
+
+// Scan 遍历匹配 match 的键；集群和分片客户端的 SCAN 只会发往单个节点，各节点的游标也无法合并，因此返回 ErrOperationNotSupported
+func (r *Redis) Scan(ctx context.Context, match string, cursor uint64, count int) ([]string, uint64, error) {
+	if r.isCluster() || r.isRing() {
+		return nil, 0, fmt.Errorf("redis scan %s: %w: cluster and ring clients", match, errors.ErrOperationNotSupported)
+	}
+	keys, next, err := r.client.Scan(ctx, cursor, match, int64(count)).Result()
+	if err != nil {
+		return nil, 0, fmt.Errorf("redis scan %s: %w", match, err)
+	}
+	return keys, next, nil
+}
+
+func (r *Redis) MExpire(ctx context.Context, keys []string, expire time.Duration) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	pipeline := r.client.Pipeline()
+	for _, key := range keys {
+		pipeline.Expire(ctx, key, expire)
+	}
+	_, err := pipeline.Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("redis mexpire: %w", err)
+	}
+	return nil
+}
+
+// GetSet 使用 SET ... GET 替换值，需要 Redis 6.2 及以上
+func (r *Redis) GetSet(ctx context.Context, key string, value []byte, expire time.Duration) ([]byte, error) {
+	old, err := r.client.SetArgs(ctx, key, value, redis.SetArgs{TTL: expire, Get: true}).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, errors.ErrNotFound
+		}
+		return nil, fmt.Errorf("redis getset %s: %w", key, err)
+	}
+	return old, nil
+}
+
+func (r *Redis) SetNX(ctx context.Context, key string, value []byte, expire time.Duration) (bool, error) {
+	ok, err := r.client.SetNX(ctx, key, value, expire).Result()
+	if err != nil {
+		return false, fmt.Errorf("redis setnx %s: %w", key, err)
+	}
+	return ok, nil
+}
+
+func (r *Redis) CompareAndDelete(ctx context.Context, key string, value []byte) (bool, error) {
+	deleted, err := compareAndDeleteScript.Run(ctx, r.client, []string{key}, value).Int()
+	if err != nil {
+		return false, fmt.Errorf("redis compare and delete %s: %w", key, err)
+	}
+	return deleted > 0, nil
+}
+
+func (r *Redis) CompareAndSet(ctx context.Context, key string, old, value []byte) (bool, error) {
+	set, err := compareAndSetScript.Run(ctx, r.client, []string{key}, old, value).Int()
+	if err != nil {
+		return false, fmt.Errorf("redis compare and set %s: %w", key, err)
+	}
+	return set > 0, nil
+}
+
+func (r *Redis) IncrBy(ctx context.Context, key string, delta int64, expire time.Duration) (int64, error) {
+	value, err := incrByScript.Run(ctx, r.client, []string{key}, delta, expire.Milliseconds()).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("redis incrby %s: %w", key, err)
+	}
+	return value, nil
+}
+
+func (r *Redis) SAdd(ctx context.Context, key string, members []string, expire time.Duration) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	args := make([]any, len(members))
+	for i, member := range members {
+		args[i] = member
+	}
+
+	pipeline := r.client.Pipeline()
+	pipeline.SAdd(ctx, key, args...)
+	pipeline.Expire(ctx, key, expire)
+	_, err := pipeline.Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("redis sadd %s: %w", key, err)
+	}
+	return nil
+}
+
+func (r *Redis) SMembers(ctx context.Context, key string) ([]string, error) {
+	members, err := r.client.SMembers(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis smembers %s: %w", key, err)
+	}
+	return members, nil
+}
+
+func (r *Redis) SPopAll(ctx context.Context, key string) ([]string, error) {
+	members, err := popAllScript.Run(ctx, r.client, []string{key}).StringSlice()
+	if err != nil {
+		return nil, fmt.Errorf("redis spopall %s: %w", key, err)
+	}
+	return members, nil
+}
+
 func (r *Redis) TTL(ctx context.Context, key string) (time.Duration, error) {
 	ttl, err := r.client.TTL(ctx, key).Result()
 	if err != nil {
@@ -93,3 +296,11 @@ func (r *Redis) TTL(ctx context.Context, key string) (time.Duration, error) {
 	}
 	return ttl, nil
 }
+
+// Close 关闭 Redis 客户端，客户端不支持关闭时直接返回
+func (r *Redis) Close() error {
+	if closer, ok := r.client.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
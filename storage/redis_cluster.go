@@ -0,0 +1,211 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// clusterSlots Redis 集群的 hash slot 数量
+	clusterSlots = 16384
+
+	// clusterWriteConcurrency 集群模式下并发写入的 slot 数量上限
+	clusterWriteConcurrency = 8
+)
+
+// SlotError 集群模式下按 slot 写入时的聚合错误，Errors 的键为出错的 slot
+type SlotError struct {
+	Errors map[int]error
+}
+
+func (e *SlotError) Error() string {
+	slots := make([]int, 0, len(e.Errors))
+	for slot := range e.Errors {
+		slots = append(slots, slot)
+	}
+	sort.Ints(slots)
+
+	msgs := make([]string, 0, len(slots))
+	for _, slot := range slots {
+		msgs = append(msgs, fmt.Sprintf("slot %d: %v", slot, e.Errors[slot]))
+	}
+	return fmt.Sprintf("%d slots failed: %s", len(slots), strings.Join(msgs, "; "))
+}
+
+func (e *SlotError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// NewRedisCluster 使用集群节点地址创建 Redis 适配器，configure 用于设置密码、超时等其他配置
+func NewRedisCluster(addrs []string, configure ...func(*redis.ClusterOptions)) (*Redis, error) {
+	if len(addrs) == 0 {
+		return nil, errors.ErrRedisAddrsRequired
+	}
+	opt := &redis.ClusterOptions{Addrs: addrs}
+	for _, fn := range configure {
+		fn(opt)
+	}
+	return NewRedisWithClient(redis.NewClusterClient(opt)), nil
+}
+
+// NewRedisRing 使用分片名称到节点地址的映射创建 Redis 适配器，configure 用于设置密码、超时等其他配置
+func NewRedisRing(addrs map[string]string, configure ...func(*redis.RingOptions)) (*Redis, error) {
+	if len(addrs) == 0 {
+		return nil, errors.ErrRedisAddrsRequired
+	}
+	opt := &redis.RingOptions{Addrs: addrs}
+	for _, fn := range configure {
+		fn(opt)
+	}
+	return NewRedisWithClient(redis.NewRing(opt)), nil
+}
+
+// mgetBySlot 按 hash slot 拆分键，每个 slot 一个 MGET，在同一个 pipeline 中执行
+func (r *Redis) mgetBySlot(ctx context.Context, keys []string) (map[string][]byte, error) {
+	var slots []int
+	slotKeys := make(map[int][]string)
+	for _, key := range keys {
+		slot := keySlot(key)
+		if _, ok := slotKeys[slot]; !ok {
+			slots = append(slots, slot)
+		}
+		slotKeys[slot] = append(slotKeys[slot], key)
+	}
+
+	pipeline := r.client.Pipeline()
+	cmds := make([]*redis.SliceCmd, len(slots))
+	for i, slot := range slots {
+		cmds[i] = pipeline.MGet(ctx, slotKeys[slot]...)
+	}
+	if _, err := pipeline.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("redis mget: %w", err)
+	}
+
+	ret := make(map[string][]byte, len(keys))
+	for i, slot := range slots {
+		for j, val := range cmds[i].Val() {
+			if val != nil {
+				ret[slotKeys[slot][j]] = []byte(val.(string))
+			}
+		}
+	}
+	return ret, nil
+}
+
+// mgetPipelined 使用 pipeline 逐个 GET，由客户端将每个键路由到所属节点
+func (r *Redis) mgetPipelined(ctx context.Context, keys []string) (map[string][]byte, error) {
+	pipeline := r.client.Pipeline()
+	cmds := make([]*redis.StringCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipeline.Get(ctx, key)
+	}
+	// 不存在的键会以 redis.Nil 作为 Exec 的错误返回，逐个检查命令的错误
+	_, _ = pipeline.Exec(ctx)
+
+	ret := make(map[string][]byte, len(keys))
+	for i, cmd := range cmds {
+		val, err := cmd.Bytes()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				continue
+			}
+			return nil, fmt.Errorf("redis mget: %w", err)
+		}
+		ret[keys[i]] = val
+	}
+	return ret, nil
+}
+
+// msetBySlot 按 hash slot 拆分写入，每个 slot 一个 pipeline，最多 clusterWriteConcurrency 个 slot 并发执行
+func (r *Redis) msetBySlot(ctx context.Context, values map[string][]byte, expire time.Duration) error {
+	slots := make(map[int]map[string][]byte)
+	for key, val := range values {
+		slot := keySlot(key)
+		if slots[slot] == nil {
+			slots[slot] = make(map[string][]byte)
+		}
+		slots[slot][key] = val
+	}
+
+	var (
+		mu   sync.Mutex
+		errs = make(map[int]error)
+		wg   sync.WaitGroup
+		sem  = make(chan struct{}, clusterWriteConcurrency)
+	)
+	for slot, slotValues := range slots {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			pipeline := r.client.Pipeline()
+			for key, val := range slotValues {
+				pipeline.Set(ctx, key, val, expire)
+			}
+			if _, err := pipeline.Exec(ctx); err != nil {
+				mu.Lock()
+				errs[slot] = err
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return fmt.Errorf("redis mset: %w", &SlotError{Errors: errs})
+	}
+	return nil
+}
+
+// isCluster 判断客户端是否为集群客户端
+func (r *Redis) isCluster() bool {
+	_, ok := r.client.(*redis.ClusterClient)
+	return ok
+}
+
+// isRing 判断客户端是否为分片客户端
+func (r *Redis) isRing() bool {
+	_, ok := r.client.(*redis.Ring)
+	return ok
+}
+
+// keySlot 计算键所属的 hash slot，键中包含非空的 {hashtag} 时只对 hashtag 计算
+func keySlot(key string) int {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	return int(crc16(key) % clusterSlots)
+}
+
+// crc16 Redis 集群使用的 CRC16（XMODEM）
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
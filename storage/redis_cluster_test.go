@@ -0,0 +1,215 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/biu7/layered-cache/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+func setupRedisCluster(t *testing.T) (*Redis, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClusterClient(&redis.ClusterOptions{
+		Addrs: []string{mr.Addr()},
+	})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+	return NewRedisWithClient(client), mr
+}
+
+func TestKeySlot(t *testing.T) {
+	tests := []struct {
+		key  string
+		slot int
+	}{
+		{key: "123456789", slot: 12739},
+		{key: "foo", slot: 12182},
+		{key: "{user1000}.following", slot: keySlot("user1000")},
+		{key: "{}.foo", slot: keySlot("{}.foo")},
+		{key: "foo{}{bar}", slot: keySlot("foo{}{bar}")},
+	}
+
+	for _, tt := range tests {
+		if got := keySlot(tt.key); got != tt.slot {
+			t.Errorf("keySlot(%q) = %d, want %d", tt.key, got, tt.slot)
+		}
+	}
+
+	if keySlot("{user1000}.following") != keySlot("{user1000}.followers") {
+		t.Error("keys with the same hashtag should share a slot")
+	}
+}
+
+func TestRedis_MSet_Cluster(t *testing.T) {
+	rdb, mr := setupRedisCluster(t)
+	ctx := context.Background()
+
+	values := make(map[string][]byte)
+	for i := 0; i < 50; i++ {
+		values[fmt.Sprintf("key:%d", i)] = []byte(fmt.Sprintf("value:%d", i))
+	}
+
+	if err := rdb.MSet(ctx, values, time.Minute); err != nil {
+		t.Fatalf("mset failed: %v", err)
+	}
+
+	for key, val := range values {
+		got, err := mr.Get(key)
+		if err != nil {
+			t.Fatalf("get %s failed: %v", key, err)
+		}
+		if got != string(val) {
+			t.Errorf("expected %s, got %s", val, got)
+		}
+		if ttl := mr.TTL(key); ttl != time.Minute {
+			t.Errorf("expected ttl 1m, got %v", ttl)
+		}
+	}
+}
+
+func TestRedis_MSet_ClusterError(t *testing.T) {
+	rdb, mr := setupRedisCluster(t)
+	ctx := context.Background()
+
+	// 先建立集群拓扑，再让后续命令失败
+	if err := rdb.Set(ctx, "warmup", []byte("v"), time.Minute); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+	mr.SetError("ERR boom")
+
+	values := map[string][]byte{"a": []byte("1"), "b": []byte("2")}
+	err := rdb.MSet(ctx, values, time.Minute)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	var slotErr *SlotError
+	if !errors.As(err, &slotErr) {
+		t.Fatalf("expected SlotError, got %T", err)
+	}
+	for key := range values {
+		if _, ok := slotErr.Errors[keySlot(key)]; !ok {
+			t.Errorf("expected error for slot of key %s", key)
+		}
+	}
+}
+
+func TestNewRedisCluster(t *testing.T) {
+	if _, err := NewRedisCluster(nil); !errors.Is(err, errors.ErrRedisAddrsRequired) {
+		t.Fatalf("expected ErrRedisAddrsRequired, got %v", err)
+	}
+
+	mr := miniredis.RunT(t)
+	configured := false
+	rdb, err := NewRedisCluster([]string{mr.Addr()}, func(opt *redis.ClusterOptions) {
+		configured = true
+		opt.MaxRetries = 1
+	})
+	if err != nil {
+		t.Fatalf("new redis cluster failed: %v", err)
+	}
+	if !configured {
+		t.Error("expected configure to be called")
+	}
+	if !rdb.isCluster() {
+		t.Errorf("expected cluster client, got %T", rdb.client)
+	}
+}
+
+func TestRedis_MGet_Cluster(t *testing.T) {
+	rdb, mr := setupRedisCluster(t)
+	ctx := context.Background()
+
+	keys := []string{"missing"}
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("key:%d", i)
+		keys = append(keys, key)
+		if err := mr.Set(key, fmt.Sprintf("value:%d", i)); err != nil {
+			t.Fatalf("set failed: %v", err)
+		}
+	}
+
+	values, err := rdb.MGet(ctx, keys)
+	if err != nil {
+		t.Fatalf("mget failed: %v", err)
+	}
+	if len(values) != 20 {
+		t.Fatalf("expected 20 values, got %d", len(values))
+	}
+	for i := 0; i < 20; i++ {
+		if got := string(values[fmt.Sprintf("key:%d", i)]); got != fmt.Sprintf("value:%d", i) {
+			t.Errorf("key:%d: expected value:%d, got %s", i, i, got)
+		}
+	}
+}
+
+func TestRedis_Ring(t *testing.T) {
+	if _, err := NewRedisRing(nil); !errors.Is(err, errors.ErrRedisAddrsRequired) {
+		t.Fatalf("expected ErrRedisAddrsRequired, got %v", err)
+	}
+
+	shards := map[string]*miniredis.Miniredis{"a": miniredis.RunT(t), "b": miniredis.RunT(t)}
+	rdb, err := NewRedisRing(map[string]string{"a": shards["a"].Addr(), "b": shards["b"].Addr()})
+	if err != nil {
+		t.Fatalf("new redis ring failed: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = rdb.client.(*redis.Ring).Close()
+	})
+	ctx := context.Background()
+
+	values := make(map[string][]byte)
+	keys := []string{"missing"}
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("key:%d", i)
+		keys = append(keys, key)
+		values[key] = []byte(fmt.Sprintf("value:%d", i))
+	}
+	if err = rdb.MSet(ctx, values, time.Minute); err != nil {
+		t.Fatalf("mset failed: %v", err)
+	}
+	if len(shards["a"].Keys()) == 0 || len(shards["b"].Keys()) == 0 {
+		t.Fatalf("expected keys on both shards, got %d and %d", len(shards["a"].Keys()), len(shards["b"].Keys()))
+	}
+
+	got, err := rdb.MGet(ctx, keys)
+	if err != nil {
+		t.Fatalf("mget failed: %v", err)
+	}
+	if len(got) != len(values) {
+		t.Fatalf("expected %d values, got %d", len(values), len(got))
+	}
+	for key, val := range values {
+		if string(got[key]) != string(val) {
+			t.Errorf("%s: expected %s, got %s", key, val, got[key])
+		}
+	}
+}
+
+func TestRedis_Scan_Cluster(t *testing.T) {
+	rdb, _ := setupRedisCluster(t)
+	ctx := context.Background()
+
+	if err := rdb.Set(ctx, "key:1", []byte("value"), time.Minute); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+	if _, _, err := rdb.Scan(ctx, "key:*", 0, 10); !errors.Is(err, errors.ErrOperationNotSupported) {
+		t.Errorf("expected ErrOperationNotSupported for cluster scan, got %v", err)
+	}
+
+	mr := miniredis.RunT(t)
+	ring := redis.NewRing(&redis.RingOptions{Addrs: map[string]string{"shard": mr.Addr()}})
+	t.Cleanup(func() {
+		_ = ring.Close()
+	})
+	if _, _, err := NewRedisWithClient(ring).Scan(ctx, "key:*", 0, 10); !errors.Is(err, errors.ErrOperationNotSupported) {
+		t.Errorf("expected ErrOperationNotSupported for ring scan, got %v", err)
+	}
+}
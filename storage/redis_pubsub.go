@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var _ InvalidationTransport = (*RedisPubSub)(nil)
+
+// RedisPubSub 基于 Redis Pub/Sub 的失效消息传输，消息不持久化，订阅断开期间的消息会丢失
+type RedisPubSub struct {
+	client  redis.UniversalClient
+	channel string
+}
+
+// NewRedisPubSub 使用已创建的客户端创建传输层，所有实例需要使用相同的 channel
+func NewRedisPubSub(client redis.UniversalClient, channel string) *RedisPubSub {
+	return &RedisPubSub{client: client, channel: channel}
+}
+
+func (r *RedisPubSub) Publish(ctx context.Context, payload []byte) error {
+	if err := r.client.Publish(ctx, r.channel, payload).Err(); err != nil {
+		return fmt.Errorf("redis publish %s: %w", r.channel, err)
+	}
+	return nil
+}
+
+func (r *RedisPubSub) Subscribe(ctx context.Context, handler func(payload []byte)) error {
+	pubsub := r.client.Subscribe(ctx, r.channel)
+	defer pubsub.Close()
+
+	// 等待订阅确认，保证返回前出错时能够感知
+	if _, err := pubsub.Receive(ctx); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("redis subscribe %s: %w", r.channel, err)
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-messages:
+			if !ok {
+				return fmt.Errorf("redis subscribe %s: channel closed", r.channel)
+			}
+			handler([]byte(msg.Payload))
+		}
+	}
+}
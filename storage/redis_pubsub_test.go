@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestRedisPubSub(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	bus := NewRedisPubSub(client, "invalidate")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	received := make(chan string, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- bus.Subscribe(ctx, func(payload []byte) {
+			received <- string(payload)
+		})
+	}()
+
+	// 订阅建立前发布的消息会丢失，重复发布直到收到
+	deadline := time.After(time.Second)
+	for got := false; !got; {
+		if err := bus.Publish(context.Background(), []byte("k1")); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+		select {
+		case payload := <-received:
+			if payload != "k1" {
+				t.Errorf("收到 %q, want k1", payload)
+			}
+			got = true
+		case <-time.After(10 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("订阅没有收到消息")
+		}
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Subscribe() error = %v, want context.Canceled", err)
+	}
+}
@@ -562,3 +562,317 @@ func BenchmarkRedis_MGet(b *testing.B) {
 		}
 	})
 }
+
+func TestRedis_Scan(t *testing.T) {
+	rdb, mr := setupRedis(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		if err := rdb.Set(ctx, fmt.Sprintf("scan:%d", i), []byte("v"), time.Minute); err != nil {
+			t.Fatalf("set failed: %v", err)
+		}
+	}
+	if err := rdb.Set(ctx, "other", []byte("v"), time.Minute); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+
+	var keys []string
+	var cursor uint64
+	for {
+		batch, next, err := rdb.Scan(ctx, "scan:*", cursor, 2)
+		if err != nil {
+			t.Fatalf("scan failed: %v", err)
+		}
+		keys = append(keys, batch...)
+		if next == 0 {
+			break
+		}
+		cursor = next
+	}
+
+	if len(keys) != 5 {
+		t.Errorf("expected 5 keys, got %d: %v", len(keys), keys)
+	}
+}
+
+func TestRedis_MDelete(t *testing.T) {
+	rdb, mr := setupRedis(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	if err := rdb.MSet(ctx, map[string][]byte{"key1": []byte("v"), "key2": []byte("v"), "key3": []byte("v")}, time.Minute); err != nil {
+		t.Fatalf("mset failed: %v", err)
+	}
+
+	if err := rdb.MDelete(ctx, []string{"key1", "key2", "missing"}); err != nil {
+		t.Fatalf("mdelete failed: %v", err)
+	}
+
+	if mr.Exists("key1") || mr.Exists("key2") {
+		t.Error("deleted keys should not exist")
+	}
+	if !mr.Exists("key3") {
+		t.Error("key3 should not be deleted")
+	}
+
+	if err := rdb.MDelete(ctx, nil); err != nil {
+		t.Errorf("empty keys should not fail: %v", err)
+	}
+}
+
+func TestRedis_MExpire(t *testing.T) {
+	rdb, mr := setupRedis(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	if err := rdb.Set(ctx, "key1", []byte("v"), time.Minute); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+
+	if err := rdb.MExpire(ctx, []string{"key1", "missing"}, time.Hour); err != nil {
+		t.Fatalf("mexpire failed: %v", err)
+	}
+
+	if ttl := mr.TTL("key1"); ttl != time.Hour {
+		t.Errorf("expected ttl 1h, got %v", ttl)
+	}
+	if mr.Exists("missing") {
+		t.Error("missing key should not be created")
+	}
+
+	if err := rdb.MExpire(ctx, nil, time.Hour); err != nil {
+		t.Errorf("empty keys should not fail: %v", err)
+	}
+}
+
+func TestRedis_MSetWithTTLs(t *testing.T) {
+	rdb, mr := setupRedis(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	err := rdb.MSetWithTTLs(ctx, map[string]ValueWithTTL{
+		"short":   {Value: []byte("a"), TTL: time.Minute},
+		"long":    {Value: []byte("b"), TTL: time.Hour},
+		"forever": {Value: []byte("c")},
+	})
+	if err != nil {
+		t.Fatalf("msetwithttls failed: %v", err)
+	}
+
+	for key, want := range map[string]time.Duration{"short": time.Minute, "long": time.Hour, "forever": 0} {
+		if ttl := mr.TTL(key); ttl != want {
+			t.Errorf("%s: expected ttl %v, got %v", key, want, ttl)
+		}
+	}
+	value, err := rdb.Get(ctx, "long")
+	if err != nil || string(value) != "b" {
+		t.Errorf("expected b, got %s, %v", value, err)
+	}
+
+	if err := rdb.MSetWithTTLs(ctx, nil); err != nil {
+		t.Errorf("empty entries should not fail: %v", err)
+	}
+}
+
+func TestRedis_SetNX(t *testing.T) {
+	rdb, mr := setupRedis(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	ok, err := rdb.SetNX(ctx, "token", []byte("first"), time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("first setnx should succeed: %v, %v", ok, err)
+	}
+	if ok, err = rdb.SetNX(ctx, "token", []byte("second"), time.Minute); err != nil || ok {
+		t.Fatalf("second setnx should fail: %v, %v", ok, err)
+	}
+
+	value, err := rdb.Get(ctx, "token")
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if string(value) != "first" {
+		t.Errorf("expected first, got %s", value)
+	}
+	if ttl := mr.TTL("token"); ttl != time.Minute {
+		t.Errorf("expected ttl 1m, got %v", ttl)
+	}
+}
+
+func TestRedis_GetSet(t *testing.T) {
+	rdb, mr := setupRedis(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	if _, err := rdb.GetSet(ctx, "flag", []byte("on"), time.Minute); !errors.Is(err, errors.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for missing key, got %v", err)
+	}
+
+	old, err := rdb.GetSet(ctx, "flag", []byte("off"), time.Hour)
+	if err != nil {
+		t.Fatalf("getset failed: %v", err)
+	}
+	if string(old) != "on" {
+		t.Errorf("expected on, got %s", old)
+	}
+
+	value, err := rdb.Get(ctx, "flag")
+	if err != nil || string(value) != "off" {
+		t.Errorf("expected off, got %s, %v", value, err)
+	}
+	if ttl := mr.TTL("flag"); ttl != time.Hour {
+		t.Errorf("expected ttl 1h, got %v", ttl)
+	}
+}
+
+func TestRedis_CompareAndDelete(t *testing.T) {
+	rdb, mr := setupRedis(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	if err := rdb.Set(ctx, "lock", []byte("token"), time.Minute); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+
+	deleted, err := rdb.CompareAndDelete(ctx, "lock", []byte("other"))
+	if err != nil || deleted {
+		t.Fatalf("mismatched value should not be deleted: %v, %v", deleted, err)
+	}
+	if !mr.Exists("lock") {
+		t.Fatal("lock should still exist")
+	}
+
+	if deleted, err = rdb.CompareAndDelete(ctx, "lock", []byte("token")); err != nil || !deleted {
+		t.Fatalf("matched value should be deleted: %v, %v", deleted, err)
+	}
+	if deleted, err = rdb.CompareAndDelete(ctx, "lock", []byte("token")); err != nil || deleted {
+		t.Errorf("missing key should not be deleted: %v, %v", deleted, err)
+	}
+}
+
+func TestRedis_CompareAndSet(t *testing.T) {
+	rdb, mr := setupRedis(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	if err := rdb.Set(ctx, "key", []byte("old"), time.Hour); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+
+	set, err := rdb.CompareAndSet(ctx, "key", []byte("other"), []byte("new"))
+	if err != nil || set {
+		t.Fatalf("mismatched value should not be replaced: %v, %v", set, err)
+	}
+
+	if set, err = rdb.CompareAndSet(ctx, "key", []byte("old"), []byte("new")); err != nil || !set {
+		t.Fatalf("matched value should be replaced: %v, %v", set, err)
+	}
+	if value, _ := mr.Get("key"); value != "new" {
+		t.Errorf("expected new, got %q", value)
+	}
+	if ttl := mr.TTL("key"); ttl != time.Hour {
+		t.Errorf("expected ttl 1h to be kept, got %v", ttl)
+	}
+
+	if set, err = rdb.CompareAndSet(ctx, "missing", nil, []byte("new")); err != nil || set {
+		t.Errorf("missing key should not be set: %v, %v", set, err)
+	}
+}
+
+func TestRedis_IncrBy(t *testing.T) {
+	rdb, mr := setupRedis(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	value, err := rdb.IncrBy(ctx, "counter", 5, time.Minute)
+	if err != nil {
+		t.Fatalf("incrby failed: %v", err)
+	}
+	if value != 5 {
+		t.Errorf("expected 5, got %d", value)
+	}
+	if ttl := mr.TTL("counter"); ttl != time.Minute {
+		t.Errorf("expected ttl 1m, got %v", ttl)
+	}
+
+	mr.SetTTL("counter", time.Hour)
+	if value, err = rdb.IncrBy(ctx, "counter", -2, time.Minute); err != nil {
+		t.Fatalf("incrby failed: %v", err)
+	}
+	if value != 3 {
+		t.Errorf("expected 3, got %d", value)
+	}
+	if ttl := mr.TTL("counter"); ttl != time.Hour {
+		t.Errorf("existing ttl should be kept, got %v", ttl)
+	}
+
+	if _, err = rdb.IncrBy(ctx, "forever", 1, 0); err != nil {
+		t.Fatalf("incrby failed: %v", err)
+	}
+	if ttl := mr.TTL("forever"); ttl != 0 {
+		t.Errorf("expected no ttl, got %v", ttl)
+	}
+
+	if err = rdb.Set(ctx, "text", []byte("abc"), 0); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+	if _, err = rdb.IncrBy(ctx, "text", 1, 0); err == nil {
+		t.Error("expected error for non-integer value")
+	}
+}
+
+func TestRedis_SAddSMembers(t *testing.T) {
+	rdb, mr := setupRedis(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	if err := rdb.SAdd(ctx, "set", []string{"a", "b"}, time.Minute); err != nil {
+		t.Fatalf("sadd failed: %v", err)
+	}
+	if err := rdb.SAdd(ctx, "set", []string{"b", "c"}, time.Hour); err != nil {
+		t.Fatalf("sadd failed: %v", err)
+	}
+
+	members, err := rdb.SMembers(ctx, "set")
+	if err != nil {
+		t.Fatalf("smembers failed: %v", err)
+	}
+	if len(members) != 3 {
+		t.Errorf("expected 3 members, got %v", members)
+	}
+	if ttl := mr.TTL("set"); ttl != time.Hour {
+		t.Errorf("expected ttl 1h, got %v", ttl)
+	}
+
+	members, err = rdb.SMembers(ctx, "missing")
+	if err != nil || len(members) != 0 {
+		t.Errorf("expected empty members, got %v, %v", members, err)
+	}
+}
+
+func TestRedis_SPopAll(t *testing.T) {
+	rdb, mr := setupRedis(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	if err := rdb.SAdd(ctx, "set", []string{"a", "b"}, time.Minute); err != nil {
+		t.Fatalf("sadd failed: %v", err)
+	}
+
+	members, err := rdb.SPopAll(ctx, "set")
+	if err != nil {
+		t.Fatalf("spopall failed: %v", err)
+	}
+	if len(members) != 2 {
+		t.Errorf("expected 2 members, got %v", members)
+	}
+	if mr.Exists("set") {
+		t.Error("expected set to be deleted")
+	}
+
+	members, err = rdb.SPopAll(ctx, "missing")
+	if err != nil || len(members) != 0 {
+		t.Errorf("expected empty members, got %v, %v", members, err)
+	}
+}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/dgraph-io/ristretto/v2"
@@ -9,14 +10,31 @@ import (
 
 var _ Memory = (*Ristretto)(nil)
 
+var _ EntrySizeLimiter = (*Ristretto)(nil)
+
+var _ CostSetter = (*Ristretto)(nil)
+
+var _ TTLGetter = (*Ristretto)(nil)
+
 type Ristretto struct {
 	client *ristretto.Cache[string, []byte]
+
+	// 条目的成本函数，限制条目数时最小成本不为 0
+	costs costFunc
 }
 
-func NewRistretto(maxMemory int) (*Ristretto, error) {
+// NewRistretto 创建 Ristretto 内存适配器，maxMemory 为字节容量，可以通过 WithMaxEntries 同时限制条目数
+func NewRistretto(maxMemory int, opts ...MemoryOption) (*Ristretto, error) {
 	if maxMemory <= 0 {
 		return nil, fmt.Errorf("ristretto create: invalid maxMemory: %d", maxMemory)
 	}
+	cfg, minCost, err := newMemoryOptions("ristretto", maxMemory, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err = cfg.rejectShardedOptions("ristretto"); err != nil {
+		return nil, err
+	}
 
 	// If you need to customize the Config, please use NewRistrettoWithClient instead.
 	config := &ristretto.Config[string, []byte]{
@@ -24,15 +42,41 @@ func NewRistretto(maxMemory int) (*Ristretto, error) {
 		MaxCost:     int64(maxMemory),
 		BufferItems: 64,
 	}
+	if cfg.maxEntries > 0 {
+		// 官方建议计数器数量为最大条目数的 10 倍
+		config.NumCounters = int64(cfg.maxEntries) * 10
+	}
 
 	cache, err := ristretto.NewCache[string, []byte](config)
 	if err != nil {
 		return nil, fmt.Errorf("ristretto create: maxMemory %d: %w", maxMemory, err)
 	}
 
-	return &Ristretto{
-		client: cache,
-	}, nil
+	r := &Ristretto{client: cache}
+	r.costs.minCost = minCost
+	return r, nil
+}
+
+// NewRistrettoWithEntries 创建按条目数限制容量的 Ristretto 内存适配器，每个条目的成本固定为 1，与值的大小无关，
+// 适合值大小均匀且较小的场景；SetCostFunc 设置的成本函数不生效
+func NewRistrettoWithEntries(maxEntries int) (*Ristretto, error) {
+	if maxEntries <= 0 {
+		return nil, fmt.Errorf("ristretto create: invalid maxEntries: %d", maxEntries)
+	}
+
+	cache, err := ristretto.NewCache[string, []byte](&ristretto.Config[string, []byte]{
+		// 官方建议计数器数量为最大条目数的 10 倍
+		NumCounters: int64(maxEntries) * 10,
+		MaxCost:     int64(maxEntries),
+		BufferItems: 64,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ristretto create: maxEntries %d: %w", maxEntries, err)
+	}
+
+	r := &Ristretto{client: cache}
+	r.costs.perEntry = true
+	return r, nil
 }
 
 func NewRistrettoWithClient(client *ristretto.Cache[string, []byte]) *Ristretto {
@@ -43,7 +87,7 @@ func NewRistrettoWithClient(client *ristretto.Cache[string, []byte]) *Ristretto
 
 func (r *Ristretto) Set(key string, value []byte, expire time.Duration) int32 {
 	var count int32
-	cost := int64(len(key) + len(value))
+	cost := r.costs.cost(key, value)
 
 	ok := r.client.SetWithTTL(key, value, cost, expire)
 	if ok {
@@ -56,7 +100,7 @@ func (r *Ristretto) Set(key string, value []byte, expire time.Duration) int32 {
 func (r *Ristretto) MSet(values map[string][]byte, expire time.Duration) int32 {
 	var count int32
 	for key, value := range values {
-		cost := int64(len(key) + len(value))
+		cost := r.costs.cost(key, value)
 		ok := r.client.SetWithTTL(key, value, cost, expire)
 		if ok {
 			count++
@@ -75,6 +119,22 @@ func (r *Ristretto) Get(key string) ([]byte, bool) {
 	return value, true
 }
 
+// GetWithTTL 返回值和剩余过期时间
+func (r *Ristretto) GetWithTTL(key string) ([]byte, time.Duration, bool) {
+	value, found := r.client.Get(key)
+	if !found {
+		return nil, 0, false
+	}
+	ttl, found := r.client.GetTTL(key)
+	if !found {
+		return nil, 0, false
+	}
+	if ttl == 0 {
+		ttl = -1
+	}
+	return value, ttl, true
+}
+
 func (r *Ristretto) MGet(keys []string) map[string][]byte {
 	ret := make(map[string][]byte)
 
@@ -89,3 +149,22 @@ func (r *Ristretto) MGet(keys []string) map[string][]byte {
 func (r *Ristretto) Delete(key string) {
 	r.client.Del(key)
 }
+
+// SetCostFunc 设置条目的成本函数
+func (r *Ristretto) SetCostFunc(fn func(key string, value []byte) int64) {
+	r.costs.set(fn)
+}
+
+// MaxEntrySize 返回单个条目的大小上限，Ristretto 拒绝超过总容量的条目；按条目数限制容量时不限制大小
+func (r *Ristretto) MaxEntrySize() int {
+	if r.costs.perEntry {
+		return math.MaxInt
+	}
+	return int(r.client.MaxCost())
+}
+
+// Close 停止后台协程并清空缓存
+func (r *Ristretto) Close() error {
+	r.client.Close()
+	return nil
+}
@@ -3,6 +3,7 @@ package storage
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"testing"
 	"time"
 )
@@ -551,3 +552,75 @@ func containsRistretto(slice []string, item string) bool {
 	}
 	return false
 }
+
+func TestRistretto_MaxEntrySize(t *testing.T) {
+	rs := setupRistretto(t, 1000)
+
+	if got := rs.MaxEntrySize(); got != 1000 {
+		t.Errorf("expected max entry size 1000, got %d", got)
+	}
+}
+
+func TestRistretto_MaxEntries(t *testing.T) {
+	if _, err := NewRistretto(1000, WithMaxEntries(-1)); err == nil {
+		t.Error("maxEntries 为负数时应该返回错误")
+	}
+
+	r, err := NewRistretto(1<<20, WithMaxEntries(100))
+	if err != nil {
+		t.Fatalf("NewRistretto() error = %v", err)
+	}
+	for i := 0; i < 1000; i++ {
+		r.Set(fmt.Sprintf("key-%d", i), []byte("v"), time.Hour)
+	}
+
+	var count int
+	for i := 0; i < 1000; i++ {
+		if _, ok := r.Get(fmt.Sprintf("key-%d", i)); ok {
+			count++
+		}
+	}
+	if count > 100 {
+		t.Errorf("条目数 %d 超过上限 100", count)
+	}
+}
+
+func TestRistretto_GetWithTTL(t *testing.T) {
+	testGetWithTTL(t, setupRistretto(t, 10000), true)
+}
+
+func TestNewRistrettoWithEntries(t *testing.T) {
+	if _, err := NewRistrettoWithEntries(0); err == nil {
+		t.Error("maxEntries 为 0 时应该返回错误")
+	}
+
+	r, err := NewRistrettoWithEntries(100)
+	if err != nil {
+		t.Fatalf("NewRistrettoWithEntries() error = %v", err)
+	}
+	t.Cleanup(func() { _ = r.Close() })
+
+	// 条目的成本与值的大小无关，大于条目数的值也能写入
+	r.Set("large", make([]byte, 1<<10), time.Hour)
+	r.client.Wait()
+	if _, ok := r.Get("large"); !ok {
+		t.Error("按条目数限制容量时不应拒绝较大的值")
+	}
+	for i := 0; i < 1000; i++ {
+		r.Set(fmt.Sprintf("key-%d", i), []byte("v"), time.Hour)
+	}
+	r.client.Wait()
+
+	var count int
+	for i := 0; i < 1000; i++ {
+		if _, ok := r.Get(fmt.Sprintf("key-%d", i)); ok {
+			count++
+		}
+	}
+	if count > 100 {
+		t.Errorf("条目数 %d 超过上限 100", count)
+	}
+	if got := r.MaxEntrySize(); got != math.MaxInt {
+		t.Errorf("MaxEntrySize() = %d, want math.MaxInt", got)
+	}
+}
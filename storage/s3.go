@@ -0,0 +1,442 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/biu7/layered-cache/errors"
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	_ Remote       = (*S3)(nil)
+	_ MultiDeleter = (*S3)(nil)
+	_ Expirer      = (*S3)(nil)
+)
+
+const (
+	// s3ExpireAtMeta 记录过期时间（Unix 毫秒）的对象元数据
+	s3ExpireAtMeta = "layered-cache-expire-at"
+
+	// s3ExpireTag 供存储桶生命周期规则使用的对象标签，值为过期前的天数（向上取整）
+	s3ExpireTag = "layered-cache-expire-days"
+
+	// s3MinPartSize S3 分片上传除最后一片外每片的最小字节数
+	s3MinPartSize = 5 << 20
+
+	// s3MaxDeleteObjects DeleteObjects 单次最多删除的对象数
+	s3MaxDeleteObjects = 1000
+)
+
+// S3API S3 适配器使用的客户端方法，*s3.Client 实现了该接口，也可以替换为兼容 S3 API 的其他实现
+type S3API interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error)
+	CopyObject(ctx context.Context, params *s3.CopyObjectInput, optFns ...func(*s3.Options)) (*s3.CopyObjectOutput, error)
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+	DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error)
+
+	CreateMultipartUpload(ctx context.Context, params *s3.CreateMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error)
+	UploadPart(ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.Options)) (*s3.UploadPartOutput, error)
+	CompleteMultipartUpload(ctx context.Context, params *s3.CompleteMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error)
+	AbortMultipartUpload(ctx context.Context, params *s3.AbortMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error)
+}
+
+// S3 基于对象存储的 Remote 适配器，适合访问频率低、体积大的值，热点数据由内存缓存保留副本
+// 过期时间记录在对象元数据中，读取时跳过已过期的对象；对象同时带有 layered-cache-expire-days 标签，
+// 可以按标签配置存储桶生命周期规则删除过期对象，适配器本身不会主动清理
+type S3 struct {
+	client S3API
+	bucket string
+
+	// keyPrefix 对象键前缀
+	keyPrefix string
+
+	// partSize 超过该大小的值使用分片上传，每片 partSize 字节
+	partSize int
+
+	// concurrency MGet、MSet 同时进行的请求数
+	concurrency int
+}
+
+// S3Option S3 适配器的可选配置
+type S3Option interface {
+	applyS3(*S3)
+}
+
+// s3KeyPrefixOption 设置对象键前缀
+type s3KeyPrefixOption struct {
+	prefix string
+}
+
+func (o s3KeyPrefixOption) applyS3(s *S3) {
+	s.keyPrefix = o.prefix
+}
+
+// WithS3KeyPrefix 设置对象键前缀，多个缓存共用一个存储桶时用于隔离，例如 "cache/"
+func WithS3KeyPrefix(prefix string) S3Option {
+	return s3KeyPrefixOption{prefix: prefix}
+}
+
+// s3PartSizeOption 设置分片大小
+type s3PartSizeOption struct {
+	size int
+}
+
+func (o s3PartSizeOption) applyS3(s *S3) {
+	s.partSize = o.size
+}
+
+// WithS3PartSize 设置分片上传的分片大小，值超过 size 时使用分片上传，最小 5MB，默认 16MB
+func WithS3PartSize(size int) S3Option {
+	return s3PartSizeOption{size: size}
+}
+
+// s3ConcurrencyOption 设置并发请求数
+type s3ConcurrencyOption struct {
+	n int
+}
+
+func (o s3ConcurrencyOption) applyS3(s *S3) {
+	s.concurrency = o.n
+}
+
+// WithS3Concurrency 设置 MGet、MSet 同时进行的请求数，默认 8
+func WithS3Concurrency(n int) S3Option {
+	return s3ConcurrencyOption{n: n}
+}
+
+// NewS3 使用已配置好的客户端创建适配器，对象保存在 bucket 中
+func NewS3(client S3API, bucket string, opts ...S3Option) (*S3, error) {
+	s := &S3{client: client, bucket: bucket, partSize: 16 << 20, concurrency: 8}
+	for _, opt := range opts {
+		opt.applyS3(s)
+	}
+	if client == nil || bucket == "" {
+		return nil, fmt.Errorf("s3 create: client and bucket are required")
+	}
+	if s.partSize < s3MinPartSize {
+		return nil, fmt.Errorf("s3 create: part size %d is smaller than %d", s.partSize, s3MinPartSize)
+	}
+	if s.concurrency <= 0 {
+		return nil, fmt.Errorf("s3 create: concurrency %d must be positive", s.concurrency)
+	}
+	return s, nil
+}
+
+// Set 写入 key，不足一片的值直接上传，不额外分配分片缓冲区
+func (s *S3) Set(ctx context.Context, key string, value []byte, expire time.Duration) error {
+	if len(value) < s.partSize {
+		return s.putObject(ctx, key, value, expire)
+	}
+	return s.SetStream(ctx, key, bytes.NewReader(value), expire)
+}
+
+// SetStream 从 r 读取值写入 key，不需要一次性读入内存；超过分片大小时使用分片上传，失败时取消已上传的分片
+func (s *S3) SetStream(ctx context.Context, key string, r io.Reader, expire time.Duration) error {
+	// 先读取一片，不足一片时直接上传；按实际读取的长度分配，较小的值不会占用整片缓冲区
+	first, err := io.ReadAll(io.LimitReader(r, int64(s.partSize)))
+	if err != nil {
+		return fmt.Errorf("s3 set %s: read: %w", key, err)
+	}
+	if len(first) < s.partSize {
+		return s.putObject(ctx, key, first, expire)
+	}
+
+	meta, tagging := s3ExpireHeaders(expire)
+	if err = s.multipartUpload(ctx, key, first, r, meta, tagging); err != nil {
+		return fmt.Errorf("s3 set %s: %w", key, err)
+	}
+	return nil
+}
+
+// putObject 一次上传整个值
+func (s *S3) putObject(ctx context.Context, key string, value []byte, expire time.Duration) error {
+	meta, tagging := s3ExpireHeaders(expire)
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(s.objectKey(key)),
+		Body:     bytes.NewReader(value),
+		Metadata: meta,
+		Tagging:  tagging,
+	})
+	if err != nil {
+		return fmt.Errorf("s3 set %s: %w", key, err)
+	}
+	return nil
+}
+
+// multipartUpload 分片上传，first 为已读取的第一片
+func (s *S3) multipartUpload(ctx context.Context, key string, first []byte, r io.Reader, meta map[string]string, tagging *string) error {
+	created, err := s.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(s.objectKey(key)),
+		Metadata: meta,
+		Tagging:  tagging,
+	})
+	if err != nil {
+		return err
+	}
+
+	parts, err := s.uploadParts(ctx, key, created.UploadId, first, r)
+	if err == nil {
+		_, err = s.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+			Bucket:          aws.String(s.bucket),
+			Key:             aws.String(s.objectKey(key)),
+			UploadId:        created.UploadId,
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+		})
+	}
+	if err != nil {
+		// 使用独立的 ctx 取消上传，避免 ctx 已取消时遗留分片持续计费
+		_, _ = s.client.AbortMultipartUpload(context.WithoutCancel(ctx), &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(s.bucket),
+			Key:      aws.String(s.objectKey(key)),
+			UploadId: created.UploadId,
+		})
+		return err
+	}
+	return nil
+}
+
+// uploadParts 依次上传各分片，每次只在内存中保留一片
+func (s *S3) uploadParts(ctx context.Context, key string, uploadID *string, first []byte, r io.Reader) ([]types.CompletedPart, error) {
+	var parts []types.CompletedPart
+	buf := first
+	for number := int32(1); ; number++ {
+		uploaded, err := s.client.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:     aws.String(s.bucket),
+			Key:        aws.String(s.objectKey(key)),
+			UploadId:   uploadID,
+			PartNumber: aws.Int32(number),
+			Body:       bytes.NewReader(buf),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("upload part %d: %w", number, err)
+		}
+		parts = append(parts, types.CompletedPart{ETag: uploaded.ETag, PartNumber: aws.Int32(number)})
+
+		if len(buf) < s.partSize {
+			return parts, nil
+		}
+		n, err := io.ReadFull(r, buf[:s.partSize])
+		if err == io.EOF {
+			return parts, nil
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return nil, fmt.Errorf("read part %d: %w", number+1, err)
+		}
+		buf = buf[:n]
+	}
+}
+
+func (s *S3) MSet(ctx context.Context, values map[string][]byte, expire time.Duration) error {
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(s.concurrency)
+	for key, value := range values {
+		g.Go(func() error {
+			return s.Set(ctx, key, value, expire)
+		})
+	}
+	return g.Wait()
+}
+
+func (s *S3) Get(ctx context.Context, key string) ([]byte, error) {
+	body, err := s.GetStream(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	value, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("s3 get %s: read: %w", key, err)
+	}
+	return value, nil
+}
+
+// GetStream 返回 key 的值的读取流，调用方需要关闭；不存在或已过期时返回 ErrNotFound
+func (s *S3) GetStream(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.objectKey(key)),
+	})
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, errors.ErrNotFound
+		}
+		return nil, fmt.Errorf("s3 get %s: %w", key, err)
+	}
+	if s3Expired(out.Metadata, time.Now()) {
+		_ = out.Body.Close()
+		return nil, errors.ErrNotFound
+	}
+	return out.Body, nil
+}
+
+func (s *S3) MGet(ctx context.Context, keys []string) (map[string][]byte, error) {
+	values := make([][]byte, len(keys))
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(s.concurrency)
+	for i, key := range keys {
+		g.Go(func() error {
+			value, err := s.Get(ctx, key)
+			if errors.Is(err, errors.ErrNotFound) {
+				return nil
+			}
+			values[i] = value
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string][]byte, len(keys))
+	for i, key := range keys {
+		if values[i] != nil {
+			ret[key] = values[i]
+		}
+	}
+	return ret, nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.objectKey(key)),
+	})
+	if err != nil && !isS3NotFound(err) {
+		return fmt.Errorf("s3 delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// MDelete 每 1000 个键调用一次 DeleteObjects
+func (s *S3) MDelete(ctx context.Context, keys []string) error {
+	for start := 0; start < len(keys); start += s3MaxDeleteObjects {
+		batch := keys[start:min(start+s3MaxDeleteObjects, len(keys))]
+		objects := make([]types.ObjectIdentifier, len(batch))
+		for i, key := range batch {
+			objects[i] = types.ObjectIdentifier{Key: aws.String(s.objectKey(key))}
+		}
+		out, err := s.client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(s.bucket),
+			Delete: &types.Delete{Objects: objects, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			return fmt.Errorf("s3 mdelete: %w", err)
+		}
+		if len(out.Errors) > 0 {
+			e := out.Errors[0]
+			return fmt.Errorf("s3 mdelete %s: %s", aws.ToString(e.Key), aws.ToString(e.Message))
+		}
+	}
+	return nil
+}
+
+// TTL 返回对象的剩余过期时间，与 Redis 一致：不存在返回 -2，没有过期时间返回 -1
+func (s *S3) TTL(ctx context.Context, key string) (time.Duration, error) {
+	out, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.objectKey(key)),
+	})
+	if err != nil {
+		if isS3NotFound(err) {
+			return -2, nil
+		}
+		return 0, fmt.Errorf("s3 ttl %s: %w", key, err)
+	}
+
+	now := time.Now()
+	if s3Expired(out.Metadata, now) {
+		return -2, nil
+	}
+	expireAt, ok := s3ExpireAtOf(out.Metadata)
+	if !ok {
+		return -1, nil
+	}
+	return expireAt.Sub(now), nil
+}
+
+// MExpire 通过将对象复制到自身来替换过期时间元数据和标签，不存在或已过期的对象忽略
+// S3 单次复制的对象上限为 5GB，更大的对象需要重新写入
+func (s *S3) MExpire(ctx context.Context, keys []string, expire time.Duration) error {
+	meta, tagging := s3ExpireHeaders(expire)
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(s.concurrency)
+	for _, key := range keys {
+		g.Go(func() error {
+			ttl, err := s.TTL(ctx, key)
+			if err != nil || ttl == -2 {
+				return err
+			}
+			_, err = s.client.CopyObject(ctx, &s3.CopyObjectInput{
+				Bucket:            aws.String(s.bucket),
+				Key:               aws.String(s.objectKey(key)),
+				CopySource:        aws.String(url.PathEscape(s.bucket) + "/" + url.PathEscape(s.objectKey(key))),
+				Metadata:          meta,
+				MetadataDirective: types.MetadataDirectiveReplace,
+				Tagging:           tagging,
+				TaggingDirective:  types.TaggingDirectiveReplace,
+			})
+			if err != nil && !isS3NotFound(err) {
+				return fmt.Errorf("s3 mexpire %s: %w", key, err)
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+// objectKey 返回 key 对应的对象键
+func (s *S3) objectKey(key string) string {
+	return s.keyPrefix + key
+}
+
+// s3ExpireHeaders 返回记录过期时间的元数据和生命周期标签，expire 小于等于 0 表示不过期
+func s3ExpireHeaders(expire time.Duration) (map[string]string, *string) {
+	if expire <= 0 {
+		return nil, nil
+	}
+	expireAt := time.Now().Add(expire)
+	days := int(math.Ceil(expire.Hours() / 24))
+	meta := map[string]string{s3ExpireAtMeta: strconv.FormatInt(expireAt.UnixMilli(), 10)}
+	return meta, aws.String(s3ExpireTag + "=" + strconv.Itoa(days))
+}
+
+// s3ExpireAtOf 返回元数据中记录的过期时间
+func s3ExpireAtOf(meta map[string]string) (time.Time, bool) {
+	value, ok := meta[s3ExpireAtMeta]
+	if !ok {
+		return time.Time{}, false
+	}
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(ms), true
+}
+
+// s3Expired 判断对象是否已过期
+func s3Expired(meta map[string]string, now time.Time) bool {
+	expireAt, ok := s3ExpireAtOf(meta)
+	return ok && !now.Before(expireAt)
+}
+
+// isS3NotFound 判断 err 是否表示对象不存在，GetObject 返回 NoSuchKey，HeadObject 返回 NotFound
+func isS3NotFound(err error) bool {
+	var noSuchKey *types.NoSuchKey
+	var notFound *types.NotFound
+	return errors.As(err, &noSuchKey) || errors.As(err, &notFound)
+}
@@ -0,0 +1,315 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/biu7/layered-cache/errors"
+)
+
+// fakeS3 内存中的 S3 实现，只支持适配器用到的方法
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]fakeObject
+	uploads map[string]map[int32][]byte
+	aborted int
+}
+
+type fakeObject struct {
+	data    []byte
+	meta    map[string]string
+	tagging string
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{objects: make(map[string]fakeObject), uploads: make(map[string]map[int32][]byte)}
+}
+
+func (f *fakeS3) PutObject(ctx context.Context, in *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	data, err := io.ReadAll(in.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.objects[aws.ToString(in.Key)] = fakeObject{data: data, meta: in.Metadata, tagging: aws.ToString(in.Tagging)}
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (f *fakeS3) GetObject(ctx context.Context, in *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	obj, ok := f.objects[aws.ToString(in.Key)]
+	if !ok {
+		return nil, &types.NoSuchKey{}
+	}
+	return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(obj.data)), Metadata: obj.meta}, nil
+}
+
+func (f *fakeS3) HeadObject(ctx context.Context, in *s3.HeadObjectInput, _ ...func(*s3.Options)) (*s3.HeadObjectOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	obj, ok := f.objects[aws.ToString(in.Key)]
+	if !ok {
+		return nil, &types.NotFound{}
+	}
+	return &s3.HeadObjectOutput{Metadata: obj.meta, ContentLength: aws.Int64(int64(len(obj.data)))}, nil
+}
+
+func (f *fakeS3) CopyObject(ctx context.Context, in *s3.CopyObjectInput, _ ...func(*s3.Options)) (*s3.CopyObjectOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	source, _ := url.PathUnescape(aws.ToString(in.CopySource))
+	_, key, _ := strings.Cut(source, "/")
+	obj, ok := f.objects[key]
+	if !ok {
+		return nil, &types.NoSuchKey{}
+	}
+	f.objects[aws.ToString(in.Key)] = fakeObject{data: obj.data, meta: in.Metadata, tagging: aws.ToString(in.Tagging)}
+	return &s3.CopyObjectOutput{}, nil
+}
+
+func (f *fakeS3) DeleteObject(ctx context.Context, in *s3.DeleteObjectInput, _ ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.objects, aws.ToString(in.Key))
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func (f *fakeS3) DeleteObjects(ctx context.Context, in *s3.DeleteObjectsInput, _ ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, obj := range in.Delete.Objects {
+		delete(f.objects, aws.ToString(obj.Key))
+	}
+	return &s3.DeleteObjectsOutput{}, nil
+}
+
+func (f *fakeS3) CreateMultipartUpload(ctx context.Context, in *s3.CreateMultipartUploadInput, _ ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := fmt.Sprintf("upload-%d", len(f.uploads))
+	f.uploads[id] = make(map[int32][]byte)
+	f.objects[id] = fakeObject{meta: in.Metadata, tagging: aws.ToString(in.Tagging)}
+	return &s3.CreateMultipartUploadOutput{UploadId: aws.String(id)}, nil
+}
+
+func (f *fakeS3) UploadPart(ctx context.Context, in *s3.UploadPartInput, _ ...func(*s3.Options)) (*s3.UploadPartOutput, error) {
+	data, err := io.ReadAll(in.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.uploads[aws.ToString(in.UploadId)][aws.ToInt32(in.PartNumber)] = data
+	return &s3.UploadPartOutput{ETag: aws.String(fmt.Sprintf("etag-%d", aws.ToInt32(in.PartNumber)))}, nil
+}
+
+func (f *fakeS3) CompleteMultipartUpload(ctx context.Context, in *s3.CompleteMultipartUploadInput, _ ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := aws.ToString(in.UploadId)
+	var data []byte
+	for _, part := range in.MultipartUpload.Parts {
+		data = append(data, f.uploads[id][aws.ToInt32(part.PartNumber)]...)
+	}
+	obj := f.objects[id]
+	delete(f.objects, id)
+	delete(f.uploads, id)
+	f.objects[aws.ToString(in.Key)] = fakeObject{data: data, meta: obj.meta, tagging: obj.tagging}
+	return &s3.CompleteMultipartUploadOutput{}, nil
+}
+
+func (f *fakeS3) AbortMultipartUpload(ctx context.Context, in *s3.AbortMultipartUploadInput, _ ...func(*s3.Options)) (*s3.AbortMultipartUploadOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.uploads, aws.ToString(in.UploadId))
+	delete(f.objects, aws.ToString(in.UploadId))
+	f.aborted++
+	return &s3.AbortMultipartUploadOutput{}, nil
+}
+
+func setupS3(t *testing.T, opts ...S3Option) (*S3, *fakeS3) {
+	t.Helper()
+
+	client := newFakeS3()
+	s, err := NewS3(client, "bucket", opts...)
+	if err != nil {
+		t.Fatalf("创建 S3 失败: %v", err)
+	}
+	return s, client
+}
+
+func TestNewS3(t *testing.T) {
+	if _, err := NewS3(nil, "bucket"); err == nil {
+		t.Error("client 为 nil 时应该返回错误")
+	}
+	if _, err := NewS3(newFakeS3(), "bucket", WithS3PartSize(1024)); err == nil {
+		t.Error("分片小于 5MB 时应该返回错误")
+	}
+	if _, err := NewS3(newFakeS3(), "bucket", WithS3Concurrency(0)); err == nil {
+		t.Error("并发数为 0 时应该返回错误")
+	}
+}
+
+func TestS3_SetGetDelete(t *testing.T) {
+	ctx := context.Background()
+	s, client := setupS3(t, WithS3KeyPrefix("cache/"))
+
+	if err := s.MSet(ctx, map[string][]byte{"a": []byte("1"), "b": []byte("2")}, time.Hour); err != nil {
+		t.Fatalf("MSet() error = %v", err)
+	}
+	if _, ok := client.objects["cache/a"]; !ok {
+		t.Error("对象键应该带有前缀")
+	}
+	if tagging := client.objects["cache/a"].tagging; tagging != s3ExpireTag+"=1" {
+		t.Errorf("tagging = %q, want %s=1", tagging, s3ExpireTag)
+	}
+
+	if value, err := s.Get(ctx, "a"); err != nil || string(value) != "1" {
+		t.Errorf("Get(a) = %q, %v, want 1", value, err)
+	}
+	values, err := s.MGet(ctx, []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("MGet() error = %v", err)
+	}
+	if len(values) != 2 || string(values["b"]) != "2" {
+		t.Errorf("MGet() = %v, want a 和 b", values)
+	}
+
+	if err := s.Delete(ctx, "a"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := s.MDelete(ctx, []string{"b", "c"}); err != nil {
+		t.Fatalf("MDelete() error = %v", err)
+	}
+	if _, err := s.Get(ctx, "b"); !errors.Is(err, errors.ErrNotFound) {
+		t.Errorf("删除后 Get(b) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestS3_TTL(t *testing.T) {
+	ctx := context.Background()
+	s, client := setupS3(t)
+
+	_ = s.Set(ctx, "long", []byte("1"), 48*time.Hour)
+	_ = s.Set(ctx, "forever", []byte("1"), 0)
+	client.objects["expired"] = fakeObject{
+		data: []byte("1"),
+		meta: map[string]string{s3ExpireAtMeta: fmt.Sprint(time.Now().Add(-time.Second).UnixMilli())},
+	}
+
+	if ttl, _ := s.TTL(ctx, "long"); ttl <= 47*time.Hour || ttl > 48*time.Hour {
+		t.Errorf("TTL(long) = %v, want 约 48h", ttl)
+	}
+	if ttl, _ := s.TTL(ctx, "forever"); ttl != -1 {
+		t.Errorf("TTL(forever) = %v, want -1", ttl)
+	}
+	if ttl, _ := s.TTL(ctx, "expired"); ttl != -2 {
+		t.Errorf("TTL(expired) = %v, want -2", ttl)
+	}
+	if _, err := s.Get(ctx, "expired"); !errors.Is(err, errors.ErrNotFound) {
+		t.Errorf("Get(expired) error = %v, want ErrNotFound", err)
+	}
+
+	if err := s.MExpire(ctx, []string{"forever", "expired", "missing"}, time.Minute); err != nil {
+		t.Fatalf("MExpire() error = %v", err)
+	}
+	if ttl, _ := s.TTL(ctx, "forever"); ttl <= 0 || ttl > time.Minute {
+		t.Errorf("MExpire 后 TTL(forever) = %v, want 约 1m", ttl)
+	}
+	if value, err := s.Get(ctx, "forever"); err != nil || string(value) != "1" {
+		t.Errorf("MExpire 后 Get(forever) = %q, %v, 值应该保持不变", value, err)
+	}
+	if _, ok := client.objects["missing"]; ok {
+		t.Error("MExpire 不应创建不存在的对象")
+	}
+	if ttl, _ := s.TTL(ctx, "expired"); ttl != -2 {
+		t.Errorf("MExpire 不应恢复已过期的对象, TTL = %v", ttl)
+	}
+}
+
+func TestS3_Multipart(t *testing.T) {
+	ctx := context.Background()
+	s, client := setupS3(t, WithS3PartSize(s3MinPartSize))
+
+	value := bytes.Repeat([]byte("0123456789"), s3MinPartSize*25/100)
+	if err := s.SetStream(ctx, "big", bytes.NewReader(value), time.Hour); err != nil {
+		t.Fatalf("SetStream() error = %v", err)
+	}
+	if len(client.uploads) != 0 {
+		t.Errorf("分片上传未完成: %d", len(client.uploads))
+	}
+
+	body, err := s.GetStream(ctx, "big")
+	if err != nil {
+		t.Fatalf("GetStream() error = %v", err)
+	}
+	defer body.Close()
+	got, _ := io.ReadAll(body)
+	if !bytes.Equal(got, value) {
+		t.Errorf("读取的值长度 %d, want %d", len(got), len(value))
+	}
+	if ttl, _ := s.TTL(ctx, "big"); ttl <= 0 {
+		t.Errorf("分片上传的对象应该带有过期时间, TTL = %v", ttl)
+	}
+
+	// 恰好是分片大小整数倍的值
+	exact := make([]byte, 2*s3MinPartSize)
+	if err := s.Set(ctx, "exact", exact, 0); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if got, _ := s.Get(ctx, "exact"); len(got) != len(exact) {
+		t.Errorf("Get(exact) 长度 %d, want %d", len(got), len(exact))
+	}
+}
+
+func TestS3_SmallValueAlloc(t *testing.T) {
+	ctx := context.Background()
+	s, _ := setupS3(t)
+
+	// 较小的值不应该按默认分片大小分配缓冲区
+	value := bytes.Repeat([]byte("v"), 1024)
+	for name, set := range map[string]func() error{
+		"Set":       func() error { return s.Set(ctx, "small", value, time.Hour) },
+		"SetStream": func() error { return s.SetStream(ctx, "small", bytes.NewReader(value), time.Hour) },
+	} {
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		if err := set(); err != nil {
+			t.Fatalf("%s() error = %v", name, err)
+		}
+		runtime.ReadMemStats(&after)
+		if alloc := after.TotalAlloc - before.TotalAlloc; alloc >= uint64(s.partSize)/4 {
+			t.Errorf("%s() 分配了 %d 字节, 分片大小 %d", name, alloc, s.partSize)
+		}
+	}
+}
+
+func TestS3_MultipartAbort(t *testing.T) {
+	ctx := context.Background()
+	s, client := setupS3(t, WithS3PartSize(s3MinPartSize))
+
+	r := io.MultiReader(bytes.NewReader(make([]byte, s3MinPartSize+1)), iotest.ErrReader(fmt.Errorf("connection reset")))
+	if err := s.SetStream(ctx, "big", r, time.Hour); err == nil {
+		t.Fatal("读取失败时 SetStream 应该返回错误")
+	}
+	if client.aborted != 1 || len(client.uploads) != 0 {
+		t.Errorf("失败后应该取消分片上传, aborted = %d, uploads = %d", client.aborted, len(client.uploads))
+	}
+	if _, err := s.Get(ctx, "big"); !errors.Is(err, errors.ErrNotFound) {
+		t.Errorf("上传失败后不应存在对象, error = %v", err)
+	}
+}
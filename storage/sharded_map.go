@@ -0,0 +1,484 @@
+package storage
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"hash/maphash"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var _ Memory = (*ShardedMap)(nil)
+
+var _ EvictionNotifier = (*ShardedMap)(nil)
+
+var _ EntrySizeLimiter = (*ShardedMap)(nil)
+
+var _ PrefixDeleter = (*ShardedMap)(nil)
+
+var _ Sweeper = (*ShardedMap)(nil)
+
+var _ CostSetter = (*ShardedMap)(nil)
+
+var _ TTLGetter = (*ShardedMap)(nil)
+
+var _ Pinner = (*ShardedMap)(nil)
+
+var _ Sizer = (*ShardedMap)(nil)
+
+// defaultShards ShardedMap 默认的分片数
+const defaultShards = 16
+
+// EvictionPolicy ShardedMap 容量不足时的淘汰策略
+type EvictionPolicy int
+
+const (
+	// EvictLRU 淘汰最久未访问的条目
+	EvictLRU EvictionPolicy = iota
+
+	// EvictLFU 淘汰访问次数最少的条目，次数相同时淘汰最久未访问的
+	EvictLFU
+
+	// EvictFIFO 淘汰最早写入的条目，访问和覆盖写入不改变顺序
+	EvictFIFO
+)
+
+// String 返回策略名称
+func (p EvictionPolicy) String() string {
+	switch p {
+	case EvictLRU:
+		return "lru"
+	case EvictLFU:
+		return "lfu"
+	case EvictFIFO:
+		return "fifo"
+	default:
+		return "unknown"
+	}
+}
+
+// ShardedMapStats ShardedMap 的运行计数
+type ShardedMapStats struct {
+	// Entries 当前缓存的条目数
+	Entries int
+	// Bytes 当前缓存条目的总字节数（键 + 值）
+	Bytes int64
+	// Evictions 因容量不足被淘汰的条目数
+	Evictions int64
+	// Expirations 因过期被删除的条目数
+	Expirations int64
+}
+
+// ShardedMap 不依赖第三方库的分片 map 内存适配器，按 EvictionPolicy 淘汰条目
+// 每个分片独立加锁，容量和条目数上限平均分配到各分片，适合不希望引入 Otter/Ristretto 的小型服务
+type ShardedMap struct {
+	shards []*mapShard
+	seed   maphash.Seed
+
+	onEvict atomic.Pointer[func(key string, value []byte)]
+	costs   costFunc
+
+	evictions   atomic.Int64
+	expirations atomic.Int64
+}
+
+// NewShardedMap 创建分片 map 内存适配器，maxMemory 为字节容量，
+// 可以通过 WithEvictionPolicy 选择淘汰策略（默认 LRU）、WithShards 设置分片数、WithMaxEntries 限制条目数
+func NewShardedMap(maxMemory int, opts ...MemoryOption) (*ShardedMap, error) {
+	if maxMemory <= 0 {
+		return nil, fmt.Errorf("sharded map create: invalid maxMemory: %d", maxMemory)
+	}
+	cfg, _, err := newMemoryOptions("sharded map", maxMemory, opts)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.policy < EvictLRU || cfg.policy > EvictFIFO {
+		return nil, fmt.Errorf("sharded map create: unknown eviction policy: %d", cfg.policy)
+	}
+
+	shards := cfg.shards
+	if shards == 0 {
+		shards = defaultShards
+	}
+	if shards < 0 || shards > maxMemory {
+		return nil, fmt.Errorf("sharded map create: invalid shards: %d", shards)
+	}
+
+	m := &ShardedMap{shards: make([]*mapShard, shards), seed: maphash.MakeSeed()}
+	for i := range m.shards {
+		m.shards[i] = &mapShard{
+			m:          m,
+			policy:     cfg.policy,
+			capacity:   int64(maxMemory / shards),
+			maxEntries: (cfg.maxEntries + shards - 1) / shards,
+			items:      make(map[string]*mapEntry),
+		}
+	}
+	return m, nil
+}
+
+func (m *ShardedMap) Set(key string, value []byte, expire time.Duration) int32 {
+	if m.shard(key).set(key, value, expire) {
+		return 1
+	}
+	return 0
+}
+
+func (m *ShardedMap) MSet(values map[string][]byte, expire time.Duration) int32 {
+	var count int32
+	for key, value := range values {
+		if m.shard(key).set(key, value, expire) {
+			count++
+		}
+	}
+	return count
+}
+
+func (m *ShardedMap) Get(key string) ([]byte, bool) {
+	return m.shard(key).get(key)
+}
+
+// GetWithTTL 返回值和剩余过期时间
+func (m *ShardedMap) GetWithTTL(key string) ([]byte, time.Duration, bool) {
+	return m.shard(key).getWithTTL(key)
+}
+
+func (m *ShardedMap) MGet(keys []string) map[string][]byte {
+	ret := make(map[string][]byte)
+	for _, key := range keys {
+		if value, ok := m.shard(key).get(key); ok {
+			ret[key] = value
+		}
+	}
+	return ret
+}
+
+func (m *ShardedMap) Delete(key string) {
+	m.shard(key).delete(key)
+}
+
+func (m *ShardedMap) DeletePrefix(prefix string) int {
+	var count int
+	for _, s := range m.shards {
+		count += s.deleteFunc(func(e *mapEntry) bool {
+			return strings.HasPrefix(e.key, prefix)
+		})
+	}
+	return count
+}
+
+// Sweep 删除已过期的条目，逐个分片清理，ctx 结束时停止
+func (m *ShardedMap) Sweep(ctx context.Context) (int, error) {
+	var count int
+	for _, s := range m.shards {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+		now := time.Now().UnixNano()
+		n := s.deleteFunc(func(e *mapEntry) bool {
+			return e.expired(now)
+		})
+		m.expirations.Add(int64(n))
+		count += n
+	}
+	return count, nil
+}
+
+// MaxEntrySize 返回单个条目的大小上限，超过单个分片容量的条目不会被缓存
+func (m *ShardedMap) MaxEntrySize() int {
+	return int(m.shards[0].capacity)
+}
+
+// SetCostFunc 设置条目的成本函数，设置后 Stats 中的 Bytes 为成本之和
+func (m *ShardedMap) SetCostFunc(fn func(key string, value []byte) int64) {
+	m.costs.set(fn)
+}
+
+// Pin 固定键，只剩固定的条目时新的未固定条目写入失败；固定的条目计入容量，数量过多会挤占其他条目
+func (m *ShardedMap) Pin(key string) {
+	m.shard(key).pin(key, true)
+}
+
+func (m *ShardedMap) Unpin(key string) {
+	m.shard(key).pin(key, false)
+}
+
+// OnEvict 设置因容量不足淘汰条目时的回调，回调在后台协程中执行
+func (m *ShardedMap) OnEvict(fn func(key string, value []byte)) {
+	m.onEvict.Store(&fn)
+}
+
+// Stats 返回当前的条目数、字节数以及淘汰和过期的累计次数
+func (m *ShardedMap) Stats() ShardedMapStats {
+	stats := ShardedMapStats{
+		Evictions:   m.evictions.Load(),
+		Expirations: m.expirations.Load(),
+	}
+	for _, s := range m.shards {
+		s.mu.Lock()
+		stats.Entries += len(s.items)
+		stats.Bytes += s.used
+		s.mu.Unlock()
+	}
+	return stats
+}
+
+// Len 返回当前缓存的条目数
+func (m *ShardedMap) Len() int {
+	return m.Stats().Entries
+}
+
+// Bytes 返回当前缓存条目的总字节数
+func (m *ShardedMap) Bytes() int64 {
+	return m.Stats().Bytes
+}
+
+func (m *ShardedMap) shard(key string) *mapShard {
+	return m.shards[maphash.String(m.seed, key)%uint64(len(m.shards))]
+}
+
+// notifyEvicted 在后台回调被淘汰的条目
+func (m *ShardedMap) notifyEvicted(evicted []*mapEntry) {
+	m.evictions.Add(int64(len(evicted)))
+	fn := m.onEvict.Load()
+	if fn == nil {
+		return
+	}
+	go func() {
+		for _, e := range evicted {
+			(*fn)(e.key, e.value)
+		}
+	}()
+}
+
+// mapEntry 分片中的条目
+type mapEntry struct {
+	key   string
+	value []byte
+
+	// 写入时计算的成本
+	weight int64
+
+	// 过期时间（UnixNano），0 表示不过期
+	expireAt int64
+
+	// 淘汰顺序：LRU 为最近访问的时钟，FIFO 为写入的时钟，LFU 先比较访问次数再比较最近访问的时钟
+	tick uint64
+	hits uint64
+
+	// 在淘汰堆中的位置
+	index int
+
+	// 是否固定，固定的条目排在淘汰堆的最后
+	pinned bool
+}
+
+func (e *mapEntry) cost() int64 {
+	return e.weight
+}
+
+func (e *mapEntry) expired(now int64) bool {
+	return e.expireAt > 0 && now >= e.expireAt
+}
+
+// mapShard 一个分片，淘汰顺序通过最小堆维护，堆顶为下一个被淘汰的条目
+type mapShard struct {
+	m *ShardedMap
+
+	mu         sync.Mutex
+	policy     EvictionPolicy
+	capacity   int64
+	maxEntries int
+	used       int64
+	clock      uint64
+	items      map[string]*mapEntry
+	order      []*mapEntry
+
+	// pinned 固定的键，可以包含尚未写入的键
+	pinned map[string]struct{}
+}
+
+func (s *mapShard) set(key string, value []byte, expire time.Duration) bool {
+	e := &mapEntry{key: key, value: value, weight: s.m.costs.cost(key, value)}
+	if e.cost() > s.capacity {
+		return false
+	}
+	if expire > 0 {
+		e.expireAt = time.Now().Add(expire).UnixNano()
+	}
+
+	s.mu.Lock()
+	s.clock++
+	_, e.pinned = s.pinned[key]
+	var evicted []*mapEntry
+	if old, ok := s.items[key]; ok {
+		s.used += e.cost() - old.cost()
+		old.value, old.weight, old.expireAt = e.value, e.weight, e.expireAt
+		s.touch(old)
+		evicted = s.evict(0, 0)
+	} else {
+		// 先为新条目腾出空间再写入，LFU 下新条目的访问次数最少，写入后再淘汰会淘汰新条目本身
+		evicted = s.evict(e.cost(), 1)
+		if !e.pinned && s.full(e.cost(), 1) {
+			s.mu.Unlock()
+			if len(evicted) > 0 {
+				s.m.notifyEvicted(evicted)
+			}
+			return false
+		}
+		e.tick = s.clock
+		s.items[key] = e
+		s.used += e.cost()
+		heap.Push(s, e)
+	}
+	s.mu.Unlock()
+
+	if len(evicted) > 0 {
+		s.m.notifyEvicted(evicted)
+	}
+	return true
+}
+
+func (s *mapShard) get(key string) ([]byte, bool) {
+	value, _, ok := s.getWithTTL(key)
+	return value, ok
+}
+
+func (s *mapShard) getWithTTL(key string) ([]byte, time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.items[key]
+	if !ok {
+		return nil, 0, false
+	}
+	now := time.Now().UnixNano()
+	if e.expired(now) {
+		s.remove(e)
+		s.m.expirations.Add(1)
+		return nil, 0, false
+	}
+	s.clock++
+	s.touch(e)
+	if e.expireAt == 0 {
+		return e.value, -1, true
+	}
+	return e.value, time.Duration(e.expireAt - now), true
+}
+
+func (s *mapShard) delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.items[key]; ok {
+		s.remove(e)
+	}
+}
+
+// deleteFunc 删除所有满足 match 的条目，返回删除的数量
+func (s *mapShard) deleteFunc(match func(e *mapEntry) bool) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var count int
+	for _, e := range s.items {
+		if match(e) {
+			s.remove(e)
+			count++
+		}
+	}
+	return count
+}
+
+// touch 按策略更新条目的淘汰顺序，调用方需持有锁
+func (s *mapShard) touch(e *mapEntry) {
+	switch s.policy {
+	case EvictLRU:
+		e.tick = s.clock
+	case EvictLFU:
+		e.tick = s.clock
+		e.hits++
+	case EvictFIFO:
+		return
+	}
+	heap.Fix(s, e.index)
+}
+
+// pin 设置键是否固定，调用方无需持有锁
+func (s *mapShard) pin(key string, pinned bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if pinned {
+		if s.pinned == nil {
+			s.pinned = make(map[string]struct{})
+		}
+		s.pinned[key] = struct{}{}
+	} else {
+		delete(s.pinned, key)
+	}
+	if e, ok := s.items[key]; ok && e.pinned != pinned {
+		e.pinned = pinned
+		heap.Fix(s, e.index)
+	}
+}
+
+// evict 淘汰条目直到再加入 cost 字节、entries 个条目后容量和条目数都不超过上限，固定的条目不淘汰，调用方需持有锁
+func (s *mapShard) evict(cost int64, entries int) []*mapEntry {
+	var evicted []*mapEntry
+	for len(s.order) > 0 && !s.order[0].pinned && s.full(cost, entries) {
+		e := s.order[0]
+		s.remove(e)
+		evicted = append(evicted, e)
+	}
+	return evicted
+}
+
+// full 再加入 cost 字节、entries 个条目后是否超过容量或条目数上限，调用方需持有锁
+func (s *mapShard) full(cost int64, entries int) bool {
+	return s.used+cost > s.capacity || (s.maxEntries > 0 && len(s.items)+entries > s.maxEntries)
+}
+
+// remove 删除条目，调用方需持有锁
+func (s *mapShard) remove(e *mapEntry) {
+	heap.Remove(s, e.index)
+	delete(s.items, e.key)
+	s.used -= e.cost()
+}
+
+// Len、Less、Swap、Push、Pop 实现 heap.Interface，调用方需持有锁
+func (s *mapShard) Len() int { return len(s.order) }
+
+func (s *mapShard) Less(i, j int) bool {
+	a, b := s.order[i], s.order[j]
+	if a.pinned != b.pinned {
+		return b.pinned
+	}
+	if s.policy == EvictLFU && a.hits != b.hits {
+		return a.hits < b.hits
+	}
+	return a.tick < b.tick
+}
+
+func (s *mapShard) Swap(i, j int) {
+	s.order[i], s.order[j] = s.order[j], s.order[i]
+	s.order[i].index = i
+	s.order[j].index = j
+}
+
+func (s *mapShard) Push(x any) {
+	e := x.(*mapEntry)
+	e.index = len(s.order)
+	s.order = append(s.order, e)
+}
+
+func (s *mapShard) Pop() any {
+	last := len(s.order) - 1
+	e := s.order[last]
+	s.order[last] = nil
+	s.order = s.order[:last]
+	return e
+}
@@ -0,0 +1,221 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func setupShardedMap(t *testing.T, capacity int, opts ...MemoryOption) *ShardedMap {
+	t.Helper()
+
+	m, err := NewShardedMap(capacity, opts...)
+	if err != nil {
+		t.Fatalf("创建 ShardedMap 失败: %v", err)
+	}
+
+	return m
+}
+
+func TestNewShardedMap(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxMemory int
+		opts      []MemoryOption
+		wantErr   bool
+	}{
+		{name: "默认配置", maxMemory: 1 << 20},
+		{name: "LFU 策略", maxMemory: 1 << 20, opts: []MemoryOption{WithEvictionPolicy(EvictLFU), WithShards(4)}},
+		{name: "容量为 0", maxMemory: 0, wantErr: true},
+		{name: "未知的淘汰策略", maxMemory: 1 << 20, opts: []MemoryOption{WithEvictionPolicy(EvictionPolicy(10))}, wantErr: true},
+		{name: "分片数为负数", maxMemory: 1 << 20, opts: []MemoryOption{WithShards(-1)}, wantErr: true},
+		{name: "条目数为负数", maxMemory: 1 << 20, opts: []MemoryOption{WithMaxEntries(-1)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewShardedMap(tt.maxMemory, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewShardedMap() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := NewOtter(1<<20, WithEvictionPolicy(EvictFIFO)); err == nil {
+		t.Error("Otter 不支持淘汰策略配置，应该返回错误")
+	}
+}
+
+func TestShardedMap_SetGetDelete(t *testing.T) {
+	m := setupShardedMap(t, 1<<20)
+
+	if n := m.MSet(map[string][]byte{"a": []byte("1"), "b": []byte("2")}, time.Hour); n != 2 {
+		t.Errorf("MSet() = %d, want 2", n)
+	}
+	m.Set("a", []byte("3"), time.Hour)
+	if value, ok := m.Get("a"); !ok || string(value) != "3" {
+		t.Errorf("Get(a) = %q, %v, want 3", value, ok)
+	}
+
+	m.Delete("a")
+	if got := m.MGet([]string{"a", "b"}); len(got) != 1 || string(got["b"]) != "2" {
+		t.Errorf("MGet() = %v, want only b", got)
+	}
+	if stats := m.Stats(); stats.Entries != 1 || stats.Bytes != 2 {
+		t.Errorf("Stats() = %+v, want 1 entry of 2 bytes", stats)
+	}
+	if m.Len() != 1 || m.Bytes() != 2 {
+		t.Errorf("Len(), Bytes() = %d, %d, want 1, 2", m.Len(), m.Bytes())
+	}
+
+	if n := m.Set("large", make([]byte, m.MaxEntrySize()), time.Hour); n != 0 {
+		t.Error("超过单个分片容量的条目应该被拒绝")
+	}
+}
+
+func TestShardedMap_TTL(t *testing.T) {
+	m := setupShardedMap(t, 1<<20)
+
+	m.Set("short", []byte("v"), 10*time.Millisecond)
+	m.Set("swept", []byte("v"), 10*time.Millisecond)
+	m.Set("forever", []byte("v"), 0)
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok := m.Get("short"); ok {
+		t.Error("过期的条目不应该被读取")
+	}
+	n, err := m.Sweep(context.Background())
+	if err != nil || n != 1 {
+		t.Errorf("Sweep() = %d, %v, want 1", n, err)
+	}
+	if _, ok := m.Get("forever"); !ok {
+		t.Error("未设置过期时间的条目不应该过期")
+	}
+	if stats := m.Stats(); stats.Expirations != 2 || stats.Entries != 1 {
+		t.Errorf("Stats() = %+v, want 2 expirations and 1 entry", stats)
+	}
+}
+
+func TestShardedMap_EvictionPolicy(t *testing.T) {
+	tests := []struct {
+		policy  EvictionPolicy
+		evicted string
+	}{
+		// 访问顺序为 c、c、b、a，c 最久未访问
+		{policy: EvictLRU, evicted: "c"},
+		// c 访问两次，a 和 b 各一次，b 更久未访问
+		{policy: EvictLFU, evicted: "b"},
+		// a 最早写入，访问不改变顺序
+		{policy: EvictFIFO, evicted: "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			m := setupShardedMap(t, 1<<20, WithShards(1), WithMaxEntries(3), WithEvictionPolicy(tt.policy))
+
+			evicted := make(chan string, 1)
+			m.OnEvict(func(key string, value []byte) {
+				evicted <- key
+			})
+
+			for _, key := range []string{"a", "b", "c"} {
+				m.Set(key, []byte(key), time.Hour)
+			}
+			for _, key := range []string{"c", "c", "b", "a"} {
+				m.Get(key)
+			}
+			m.Set("d", []byte("d"), time.Hour)
+
+			if _, ok := m.Get(tt.evicted); ok {
+				t.Errorf("%s 应该被淘汰", tt.evicted)
+			}
+			if stats := m.Stats(); stats.Entries != 3 || stats.Evictions != 1 {
+				t.Errorf("Stats() = %+v, want 3 entries and 1 eviction", stats)
+			}
+			select {
+			case key := <-evicted:
+				if key != tt.evicted {
+					t.Errorf("OnEvict key = %s, want %s", key, tt.evicted)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("淘汰时应触发回调")
+			}
+		})
+	}
+}
+
+func TestShardedMap_Capacity(t *testing.T) {
+	m := setupShardedMap(t, 100, WithShards(1))
+
+	for _, key := range []string{"k1", "k2", "k3"} {
+		m.Set(key, make([]byte, 38), time.Hour)
+	}
+	if stats := m.Stats(); stats.Bytes > 100 || stats.Entries != 2 || stats.Evictions != 1 {
+		t.Errorf("Stats() = %+v, want 2 entries within 100 bytes", stats)
+	}
+	if _, ok := m.Get("k1"); ok {
+		t.Error("超出容量时应该淘汰最早的条目")
+	}
+}
+
+func TestShardedMap_DeletePrefix(t *testing.T) {
+	testDeletePrefix(t, setupShardedMap(t, 1<<20))
+}
+
+func TestShardedMap_CostFunc(t *testing.T) {
+	m := setupShardedMap(t, 2, WithShards(1))
+	m.SetCostFunc(func(key string, value []byte) int64 { return 1 })
+
+	for _, key := range []string{"k1", "k2", "k3"} {
+		m.Set(key, make([]byte, 100), time.Hour)
+	}
+	if stats := m.Stats(); stats.Entries != 2 || stats.Bytes != 2 || stats.Evictions != 1 {
+		t.Errorf("Stats() = %+v, want 2 entries with cost 2", stats)
+	}
+	if _, ok := m.Get("k3"); !ok {
+		t.Error("按成本计算时应该能缓存超过字节容量的值")
+	}
+}
+
+func TestShardedMap_GetWithTTL(t *testing.T) {
+	testGetWithTTL(t, setupShardedMap(t, 1<<20), true)
+}
+
+func TestShardedMap_Pin(t *testing.T) {
+	m := setupShardedMap(t, 300, WithShards(1))
+	m.Pin("config")
+
+	m.Set("config", make([]byte, 94), time.Hour)
+	for _, key := range []string{"k1", "k2", "k3"} {
+		m.Set(key, make([]byte, 98), time.Hour)
+	}
+	if _, ok := m.Get("config"); !ok {
+		t.Fatal("固定的条目不应被淘汰")
+	}
+	if stats := m.Stats(); stats.Evictions != 1 {
+		t.Errorf("Evictions = %d, want 1", stats.Evictions)
+	}
+
+	m.Unpin("config")
+	for _, key := range []string{"k4", "k5", "k6"} {
+		m.Set(key, make([]byte, 98), time.Hour)
+	}
+	if _, ok := m.Get("config"); ok {
+		t.Error("取消固定后应按 LRU 淘汰")
+	}
+}
+
+func TestShardedMap_PinnedFull(t *testing.T) {
+	m := setupShardedMap(t, 200, WithShards(1))
+	m.Pin("a")
+	m.Pin("b")
+	m.Set("a", make([]byte, 99), time.Hour)
+	m.Set("b", make([]byte, 99), time.Hour)
+
+	if m.Set("c", make([]byte, 99), time.Hour) != 0 {
+		t.Error("只剩固定的条目时应拒绝写入")
+	}
+	if _, ok := m.Get("a"); !ok {
+		t.Error("固定的条目不应被淘汰")
+	}
+}
@@ -14,7 +14,7 @@ type Remote interface {
 
 	Delete(ctx context.Context, key string) error
 
-	// TTL 仅用于单元测试
+	// TTL 返回键的剩余过期时间
 	TTL(ctx context.Context, key string) (time.Duration, error)
 }
 
@@ -27,3 +27,138 @@ type Memory interface {
 
 	Delete(key string)
 }
+
+// Scanner 支持增量遍历键的 Remote 适配器
+type Scanner interface {
+	// Scan 返回匹配 match 的一批键以及下一次遍历的游标，游标为 0 表示遍历结束；
+	// count 为单次遍历的建议数量，返回的键数量可能多于或少于 count；
+	// 当前配置无法遍历所有键时（例如 Redis 集群）返回 errors.ErrOperationNotSupported
+	Scan(ctx context.Context, match string, cursor uint64, count int) ([]string, uint64, error)
+}
+
+// MultiDeleter 支持批量删除的 Remote 适配器
+type MultiDeleter interface {
+	// MDelete 在一次往返中删除多个键，不存在的键忽略
+	MDelete(ctx context.Context, keys []string) error
+}
+
+// Expirer 支持批量修改过期时间的 Remote 适配器
+type Expirer interface {
+	// MExpire 将已存在的键的过期时间设置为 expire，不存在的键忽略
+	MExpire(ctx context.Context, keys []string, expire time.Duration) error
+}
+
+// ValueWithTTL 带有独立过期时间的值
+type ValueWithTTL struct {
+	Value []byte
+	TTL   time.Duration
+}
+
+// MultiTTLSetter 支持批量写入不同过期时间的 Remote 适配器
+type MultiTTLSetter interface {
+	// MSetWithTTLs 在一次往返中写入多个键，每个键使用各自的过期时间，TTL 为 0 表示不过期
+	MSetWithTTLs(ctx context.Context, entries map[string]ValueWithTTL) error
+}
+
+// ConditionalSetter 支持条件写入的 Remote 适配器
+type ConditionalSetter interface {
+	// SetNX 仅在键不存在时写入，返回是否写入成功
+	SetNX(ctx context.Context, key string, value []byte, expire time.Duration) (bool, error)
+}
+
+// Swapper 支持原子替换的 Remote 适配器
+type Swapper interface {
+	// GetSet 写入新值并返回替换前的值，替换前不存在时仍写入新值并返回 ErrNotFound
+	GetSet(ctx context.Context, key string, value []byte, expire time.Duration) ([]byte, error)
+}
+
+// CompareSetter 支持条件写入的 Remote 适配器
+type CompareSetter interface {
+	// CompareAndSet 仅在键的当前值等于 old 时写入 value 并保留原有的过期时间，返回是否写入；键不存在时不写入
+	CompareAndSet(ctx context.Context, key string, old, value []byte) (bool, error)
+}
+
+// CompareDeleter 支持条件删除的 Remote 适配器
+type CompareDeleter interface {
+	// CompareAndDelete 仅在键的当前值等于 value 时删除，返回是否删除
+	CompareAndDelete(ctx context.Context, key string, value []byte) (bool, error)
+}
+
+// Counter 支持原子计数的 Remote 适配器
+type Counter interface {
+	// IncrBy 将键的整数值原子地增加 delta 并返回增加后的值，键不存在时从 0 开始；
+	// 键没有过期时间时将过期时间设置为 expire，已有的过期时间保持不变，expire 为 0 表示不过期
+	IncrBy(ctx context.Context, key string, delta int64, expire time.Duration) (int64, error)
+}
+
+// SetStore 支持集合操作的 Remote 适配器
+type SetStore interface {
+	// SAdd 向集合添加成员，并将集合的过期时间设置为 expire
+	SAdd(ctx context.Context, key string, members []string, expire time.Duration) error
+	// SMembers 返回集合的所有成员，集合不存在时返回空
+	SMembers(ctx context.Context, key string) ([]string, error)
+	// SPopAll 原子地返回并删除集合的所有成员，集合不存在时返回空
+	SPopAll(ctx context.Context, key string) ([]string, error)
+}
+
+// InvalidationTransport 跨实例广播失效消息的传输层，例如 Redis Pub/Sub 或 NATS
+type InvalidationTransport interface {
+	// Publish 向所有订阅者（包括自己）广播一条消息
+	Publish(ctx context.Context, payload []byte) error
+	// Subscribe 订阅消息并阻塞直到 ctx 结束或订阅出错，每条消息调用一次 handler；ctx 结束时返回 ctx.Err()
+	Subscribe(ctx context.Context, handler func(payload []byte)) error
+}
+
+// Sweeper 支持主动清理过期条目的内存适配器
+// 内置的 Otter 和 Ristretto 会在后台定期清理过期条目，无需实现
+type Sweeper interface {
+	// Sweep 删除已过期的条目并返回删除的数量，ctx 取消或到期时停止并返回已删除的数量
+	Sweep(ctx context.Context) (int, error)
+}
+
+// PrefixDeleter 支持按前缀删除的内存适配器
+type PrefixDeleter interface {
+	// DeletePrefix 删除所有以 prefix 开头的键，返回删除的数量；需要遍历所有条目，不适合高频调用
+	DeletePrefix(prefix string) int
+}
+
+// EntrySizeLimiter 单个条目有大小上限的内存适配器
+type EntrySizeLimiter interface {
+	// MaxEntrySize 返回单个条目（键长度 + 值长度）允许的最大字节数，超过的条目不会被缓存
+	MaxEntrySize() int
+}
+
+// Sizer 支持查询当前条目数和字节数的内存适配器
+type Sizer interface {
+	// Len 返回当前缓存的条目数，可能包含尚未清理的过期条目
+	Len() int
+	// Bytes 返回当前缓存条目的总字节数（键 + 值），部分适配器需要遍历所有条目，不适合高频调用
+	Bytes() int64
+}
+
+// TTLGetter 支持读取剩余过期时间的内存适配器
+type TTLGetter interface {
+	// GetWithTTL 返回值和剩余过期时间，没有过期时间时为 -1；精度取决于适配器，Otter 和 Freecache 为秒
+	GetWithTTL(key string) ([]byte, time.Duration, bool)
+}
+
+// Pinner 支持固定条目的内存适配器
+type Pinner interface {
+	// Pin 固定键，固定的条目不会因容量不足被淘汰，但仍会过期和被删除；可以在写入条目之前调用
+	Pin(key string)
+	// Unpin 取消固定
+	Unpin(key string)
+}
+
+// CostSetter 支持自定义条目成本的内存适配器
+type CostSetter interface {
+	// SetCostFunc 设置条目的成本函数，替代默认的键长度 + 值长度，容量按成本之和限制；应在写入条目之前设置
+	SetCostFunc(fn func(key string, value []byte) int64)
+}
+
+// EvictionNotifier 支持淘汰通知的内存适配器
+type EvictionNotifier interface {
+	// OnEvict 设置因容量不足淘汰条目时的回调。回调可能在适配器持有内部锁时同步执行（例如 Otter 在淘汰协程中持有淘汰锁），
+	// 必须尽快返回，不能阻塞或访问外部存储，耗时的处理应放入队列在其他协程中执行
+	OnEvict(fn func(key string, value []byte))
+}
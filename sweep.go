@@ -0,0 +1,31 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/biu7/layered-cache/storage"
+)
+
+// SweepMemory 在 budget 时间内主动清理内存缓存中已过期的条目，返回清理的数量
+// 内存适配器需要实现 storage.Sweeper；内置的 Otter 和 Ristretto 已在后台定期清理过期条目，调用时直接返回 0
+// 可在长时间空闲的服务中定期调用，避免内存中长期保留已过期的数据
+func (c *LayeredCache) SweepMemory(ctx context.Context, budget time.Duration) (int, error) {
+	if c.memory == nil || budget <= 0 {
+		return 0, nil
+	}
+	sweeper, ok := c.memory.(storage.Sweeper)
+	if !ok {
+		return 0, nil
+	}
+
+	sweepCtx, cancel := context.WithTimeout(ctx, budget)
+	defer cancel()
+
+	n, err := sweeper.Sweep(sweepCtx)
+	if err != nil && ctx.Err() == nil && sweepCtx.Err() != nil {
+		// 时间预算用尽不视为错误，剩余的条目留给下一次清理
+		return n, nil
+	}
+	return n, err
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// lazyMemory 惰性过期的内存适配器，只在 Get 或 Sweep 时删除过期条目
+type lazyMemory struct {
+	storage.Memory
+	mu      sync.Mutex
+	expires map[string]time.Time
+	// sweepDelay 每清理一个条目的耗时
+	sweepDelay time.Duration
+}
+
+func (m *lazyMemory) Set(key string, value []byte, expire time.Duration) int32 {
+	m.mu.Lock()
+	m.expires[key] = time.Now().Add(expire)
+	m.mu.Unlock()
+	return m.Memory.Set(key, value, time.Hour)
+}
+
+func (m *lazyMemory) Sweep(ctx context.Context) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n := 0
+	for key, expireAt := range m.expires {
+		if err := ctx.Err(); err != nil {
+			return n, err
+		}
+		if time.Now().After(expireAt) {
+			time.Sleep(m.sweepDelay)
+			m.Memory.Delete(key)
+			delete(m.expires, key)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func TestLayeredCache_SweepMemory(t *testing.T) {
+	ctx := context.Background()
+	newCache := func(sweepDelay time.Duration) (*LayeredCache, *lazyMemory) {
+		memory := &lazyMemory{Memory: createOtterAdapter(t), expires: make(map[string]time.Time), sweepDelay: sweepDelay}
+		cache, err := NewCache(WithConfigMemory(memory))
+		assert.NoError(t, err)
+		return cache.(*LayeredCache), memory
+	}
+
+	t.Run("清理过期条目", func(t *testing.T) {
+		c, memory := newCache(0)
+		assert.NoError(t, c.Set(ctx, "expired", "v", WithMemoryTTL(time.Millisecond)))
+		assert.NoError(t, c.Set(ctx, "alive", "v", WithMemoryTTL(time.Hour)))
+		time.Sleep(5 * time.Millisecond)
+
+		n, err := c.SweepMemory(ctx, time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, n)
+
+		_, exists := memory.Memory.Get("expired")
+		assert.False(t, exists)
+		_, exists = memory.Memory.Get("alive")
+		assert.True(t, exists)
+	})
+
+	t.Run("超出时间预算时停止", func(t *testing.T) {
+		c, _ := newCache(10 * time.Millisecond)
+		for i := 0; i < 20; i++ {
+			assert.NoError(t, c.Set(ctx, fmt.Sprintf("k%d", i), "v", WithMemoryTTL(time.Millisecond)))
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		n, err := c.SweepMemory(ctx, 35*time.Millisecond)
+		assert.NoError(t, err)
+		assert.Less(t, n, 20)
+
+		rest, err := c.SweepMemory(ctx, time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, 20, n+rest)
+	})
+
+	t.Run("调用方取消", func(t *testing.T) {
+		c, _ := newCache(10 * time.Millisecond)
+		assert.NoError(t, c.Set(ctx, "expired", "v", WithMemoryTTL(time.Millisecond)))
+		time.Sleep(5 * time.Millisecond)
+
+		cancelCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := c.SweepMemory(cancelCtx, time.Second)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("内置适配器无需清理", func(t *testing.T) {
+		n, err := createTestCache(t).SweepMemory(ctx, time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+	})
+}
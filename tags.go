@@ -0,0 +1,180 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+)
+
+// tagKeySuffix 标签集合键的后缀，集合中保存带有该标签的所有键
+const tagKeySuffix = "\x00tag"
+
+// tagKey 返回 tag 对应的 Remote 集合键
+func tagKey(tag string) string {
+	return tag + tagKeySuffix
+}
+
+// withTags 设置写入的键携带的标签
+type withTags struct {
+	tags []string
+}
+
+func (w withTags) applySet(cfg *setOptions) {
+	cfg.tags = append(cfg.tags, w.tags...)
+}
+
+// WithTags 为 Set/MSet 写入的键添加标签，之后可以通过 InvalidateTag 删除带有某个标签的所有键，例如 WithTags("user:42", "org:7")
+// 配置 Remote 时标签的反向索引保存在 Remote 集合中（需要 Remote 适配器实现 storage.SetStore），
+// Remote 集合的过期时间在每次写入时刷新为本次 Remote TTL 与默认 Remote TTL 中较大的一个；
+// 只有内存缓存时保存在进程内，删除键时同时从索引中移除
+func WithTags(tags ...string) SetOption {
+	return withTags{tags: tags}
+}
+
+// tagIndex 进程内的标签反向索引，只在没有 Remote 时使用
+type tagIndex struct {
+	mu   sync.Mutex
+	keys map[string]map[string]struct{}
+	// tags 键到标签的索引，删除键时用于从 keys 中移除
+	tags map[string]map[string]struct{}
+}
+
+func newTagIndex() *tagIndex {
+	return &tagIndex{
+		keys: make(map[string]map[string]struct{}),
+		tags: make(map[string]map[string]struct{}),
+	}
+}
+
+// add 记录 keys 带有 tags
+func (t *tagIndex) add(tags, keys []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, tag := range tags {
+		set, ok := t.keys[tag]
+		if !ok {
+			set = make(map[string]struct{}, len(keys))
+			t.keys[tag] = set
+		}
+		for _, key := range keys {
+			set[key] = struct{}{}
+			tags, ok := t.tags[key]
+			if !ok {
+				tags = make(map[string]struct{}, 1)
+				t.tags[key] = tags
+			}
+			tags[tag] = struct{}{}
+		}
+	}
+}
+
+// remove 从索引中移除已删除的 keys
+func (t *tagIndex) remove(keys []string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.tags) == 0 {
+		return
+	}
+	for _, key := range keys {
+		for tag := range t.tags[key] {
+			delete(t.keys[tag], key)
+			if len(t.keys[tag]) == 0 {
+				delete(t.keys, tag)
+			}
+		}
+		delete(t.tags, key)
+	}
+}
+
+// take 返回并移除带有 tag 的所有键
+func (t *tagIndex) take(tag string) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	set := t.keys[tag]
+	delete(t.keys, tag)
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+		delete(t.tags[key], tag)
+		if len(t.tags[key]) == 0 {
+			delete(t.tags, key)
+		}
+	}
+	return keys
+}
+
+// checkTags 写入前检查 Remote 是否支持标签索引
+func (c *LayeredCache) checkTags(config *setOptions) error {
+	if len(config.tags) == 0 || c.remote == nil {
+		return nil
+	}
+	if _, ok := c.remote.(storage.SetStore); !ok {
+		return errors.ErrOperationNotSupported
+	}
+	return nil
+}
+
+// tagKeys 写入成功后记录 keys 的标签
+func (c *LayeredCache) tagKeys(ctx context.Context, config *setOptions, keys []string, remoteTTL time.Duration) error {
+	if len(config.tags) == 0 || len(keys) == 0 {
+		return nil
+	}
+
+	if c.remote == nil {
+		c.tags.add(config.tags, keys)
+		return nil
+	}
+
+	store := c.remote.(storage.SetStore)
+	ttl := max(remoteTTL, c.defaultRemoteTTL)
+	for _, tag := range config.tags {
+		if err := c.stats.remoteError(store.SAdd(ctx, tagKey(tag), keys, ttl)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InvalidateTag 删除两层缓存中所有带有 tag 的键，键通过 Set/MSet 的 WithTags 添加标签
+// 配置 Remote 时原子地取出并删除 Remote 集合，删除期间新写入的键保留在新的集合中；删除失败时把键放回集合
+func (c *LayeredCache) InvalidateTag(ctx context.Context, tag string) error {
+	scope, ctx := c.takeKeyContext(ctx)
+	tag = scope.key(tag)
+	if c.remote == nil {
+		return c.MDelete(ctx, c.tags.take(tag))
+	}
+
+	store, ok := c.remote.(storage.SetStore)
+	if !ok {
+		return errors.ErrOperationNotSupported
+	}
+	keys, err := store.SPopAll(ctx, tagKey(tag))
+	if err != nil {
+		return c.stats.remoteError(err)
+	}
+	if err = c.MDelete(ctx, uniqueKeys(keys)); err != nil {
+		_ = c.stats.remoteError(store.SAdd(ctx, tagKey(tag), keys, c.defaultRemoteTTL))
+		return err
+	}
+	return nil
+}
+
+// uniqueKeys 返回去重后的键，保持首次出现的顺序
+func uniqueKeys(keys []string) []string {
+	seen := make(map[string]struct{}, len(keys))
+	result := keys[:0]
+	for _, key := range keys {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, key)
+	}
+	return result
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_InvalidateTag(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("删除两层中带有标签的键", func(t *testing.T) {
+		c, err := NewCache(WithConfigMemory(createOtterAdapter(t)), WithConfigRemote(createRemoteAdapter(t)))
+		assert.NoError(t, err)
+
+		assert.NoError(t, c.Set(ctx, "profile:42", "alice", WithTags("user:42")))
+		assert.NoError(t, c.MSet(ctx, map[string]any{"orders:42": 3, "settings:42": "dark"}, WithTags("user:42", "org:7")))
+		assert.NoError(t, c.Set(ctx, "profile:43", "bob", WithTags("user:43")))
+
+		assert.NoError(t, c.InvalidateTag(ctx, "user:42"))
+
+		var value string
+		for _, key := range []string{"profile:42", "orders:42", "settings:42"} {
+			assert.ErrorIs(t, c.Get(ctx, key, &value), errors.ErrNotFound, key)
+		}
+		assert.NoError(t, c.Get(ctx, "profile:43", &value))
+
+		keys, _, err := c.RemoteKeys(ctx, "", 0, 100)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"profile:43"}, keys, "标签集合不出现在遍历结果中")
+	})
+
+	t.Run("其他实例通过Remote集合删除", func(t *testing.T) {
+		remote := createRemoteAdapter(t)
+		writer, err := NewCache(WithConfigMemory(createOtterAdapter(t)), WithConfigRemote(remote))
+		assert.NoError(t, err)
+		other, err := NewCache(WithConfigRemote(remote))
+		assert.NoError(t, err)
+
+		assert.NoError(t, writer.Set(ctx, "profile:42", "alice", WithTags("user:42")))
+		assert.NoError(t, other.InvalidateTag(ctx, "user:42"))
+
+		var value string
+		assert.ErrorIs(t, other.Get(ctx, "profile:42", &value), errors.ErrNotFound)
+	})
+
+	t.Run("只有内存缓存", func(t *testing.T) {
+		c, err := NewCache(WithConfigMemory(createOtterAdapter(t)))
+		assert.NoError(t, err)
+
+		assert.NoError(t, c.Set(ctx, "profile:42", "alice", WithTags("user:42")))
+		assert.NoError(t, c.InvalidateTag(ctx, "user:42"))
+
+		var value string
+		assert.ErrorIs(t, c.Get(ctx, "profile:42", &value), errors.ErrNotFound)
+	})
+
+	t.Run("删除键时移除进程内索引", func(t *testing.T) {
+		c, err := NewCache(WithConfigMemory(createOtterAdapter(t)))
+		assert.NoError(t, err)
+
+		assert.NoError(t, c.Set(ctx, "profile:42", "alice", WithTags("user:42", "org:7")))
+		assert.NoError(t, c.MSet(ctx, map[string]any{"orders:42": 3}, WithTags("user:42")))
+		assert.NoError(t, c.Delete(ctx, "profile:42"))
+		assert.NoError(t, c.MDelete(ctx, []string{"orders:42"}))
+
+		lc := c.(*LayeredCache)
+		assert.Empty(t, lc.tags.keys)
+		assert.Empty(t, lc.tags.tags)
+	})
+
+	t.Run("配置Remote时不使用进程内索引", func(t *testing.T) {
+		c, err := NewCache(WithConfigMemory(createOtterAdapter(t)), WithConfigRemote(createRemoteAdapter(t)))
+		assert.NoError(t, err)
+
+		assert.NoError(t, c.Set(ctx, "profile:42", "alice", WithTags("user:42")))
+		assert.Empty(t, c.(*LayeredCache).tags.keys)
+
+		assert.NoError(t, c.InvalidateTag(ctx, "user:42"))
+		assert.NoError(t, c.Set(ctx, "profile:42", "bob", WithTags("user:42")))
+		assert.NoError(t, c.InvalidateTag(ctx, "user:42"))
+
+		var value string
+		assert.ErrorIs(t, c.Get(ctx, "profile:42", &value), errors.ErrNotFound, "取出集合后重新写入的键仍然可以按标签删除")
+	})
+}
@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName OpenTelemetry instrumentation 名称
+const tracerName = "github.com/biu7/layered-cache"
+
+// Span 属性名
+const (
+	attrOperation   = attribute.Key("cache.operation")
+	attrKeyCount    = attribute.Key("cache.key_count")
+	attrPayloadSize = attribute.Key("cache.payload_size")
+	attrLoaded      = attribute.Key("cache.loader.found")
+)
+
+// traceCall 为一次缓存操作创建 span，记录操作名和键的数量
+func (c *LayeredCache) traceCall(op Operation, call Invoker) Invoker {
+	return func(ctx context.Context) error {
+		ctx, span := c.tracer.Start(ctx, "cache."+op.Name,
+			trace.WithSpanKind(trace.SpanKindInternal),
+			trace.WithAttributes(attrOperation.String(op.Name), attrKeyCount.Int(len(op.Keys))),
+		)
+		defer span.End()
+
+		err := call(ctx)
+		endSpan(span, err)
+		return err
+	}
+}
+
+// traceLoad 为一次 loader / batchLoader 调用创建 span，未开启追踪时返回空操作的 end
+func (c *LayeredCache) traceLoad(ctx context.Context, keys int) (context.Context, func(found int, err error)) {
+	if c.tracer == nil {
+		return ctx, func(int, error) {}
+	}
+	ctx, span := c.tracer.Start(ctx, "cache.load",
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(attrKeyCount.Int(keys)),
+	)
+	return ctx, func(found int, err error) {
+		span.SetAttributes(attrLoaded.Int(found))
+		endSpan(span, err)
+		span.End()
+	}
+}
+
+// tracePayload 在当前操作的 span 上记录读取或写入的值的总字节数
+func (c *LayeredCache) tracePayload(ctx context.Context, size int) {
+	if c.tracer == nil {
+		return
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attrPayloadSize.Int(size))
+}
+
+// traceLayer 在 span 上记录一层的命中和未命中数量，写入操作只记录错误
+// loader 的结果记录在单独的 span 上
+func traceLayer(span trace.Span, op string, layer Layer, keys, hits int, err error) {
+	if layer == LayerLoader {
+		return
+	}
+	if err != nil && !IsNotFound(err) {
+		span.RecordError(err, trace.WithAttributes(attribute.String("cache.layer", string(layer))))
+	}
+	if op != "get" && op != "mget" {
+		return
+	}
+	span.SetAttributes(
+		attribute.Int("cache."+string(layer)+".hits", hits),
+		attribute.Int("cache."+string(layer)+".misses", keys-hits),
+	)
+}
+
+// endSpan 根据 err 设置 span 状态，缺失值不视为错误
+func endSpan(span trace.Span, err error) {
+	if err == nil || IsNotFound(err) {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// payloadSize 返回所有值的总字节数
+func payloadSize(data map[string][]byte) int {
+	var size int
+	for _, value := range data {
+		size += len(value)
+	}
+	return size
+}
@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// spanAttrs 将 span 的属性转换为 map，便于断言
+func spanAttrs(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestLayeredCache_Tracing(t *testing.T) {
+	ctx := context.Background()
+
+	newTracedCache := func(t *testing.T) (Cache, *tracetest.SpanRecorder) {
+		recorder := tracetest.NewSpanRecorder()
+		c, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigRemote(createRemoteAdapter(t)),
+			WithConfigTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+		)
+		assert.NoError(t, err)
+		return c, recorder
+	}
+
+	t.Run("写入记录键数量和字节数", func(t *testing.T) {
+		c, recorder := newTracedCache(t)
+		assert.NoError(t, c.MSet(ctx, map[string]any{"k1": "v1", "k2": "v2"}))
+
+		spans := recorder.Ended()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, "cache.mset", spans[0].Name())
+		attrs := spanAttrs(spans[0])
+		assert.Equal(t, int64(2), attrs[attrKeyCount].AsInt64())
+		assert.Positive(t, attrs[attrPayloadSize].AsInt64())
+	})
+
+	t.Run("读取记录各层命中", func(t *testing.T) {
+		c, recorder := newTracedCache(t)
+		assert.NoError(t, c.Set(ctx, "k1", "v1"))
+		c.(*LayeredCache).memory.Delete("k1")
+
+		values := make(map[string]string)
+		assert.NoError(t, c.MGet(ctx, []string{"k1", "k2"}, &values))
+
+		spans := recorder.Ended()
+		assert.Len(t, spans, 2)
+		attrs := spanAttrs(spans[1])
+		assert.Equal(t, "cache.mget", spans[1].Name())
+		assert.Equal(t, int64(0), attrs["cache.memory.hits"].AsInt64())
+		assert.Equal(t, int64(2), attrs["cache.memory.misses"].AsInt64())
+		assert.Equal(t, int64(1), attrs["cache.remote.hits"].AsInt64())
+		assert.Equal(t, int64(1), attrs["cache.remote.misses"].AsInt64())
+	})
+
+	t.Run("loader 调用创建子 span", func(t *testing.T) {
+		c, recorder := newTracedCache(t)
+		failed := stderrors.New("db down")
+
+		var value string
+		err := c.Get(ctx, "k", &value, WithLoader(func(ctx context.Context, key string) (any, error) {
+			return nil, failed
+		}))
+		assert.ErrorIs(t, err, failed)
+
+		spans := recorder.Ended()
+		assert.Len(t, spans, 2)
+		load, get := spans[0], spans[1]
+		assert.Equal(t, "cache.load", load.Name())
+		assert.Equal(t, "cache.get", get.Name())
+		assert.Equal(t, get.SpanContext().SpanID(), load.Parent().SpanID())
+		assert.Equal(t, codes.Error, load.Status().Code)
+		assert.Equal(t, codes.Error, get.Status().Code)
+	})
+
+	t.Run("缺失值不视为错误", func(t *testing.T) {
+		c, recorder := newTracedCache(t)
+
+		var value string
+		assert.ErrorIs(t, c.Get(ctx, "missing", &value), ErrNotFound)
+
+		spans := recorder.Ended()
+		assert.Len(t, spans, 1)
+		assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	})
+}
@@ -0,0 +1,99 @@
+package cache
+
+import "time"
+
+// TTLFunc 根据键和 loader 返回的值计算写入缓存的过期时间，返回值不大于 0 时使用默认或选项指定的 TTL
+type TTLFunc func(key string, value any) (memoryTTL, remoteTTL time.Duration)
+
+// withTTLFunc 设置按键计算过期时间的函数
+type withTTLFunc struct {
+	readOption
+
+	fn TTLFunc
+}
+
+func (w withTTLFunc) applyGet(cfg *getOptions) {
+	cfg.ttlFunc = w.fn
+}
+
+func (w withTTLFunc) applySet(cfg *setOptions) {
+	cfg.ttlFunc = w.fn
+}
+
+// WithTTLFunc 设置 loader / batchLoader 加载的值或 Set / MSet 写入的值按键和值分别计算过期时间，
+// 例如频繁变化的实体使用较短的 TTL、归档数据使用较长的 TTL；
+// 读取时仅作用于 loader 加载后的写入，Remote 命中后写回内存缓存时仍使用默认或选项指定的 TTL。
+// MSet 中过期时间不同的键在 Remote 实现 storage.MultiTTLSetter 时合并为一次写入
+func WithTTLFunc(fn func(key string, value any) (memoryTTL, remoteTTL time.Duration)) interface {
+	ReadOption
+	SetOption
+} {
+	return withTTLFunc{fn: fn}
+}
+
+// ttlGroup 过期时间相同的一组数据
+type ttlGroup struct {
+	memoryTTL time.Duration
+	remoteTTL time.Duration
+	data      map[string][]byte
+}
+
+// calculateValueTTL 计算 loader 加载的单个值的TTL，设置了 ttlFunc 时以其返回的正数为准
+func (c *LayeredCache) calculateValueTTL(config *getOptions, key string, value any) (memoryTTL, remoteTTL time.Duration) {
+	memoryTTL, remoteTTL = c.calculateLoaderTTL(config)
+	return applyTTLFunc(config.ttlFunc, key, value, memoryTTL, remoteTTL)
+}
+
+// calculateSetValueTTL 计算 Set 写入的单个值的TTL，设置了 ttlFunc 时以其返回的正数为准
+func (c *LayeredCache) calculateSetValueTTL(config *setOptions, key string, value any) (memoryTTL, remoteTTL time.Duration) {
+	memoryTTL, remoteTTL = c.calculateSetTTL(config)
+	return applyTTLFunc(config.ttlFunc, key, value, memoryTTL, remoteTTL)
+}
+
+// applyTTLFunc 使用 fn 返回的正数覆盖 memoryTTL 和 remoteTTL，fn 为 nil 时原样返回
+func applyTTLFunc(fn TTLFunc, key string, value any, memoryTTL, remoteTTL time.Duration) (time.Duration, time.Duration) {
+	if fn == nil {
+		return memoryTTL, remoteTTL
+	}
+
+	m, r := fn(key, value)
+	if m > 0 {
+		memoryTTL = m
+	}
+	if r > 0 {
+		remoteTTL = r
+	}
+	return memoryTTL, remoteTTL
+}
+
+// groupByTTL 将批量加载的数据按过期时间分组，没有设置 ttlFunc 时只有一组
+func (c *LayeredCache) groupByTTL(config *getOptions, data map[string][]byte, values map[string]any) []ttlGroup {
+	memoryTTL, remoteTTL := c.calculateLoaderTTL(config)
+	return groupTTLs(config.ttlFunc, memoryTTL, remoteTTL, data, values)
+}
+
+// groupTTLs 按 fn 计算的过期时间将数据分组，fn 为 nil 时只有一组，使用 memoryTTL 和 remoteTTL
+func groupTTLs(fn TTLFunc, memoryTTL, remoteTTL time.Duration, data map[string][]byte, values map[string]any) []ttlGroup {
+	if len(data) == 0 {
+		return nil
+	}
+
+	if fn == nil {
+		return []ttlGroup{{memoryTTL: memoryTTL, remoteTTL: remoteTTL, data: data}}
+	}
+
+	var groups []ttlGroup
+	index := make(map[[2]time.Duration]int)
+	for key, value := range data {
+		memoryTTL, remoteTTL := applyTTLFunc(fn, key, values[key], memoryTTL, remoteTTL)
+		ttl := [2]time.Duration{memoryTTL, remoteTTL}
+		i, ok := index[ttl]
+		if !ok {
+			i = len(groups)
+			index[ttl] = i
+			groups = append(groups, ttlGroup{memoryTTL: memoryTTL, remoteTTL: remoteTTL, data: make(map[string][]byte)})
+		}
+		groups[i].data[key] = value
+	}
+	return groups
+}
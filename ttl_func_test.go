@@ -0,0 +1,118 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_WithTTLFunc(t *testing.T) {
+	ctx := context.Background()
+	newCache := func(t *testing.T) (*LayeredCache, *ttlRecordingMemory) {
+		memory := &ttlRecordingMemory{Memory: createOtterAdapter(t), ttls: make(map[string]time.Duration)}
+		cache, err := NewCache(
+			WithConfigMemory(memory),
+			WithConfigRemote(createRemoteAdapter(t)),
+			WithConfigDefaultTTL(time.Minute, time.Hour),
+		)
+		assert.NoError(t, err)
+		return cache.(*LayeredCache), memory
+	}
+
+	// 归档数据使用较长的 TTL，其他使用默认值
+	ttlFunc := WithTTLFunc(func(key string, value any) (time.Duration, time.Duration) {
+		if strings.HasPrefix(value.(string), "archived") {
+			return 10 * time.Minute, 24 * time.Hour
+		}
+		return 0, 0
+	})
+
+	assertTTL := func(t *testing.T, c *LayeredCache, memory *ttlRecordingMemory, key string, memoryTTL, remoteTTL time.Duration) {
+		t.Helper()
+		assert.Equal(t, memoryTTL, memory.ttls[key])
+		ttl, err := c.remote.TTL(ctx, key)
+		assert.NoError(t, err)
+		assert.Equal(t, remoteTTL, ttl)
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		c, memory := newCache(t)
+		loader := func(ctx context.Context, key string) (any, error) {
+			if key == "order:1" {
+				return "archived order", nil
+			}
+			return "active order", nil
+		}
+
+		var result string
+		assert.NoError(t, c.Get(ctx, "order:1", &result, WithLoader(loader), ttlFunc))
+		assert.NoError(t, c.Get(ctx, "order:2", &result, WithLoader(loader), ttlFunc))
+
+		assertTTL(t, c, memory, "order:1", 10*time.Minute, 24*time.Hour)
+		assertTTL(t, c, memory, "order:2", time.Minute, time.Hour)
+	})
+
+	t.Run("MGet", func(t *testing.T) {
+		c, memory := newCache(t)
+		batchLoader := func(ctx context.Context, keys []string) (map[string]any, error) {
+			return map[string]any{
+				"order:1": "archived order",
+				"order:2": "active order",
+				"order:3": "archived order",
+			}, nil
+		}
+
+		result := make(map[string]string)
+		assert.NoError(t, c.MGet(ctx, []string{"order:1", "order:2", "order:3"}, &result, WithBatchLoader(batchLoader), ttlFunc))
+		assert.Len(t, result, 3)
+
+		assertTTL(t, c, memory, "order:1", 10*time.Minute, 24*time.Hour)
+		assertTTL(t, c, memory, "order:2", time.Minute, time.Hour)
+		assertTTL(t, c, memory, "order:3", 10*time.Minute, 24*time.Hour)
+	})
+
+	t.Run("Set 和 MSet", func(t *testing.T) {
+		c, memory := newCache(t)
+		remote := &ttlBatchRemote{Remote: c.remote}
+		c.remote = remote
+
+		assert.NoError(t, c.Set(ctx, "order:0", "archived order", ttlFunc))
+		assertTTL(t, c, memory, "order:0", 10*time.Minute, 24*time.Hour)
+
+		err := c.MSet(ctx, map[string]any{
+			"order:1": "archived order",
+			"order:2": "active order",
+		}, ttlFunc)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, remote.calls, "不同过期时间的键合并为一次写入")
+
+		assertTTL(t, c, memory, "order:1", 10*time.Minute, 24*time.Hour)
+		assertTTL(t, c, memory, "order:2", time.Minute, time.Hour)
+	})
+
+	t.Run("选项指定的TTL作为兜底", func(t *testing.T) {
+		c, memory := newCache(t)
+		loader := func(ctx context.Context, key string) (any, error) {
+			return "active order", nil
+		}
+
+		var result string
+		assert.NoError(t, c.Get(ctx, "order:1", &result, WithLoader(loader), WithTTL(2*time.Minute, 2*time.Hour), ttlFunc))
+		assertTTL(t, c, memory, "order:1", 2*time.Minute, 2*time.Hour)
+	})
+}
+
+// ttlBatchRemote 记录 MSetWithTTLs 调用次数的 Remote
+type ttlBatchRemote struct {
+	storage.Remote
+	calls int
+}
+
+func (r *ttlBatchRemote) MSetWithTTLs(ctx context.Context, entries map[string]storage.ValueWithTTL) error {
+	r.calls++
+	return r.Remote.(storage.MultiTTLSetter).MSetWithTTLs(ctx, entries)
+}
@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// jitterBuckets 批量写入时按不同 TTL 拆分的最大组数
+const jitterBuckets = 8
+
+// jitter 在 ±ttlJitter 范围内随机调整 ttl
+func (c *LayeredCache) jitter(ttl time.Duration) time.Duration {
+	return ttl + time.Duration(float64(ttl)*c.ttlJitter*(2*rand.Float64()-1))
+}
+
+// jitterTTL 对单次写入的 TTL 随机抖动，内存 TTL 仅在开启 memoryTTLJitter 时抖动
+func (c *LayeredCache) jitterTTL(memoryTTL, remoteTTL time.Duration) (time.Duration, time.Duration) {
+	if c.ttlJitter <= 0 {
+		return memoryTTL, remoteTTL
+	}
+	if c.memoryTTLJitter {
+		memoryTTL = c.jitter(memoryTTL)
+	}
+	return memoryTTL, c.jitter(remoteTTL)
+}
+
+// jitterGroups 将每组数据随机拆分为最多 jitterBuckets 组，每组使用独立抖动后的 TTL
+func (c *LayeredCache) jitterGroups(groups []ttlGroup) []ttlGroup {
+	if c.ttlJitter <= 0 {
+		return groups
+	}
+
+	var ret []ttlGroup
+	for _, group := range groups {
+		buckets := make([]ttlGroup, min(len(group.data), jitterBuckets))
+		for i := range buckets {
+			memoryTTL, remoteTTL := c.jitterTTL(group.memoryTTL, group.remoteTTL)
+			buckets[i] = ttlGroup{memoryTTL: memoryTTL, remoteTTL: remoteTTL, data: make(map[string][]byte)}
+		}
+		for key, data := range group.data {
+			buckets[rand.IntN(len(buckets))].data[key] = data
+		}
+		for _, bucket := range buckets {
+			if len(bucket.data) > 0 {
+				ret = append(ret, bucket)
+			}
+		}
+	}
+	return ret
+}
@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_TTLJitter(t *testing.T) {
+	ctx := context.Background()
+	newCache := func(t *testing.T, opts ...Option) (*LayeredCache, *ttlRecordingMemory) {
+		memory := &ttlRecordingMemory{Memory: createOtterAdapter(t), ttls: make(map[string]time.Duration)}
+		opts = append([]Option{
+			WithConfigMemory(memory),
+			WithConfigRemote(createRemoteAdapter(t)),
+			WithConfigDefaultTTL(time.Minute, time.Hour),
+		}, opts...)
+		cache, err := NewCache(opts...)
+		assert.NoError(t, err)
+		return cache.(*LayeredCache), memory
+	}
+
+	remoteTTLs := func(t *testing.T, c *LayeredCache, keys []string) map[time.Duration]int {
+		ttls := make(map[time.Duration]int)
+		for _, key := range keys {
+			ttl, err := c.remote.TTL(ctx, key)
+			assert.NoError(t, err)
+			assert.GreaterOrEqual(t, ttl, 48*time.Minute)
+			assert.LessOrEqual(t, ttl, 72*time.Minute)
+			ttls[ttl]++
+		}
+		return ttls
+	}
+
+	keys := make([]string, 100)
+	values := make(map[string]any, len(keys))
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key:%d", i)
+		values[keys[i]] = "v"
+	}
+
+	t.Run("配置校验", func(t *testing.T) {
+		for _, fraction := range []float64{-0.1, 1} {
+			_, err := NewCache(WithConfigRemote(createRemoteAdapter(t)), WithConfigTTLJitter(fraction))
+			assert.ErrorIs(t, err, errors.ErrInvalidTTLJitter)
+		}
+	})
+
+	t.Run("MSet的键分散过期", func(t *testing.T) {
+		c, memory := newCache(t, WithConfigTTLJitter(0.2))
+		assert.NoError(t, c.MSet(ctx, values))
+
+		ttls := remoteTTLs(t, c, keys)
+		assert.Greater(t, len(ttls), 1)
+		assert.LessOrEqual(t, len(ttls), jitterBuckets)
+
+		// 默认不抖动内存 TTL
+		for _, key := range keys {
+			assert.Equal(t, time.Minute, memory.ttls[key])
+		}
+	})
+
+	t.Run("批量加载的键分散过期", func(t *testing.T) {
+		c, _ := newCache(t, WithConfigTTLJitter(0.2))
+		result := make(map[string]string)
+		assert.NoError(t, c.MGet(ctx, keys, &result, WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+			return values, nil
+		})))
+		assert.Greater(t, len(remoteTTLs(t, c, keys)), 1)
+	})
+
+	t.Run("内存TTL抖动", func(t *testing.T) {
+		c, memory := newCache(t, WithConfigTTLJitter(0.2), WithConfigMemoryTTLJitter(true))
+		for _, key := range keys[:20] {
+			assert.NoError(t, c.Set(ctx, key, "v"))
+		}
+
+		distinct := make(map[time.Duration]struct{})
+		for _, key := range keys[:20] {
+			ttl := memory.ttls[key]
+			assert.GreaterOrEqual(t, ttl, 48*time.Second)
+			assert.LessOrEqual(t, ttl, 72*time.Second)
+			distinct[ttl] = struct{}{}
+		}
+		assert.Greater(t, len(distinct), 1)
+		assert.Greater(t, len(remoteTTLs(t, c, keys[:20])), 1)
+	})
+
+	t.Run("默认关闭", func(t *testing.T) {
+		c, _ := newCache(t)
+		assert.NoError(t, c.MSet(ctx, values))
+		assert.Equal(t, map[time.Duration]int{time.Hour: len(keys)}, remoteTTLs(t, c, keys))
+	})
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/biu7/layered-cache/serializer"
+)
+
+// typeRegistry 类型名称与具体类型的双向映射，用于把值还原到接口类型的 target
+var typeRegistry = struct {
+	sync.RWMutex
+	byName map[string]reflect.Type
+	byType map[reflect.Type]string
+}{
+	byName: make(map[string]reflect.Type),
+	byType: make(map[reflect.Type]string),
+}
+
+// RegisterType 注册类型 T 的名称
+// 开启封装格式（WithConfigEnvelope）后，写入已注册类型的值时会记录类型名称，
+// 读取到 *any 或接口类型的 target 时按名称还原为具体类型，而不是通用的 map；
+// 同一名称或同一类型重复注册为不同的值时 panic
+func RegisterType[T any](name string) {
+	t := reflect.TypeFor[T]()
+	if name == "" {
+		panic("layered-cache: RegisterType name must not be empty")
+	}
+	if len(name) > maxTypeNameLen {
+		panic(fmt.Sprintf("layered-cache: RegisterType name %q is longer than %d bytes", name, maxTypeNameLen))
+	}
+
+	typeRegistry.Lock()
+	defer typeRegistry.Unlock()
+
+	if registered, ok := typeRegistry.byName[name]; ok && registered != t {
+		panic(fmt.Sprintf("layered-cache: type name %q already registered for %v", name, registered))
+	}
+	if registered, ok := typeRegistry.byType[t]; ok && registered != name {
+		panic(fmt.Sprintf("layered-cache: type %v already registered as %q", t, registered))
+	}
+	typeRegistry.byName[name] = t
+	typeRegistry.byType[t] = name
+}
+
+// registeredName 返回值的注册类型名称，未注册时返回空字符串
+func registeredName(value any) string {
+	if value == nil {
+		return ""
+	}
+
+	typeRegistry.RLock()
+	defer typeRegistry.RUnlock()
+	return typeRegistry.byType[reflect.TypeOf(value)]
+}
+
+// registeredType 返回名称对应的注册类型
+func registeredType(name string) (reflect.Type, bool) {
+	typeRegistry.RLock()
+	defer typeRegistry.RUnlock()
+	t, ok := typeRegistry.byName[name]
+	return t, ok
+}
+
+// decodeTyped 按类型名称将数据还原到接口类型的 target，返回 false 表示不适用
+func (c *LayeredCache) decodeTyped(env envelope, target any, s serializer.Serializer) (bool, error) {
+	targetValue := reflect.ValueOf(target)
+	if targetValue.Kind() != reflect.Ptr || targetValue.IsNil() || targetValue.Elem().Kind() != reflect.Interface {
+		return false, nil
+	}
+
+	t, ok := registeredType(env.typeName)
+	if !ok || !t.AssignableTo(targetValue.Elem().Type()) {
+		return false, nil
+	}
+
+	value := reflect.New(t)
+	if err := c.unmarshalEnvelope(env, value.Interface(), s); err != nil {
+		return true, err
+	}
+	targetValue.Elem().Set(value.Elem())
+	return true, nil
+}
@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type feedItem interface {
+	Kind() string
+}
+
+type feedVideo struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+func (v *feedVideo) Kind() string { return "video" }
+
+type feedArticle struct {
+	ID     int    `json:"id"`
+	Author string `json:"author"`
+}
+
+func (a feedArticle) Kind() string { return "article" }
+
+func init() {
+	RegisterType[*feedVideo]("test.feedVideo")
+	RegisterType[feedArticle]("test.feedArticle")
+	RegisterType[TestUser]("test.user")
+}
+
+func TestRegisterType(t *testing.T) {
+	t.Run("重复注册相同类型", func(t *testing.T) {
+		assert.NotPanics(t, func() { RegisterType[TestUser]("test.user") })
+	})
+
+	t.Run("名称已被其他类型占用", func(t *testing.T) {
+		assert.Panics(t, func() { RegisterType[TestProduct]("test.user") })
+	})
+
+	t.Run("类型已注册为其他名称", func(t *testing.T) {
+		assert.Panics(t, func() { RegisterType[TestUser]("test.user2") })
+	})
+
+	t.Run("空名称", func(t *testing.T) {
+		assert.Panics(t, func() { RegisterType[TestOrder]("") })
+	})
+}
+
+func TestLayeredCache_GetInterfaceTarget(t *testing.T) {
+	ctx := context.Background()
+	cache, err := NewCache(
+		WithConfigMemory(createOtterAdapter(t)),
+		WithConfigRemote(createRemoteAdapter(t)),
+		WithConfigEnvelope(true),
+	)
+	assert.NoError(t, err)
+	c := cache.(*LayeredCache)
+
+	assert.NoError(t, c.Set(ctx, "feed:1", &feedVideo{ID: 1, Title: "Intro"}))
+	assert.NoError(t, c.Set(ctx, "feed:2", feedArticle{ID: 2, Author: "Alice"}))
+	assert.NoError(t, c.Set(ctx, "feed:3", TestProduct{ID: 3, Name: "Book"}))
+
+	t.Run("读取到any", func(t *testing.T) {
+		var result any
+		assert.NoError(t, c.Get(ctx, "feed:1", &result))
+		assert.Equal(t, &feedVideo{ID: 1, Title: "Intro"}, result)
+	})
+
+	t.Run("读取到接口类型", func(t *testing.T) {
+		var item feedItem
+		assert.NoError(t, c.Get(ctx, "feed:2", &item))
+		assert.Equal(t, feedArticle{ID: 2, Author: "Alice"}, item)
+	})
+
+	t.Run("读取到具体类型不受影响", func(t *testing.T) {
+		var video feedVideo
+		assert.NoError(t, c.Get(ctx, "feed:1", &video))
+		assert.Equal(t, feedVideo{ID: 1, Title: "Intro"}, video)
+	})
+
+	t.Run("未注册的类型仍为通用map", func(t *testing.T) {
+		var result any
+		assert.NoError(t, c.Get(ctx, "feed:3", &result))
+		assert.IsType(t, map[string]any{}, result)
+	})
+
+	t.Run("MGet读取异构数据", func(t *testing.T) {
+		items := make(map[string]feedItem)
+		assert.NoError(t, c.MGet(ctx, []string{"feed:1", "feed:2"}, &items))
+		assert.Equal(t, "video", items["feed:1"].Kind())
+		assert.Equal(t, "article", items["feed:2"].Kind())
+	})
+}
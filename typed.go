@@ -5,35 +5,103 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+
+	"github.com/biu7/layered-cache/errors"
 )
 
 const separator = ":"
 
 type TypedCache[ID comparable, T any] struct {
 	cache Cache
+
+	// devMode 继承自底层缓存的开发模式
+	devMode bool
+
+	// keyBuilder 自定义的键生成函数，为 nil 时使用 keyPrefix + ":" + ID
+	keyBuilder KeyBuilder[ID]
 }
 
-func Typed[ID comparable, T any](cache Cache) *TypedCache[ID, T] {
-	return &TypedCache[ID, T]{cache: cache}
+func Typed[ID comparable, T any](cache Cache, opts ...TypedOption[ID]) *TypedCache[ID, T] {
+	config := &typedOptions[ID]{}
+	for _, opt := range opts {
+		opt.applyTyped(config)
+	}
+
+	typed := &TypedCache[ID, T]{cache: cache, keyBuilder: config.keyBuilder}
+	if lc, ok := cache.(*LayeredCache); ok {
+		typed.devMode = lc.devMode
+	}
+	return typed
 }
 
 type TypedLoaderFunc[ID comparable, T any] func(ctx context.Context, id ID) (T, error)
 
 type TypedBatchLoaderFunc[ID comparable, T any] func(ctx context.Context, ids []ID) (map[ID]T, error)
 
-func (c *TypedCache[ID, T]) Get(ctx context.Context, keyPrefix string, id ID, loader TypedLoaderFunc[ID, T], opts ...GetOption) (T, error) {
+func (c *TypedCache[ID, T]) Get(ctx context.Context, keyPrefix string, id ID, loader TypedLoaderFunc[ID, T], opts ...TypedGetOption) (T, error) {
+	getOpts := getOptionsOf(opts)
 	if loader != nil {
-		opts = append(opts, WithLoader(func(ctx context.Context, _ string) (any, error) {
+		getOpts = append(getOpts, WithLoader(func(ctx context.Context, _ string) (any, error) {
 			return loader(ctx, id)
 		}))
 	}
 
 	var result T
-	err := c.cache.Get(ctx, c.buildKey(keyPrefix, id), &result, opts...)
+	err := c.cache.Get(ctx, c.buildKey(keyPrefix, id), &result, getOpts...)
 	return result, err
 }
 
-func (c *TypedCache[ID, T]) MGet(ctx context.Context, keyPrefix string, ids []ID, loader TypedBatchLoaderFunc[ID, T], opts ...GetOption) (map[ID]T, error) {
+func (c *TypedCache[ID, T]) MGet(ctx context.Context, keyPrefix string, ids []ID, loader TypedBatchLoaderFunc[ID, T], opts ...TypedMGetOption) (map[ID]T, error) {
+	keys, key2ID, getOpts := c.buildBatch(keyPrefix, ids, loader, opts)
+
+	var ret = make(map[string]T)
+	err := c.cache.MGet(ctx, keys, &ret, getOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.toIDMap(ret, key2ID), nil
+}
+
+// GetOrLoadMany 与 MGet 相同，但 loader 额外返回的 ID（不在 ids 中）也会写入缓存，
+// 适用于加载父对象时一并返回所有子对象的场景；额外的 ID 不会出现在返回结果中
+func (c *TypedCache[ID, T]) GetOrLoadMany(ctx context.Context, keyPrefix string, ids []ID, loader TypedBatchLoaderFunc[ID, T], opts ...TypedMGetOption) (map[ID]T, error) {
+	return c.MGet(ctx, keyPrefix, ids, loader, append(opts, WithCacheExtra(true))...)
+}
+
+// Fetch 构建一个可与其他 TypedCache 合并执行的批量读取，配合 Cache.MultiFetch 使用
+func (c *TypedCache[ID, T]) Fetch(keyPrefix string, ids []ID, loader TypedBatchLoaderFunc[ID, T], opts ...TypedMGetOption) *TypedFetch[ID, T] {
+	keys, key2ID, getOpts := c.buildBatch(keyPrefix, ids, loader, opts)
+
+	f := &TypedFetch[ID, T]{
+		typed:  c,
+		key2ID: key2ID,
+		values: make(map[string]T),
+	}
+	f.request = FetchRequest{Keys: keys, Target: &f.values, Options: getOpts}
+	return f
+}
+
+// TypedFetch TypedCache 参与 MultiFetch 的批量读取
+type TypedFetch[ID comparable, T any] struct {
+	typed   *TypedCache[ID, T]
+	request FetchRequest
+	key2ID  map[string]ID
+	values  map[string]T
+}
+
+// Request 返回传给 MultiFetch 的请求
+func (f *TypedFetch[ID, T]) Request() FetchRequest {
+	return f.request
+}
+
+// Result 返回 MultiFetch 执行后的结果
+func (f *TypedFetch[ID, T]) Result() map[ID]T {
+	return f.typed.toIDMap(f.values, f.key2ID)
+}
+
+// buildBatch 构建批量读取的键、键到 ID 的映射以及包装后的 batchLoader 选项
+func (c *TypedCache[ID, T]) buildBatch(keyPrefix string, ids []ID, loader TypedBatchLoaderFunc[ID, T], opts []TypedMGetOption) ([]string, map[string]ID, []GetOption) {
 	var keys = make([]string, 0, len(ids))
 	var key2ID = make(map[string]ID, len(ids))
 
@@ -43,8 +111,9 @@ func (c *TypedCache[ID, T]) MGet(ctx context.Context, keyPrefix string, ids []ID
 		key2ID[key] = id
 	}
 
+	getOpts := getOptionsOf(opts)
 	if loader != nil {
-		opts = append(opts, WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+		getOpts = append(getOpts, WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
 			var loaderIds = make([]ID, 0, len(keys))
 			for _, key := range keys {
 				loaderIds = append(loaderIds, key2ID[key])
@@ -62,18 +131,16 @@ func (c *TypedCache[ID, T]) MGet(ctx context.Context, keyPrefix string, ids []ID
 		}))
 	}
 
-	var ret = make(map[string]T)
-	err := c.cache.MGet(ctx, keys, &ret, opts...)
-	if err != nil {
-		return nil, err
-	}
+	return keys, key2ID, getOpts
+}
 
-	var result = make(map[ID]T, len(ret))
-	for key, value := range ret {
+// toIDMap 将以键为索引的结果转换为以 ID 为索引
+func (c *TypedCache[ID, T]) toIDMap(values map[string]T, key2ID map[string]ID) map[ID]T {
+	var result = make(map[ID]T, len(values))
+	for key, value := range values {
 		result[key2ID[key]] = value
 	}
-
-	return result, nil
+	return result
 }
 
 func (c *TypedCache[ID, T]) Set(ctx context.Context, keyPrefix string, id ID, value T, opts ...SetOption) error {
@@ -92,23 +159,50 @@ func (c *TypedCache[ID, T]) Delete(ctx context.Context, keyPrefix string, id ID)
 	return c.cache.Delete(ctx, c.buildKey(keyPrefix, id))
 }
 
+func (c *TypedCache[ID, T]) MDelete(ctx context.Context, keyPrefix string, ids []ID) error {
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, c.buildKey(keyPrefix, id))
+	}
+	return c.cache.MDelete(ctx, keys)
+}
+
+// DeleteAll 删除 keyPrefix 下的所有缓存值，即所有以 keyPrefix + ":" 开头的键
+// 设置了自定义键生成方式时键不一定以 keyPrefix + ":" 开头，返回 ErrCustomKeyLayout
+func (c *TypedCache[ID, T]) DeleteAll(ctx context.Context, keyPrefix string) error {
+	if c.keyBuilder != nil {
+		return errors.ErrCustomKeyLayout
+	}
+	return c.cache.DeleteByPrefix(ctx, keyPrefix+separator)
+}
+
 func (c *TypedCache[ID, T]) buildKey(keyPrefix string, id ID) string {
+	if c.keyBuilder != nil {
+		return c.keyBuilder(keyPrefix, id)
+	}
+
+	c.checkID(keyPrefix, id)
+
 	var builder strings.Builder
 	builder.WriteString(keyPrefix)
 	builder.WriteString(separator)
+	builder.WriteString(formatID(id))
+	return builder.String()
+}
 
+// formatID 将 ID 格式化为键中的字符串
+func formatID[ID comparable](id ID) string {
 	switch v := any(id).(type) {
 	case string:
-		builder.WriteString(v)
+		return v
 	case int:
-		builder.WriteString(strconv.FormatInt(int64(v), 10))
+		return strconv.FormatInt(int64(v), 10)
 	case int32:
-		builder.WriteString(strconv.FormatInt(int64(v), 10))
+		return strconv.FormatInt(int64(v), 10)
 	case int64:
-		builder.WriteString(strconv.FormatInt(v, 10))
+		return strconv.FormatInt(v, 10)
 	default:
 		// 以上足够覆盖 99% 的场景，其他类型直接 fmt 处理
-		builder.WriteString(fmt.Sprintf("%v", v))
+		return fmt.Sprintf("%v", v)
 	}
-	return builder.String()
 }
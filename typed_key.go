@@ -0,0 +1,39 @@
+package cache
+
+import "strings"
+
+// KeyBuilder 按 keyPrefix 和 ID 生成缓存键
+type KeyBuilder[ID comparable] func(keyPrefix string, id ID) string
+
+// TypedOption TypedCache 的配置
+type TypedOption[ID comparable] interface {
+	applyTyped(*typedOptions[ID])
+}
+
+// typedOptions TypedCache 配置
+type typedOptions[ID comparable] struct {
+	keyBuilder KeyBuilder[ID]
+}
+
+// withKeyBuilder 设置自定义的键生成函数
+type withKeyBuilder[ID comparable] struct {
+	build KeyBuilder[ID]
+}
+
+func (w withKeyBuilder[ID]) applyTyped(opts *typedOptions[ID]) {
+	opts.keyBuilder = w.build
+}
+
+// WithKeyBuilder 设置自定义的键生成函数，用于沿用已有系统的 Redis 键命名规则
+// 设置后 DeleteAll 不可用，返回 ErrCustomKeyLayout
+func WithKeyBuilder[ID comparable](build KeyBuilder[ID]) TypedOption[ID] {
+	return withKeyBuilder[ID]{build: build}
+}
+
+// WithKeyTemplate 按模板生成键，模板中的 {prefix} 替换为 keyPrefix，{id} 替换为 ID，
+// 例如 "{prefix}:{id}:profile" 或 "user:{id}:profile"；ID 的格式化方式与默认的 keyPrefix:ID 相同
+func WithKeyTemplate[ID comparable](template string) TypedOption[ID] {
+	return withKeyBuilder[ID]{build: func(keyPrefix string, id ID) string {
+		return strings.NewReplacer("{prefix}", keyPrefix, "{id}", formatID(id)).Replace(template)
+	}}
+}
@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypedCache_KeyBuilder(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("按模板生成键", func(t *testing.T) {
+		c := createTestCache(t)
+		profiles := Typed[int64, string](c, WithKeyTemplate[int64]("{prefix}:{id}:profile"))
+		assert.NoError(t, profiles.Set(ctx, "user", 42, "alice"))
+
+		var value string
+		assert.NoError(t, c.Get(ctx, "user:42:profile", &value))
+		assert.Equal(t, "alice", value)
+
+		values, err := profiles.MGet(ctx, "user", []int64{42}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, map[int64]string{42: "alice"}, values)
+	})
+
+	t.Run("自定义键生成函数", func(t *testing.T) {
+		c := createTestCache(t)
+		legacy := NewTypedWithPrefix[int, string](c, "order", WithKeyBuilder(func(keyPrefix string, id int) string {
+			return fmt.Sprintf("legacy_%s_%06d", keyPrefix, id)
+		}))
+		assert.NoError(t, legacy.Set(ctx, 7, "v"))
+
+		var value string
+		assert.NoError(t, c.Get(ctx, "legacy_order_000007", &value))
+		assert.Equal(t, "v", value)
+
+		assert.ErrorIs(t, legacy.DeleteAll(ctx), errors.ErrCustomKeyLayout)
+	})
+}
@@ -0,0 +1,81 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// withNotFoundCached 设置批量读取命中缺失值标记时的回调
+type withNotFoundCached struct {
+	batchOption
+
+	fn func(key string)
+}
+
+func (w withNotFoundCached) applyGet(cfg *getOptions) {
+	cfg.notFoundCached = w.fn
+}
+
+// reportNotFoundCached 记录命中缺失值标记的键
+func (cfg *getOptions) reportNotFoundCached(key string) {
+	if cfg.notFoundCached != nil {
+		cfg.notFoundCached(key)
+	}
+}
+
+// WithTypedCacheNotFound 开启本次读取的缺失值缓存，loader 没有返回的 ID 写入过期时间为 ttl 的缺失值标记，
+// 等同于 WithCacheNotFound(true, ttl)；ttl 小于 0 时使用默认值
+func WithTypedCacheNotFound(ttl time.Duration) ReadOption {
+	return withCacheNotFound{cacheNotFound: true, cacheNotFoundTTL: ttl}
+}
+
+// TypedMisses TypedCache.MGetWithMisses 中没有返回值的 ID，按请求的顺序排列
+type TypedMisses[ID comparable] struct {
+	// Cached 命中缺失值标记的 ID，此前已确认不存在，本次没有调用 loader
+	Cached []ID
+
+	// Absent 其余没有值的 ID：缓存中没有，loader 也没有返回
+	Absent []ID
+}
+
+// MGetWithMisses 与 MGet 相同，同时返回没有值的 ID，并区分命中缺失值标记的 ID 和本次确认不存在的 ID，
+// 便于构建 API 响应时区分不同的缺失原因
+func (c *TypedCache[ID, T]) MGetWithMisses(ctx context.Context, keyPrefix string, ids []ID, loader TypedBatchLoaderFunc[ID, T], opts ...TypedMGetOption) (map[ID]T, TypedMisses[ID], error) {
+	var mu sync.Mutex
+	cached := make(map[string]struct{})
+	keys, key2ID, getOpts := c.buildBatch(keyPrefix, ids, loader, opts)
+	getOpts = append(getOpts, withNotFoundCached{fn: func(key string) {
+		mu.Lock()
+		cached[key] = struct{}{}
+		mu.Unlock()
+	}})
+
+	var ret = make(map[string]T)
+	if err := c.cache.MGet(ctx, keys, &ret, getOpts...); err != nil {
+		return nil, TypedMisses[ID]{}, err
+	}
+
+	var misses TypedMisses[ID]
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		if _, ok := ret[key]; ok {
+			continue
+		}
+		if _, ok := cached[key]; ok {
+			misses.Cached = append(misses.Cached, key2ID[key])
+		} else {
+			misses.Absent = append(misses.Absent, key2ID[key])
+		}
+	}
+	return c.toIDMap(ret, key2ID), misses, nil
+}
+
+// MGetWithMisses 见 TypedCache.MGetWithMisses
+func (c *PrefixedTypedCache[ID, T]) MGetWithMisses(ctx context.Context, ids []ID, loader TypedBatchLoaderFunc[ID, T], opts ...TypedMGetOption) (map[ID]T, TypedMisses[ID], error) {
+	return c.typed.MGetWithMisses(ctx, c.keyPrefix, ids, loader, opts...)
+}
@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTypedCache_MGetWithMisses(t *testing.T) {
+	ctx := context.Background()
+	loader := func(ctx context.Context, ids []int) (map[int]string, error) {
+		values := make(map[int]string)
+		for _, id := range ids {
+			if id == 1 {
+				values[id] = "one"
+			}
+		}
+		return values, nil
+	}
+
+	t.Run("区分缺失值标记和本次确认不存在", func(t *testing.T) {
+		typed := Typed[int, string](createTestCache(t))
+
+		// 首次加载时 2 写入缺失值标记
+		_, err := typed.MGet(ctx, "n", []int{2}, loader, WithTypedCacheNotFound(time.Minute))
+		assert.NoError(t, err)
+
+		values, misses, err := typed.MGetWithMisses(ctx, "n", []int{1, 2, 3, 3}, loader)
+		assert.NoError(t, err)
+		assert.Equal(t, map[int]string{1: "one"}, values)
+		assert.Equal(t, []int{2}, misses.Cached)
+		assert.Equal(t, []int{3}, misses.Absent)
+	})
+
+	t.Run("键前缀隔离", func(t *testing.T) {
+		typed := NewTypedWithPrefix[int, string](createTestCache(t), "n")
+		scoped := WithKeyContext(ctx, "tenant:")
+
+		_, err := typed.MGet(scoped, []int{2}, loader, WithTypedCacheNotFound(time.Minute))
+		assert.NoError(t, err)
+
+		_, misses, err := typed.MGetWithMisses(scoped, []int{2}, loader)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{2}, misses.Cached)
+		assert.Empty(t, misses.Absent)
+	})
+}
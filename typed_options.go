@@ -0,0 +1,42 @@
+package cache
+
+// TypedGetOption TypedCache.Get 可用的选项
+// loader 通过 Get 的参数传入，WithLoader、WithBatchLoader 以及只对批量读取生效的 WithCacheExtra 无法传入，
+// 避免这类选项在编译期无法发现、运行时被静默忽略
+type TypedGetOption interface {
+	GetOption
+	typedGet()
+}
+
+// TypedMGetOption TypedCache.MGet、GetOrLoadMany、Fetch 可用的选项
+// batchLoader 通过参数传入，WithLoader、WithBatchLoader 无法传入
+type TypedMGetOption interface {
+	GetOption
+	typedMGet()
+}
+
+// ReadOption 单键和批量读取都可用的选项
+type ReadOption interface {
+	TypedGetOption
+	TypedMGetOption
+}
+
+// readOption 嵌入到单键和批量读取都生效的选项中
+type readOption struct{}
+
+func (readOption) typedGet()  {}
+func (readOption) typedMGet() {}
+
+// batchOption 嵌入到只对批量读取生效的选项中
+type batchOption struct{}
+
+func (batchOption) typedMGet() {}
+
+// getOptionsOf 将 TypedCache 的选项转换为 GetOption，预留一个位置给包装后的 loader
+func getOptionsOf[O GetOption](opts []O) []GetOption {
+	ret := make([]GetOption, 0, len(opts)+1)
+	for _, opt := range opts {
+		ret = append(ret, opt)
+	}
+	return ret
+}
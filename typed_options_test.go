@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 通用的读取选项同时满足 TypedGetOption 和 TypedMGetOption，WithCacheExtra 只满足 TypedMGetOption
+var (
+	_ ReadOption      = WithTTL(time.Minute, time.Hour)
+	_ ReadOption      = WithMemoryTTL(time.Minute)
+	_ ReadOption      = WithRemoteTTL(time.Hour)
+	_ ReadOption      = WithCacheNotFound(true, time.Minute)
+	_ ReadOption      = WithLoaderTimeout(time.Second, false)
+	_ ReadOption      = WithServeStale(true)
+	_ ReadOption      = WithReloadNotFound(true)
+	_ ReadOption      = WithTTLFunc(nil)
+	_ ReadOption      = WithShadowCompare(0.1, nil)
+	_ TypedMGetOption = WithCacheExtra(true)
+)
+
+func TestTypedCache_Options(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("单键读取的选项生效", func(t *testing.T) {
+		c := createTestCache(t)
+		typedCache := Typed[int, TestProduct](c)
+		notFound := func(ctx context.Context, id int) (TestProduct, error) {
+			return TestProduct{}, ErrNotFound
+		}
+
+		_, err := typedCache.Get(ctx, "product", 1, notFound, WithCacheNotFound(true, time.Minute))
+		assert.ErrorIs(t, err, ErrNotFound)
+		_, err = typedCache.Get(ctx, "product", 1, nil)
+		assert.ErrorIs(t, err, ErrNotFoundCached)
+
+		loaded, err := typedCache.Get(ctx, "product", 1, func(ctx context.Context, id int) (TestProduct, error) {
+			return TestProduct{ID: id}, nil
+		}, WithReloadNotFound(true), WithTTL(time.Minute, time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, TestProduct{ID: 1}, loaded)
+
+		ttl, err := c.(*LayeredCache).remote.TTL(ctx, "product:1")
+		assert.NoError(t, err)
+		assert.Equal(t, time.Hour, ttl)
+	})
+
+	t.Run("批量读取的选项生效", func(t *testing.T) {
+		typedCache := Typed[int, TestProduct](createTestCache(t))
+		loader := func(ctx context.Context, ids []int) (map[int]TestProduct, error) {
+			return map[int]TestProduct{1: {ID: 1}, 2: {ID: 2}}, nil
+		}
+
+		_, err := typedCache.MGet(ctx, "product", []int{1}, loader, WithCacheExtra(true), WithTTL(time.Minute, time.Hour))
+		assert.NoError(t, err)
+
+		result, err := typedCache.MGet(ctx, "product", []int{2}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, map[int]TestProduct{2: {ID: 2}}, result)
+	})
+}
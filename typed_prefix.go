@@ -0,0 +1,58 @@
+package cache
+
+import "context"
+
+// PrefixedTypedCache 绑定了 keyPrefix 的 TypedCache，调用时不再需要传入 keyPrefix，生成的键与 TypedCache 相同
+type PrefixedTypedCache[ID comparable, T any] struct {
+	typed     *TypedCache[ID, T]
+	keyPrefix string
+}
+
+// NewTypedWithPrefix 创建绑定 keyPrefix 的 TypedCache，例如 NewTypedWithPrefix[int64, User](c, "user")
+func NewTypedWithPrefix[ID comparable, T any](cache Cache, keyPrefix string, opts ...TypedOption[ID]) *PrefixedTypedCache[ID, T] {
+	return &PrefixedTypedCache[ID, T]{typed: Typed[ID, T](cache, opts...), keyPrefix: keyPrefix}
+}
+
+// KeyPrefix 返回绑定的 keyPrefix
+func (c *PrefixedTypedCache[ID, T]) KeyPrefix() string {
+	return c.keyPrefix
+}
+
+func (c *PrefixedTypedCache[ID, T]) Get(ctx context.Context, id ID, loader TypedLoaderFunc[ID, T], opts ...TypedGetOption) (T, error) {
+	return c.typed.Get(ctx, c.keyPrefix, id, loader, opts...)
+}
+
+func (c *PrefixedTypedCache[ID, T]) MGet(ctx context.Context, ids []ID, loader TypedBatchLoaderFunc[ID, T], opts ...TypedMGetOption) (map[ID]T, error) {
+	return c.typed.MGet(ctx, c.keyPrefix, ids, loader, opts...)
+}
+
+// GetOrLoadMany 见 TypedCache.GetOrLoadMany
+func (c *PrefixedTypedCache[ID, T]) GetOrLoadMany(ctx context.Context, ids []ID, loader TypedBatchLoaderFunc[ID, T], opts ...TypedMGetOption) (map[ID]T, error) {
+	return c.typed.GetOrLoadMany(ctx, c.keyPrefix, ids, loader, opts...)
+}
+
+// Fetch 见 TypedCache.Fetch
+func (c *PrefixedTypedCache[ID, T]) Fetch(ids []ID, loader TypedBatchLoaderFunc[ID, T], opts ...TypedMGetOption) *TypedFetch[ID, T] {
+	return c.typed.Fetch(c.keyPrefix, ids, loader, opts...)
+}
+
+func (c *PrefixedTypedCache[ID, T]) Set(ctx context.Context, id ID, value T, opts ...SetOption) error {
+	return c.typed.Set(ctx, c.keyPrefix, id, value, opts...)
+}
+
+func (c *PrefixedTypedCache[ID, T]) MSet(ctx context.Context, values map[ID]T, opts ...SetOption) error {
+	return c.typed.MSet(ctx, c.keyPrefix, values, opts...)
+}
+
+func (c *PrefixedTypedCache[ID, T]) Delete(ctx context.Context, id ID) error {
+	return c.typed.Delete(ctx, c.keyPrefix, id)
+}
+
+func (c *PrefixedTypedCache[ID, T]) MDelete(ctx context.Context, ids []ID) error {
+	return c.typed.MDelete(ctx, c.keyPrefix, ids)
+}
+
+// DeleteAll 删除绑定的 keyPrefix 下的所有缓存值
+func (c *PrefixedTypedCache[ID, T]) DeleteAll(ctx context.Context) error {
+	return c.typed.DeleteAll(ctx, c.keyPrefix)
+}
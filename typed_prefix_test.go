@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefixedTypedCache(t *testing.T) {
+	ctx := context.Background()
+	c := createTestCache(t)
+	products := NewTypedWithPrefix[int, TestProduct](c, "product")
+	assert.Equal(t, "product", products.KeyPrefix())
+
+	t.Run("与 TypedCache 使用相同的键", func(t *testing.T) {
+		assert.NoError(t, products.Set(ctx, 1, TestProduct{ID: 1, Name: "book"}))
+
+		value, err := Typed[int, TestProduct](c).Get(ctx, "product", 1, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "book", value.Name)
+
+		value, err = products.Get(ctx, 1, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "book", value.Name)
+	})
+
+	t.Run("批量读写和删除", func(t *testing.T) {
+		assert.NoError(t, products.MSet(ctx, map[int]TestProduct{2: {ID: 2}, 3: {ID: 3}}))
+
+		values, err := products.MGet(ctx, []int{2, 3, 4}, func(ctx context.Context, ids []int) (map[int]TestProduct, error) {
+			assert.Equal(t, []int{4}, ids)
+			return map[int]TestProduct{4: {ID: 4}}, nil
+		})
+		assert.NoError(t, err)
+		assert.Len(t, values, 3)
+
+		assert.NoError(t, products.Delete(ctx, 2))
+		assert.NoError(t, products.MDelete(ctx, []int{3}))
+		_, err = products.Get(ctx, 2, nil)
+		assert.ErrorIs(t, err, ErrNotFound)
+		_, err = products.Get(ctx, 3, nil)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
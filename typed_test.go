@@ -634,6 +634,48 @@ func TestTypedCache_MGet(t *testing.T) {
 	})
 }
 
+func TestTypedCache_GetOrLoadMany(t *testing.T) {
+	ctx := context.Background()
+
+	var loaderCalls int
+	loader := func(ctx context.Context, ids []int) (map[int]TestProduct, error) {
+		loaderCalls++
+		// 加载时一并返回同一分类下的所有商品
+		return map[int]TestProduct{
+			1: {ID: 1, Name: "A"},
+			2: {ID: 2, Name: "B"},
+			3: {ID: 3, Name: "C"},
+		}, nil
+	}
+
+	t.Run("额外返回的ID写入缓存", func(t *testing.T) {
+		loaderCalls = 0
+		typedCache := Typed[int, TestProduct](createTestCache(t))
+
+		result, err := typedCache.GetOrLoadMany(ctx, "product", []int{1}, loader)
+		assert.NoError(t, err)
+		assert.Equal(t, map[int]TestProduct{1: {ID: 1, Name: "A"}}, result)
+
+		result, err = typedCache.GetOrLoadMany(ctx, "product", []int{2, 3}, loader)
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, 1, loaderCalls)
+	})
+
+	t.Run("MGet默认丢弃额外返回的ID", func(t *testing.T) {
+		loaderCalls = 0
+		typedCache := Typed[int, TestProduct](createTestCache(t))
+
+		_, err := typedCache.MGet(ctx, "product", []int{1}, loader)
+		assert.NoError(t, err)
+
+		result, err := typedCache.MGet(ctx, "product", []int{2}, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+		assert.Equal(t, 1, loaderCalls)
+	})
+}
+
 func TestTypedCache_Delete(t *testing.T) {
 	ctx := context.Background()
 
@@ -716,6 +758,19 @@ func TestTypedCache_Delete(t *testing.T) {
 	})
 }
 
+func TestTypedCache_MDelete(t *testing.T) {
+	ctx := context.Background()
+	cache := createTestCache(t)
+	typedCache := Typed[int64, string](cache)
+
+	assert.NoError(t, typedCache.MSet(ctx, "user", map[int64]string{1: "a", 2: "b", 3: "c"}))
+	assert.NoError(t, typedCache.MDelete(ctx, "user", []int64{1, 2}))
+
+	result, err := typedCache.MGet(ctx, "user", []int64{1, 2, 3}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]string{3: "c"}, result)
+}
+
 func TestTypedCache_MemoryOnly(t *testing.T) {
 	ctx := context.Background()
 
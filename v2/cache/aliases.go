@@ -0,0 +1,204 @@
+// Code generated by go run ../internal/gen; DO NOT EDIT.
+
+package cache
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/biu7/layered-cache"
+	"github.com/biu7/layered-cache/serializer"
+)
+
+const (
+	ExistenceUnknown     = v1.ExistenceUnknown
+	ExistencePresent     = v1.ExistencePresent
+	ExistenceKnownAbsent = v1.ExistenceKnownAbsent
+	MetricOperations     = v1.MetricOperations
+	MetricKeys           = v1.MetricKeys
+	MetricHits           = v1.MetricHits
+	MetricErrors         = v1.MetricErrors
+	MetricDuration       = v1.MetricDuration
+	MetricSingleflight   = v1.MetricSingleflight
+	LabelLayer           = v1.LabelLayer
+	LabelOp              = v1.LabelOp
+	LabelPrefix          = v1.LabelPrefix
+	LabelShared          = v1.LabelShared
+	PrefixOther          = v1.PrefixOther
+	PrefixMixed          = v1.PrefixMixed
+	LayerMemory          = v1.LayerMemory
+	LayerRemote          = v1.LayerRemote
+	LayerLoader          = v1.LayerLoader
+	FailClosed           = v1.FailClosed
+	FailOpen             = v1.FailOpen
+	ValueSizeReject      = v1.ValueSizeReject
+	ValueSizeRemoteOnly  = v1.ValueSizeRemoteOnly
+	ValueSizeSkip        = v1.ValueSizeSkip
+	WriteThroughFailCall = v1.WriteThroughFailCall
+	WriteThroughAsync    = v1.WriteThroughAsync
+)
+
+var (
+	IsNotFound                      = v1.IsNotFound
+	WithDistributedSingleflight     = v1.WithDistributedSingleflight
+	WithKeyContext                  = v1.WithKeyContext
+	WithSkipLayers                  = v1.WithSkipLayers
+	WithOnlyMemory                  = v1.WithOnlyMemory
+	WithOnlyRedis                   = v1.WithOnlyRedis
+	WithConfigMemory                = v1.WithConfigMemory
+	WithConfigRemote                = v1.WithConfigRemote
+	WithConfigInvalidationTransport = v1.WithConfigInvalidationTransport
+	WithConfigLayers                = v1.WithConfigLayers
+	WithConfigSerializer            = v1.WithConfigSerializer
+	WithConfigDefaultTTL            = v1.WithConfigDefaultTTL
+	WithConfigDefaultCacheNotFound  = v1.WithConfigDefaultCacheNotFound
+	WithConfigReadRepair            = v1.WithConfigReadRepair
+	WithConfigDeleteShield          = v1.WithConfigDeleteShield
+	WithConfigDemoteOnEvict         = v1.WithConfigDemoteOnEvict
+	WithConfigCoalesceWrites        = v1.WithConfigCoalesceWrites
+	WithConfigExpvar                = v1.WithConfigExpvar
+	WithConfigDevMode               = v1.WithConfigDevMode
+	WithConfigStrictMemorySize      = v1.WithConfigStrictMemorySize
+	WithConfigStrictMemoryWrites    = v1.WithConfigStrictMemoryWrites
+	WithConfigMemoryCostFunc        = v1.WithConfigMemoryCostFunc
+	WithConfigDependencies          = v1.WithConfigDependencies
+	WithConfigMetricsCollector      = v1.WithConfigMetricsCollector
+	WithConfigMetricsPrefixes       = v1.WithConfigMetricsPrefixes
+	WithConfigLoaderPrefixes        = v1.WithConfigLoaderPrefixes
+	WithConfigLoaderBreaker         = v1.WithConfigLoaderBreaker
+	WithConfigRemoteBreaker         = v1.WithConfigRemoteBreaker
+	WithConfigLoaderRateLimit       = v1.WithConfigLoaderRateLimit
+	WithConfigWriteThrough          = v1.WithConfigWriteThrough
+	WithConfigDefaultLoaderTimeout  = v1.WithConfigDefaultLoaderTimeout
+	WithConfigAsyncRemoteWrites     = v1.WithConfigAsyncRemoteWrites
+	WithConfigCloseAdapters         = v1.WithConfigCloseAdapters
+	WithConfigRemoteFailurePolicy   = v1.WithConfigRemoteFailurePolicy
+	WithConfigTracerProvider        = v1.WithConfigTracerProvider
+	WithConfigRefreshAhead          = v1.WithConfigRefreshAhead
+	WithConfigPoisonThreshold       = v1.WithConfigPoisonThreshold
+	WithConfigBatchChunkSize        = v1.WithConfigBatchChunkSize
+	WithConfigRemoteConcurrency     = v1.WithConfigRemoteConcurrency
+	WithConfigKeyPrefix             = v1.WithConfigKeyPrefix
+	WithConfigKeyHasher             = v1.WithConfigKeyHasher
+	WithConfigAdaptiveBatch         = v1.WithConfigAdaptiveBatch
+	WithConfigSiblingPrefetch       = v1.WithConfigSiblingPrefetch
+	WithConfigHotKeys               = v1.WithConfigHotKeys
+	WithConfigMaxValueSize          = v1.WithConfigMaxValueSize
+	WithConfigWatchInterval         = v1.WithConfigWatchInterval
+	WithConfigTTLJitter             = v1.WithConfigTTLJitter
+	WithConfigMemoryTTLJitter       = v1.WithConfigMemoryTTLJitter
+	WithConfigValueMiddleware       = v1.WithConfigValueMiddleware
+	WithConfigInterceptor           = v1.WithConfigInterceptor
+	WithLoader                      = v1.WithLoader
+	WithBatchLoader                 = v1.WithBatchLoader
+	WithTTL                         = v1.WithTTL
+	WithMemoryTTL                   = v1.WithMemoryTTL
+	WithRemoteTTL                   = v1.WithRemoteTTL
+	WithCacheNotFound               = v1.WithCacheNotFound
+	WithCacheExtra                  = v1.WithCacheExtra
+	WithLoaderTimeout               = v1.WithLoaderTimeout
+	WithServeStale                  = v1.WithServeStale
+	WithMaxAge                      = v1.WithMaxAge
+	WithReloadNotFound              = v1.WithReloadNotFound
+	WithRecorder                    = v1.WithRecorder
+	WithSerializer                  = v1.WithSerializer
+	WithShadowCompare               = v1.WithShadowCompare
+	WithTags                        = v1.WithTags
+	WithTTLFunc                     = v1.WithTTLFunc
+	WithTypedCacheNotFound          = v1.WithTypedCacheNotFound
+	ValidateConfig                  = v1.ValidateConfig
+	NewValueMiddleware              = v1.NewValueMiddleware
+	ChecksumMiddleware              = v1.ChecksumMiddleware
+)
+
+type (
+	AutoBatcher                                = v1.AutoBatcher
+	LayeredCache                               = v1.LayeredCache
+	Page[T any]                                = v1.Page[T]
+	PageLoaderFunc[T any]                      = v1.PageLoaderFunc[T]
+	Cursor[T any]                              = v1.Cursor[T]
+	Existence                                  = v1.Existence
+	HotKey                                     = v1.HotKey
+	Operation                                  = v1.Operation
+	Invoker                                    = v1.Invoker
+	Interceptor                                = v1.Interceptor
+	LocalCache[ID comparable, T any]           = v1.LocalCache[ID, T]
+	LocalOption[T any]                         = v1.LocalOption[T]
+	Unlock                                     = v1.Unlock
+	MetricsCollector                           = v1.MetricsCollector
+	MetricLabels                               = v1.MetricLabels
+	LabeledCollector                           = v1.LabeledCollector
+	FetchRequest                               = v1.FetchRequest
+	FetchResult                                = v1.FetchResult
+	Option                                     = v1.Option
+	LoaderFunc                                 = v1.LoaderFunc
+	BatchLoaderFunc                            = v1.BatchLoaderFunc
+	GetOption                                  = v1.GetOption
+	SetOption                                  = v1.SetOption
+	Layer                                      = v1.Layer
+	Interaction                                = v1.Interaction
+	Recorder                                   = v1.Recorder
+	RefreshKeysFunc                            = v1.RefreshKeysFunc
+	RemoteFailurePolicy                        = v1.RemoteFailurePolicy
+	ShadowReporter                             = v1.ShadowReporter
+	View                                       = v1.View
+	Stats                                      = v1.Stats
+	TTLFunc                                    = v1.TTLFunc
+	TypedCache[ID comparable, T any]           = v1.TypedCache[ID, T]
+	TypedLoaderFunc[ID comparable, T any]      = v1.TypedLoaderFunc[ID, T]
+	TypedBatchLoaderFunc[ID comparable, T any] = v1.TypedBatchLoaderFunc[ID, T]
+	TypedFetch[ID comparable, T any]           = v1.TypedFetch[ID, T]
+	KeyBuilder[ID comparable]                  = v1.KeyBuilder[ID]
+	TypedOption[ID comparable]                 = v1.TypedOption[ID]
+	TypedMisses[ID comparable]                 = v1.TypedMisses[ID]
+	TypedGetOption                             = v1.TypedGetOption
+	TypedMGetOption                            = v1.TypedMGetOption
+	ReadOption                                 = v1.ReadOption
+	PrefixedTypedCache[ID comparable, T any]   = v1.PrefixedTypedCache[ID, T]
+	Report                                     = v1.Report
+	ValueMiddleware                            = v1.ValueMiddleware
+	ValueSizePolicy                            = v1.ValueSizePolicy
+	WriteThroughFunc                           = v1.WriteThroughFunc
+	WriteThroughReporter                       = v1.WriteThroughReporter
+	WriteThroughPolicy                         = v1.WriteThroughPolicy
+)
+
+func NewCursor[T any](cache Cache, namespace string) *Cursor[T] {
+	return v1.NewCursor[T](cache, namespace)
+}
+
+func Fetch[T any](ctx context.Context, c Cache, key string, loader func(ctx context.Context, key string) (T, error), opts ...GetOption) (T, error) {
+	return v1.Fetch[T](ctx, c, key, loader, opts...)
+}
+
+func WithClone[T any](clone func(T) T) LocalOption[T] {
+	return v1.WithClone[T](clone)
+}
+
+func NewLocal[ID comparable, T any](capacity int, ttl time.Duration, opts ...LocalOption[T]) (*LocalCache[ID, T], error) {
+	return v1.NewLocal[ID, T](capacity, ttl, opts...)
+}
+
+func RegisterSerializer[T any](s serializer.Serializer) {
+	v1.RegisterSerializer[T](s)
+}
+
+func RegisterType[T any](name string) {
+	v1.RegisterType[T](name)
+}
+
+func Typed[ID comparable, T any](cache Cache, opts ...TypedOption[ID]) *TypedCache[ID, T] {
+	return v1.Typed[ID, T](cache, opts...)
+}
+
+func WithKeyBuilder[ID comparable](build KeyBuilder[ID]) TypedOption[ID] {
+	return v1.WithKeyBuilder[ID](build)
+}
+
+func WithKeyTemplate[ID comparable](template string) TypedOption[ID] {
+	return v1.WithKeyTemplate[ID](template)
+}
+
+func NewTypedWithPrefix[ID comparable, T any](cache Cache, keyPrefix string, opts ...TypedOption[ID]) *PrefixedTypedCache[ID, T] {
+	return v1.NewTypedWithPrefix[ID, T](cache, keyPrefix, opts...)
+}
@@ -0,0 +1,75 @@
+// Package cache 是 v2 的两层缓存入口，实现与 v1 根包共用，区别在于：
+//
+//   - Cache 接口拆分为 Reader、Writer、Invalidator 和 Lifecycle，只需要读取的调用方可以只依赖 Reader；
+//   - 错误只定义在 errors 包中，不再提供 cache.ErrNotFound 和 cache.ErrNotFoundCached；
+//   - New 总是使用封装格式写入，仍能读取 v1 未封装的数据，不再提供 WithConfigEnvelope 和 WithConfigEnvelopeCompat。
+//
+// storage、serializer、errors 等包与 v1 共用，其余导出定义见 aliases.go
+package cache
+
+//go:generate go run ../internal/gen -src ../.. -pkg cache -exclude Cache,NewCache,ErrNotFound,ErrNotFoundCached,WithConfigEnvelope,WithConfigEnvelopeCompat -o aliases.go
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/biu7/layered-cache"
+)
+
+// Reader 缓存的读取操作
+type Reader interface {
+	Get(ctx context.Context, key string, target any, opts ...GetOption) error
+	MGet(ctx context.Context, keys []string, target any, opts ...GetOption) error
+	MGetWithMissing(ctx context.Context, keys []string, target any, opts ...GetOption) ([]string, error)
+	MultiFetch(ctx context.Context, requests []FetchRequest) ([]FetchResult, error)
+	Snapshot(ctx context.Context, keys []string) (View, error)
+	Exists(ctx context.Context, key string) (Existence, error)
+	TTL(ctx context.Context, key string) (memoryKnown bool, remoteTTL time.Duration, err error)
+	RemoteKeys(ctx context.Context, prefix string, cursor uint64, count int) ([]string, uint64, error)
+}
+
+// Writer 缓存的写入操作
+type Writer interface {
+	Set(ctx context.Context, key string, value any, opts ...SetOption) error
+	MSet(ctx context.Context, keyValues map[string]any, opts ...SetOption) error
+	SetNX(ctx context.Context, key string, value any, opts ...SetOption) (bool, error)
+	GetSet(ctx context.Context, key string, value any, target any, opts ...SetOption) error
+	Incr(ctx context.Context, key string, delta int64, opts ...SetOption) (int64, error)
+	Expire(ctx context.Context, key string, memoryTTL, remoteTTL time.Duration) error
+	MExpire(ctx context.Context, keys []string, memoryTTL, remoteTTL time.Duration) error
+	DependOn(ctx context.Context, child, parent string) error
+	Lock(ctx context.Context, key string, ttl time.Duration) (Unlock, error)
+}
+
+// Invalidator 缓存的删除和失效操作
+type Invalidator interface {
+	Delete(ctx context.Context, key string) error
+	MDelete(ctx context.Context, keys []string) error
+	DeleteByPrefix(ctx context.Context, prefix string) error
+	Invalidate(ctx context.Context, key string) error
+	InvalidateTag(ctx context.Context, tag string) error
+	ScheduleInvalidation(prefix string, cron string) (stop func(), err error)
+	Flush(ctx context.Context) error
+}
+
+// Lifecycle 缓存的运行状态和关闭
+type Lifecycle interface {
+	SweepMemory(ctx context.Context, budget time.Duration) (int, error)
+	Stats() Stats
+	Close(ctx context.Context) error
+}
+
+// Cache 完整的缓存接口，方法集与 v1 的 Cache 相同，两者的值可以直接互相赋值
+type Cache interface {
+	Reader
+	Writer
+	Invalidator
+	Lifecycle
+}
+
+var _ Cache = (*LayeredCache)(nil)
+
+// New 创建新的缓存实例，总是以封装格式写入；读取时兼容 v1 未封装的数据，v1 实例需要先开启 WithConfigEnvelopeCompat 才能读取 v2 写入的数据
+func New(opts ...Option) (Cache, error) {
+	return v1.NewCache(append(opts, v1.WithConfigEnvelope(true))...)
+}
@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	v1 "github.com/biu7/layered-cache"
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// v1 与 v2 的 Cache 方法集相同，可以直接互相赋值
+var (
+	_ v1.Cache = Cache(nil)
+	_ Cache    = v1.Cache(nil)
+)
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+	newRemote := func(t *testing.T) storage.Remote {
+		mr := miniredis.RunT(t)
+		return storage.NewRedisWithClient(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	}
+	newCache := func(t *testing.T, opts ...Option) Cache {
+		c, err := New(opts...)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = c.Close(context.Background()) })
+		return c
+	}
+
+	t.Run("以封装格式写入", func(t *testing.T) {
+		remote := newRemote(t)
+		c := newCache(t, WithConfigRemote(remote))
+		require.NoError(t, c.Set(ctx, "k", "v"))
+
+		// 开启兼容模式的 v1 实例可以读取
+		compat, err := v1.NewCache(v1.WithConfigRemote(remote), v1.WithConfigEnvelopeCompat(true))
+		require.NoError(t, err)
+		var value string
+		require.NoError(t, compat.Get(ctx, "k", &value))
+		assert.Equal(t, "v", value)
+
+		data, err := remote.Get(ctx, "k")
+		require.NoError(t, err)
+		assert.NotEqual(t, `"v"`, string(data), "Remote 中保存的是封装后的数据")
+	})
+
+	t.Run("读取v1未封装的数据", func(t *testing.T) {
+		remote := newRemote(t)
+		legacy, err := v1.NewCache(v1.WithConfigRemote(remote))
+		require.NoError(t, err)
+		require.NoError(t, legacy.Set(ctx, "k", "v1"))
+
+		c := newCache(t, WithConfigRemote(remote))
+		var value string
+		require.NoError(t, c.Get(ctx, "k", &value))
+		assert.Equal(t, "v1", value)
+	})
+
+	t.Run("错误只定义在errors包中", func(t *testing.T) {
+		c := newCache(t, WithConfigRemote(newRemote(t)))
+		var value string
+		assert.ErrorIs(t, c.Get(ctx, "missing", &value), errors.ErrNotFound)
+	})
+
+	t.Run("只依赖拆分后的接口", func(t *testing.T) {
+		c := newCache(t, WithConfigRemote(newRemote(t)))
+		var w Writer = c
+		var r Reader = c
+		require.NoError(t, w.Set(ctx, "k", "v"))
+		var value string
+		require.NoError(t, r.Get(ctx, "k", &value))
+		assert.Equal(t, "v", value)
+	})
+}
@@ -0,0 +1,322 @@
+// Command gen 根据 v1 根包的导出定义生成 v2 包中的别名文件，v1 新增导出定义后重新运行 go generate 即可同步
+//
+//	go run ../internal/gen -src ../.. -pkg cache -exclude NewCache -o aliases.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// v1Path v1 根包的导入路径，生成的代码以 v1 引用
+const v1Path = "github.com/biu7/layered-cache"
+
+func main() {
+	cfg, err := parseArgs(os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+	code, err := generate(cfg.src, cfg.pkg, cfg.excluded)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = os.WriteFile(cfg.out, code, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// config 命令行参数
+type config struct {
+	src, pkg, out string
+	excluded      map[string]bool
+}
+
+// parseArgs 解析命令行参数，测试中用于解析 go:generate 指令
+func parseArgs(args []string) (config, error) {
+	flags := flag.NewFlagSet("gen", flag.ContinueOnError)
+	src := flags.String("src", "../..", "v1 根包所在的目录")
+	pkg := flags.String("pkg", "", "生成文件的包名")
+	exclude := flags.String("exclude", "", "不生成别名的导出名，逗号分隔")
+	out := flags.String("o", "aliases.go", "输出文件")
+	if err := flags.Parse(args); err != nil {
+		return config{}, err
+	}
+
+	cfg := config{src: *src, pkg: *pkg, out: *out, excluded: make(map[string]bool)}
+	for _, name := range strings.Split(*exclude, ",") {
+		if name != "" {
+			cfg.excluded[name] = true
+		}
+	}
+	return cfg, nil
+}
+
+// generator 收集 v1 根包的导出定义
+type generator struct {
+	fset *token.FileSet
+
+	consts, vars []string
+	typeDecls    []string
+	funcs        []string
+
+	// imports 泛型函数签名引用的其他包
+	imports map[string]string
+}
+
+// generate 解析 src 中的非测试文件并生成 pkg 包的别名文件
+func generate(src, pkg string, excluded map[string]bool) ([]byte, error) {
+	g := &generator{fset: token.NewFileSet(), imports: make(map[string]string)}
+	pkgs, err := parser.ParseDir(g.fset, src, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := pkgs["cache"]
+	if !ok {
+		return nil, fmt.Errorf("%s: package cache not found", src)
+	}
+
+	files := make([]string, 0, len(root.Files))
+	for name := range root.Files {
+		files = append(files, name)
+	}
+	slices.Sort(files)
+
+	for _, name := range files {
+		if err = g.file(root.Files[name], excluded); err != nil {
+			return nil, err
+		}
+	}
+	return g.output(pkg)
+}
+
+// file 收集一个文件中的导出定义
+func (g *generator) file(f *ast.File, excluded map[string]bool) error {
+	imports := make(map[string]string)
+	for _, spec := range f.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = path
+	}
+
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.ValueSpec:
+					for _, name := range spec.Names {
+						if !name.IsExported() || excluded[name.Name] {
+							continue
+						}
+						if decl.Tok == token.CONST {
+							g.consts = append(g.consts, name.Name)
+						} else {
+							g.vars = append(g.vars, name.Name)
+						}
+					}
+				case *ast.TypeSpec:
+					if !spec.Name.IsExported() || excluded[spec.Name.Name] {
+						continue
+					}
+					decl, err := g.typeAlias(spec, imports)
+					if err != nil {
+						return err
+					}
+					g.typeDecls = append(g.typeDecls, decl)
+				}
+			}
+		case *ast.FuncDecl:
+			if decl.Recv != nil || !decl.Name.IsExported() || excluded[decl.Name.Name] {
+				continue
+			}
+			if decl.Type.TypeParams == nil {
+				g.vars = append(g.vars, decl.Name.Name)
+				continue
+			}
+			fn, err := g.genericFunc(decl, imports)
+			if err != nil {
+				return err
+			}
+			g.funcs = append(g.funcs, fn)
+		}
+	}
+	return nil
+}
+
+// typeAlias 生成类型别名，泛型类型带上相同的类型参数
+func (g *generator) typeAlias(spec *ast.TypeSpec, imports map[string]string) (string, error) {
+	if spec.TypeParams == nil {
+		return fmt.Sprintf("%s = v1.%s", spec.Name.Name, spec.Name.Name), nil
+	}
+	params, names, err := g.typeParams(spec.TypeParams, imports)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s[%s] = v1.%s[%s]", spec.Name.Name, params, spec.Name.Name, names), nil
+}
+
+// genericFunc 泛型函数不能赋值给变量，生成转发调用的同名函数；签名中的 v1 类型使用同名别名
+func (g *generator) genericFunc(decl *ast.FuncDecl, imports map[string]string) (string, error) {
+	typeParams, typeNames, err := g.typeParams(decl.Type.TypeParams, imports)
+	if err != nil {
+		return "", err
+	}
+
+	var params, args []string
+	for i, field := range decl.Type.Params.List {
+		typ, err := g.expr(field.Type, imports)
+		if err != nil {
+			return "", err
+		}
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{ast.NewIdent(fmt.Sprintf("p%d", i))}
+		}
+		for _, name := range names {
+			params = append(params, name.Name+" "+typ)
+			if _, ok := field.Type.(*ast.Ellipsis); ok {
+				args = append(args, name.Name+"...")
+			} else {
+				args = append(args, name.Name)
+			}
+		}
+	}
+
+	var results []string
+	if decl.Type.Results != nil {
+		for _, field := range decl.Type.Results.List {
+			typ, err := g.expr(field.Type, imports)
+			if err != nil {
+				return "", err
+			}
+			for range max(len(field.Names), 1) {
+				results = append(results, typ)
+			}
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "func %s[%s](%s)", decl.Name.Name, typeParams, strings.Join(params, ", "))
+	switch len(results) {
+	case 0:
+		fmt.Fprintf(&b, " {\n\tv1.%s[%s](%s)\n}", decl.Name.Name, typeNames, strings.Join(args, ", "))
+		return b.String(), nil
+	case 1:
+		fmt.Fprintf(&b, " %s", results[0])
+	default:
+		fmt.Fprintf(&b, " (%s)", strings.Join(results, ", "))
+	}
+	fmt.Fprintf(&b, " {\n\treturn v1.%s[%s](%s)\n}", decl.Name.Name, typeNames, strings.Join(args, ", "))
+	return b.String(), nil
+}
+
+// typeParams 返回类型参数列表及只包含参数名的列表
+func (g *generator) typeParams(list *ast.FieldList, imports map[string]string) (string, string, error) {
+	var params, names []string
+	for _, field := range list.List {
+		constraint, err := g.expr(field.Type, imports)
+		if err != nil {
+			return "", "", err
+		}
+		fieldNames := make([]string, len(field.Names))
+		for i, name := range field.Names {
+			fieldNames[i] = name.Name
+		}
+		params = append(params, strings.Join(fieldNames, ", ")+" "+constraint)
+		names = append(names, fieldNames...)
+	}
+	return strings.Join(params, ", "), strings.Join(names, ", "), nil
+}
+
+// expr 打印类型表达式，引用的其他包加入导入列表
+func (g *generator) expr(e ast.Expr, imports map[string]string) (string, error) {
+	var err error
+	ast.Inspect(e, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok || err != nil {
+			return err == nil
+		}
+		pkg := sel.X.(*ast.Ident).Name
+		path, ok := imports[pkg]
+		if !ok {
+			err = fmt.Errorf("unknown package %s", pkg)
+			return false
+		}
+		g.imports[pkg] = path
+		return false
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var b bytes.Buffer
+	if err = printer.Fprint(&b, g.fset, e); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// output 输出格式化后的别名文件
+func (g *generator) output(pkg string) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by go run ../internal/gen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\nimport (\n", pkg)
+	// 标准库在前，其余导入在后，与手写代码的分组一致
+	std, others := []string{}, []string{fmt.Sprintf("v1 %q", v1Path)}
+	for name, path := range g.imports {
+		spec := strconv.Quote(path)
+		if path[strings.LastIndex(path, "/")+1:] != name {
+			spec = name + " " + spec
+		}
+		if strings.Contains(strings.SplitN(path, "/", 2)[0], ".") {
+			others = append(others, spec)
+		} else {
+			std = append(std, spec)
+		}
+	}
+	for _, spec := range std {
+		fmt.Fprintf(&b, "\t%s\n", spec)
+	}
+	if len(std) > 0 {
+		b.WriteString("\n")
+	}
+	for _, spec := range others {
+		fmt.Fprintf(&b, "\t%s\n", spec)
+	}
+	b.WriteString(")\n")
+
+	block := func(keyword string, lines []string, format func(string) string) {
+		if len(lines) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n%s (\n", keyword)
+		for _, line := range lines {
+			fmt.Fprintf(&b, "\t%s\n", format(line))
+		}
+		b.WriteString(")\n")
+	}
+	alias := func(name string) string { return fmt.Sprintf("%s = v1.%s", name, name) }
+	block("const", g.consts, alias)
+	block("var", g.vars, alias)
+	block("type", g.typeDecls, func(decl string) string { return decl })
+	for _, fn := range g.funcs {
+		fmt.Fprintf(&b, "\n%s\n", fn)
+	}
+	return format.Source(b.Bytes())
+}
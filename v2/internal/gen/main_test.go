@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGeneratedUpToDate v1 新增或删除导出定义后，需要重新运行 go generate 更新别名文件
+func TestGeneratedUpToDate(t *testing.T) {
+	for _, dir := range []string{"../../cache", "../../v1compat"} {
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			args := generateArgs(t, dir)
+			cfg, err := parseArgs(args)
+			require.NoError(t, err)
+
+			want, err := generate(filepath.Join(dir, cfg.src), cfg.pkg, cfg.excluded)
+			require.NoError(t, err)
+			got, err := os.ReadFile(filepath.Join(dir, cfg.out))
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(got), "别名文件已过期，请运行 go generate ./...")
+		})
+	}
+}
+
+// generateArgs 返回 dir 中调用本命令的 go:generate 指令的参数
+func generateArgs(t *testing.T, dir string) []string {
+	const prefix = "//go:generate go run ../internal/gen "
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	require.NoError(t, err)
+	for _, name := range files {
+		f, err := os.Open(name)
+		require.NoError(t, err)
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if line := scanner.Text(); strings.HasPrefix(line, prefix) {
+				_ = f.Close()
+				return strings.Fields(strings.TrimPrefix(line, prefix))
+			}
+		}
+		_ = f.Close()
+	}
+	t.Fatalf("%s 中没有 go:generate 指令", dir)
+	return nil
+}
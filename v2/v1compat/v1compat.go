@@ -0,0 +1,27 @@
+// Package v1compat 帮助 v1 用户逐步迁移到 v2 模块：导出 v1 根包的全部名称（见 aliases.go），
+// 把导入路径从 github.com/biu7/layered-cache 换成本包即可编译，之后再逐个包改用 v2 的名称。
+//
+// NewCache、WithConfigEnvelope 等保持 v1 的行为，迁移期间存储的数据格式不会因为更换导入路径而改变；
+// Wrap 和 Unwrap 在 v1 的 Cache 与 v2 的 cache.Cache 之间转换，新旧代码可以互相传递缓存实例
+package v1compat
+
+//go:generate go run ../internal/gen -src ../.. -pkg v1compat -o aliases.go
+
+import (
+	"github.com/biu7/layered-cache/v2/cache"
+)
+
+// Wrap 将 v2 的缓存实例转换为 v1 的 Cache，用于传给仍使用 v1 名称的代码
+func Wrap(c cache.Cache) Cache {
+	return c
+}
+
+// Unwrap 将 v1 的 Cache 转换为 v2 的缓存实例，用于传给已经迁移到 v2 的代码
+func Unwrap(c Cache) cache.Cache {
+	return c
+}
+
+// Reader 返回 v1 Cache 的只读视图，迁移后只需要读取的代码可以只依赖 cache.Reader
+func Reader(c Cache) cache.Reader {
+	return c
+}
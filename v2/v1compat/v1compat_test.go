@@ -0,0 +1,45 @@
+package v1compat
+
+import (
+	"context"
+	"testing"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/biu7/layered-cache/storage"
+	"github.com/biu7/layered-cache/v2/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrap(t *testing.T) {
+	ctx := context.Background()
+	memory, err := storage.NewOtter(1000)
+	require.NoError(t, err)
+
+	t.Run("v2实例传给v1代码", func(t *testing.T) {
+		c, err := cache.New(cache.WithConfigMemory(memory))
+		require.NoError(t, err)
+
+		legacy := Wrap(c)
+		require.NoError(t, legacy.Set(ctx, "k", "v"))
+		var value string
+		require.NoError(t, c.Get(ctx, "k", &value))
+		assert.Equal(t, "v", value)
+		assert.Same(t, c, Unwrap(legacy))
+	})
+
+	t.Run("v1名称保持原有行为", func(t *testing.T) {
+		c, err := NewCache(WithConfigMemory(memory), WithConfigEnvelope(false))
+		require.NoError(t, err)
+
+		var value string
+		err = Unwrap(c).Get(ctx, "missing", &value)
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.ErrorIs(t, err, errors.ErrNotFound)
+
+		users := Typed[int, string](c)
+		require.NoError(t, users.Set(ctx, "user", 1, "alice"))
+		require.NoError(t, Reader(c).Get(ctx, "user:1", &value))
+		assert.Equal(t, "alice", value)
+	})
+}
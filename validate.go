@@ -0,0 +1,194 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Report 配置校验报告，描述生效的配置以及可能存在问题的组合
+type Report struct {
+	// MemoryAdapter 内存适配器类型，未配置时为空
+	MemoryAdapter string
+	// RemoteAdapter Remote 适配器类型，未配置时为空
+	RemoteAdapter string
+	// Serializer 序列化器类型
+	Serializer string
+
+	DefaultMemoryTTL        time.Duration
+	DefaultRemoteTTL        time.Duration
+	DefaultCacheNotFound    bool
+	DefaultCacheNotFoundTTL time.Duration
+
+	// Features 已开启的可选功能
+	Features []string
+
+	// Warnings 不会导致创建失败但可能不符合预期的配置组合
+	Warnings []string
+}
+
+// ValidateConfig 校验配置但不创建缓存实例（dry-run），返回生效配置的报告
+// 配置无效时返回与 NewCache 相同的错误；可在启动时打印报告，或在 CI 中断言没有警告
+func ValidateConfig(opts ...Option) (Report, error) {
+	config := newOptions()
+	if err := applyOptions(config, opts...); err != nil {
+		return Report{}, err
+	}
+	return newReport(config), nil
+}
+
+// newReport 根据配置生成报告
+func newReport(cfg *options) Report {
+	report := Report{
+		Serializer:              fmt.Sprintf("%T", cfg.serializer),
+		DefaultMemoryTTL:        cfg.defaultMemoryTTL,
+		DefaultRemoteTTL:        cfg.defaultRemoteTTL,
+		DefaultCacheNotFound:    cfg.defaultCacheNotFound,
+		DefaultCacheNotFoundTTL: cfg.defaultCacheNotFoundTTL,
+	}
+	hasMemory, hasRemote := cfg.memoryAdapter != nil, cfg.remoteAdapter != nil
+	if hasMemory {
+		report.MemoryAdapter = fmt.Sprintf("%T", cfg.memoryAdapter)
+	}
+	if hasRemote {
+		report.RemoteAdapter = fmt.Sprintf("%T", cfg.remoteAdapter)
+	}
+
+	feature := func(enabled bool, name string) {
+		if enabled {
+			report.Features = append(report.Features, name)
+		}
+	}
+	feature(cfg.readRepairRate > 0, fmt.Sprintf("read-repair(%g)", cfg.readRepairRate))
+	feature(cfg.deleteShieldTTL > 0, fmt.Sprintf("delete-shield(%s)", cfg.deleteShieldTTL))
+	feature(cfg.envelope && !cfg.envelopeLegacyWrite, "envelope")
+	feature(cfg.envelope && cfg.envelopeLegacyWrite, "envelope-compat(legacy-write)")
+	feature(cfg.demoteOnEvict, "demote-on-evict")
+	feature(cfg.coalesceWrites, "coalesce-writes")
+	feature(cfg.expvarName != "", fmt.Sprintf("expvar(%s)", cfg.expvarName))
+	feature(cfg.devMode, "dev-mode")
+	feature(cfg.strictMemorySize, "strict-memory-size")
+	feature(cfg.strictMemoryWrites, "strict-memory-writes")
+	feature(cfg.memoryCostFunc != nil, "memory-cost-func")
+	feature(cfg.dependencies, "dependencies")
+	feature(cfg.metrics != nil, "metrics")
+	feature(cfg.tracerProvider != nil, "tracing")
+	feature(len(cfg.metricsPrefixes) > 0, fmt.Sprintf("metrics-prefixes(%d)", len(cfg.metricsPrefixes)))
+	feature(cfg.batchChunkSize > 0, fmt.Sprintf("batch-chunk(%d)", cfg.batchChunkSize))
+	feature(cfg.remoteConcurrency > 1, fmt.Sprintf("remote-concurrency(%d)", cfg.remoteConcurrency))
+	feature(cfg.invalidationTransport != nil, "invalidation-transport")
+	feature(len(cfg.layers) > 0, fmt.Sprintf("layers(%d)", len(cfg.layers)))
+	feature(cfg.keyPrefix != "", fmt.Sprintf("key-prefix(%q)", cfg.keyPrefix))
+	feature(cfg.keyHasher != nil, "key-hasher")
+	if a := cfg.adaptiveBatch; a != nil {
+		feature(true, fmt.Sprintf("adaptive-batch(%s, %d-%d)", a.target, a.minSize, a.maxSize))
+	}
+	if p := cfg.siblingPrefetch; p != nil {
+		feature(true, fmt.Sprintf("sibling-prefetch(window %d, %d/s)", p.window, p.rate))
+	}
+	if m := cfg.maxValueSize; m != nil {
+		feature(true, fmt.Sprintf("max-value-size(%d, %s)", m.size, m.policy))
+	}
+	if h := cfg.hotKeys; h != nil {
+		feature(true, fmt.Sprintf("hot-keys(%s, %d)", h.window, h.threshold))
+	}
+	feature(cfg.ttlJitter > 0 && !cfg.memoryTTLJitter, fmt.Sprintf("ttl-jitter(%g)", cfg.ttlJitter))
+	feature(cfg.ttlJitter > 0 && cfg.memoryTTLJitter, fmt.Sprintf("ttl-jitter(%g, memory)", cfg.ttlJitter))
+	feature(len(cfg.valueMiddlewares) > 0, fmt.Sprintf("value-middleware(%d)", len(cfg.valueMiddlewares)))
+	feature(len(cfg.interceptors) > 0, fmt.Sprintf("interceptors(%d)", len(cfg.interceptors)))
+	if b := cfg.loaderBreaker; b != nil {
+		feature(true, fmt.Sprintf("loader-breaker(%d, %s)", b.failures, b.cooldown))
+	}
+	if b := cfg.remoteBreaker; b != nil {
+		feature(true, fmt.Sprintf("remote-breaker(%d, %s)", b.failures, b.cooldown))
+	}
+	if l := cfg.loaderRateLimit; l != nil {
+		feature(true, fmt.Sprintf("loader-rate-limit(%g/s, burst %d)", l.rate, l.burst))
+	}
+	feature(len(cfg.loaderPrefixes) > 0, fmt.Sprintf("loader-prefixes(%d)", len(cfg.loaderPrefixes)))
+	feature(cfg.defaultLoaderTimeout > 0, fmt.Sprintf("default-loader-timeout(%s)", cfg.defaultLoaderTimeout))
+	feature(cfg.poisonThreshold > 0, fmt.Sprintf("poison-threshold(%d)", cfg.poisonThreshold))
+	if a := cfg.asyncRemoteWrites; a != nil {
+		feature(true, fmt.Sprintf("async-remote-writes(queue %d, workers %d)", a.queueSize, a.workers))
+	}
+	feature(cfg.closeAdapters, "close-adapters")
+	feature(cfg.remoteFailurePolicy == FailOpen, "remote-fail-open")
+	if r := cfg.refreshAhead; r != nil {
+		feature(r.keys == nil, fmt.Sprintf("refresh-ahead(%s, hot %d)", r.interval, r.maxKeys))
+		feature(r.keys != nil, fmt.Sprintf("refresh-ahead(%s, keys %d)", r.interval, r.maxKeys))
+	}
+	if w := cfg.writeThrough; w != nil {
+		feature(w.policy == WriteThroughFailCall, "write-through(fail-call)")
+		feature(w.policy == WriteThroughAsync, "write-through(async)")
+	}
+
+	warn := func(format string, args ...any) {
+		report.Warnings = append(report.Warnings, fmt.Sprintf(format, args...))
+	}
+	if hasMemory && hasRemote && cfg.defaultMemoryTTL > cfg.defaultRemoteTTL {
+		warn("default memory ttl %s is longer than remote ttl %s, memory may serve data already expired in remote",
+			cfg.defaultMemoryTTL, cfg.defaultRemoteTTL)
+	}
+	if cfg.defaultCacheNotFound && hasRemote && cfg.defaultCacheNotFoundTTL > cfg.defaultRemoteTTL {
+		warn("not found ttl %s is longer than remote ttl %s, missing keys are cached longer than values",
+			cfg.defaultCacheNotFoundTTL, cfg.defaultRemoteTTL)
+	}
+	if cfg.readRepairRate > 0 && !(hasMemory && hasRemote) {
+		warn("read repair has no effect without both memory and remote adapters")
+	}
+	if cfg.coalesceWrites && !hasRemote {
+		warn("coalesce writes has no effect without a remote adapter")
+	}
+	if _, ok := cfg.metrics.(LabeledCollector); len(cfg.metricsPrefixes) > 0 && !ok {
+		warn("metrics prefixes have no effect without a LabeledCollector")
+	}
+	if cfg.memoryTTLJitter && cfg.ttlJitter <= 0 {
+		warn("memory ttl jitter has no effect without WithConfigTTLJitter")
+	}
+	if len(cfg.loaderPrefixes) > 0 && cfg.loaderBreaker == nil && cfg.loaderRateLimit == nil {
+		warn("loader prefixes have no effect without WithConfigLoaderBreaker or WithConfigLoaderRateLimit")
+	}
+	if cfg.asyncRemoteWrites != nil && !hasRemote {
+		warn("async remote writes have no effect without a remote adapter")
+	}
+	if cfg.remoteConcurrency > 1 && cfg.batchChunkSize == 0 {
+		warn("remote concurrency has no effect without WithConfigBatchChunkSize")
+	}
+	if cfg.remoteBreaker != nil && !hasRemote {
+		warn("remote breaker has no effect without a remote adapter")
+	}
+	if cfg.remoteFailurePolicy == FailOpen && !(hasMemory && hasRemote) {
+		warn("remote fail-open has no effect without both memory and remote adapters")
+	}
+	if cfg.strictMemorySize && !hasMemory {
+		warn("strict memory size has no effect without a memory adapter")
+	}
+	if cfg.strictMemoryWrites && !hasMemory {
+		warn("strict memory writes have no effect without a memory adapter")
+	}
+	if cfg.memoryCostFunc != nil && !hasMemory {
+		warn("memory cost func has no effect without a memory adapter")
+	}
+	return report
+}
+
+// String 返回适合打印到日志的多行文本
+func (r Report) String() string {
+	var b strings.Builder
+	orNone := func(s string) string {
+		if s == "" {
+			return "none"
+		}
+		return s
+	}
+
+	fmt.Fprintf(&b, "memory: %s, ttl %s\n", orNone(r.MemoryAdapter), r.DefaultMemoryTTL)
+	fmt.Fprintf(&b, "remote: %s, ttl %s\n", orNone(r.RemoteAdapter), r.DefaultRemoteTTL)
+	fmt.Fprintf(&b, "serializer: %s\n", r.Serializer)
+	fmt.Fprintf(&b, "cache not found: %t, ttl %s\n", r.DefaultCacheNotFound, r.DefaultCacheNotFoundTTL)
+	fmt.Fprintf(&b, "features: %s\n", orNone(strings.Join(r.Features, ", ")))
+	for _, warning := range r.Warnings {
+		fmt.Fprintf(&b, "warning: %s\n", warning)
+	}
+	return b.String()
+}
@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateConfig(t *testing.T) {
+	t.Run("默认配置", func(t *testing.T) {
+		report, err := ValidateConfig(
+			WithConfigMemory(createOtterAdapter(t)),
+			WithConfigRemote(createRemoteAdapter(t)),
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, "*storage.Otter", report.MemoryAdapter)
+		assert.Equal(t, "*storage.Redis", report.RemoteAdapter)
+		assert.Equal(t, "*serializer.sonicJson", report.Serializer)
+		assert.Equal(t, 5*time.Minute, report.DefaultMemoryTTL)
+		assert.Equal(t, 14*24*time.Hour, report.DefaultRemoteTTL)
+		assert.Empty(t, report.Features)
+		assert.Empty(t, report.Warnings)
+	})
+
+	t.Run("配置冲突", func(t *testing.T) {
+		report, err := ValidateConfig(
+			WithConfigMemory(createOtterAdapter(t)),
+			WithConfigRemote(createRemoteAdapter(t)),
+			WithConfigDefaultTTL(time.Hour, time.Minute),
+			WithConfigDefaultCacheNotFound(true, 2*time.Minute),
+			WithConfigEnvelope(true),
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"envelope"}, report.Features)
+		assert.Len(t, report.Warnings, 2)
+		assert.Contains(t, report.String(), "warning: not found ttl 2m0s is longer than remote ttl 1m0s")
+	})
+
+	t.Run("功能缺少对应的缓存层", func(t *testing.T) {
+		report, err := ValidateConfig(
+			WithConfigMemory(createOtterAdapter(t)),
+			WithConfigReadRepair(0.1),
+			WithConfigCoalesceWrites(true),
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"read-repair(0.1)", "coalesce-writes"}, report.Features)
+		assert.Len(t, report.Warnings, 2)
+		assert.Contains(t, report.String(), "remote: none")
+	})
+
+	t.Run("TTL 抖动", func(t *testing.T) {
+		report, err := ValidateConfig(
+			WithConfigMemory(createOtterAdapter(t)),
+			WithConfigTTLJitter(0.1),
+			WithConfigMemoryTTLJitter(true),
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ttl-jitter(0.1, memory)"}, report.Features)
+
+		report, err = ValidateConfig(
+			WithConfigMemory(createOtterAdapter(t)),
+			WithConfigMemoryTTLJitter(true),
+		)
+		assert.NoError(t, err)
+		assert.Empty(t, report.Features)
+		assert.Contains(t, report.Warnings, "memory ttl jitter has no effect without WithConfigTTLJitter")
+	})
+
+	t.Run("loader 熔断和限流", func(t *testing.T) {
+		report, err := ValidateConfig(
+			WithConfigMemory(createOtterAdapter(t)),
+			WithConfigLoaderBreaker(5, time.Second),
+			WithConfigLoaderRateLimit(100, 10),
+			WithConfigLoaderPrefixes("rec:"),
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"loader-breaker(5, 1s)", "loader-rate-limit(100/s, burst 10)", "loader-prefixes(1)"}, report.Features)
+
+		report, err = ValidateConfig(WithConfigMemory(createOtterAdapter(t)), WithConfigLoaderPrefixes("rec:"))
+		assert.NoError(t, err)
+		assert.Contains(t, report.Warnings, "loader prefixes have no effect without WithConfigLoaderBreaker or WithConfigLoaderRateLimit")
+	})
+
+	t.Run("异步 Remote 写入", func(t *testing.T) {
+		report, err := ValidateConfig(WithConfigMemory(createOtterAdapter(t)), WithConfigAsyncRemoteWrites(1000, 4))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"async-remote-writes(queue 1000, workers 4)"}, report.Features)
+		assert.Contains(t, report.Warnings, "async remote writes have no effect without a remote adapter")
+	})
+
+	t.Run("提前刷新", func(t *testing.T) {
+		report, err := ValidateConfig(WithConfigMemory(createOtterAdapter(t)), WithConfigRefreshAhead(time.Second, 100, nil))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"refresh-ahead(1s, hot 100)"}, report.Features)
+	})
+
+	t.Run("Remote 出错降级", func(t *testing.T) {
+		report, err := ValidateConfig(WithConfigMemory(createOtterAdapter(t)), WithConfigRemoteFailurePolicy(FailOpen))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"remote-fail-open"}, report.Features)
+		assert.Contains(t, report.Warnings, "remote fail-open has no effect without both memory and remote adapters")
+	})
+
+	t.Run("无效配置", func(t *testing.T) {
+		_, err := ValidateConfig()
+		assert.ErrorIs(t, err, errors.ErrAdapterRequired)
+	})
+}
@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+
+	"github.com/biu7/layered-cache/errors"
+)
+
+// ValueMiddleware 值字节的编解码中间件，例如压缩、加密、校验
+// 写入时在序列化之后按注册顺序调用 Encode，读取时按相反顺序调用 Decode；
+// 开启封装格式时作用于封装内的数据，封装头始终位于最外层
+type ValueMiddleware interface {
+	// Encode 处理写入缓存前的数据
+	Encode(data []byte) ([]byte, error)
+
+	// Decode 还原从缓存读取的数据
+	Decode(data []byte) ([]byte, error)
+}
+
+// valueMiddlewareFunc 由一对函数组成的 ValueMiddleware
+type valueMiddlewareFunc struct {
+	encode func([]byte) ([]byte, error)
+	decode func([]byte) ([]byte, error)
+}
+
+func (m valueMiddlewareFunc) Encode(data []byte) ([]byte, error) {
+	return m.encode(data)
+}
+
+func (m valueMiddlewareFunc) Decode(data []byte) ([]byte, error) {
+	return m.decode(data)
+}
+
+// NewValueMiddleware 使用一对编解码函数创建 ValueMiddleware
+func NewValueMiddleware(encode, decode func([]byte) ([]byte, error)) ValueMiddleware {
+	return valueMiddlewareFunc{encode: encode, decode: decode}
+}
+
+// checksumSize CRC32 校验和的长度
+const checksumSize = 4
+
+// ChecksumMiddleware 在数据末尾追加 CRC32 校验和，读取时校验不一致返回 ErrChecksumMismatch
+func ChecksumMiddleware() ValueMiddleware {
+	return NewValueMiddleware(
+		func(data []byte) ([]byte, error) {
+			return binary.BigEndian.AppendUint32(data[:len(data):len(data)], crc32.ChecksumIEEE(data)), nil
+		},
+		func(data []byte) ([]byte, error) {
+			if len(data) < checksumSize {
+				return nil, errors.ErrChecksumMismatch
+			}
+			n := len(data) - checksumSize
+			if binary.BigEndian.Uint32(data[n:]) != crc32.ChecksumIEEE(data[:n]) {
+				return nil, errors.ErrChecksumMismatch
+			}
+			return data[:n], nil
+		},
+	)
+}
+
+// encodeValue 按注册顺序调用中间件处理序列化后的数据
+func (c *LayeredCache) encodeValue(data []byte) ([]byte, error) {
+	var err error
+	for _, m := range c.valueMiddlewares {
+		if data, err = m.Encode(data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// decodeValue 按相反顺序调用中间件还原数据
+func (c *LayeredCache) decodeValue(data []byte) ([]byte, error) {
+	var err error
+	for i := len(c.valueMiddlewares) - 1; i >= 0; i-- {
+		if data, err = c.valueMiddlewares[i].Decode(data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
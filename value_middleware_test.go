@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// xorMiddleware 测试用的中间件，对每个字节异或 key
+func xorMiddleware(key byte) ValueMiddleware {
+	xor := func(data []byte) ([]byte, error) {
+		out := make([]byte, len(data))
+		for i, b := range data {
+			out[i] = b ^ key
+		}
+		return out, nil
+	}
+	return NewValueMiddleware(xor, xor)
+}
+
+// prefixMiddleware 测试用的中间件，写入时添加前缀，读取时校验并去掉前缀
+func prefixMiddleware(prefix string) ValueMiddleware {
+	return NewValueMiddleware(
+		func(data []byte) ([]byte, error) {
+			return append([]byte(prefix), data...), nil
+		},
+		func(data []byte) ([]byte, error) {
+			if !bytes.HasPrefix(data, []byte(prefix)) {
+				return nil, errors.New("missing prefix " + prefix)
+			}
+			return data[len(prefix):], nil
+		},
+	)
+}
+
+func TestLayeredCache_ValueMiddleware(t *testing.T) {
+	ctx := context.Background()
+	newCache := func(t *testing.T, opts ...Option) *LayeredCache {
+		opts = append([]Option{
+			WithConfigMemory(createOtterAdapter(t)),
+			WithConfigRemote(createRemoteAdapter(t)),
+		}, opts...)
+		c, err := NewCache(opts...)
+		assert.NoError(t, err)
+		return c.(*LayeredCache)
+	}
+
+	t.Run("按添加顺序编码，相反顺序解码", func(t *testing.T) {
+		c := newCache(t,
+			WithConfigValueMiddleware(prefixMiddleware("a:")),
+			WithConfigValueMiddleware(prefixMiddleware("b:")),
+		)
+		assert.NoError(t, c.Set(ctx, "key", "value"))
+
+		data, err := c.remote.Get(ctx, "key")
+		assert.NoError(t, err)
+		assert.Equal(t, "b:a:value", string(data))
+
+		c.memory.Delete("key")
+		var result string
+		assert.NoError(t, c.Get(ctx, "key", &result))
+		assert.Equal(t, "value", result)
+	})
+
+	t.Run("作用于封装内的数据", func(t *testing.T) {
+		c := newCache(t, WithConfigEnvelope(true), WithConfigValueMiddleware(xorMiddleware(0x5A)))
+		user := TestUser{ID: 1, Name: "Alice"}
+		assert.NoError(t, c.Set(ctx, "user", user))
+
+		data, exists := c.memory.Get("user")
+		assert.True(t, exists)
+		env, ok := decodeEnvelope(data)
+		assert.True(t, ok)
+		assert.NotContains(t, string(env.payload), "Alice")
+
+		var result TestUser
+		assert.NoError(t, c.Get(ctx, "user", &result))
+		assert.Equal(t, user, result)
+	})
+
+	t.Run("MGet和loader写入的数据同样经过中间件", func(t *testing.T) {
+		c := newCache(t, WithConfigValueMiddleware(xorMiddleware(0x5A)))
+		values := make(map[string]string)
+		err := c.MGet(ctx, []string{"a", "b"}, &values, WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+			return map[string]any{"a": "1", "b": "2"}, nil
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"a": "1", "b": "2"}, values)
+
+		data, err := c.remote.Get(ctx, "a")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte{'1' ^ 0x5A}, data)
+
+		values = make(map[string]string)
+		assert.NoError(t, c.MGet(ctx, []string{"a", "b"}, &values))
+		assert.Equal(t, map[string]string{"a": "1", "b": "2"}, values)
+	})
+
+	t.Run("解码失败返回中间件的错误", func(t *testing.T) {
+		c := newCache(t, WithConfigValueMiddleware(ChecksumMiddleware()))
+		assert.NoError(t, c.remote.Set(ctx, "corrupted", []byte("value\x00\x00\x00\x00"), 0))
+
+		var result string
+		assert.ErrorIs(t, c.Get(ctx, "corrupted", &result), errors.ErrChecksumMismatch)
+	})
+
+	t.Run("nil中间件", func(t *testing.T) {
+		_, err := NewCache(WithConfigMemory(createOtterAdapter(t)), WithConfigValueMiddleware(nil))
+		assert.ErrorIs(t, err, errors.ErrInvalidValueMiddleware)
+	})
+}
+
+func TestChecksumMiddleware(t *testing.T) {
+	m := ChecksumMiddleware()
+
+	data := []byte("value")
+	encoded, err := m.Encode(data)
+	assert.NoError(t, err)
+	assert.Len(t, encoded, len(data)+checksumSize)
+	assert.Equal(t, "value", string(data))
+
+	decoded, err := m.Decode(encoded)
+	assert.NoError(t, err)
+	assert.Equal(t, data, decoded)
+
+	encoded[0] ^= 0xFF
+	_, err = m.Decode(encoded)
+	assert.ErrorIs(t, err, errors.ErrChecksumMismatch)
+
+	_, err = m.Decode([]byte{1})
+	assert.ErrorIs(t, err, errors.ErrChecksumMismatch)
+}
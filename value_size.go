@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/biu7/layered-cache/errors"
+)
+
+// ValueSizePolicy 值超过 WithConfigMaxValueSize 上限时的处理策略
+type ValueSizePolicy int
+
+const (
+	// ValueSizeReject Set/MSet 返回 ErrValueTooLarge，不写入任何缓存层；loader 加载的值返回给调用方但不缓存
+	ValueSizeReject ValueSizePolicy = iota
+
+	// ValueSizeRemoteOnly 只写入 Remote，并删除内存缓存中的旧值
+	ValueSizeRemoteOnly
+
+	// ValueSizeSkip Set/MSet 不写入任何缓存层并删除各层中的旧值，后续读取回源；loader 加载的值返回给调用方但不缓存
+	// SetNX / GetSet 需要在 Remote 中原子完成，按 ValueSizeRemoteOnly 处理
+	ValueSizeSkip
+)
+
+func (p ValueSizePolicy) String() string {
+	switch p {
+	case ValueSizeReject:
+		return "reject"
+	case ValueSizeRemoteOnly:
+		return "remote-only"
+	case ValueSizeSkip:
+		return "skip"
+	}
+	return fmt.Sprintf("ValueSizePolicy(%d)", int(p))
+}
+
+// valueSizeLimit 值大小上限配置
+type valueSizeLimit struct {
+	size   int
+	policy ValueSizePolicy
+}
+
+// checkValueSize 策略为 ValueSizeReject 时检查写入的值是否超过上限
+func (c *LayeredCache) checkValueSize(key string, data []byte) error {
+	l := c.valueSize
+	if l == nil || l.policy != ValueSizeReject || len(data) <= l.size {
+		return nil
+	}
+	c.stats.oversizedValues.Add(1)
+	return fmt.Errorf("%w: key %s size %d, limit %d", errors.ErrValueTooLarge, key, len(data), l.size)
+}
+
+// oversized 返回超过上限的键，未设置上限或没有超过上限的值时返回 nil
+func (c *LayeredCache) oversized(data map[string][]byte) map[string]struct{} {
+	if c.valueSize == nil {
+		return nil
+	}
+	var keys map[string]struct{}
+	for key, value := range data {
+		if len(value) > c.valueSize.size {
+			if keys == nil {
+				keys = make(map[string]struct{})
+			}
+			keys[key] = struct{}{}
+		}
+	}
+	c.stats.oversizedValues.Add(int64(len(keys)))
+	return keys
+}
+
+// oversizedRemote 超过上限的值是否仍写入 Remote
+func (c *LayeredCache) oversizedRemote() bool {
+	return c.valueSize != nil && c.valueSize.policy == ValueSizeRemoteOnly
+}
+
+// dropOversized 删除超过上限的键在缓存中的旧值：只写入 Remote 时删除内存中的旧值，不缓存时删除各层中的旧值
+func (c *LayeredCache) dropOversized(ctx context.Context, keys map[string]struct{}) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	if !c.oversizedRemote() {
+		return c.deleteKeys(ctx, mapKeys(keys))
+	}
+	if c.memory != nil {
+		for key := range keys {
+			c.memory.Delete(key)
+		}
+	}
+	return nil
+}
+
+// withoutKeys 返回 data 中去掉 keys 后的副本，keys 为空时直接返回 data
+func withoutKeys(data map[string][]byte, keys map[string]struct{}) map[string][]byte {
+	if len(keys) == 0 {
+		return data
+	}
+	result := make(map[string][]byte, len(data))
+	for key, value := range data {
+		if _, ok := keys[key]; !ok {
+			result[key] = value
+		}
+	}
+	return result
+}
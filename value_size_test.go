@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_MaxValueSize(t *testing.T) {
+	ctx := context.Background()
+	large := strings.Repeat("x", 64)
+
+	newCache := func(t *testing.T, policy ValueSizePolicy) *LayeredCache {
+		cache, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigRemote(createRemoteAdapter(t)),
+			WithConfigMaxValueSize(32, policy),
+		)
+		assert.NoError(t, err)
+		return cache.(*LayeredCache)
+	}
+
+	t.Run("配置校验", func(t *testing.T) {
+		_, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigMaxValueSize(0, ValueSizeReject))
+		assert.ErrorIs(t, err, errors.ErrInvalidMaxValueSize)
+		_, err = NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigMaxValueSize(32, ValueSizePolicy(9)))
+		assert.ErrorIs(t, err, errors.ErrInvalidMaxValueSize)
+	})
+
+	t.Run("拒绝", func(t *testing.T) {
+		c := newCache(t, ValueSizeReject)
+		assert.ErrorIs(t, c.Set(ctx, "big", large), errors.ErrValueTooLarge)
+		assert.ErrorIs(t, c.MSet(ctx, map[string]any{"small": "ok", "big": large}), errors.ErrValueTooLarge)
+		assert.NoError(t, c.Set(ctx, "small", "ok"))
+
+		_, err := c.remote.Get(ctx, "big")
+		assert.ErrorIs(t, err, errors.ErrNotFound)
+
+		// loader 加载的值返回给调用方但不缓存
+		var value string
+		assert.NoError(t, c.Get(ctx, "loaded", &value, WithLoader(func(ctx context.Context, key string) (any, error) {
+			return large, nil
+		})))
+		assert.Equal(t, large, value)
+		_, ok := c.memory.Get("loaded")
+		assert.False(t, ok)
+		_, err = c.remote.Get(ctx, "loaded")
+		assert.ErrorIs(t, err, errors.ErrNotFound)
+		assert.Equal(t, int64(3), c.Stats().OversizedValues)
+	})
+
+	t.Run("只写入 Remote", func(t *testing.T) {
+		c := newCache(t, ValueSizeRemoteOnly)
+		assert.NoError(t, c.Set(ctx, "big", "old"))
+		assert.NoError(t, c.Set(ctx, "big", large))
+		_, ok := c.memory.Get("big")
+		assert.False(t, ok, "内存中的旧值被删除")
+
+		var value string
+		assert.NoError(t, c.Get(ctx, "big", &value))
+		assert.Equal(t, large, value)
+
+		assert.NoError(t, c.MSet(ctx, map[string]any{"small": "ok", "big2": large}))
+		_, ok = c.memory.Get("small")
+		assert.True(t, ok)
+		_, ok = c.memory.Get("big2")
+		assert.False(t, ok)
+		_, err := c.remote.Get(ctx, "big2")
+		assert.NoError(t, err)
+	})
+
+	t.Run("不缓存", func(t *testing.T) {
+		c := newCache(t, ValueSizeSkip)
+		assert.NoError(t, c.Set(ctx, "big", "old"))
+		assert.NoError(t, c.Set(ctx, "big", large))
+		_, ok := c.memory.Get("big")
+		assert.False(t, ok)
+		_, err := c.remote.Get(ctx, "big")
+		assert.ErrorIs(t, err, errors.ErrNotFound, "各层中的旧值被删除")
+
+		values := make(map[string]string)
+		assert.NoError(t, c.MGet(ctx, []string{"a", "b"}, &values, WithBatchLoader(func(ctx context.Context, keys []string) (map[string]any, error) {
+			return map[string]any{"a": "ok", "b": large}, nil
+		})))
+		assert.Equal(t, map[string]string{"a": "ok", "b": large}, values)
+		_, err = c.remote.Get(ctx, "a")
+		assert.NoError(t, err)
+		_, err = c.remote.Get(ctx, "b")
+		assert.ErrorIs(t, err, errors.ErrNotFound)
+	})
+}
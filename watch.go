@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+)
+
+// watchHub 按键登记 Watch，键被写入、删除或收到失效消息时唤醒
+type watchHub struct {
+	mu      sync.Mutex
+	watches map[string]map[chan struct{}]struct{}
+}
+
+// add 登记一个 Watch，返回的 channel 在键变化时收到通知，多次通知可能合并为一次
+func (h *watchHub) add(key string) chan struct{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.watches == nil {
+		h.watches = make(map[string]map[chan struct{}]struct{})
+	}
+	if h.watches[key] == nil {
+		h.watches[key] = make(map[chan struct{}]struct{})
+	}
+	ch := make(chan struct{}, 1)
+	h.watches[key][ch] = struct{}{}
+	return ch
+}
+
+// remove 取消登记
+func (h *watchHub) remove(key string, ch chan struct{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.watches[key], ch)
+	if len(h.watches[key]) == 0 {
+		delete(h.watches, key)
+	}
+}
+
+// notify 唤醒 keys 的所有 Watch，不阻塞
+func (h *watchHub) notify(keys []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.watches) == 0 {
+		return
+	}
+	for _, key := range keys {
+		for ch := range h.watches[key] {
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// Watch 监听键的值，值变化时调用 fn，适用于通过缓存近实时地下发配置和功能开关
+// 开始时键存在则先以当前值调用一次；之后本实例写入或删除该键、收到其他实例的失效消息（WithConfigInvalidationTransport）
+// 或到达轮询间隔（WithConfigWatchInterval）时重新读取，值与上次不同时调用 fn，键被删除时以 nil 调用。
+// 有 Remote 时以 Remote 为准，否则读取内存缓存；fn 收到的是序列化后的数据，读取出错时等待下次重新读取。
+// Watch 阻塞直到 ctx 结束或缓存关闭，分别返回 ctx.Err() 和 ErrClosed，通常在单独的协程中调用
+func (c *LayeredCache) Watch(ctx context.Context, key string, fn func(value []byte)) error {
+	scope, ctx := c.takeKeyContext(ctx)
+	key = scope.key(key)
+
+	changed := c.watches.add(key)
+	defer c.watches.remove(key, changed)
+
+	ticker := time.NewTicker(c.watchInterval)
+	defer ticker.Stop()
+
+	var last []byte
+	for {
+		data, exists, err := c.watchRead(ctx, key)
+		if err == nil {
+			if exists && (last == nil || !bytes.Equal(data, last)) {
+				fn(c.payload(data))
+			} else if !exists && last != nil {
+				fn(nil)
+			}
+			last = data
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.life.done:
+			return errors.ErrClosed
+		case <-changed:
+		case <-ticker.C:
+		}
+	}
+}
+
+// watchRead 读取键当前存储的数据，缺失值标记视为不存在
+func (c *LayeredCache) watchRead(ctx context.Context, key string) ([]byte, bool, error) {
+	if c.remote == nil {
+		data, exists := c.memory.Get(key)
+		if !exists || isNotFoundPlaceholder(data) {
+			return nil, false, nil
+		}
+		return data, true, nil
+	}
+
+	var data []byte
+	err := c.remoteCall(ctx, func(ctx context.Context) (err error) {
+		data, err = c.remote.Get(ctx, key)
+		return err
+	})
+	if IsNotFound(err) || (err == nil && isNotFoundPlaceholder(data)) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
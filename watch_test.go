@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_Watch(t *testing.T) {
+	ctx := context.Background()
+
+	// watch 在后台监听 key，返回收到的值和停止函数
+	watch := func(t *testing.T, c *LayeredCache, key string) (<-chan []byte, func() error) {
+		values := make(chan []byte, 16)
+		ctx, cancel := context.WithCancel(ctx)
+		done := make(chan error, 1)
+		go func() {
+			done <- c.Watch(ctx, key, func(value []byte) { values <- value })
+		}()
+		return values, func() error {
+			cancel()
+			return <-done
+		}
+	}
+	receive := func(t *testing.T, values <-chan []byte) []byte {
+		t.Helper()
+		select {
+		case value := <-values:
+			return value
+		case <-time.After(time.Second):
+			t.Fatal("没有收到变化通知")
+			return nil
+		}
+	}
+
+	t.Run("配置校验", func(t *testing.T) {
+		_, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigWatchInterval(0))
+		assert.ErrorIs(t, err, errors.ErrInvalidWatchInterval)
+	})
+
+	t.Run("本实例写入和删除", func(t *testing.T) {
+		c := createTestCache(t).(*LayeredCache)
+		assert.NoError(t, c.Set(ctx, "flag", "on"))
+
+		values, stop := watch(t, c, "flag")
+		assert.Equal(t, "on", string(receive(t, values)))
+
+		assert.NoError(t, c.Set(ctx, "flag", "off"))
+		assert.Equal(t, "off", string(receive(t, values)))
+
+		assert.NoError(t, c.Delete(ctx, "flag"))
+		assert.Nil(t, receive(t, values))
+
+		assert.ErrorIs(t, stop(), context.Canceled)
+	})
+
+	t.Run("轮询发现其他实例的写入", func(t *testing.T) {
+		cache, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigRemote(createRemoteAdapter(t)),
+			WithConfigWatchInterval(10*time.Millisecond),
+		)
+		assert.NoError(t, err)
+		c := cache.(*LayeredCache)
+
+		values, stop := watch(t, c, "flag")
+		defer stop()
+
+		assert.NoError(t, c.remote.Set(ctx, "flag", []byte(`"on"`), time.Minute))
+		assert.Equal(t, `"on"`, string(receive(t, values)))
+
+		select {
+		case value := <-values:
+			t.Fatalf("值没有变化时不应通知: %s", value)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("关闭缓存后返回", func(t *testing.T) {
+		c := createTestCache(t).(*LayeredCache)
+		done := make(chan error, 1)
+		go func() {
+			done <- c.Watch(ctx, "flag", func([]byte) {})
+		}()
+		assert.NoError(t, c.Close(ctx))
+		assert.ErrorIs(t, <-done, errors.ErrClosed)
+	})
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// writeCoalescer 按键串行化 Remote 写入，同一键同时只有一个写入在执行，
+// 执行期间到达的写入只保留最新的一个，被覆盖的写入等待并共享最新写入的结果
+type writeCoalescer struct {
+	mu    sync.Mutex
+	slots map[string]*writeSlot
+
+	// spawn 启动执行排队写入的后台任务，返回 false 时在当前调用中执行
+	spawn func(fn func()) bool
+}
+
+// writeSlot 单个键的写入状态
+type writeSlot struct {
+	// pending 等待执行的最新写入，为 nil 表示没有排队的写入
+	pending *pendingWrite
+}
+
+// pendingWrite 一次待执行的写入，被覆盖时原地替换数据，所有等待者共享同一个结果
+type pendingWrite struct {
+	ctx  context.Context
+	data []byte
+	ttl  time.Duration
+
+	done chan struct{}
+	err  error
+}
+
+type writeFunc func(ctx context.Context, data []byte, ttl time.Duration) error
+
+func newWriteCoalescer(spawn func(fn func()) bool) *writeCoalescer {
+	return &writeCoalescer{slots: make(map[string]*writeSlot), spawn: spawn}
+}
+
+// write 写入键，保证最后提交的写入最后执行
+func (w *writeCoalescer) write(ctx context.Context, key string, data []byte, ttl time.Duration, fn writeFunc) error {
+	w.mu.Lock()
+	slot, running := w.slots[key]
+	if running {
+		pw := slot.pending
+		if pw == nil {
+			pw = &pendingWrite{done: make(chan struct{})}
+			slot.pending = pw
+		}
+		// 覆盖尚未执行的写入，之前的等待者将得到本次写入的结果；
+		// 排队的写入可能代表多个调用方，不随单个调用方取消
+		pw.ctx, pw.data, pw.ttl = context.WithoutCancel(ctx), data, ttl
+		w.mu.Unlock()
+
+		select {
+		case <-pw.done:
+			return pw.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	slot = &writeSlot{}
+	w.slots[key] = slot
+	w.mu.Unlock()
+
+	err := fn(ctx, data, ttl)
+	if pw := w.next(key, slot); pw != nil {
+		if !w.spawn(func() { w.drain(key, slot, pw, fn) }) {
+			w.drain(key, slot, pw, fn)
+		}
+	}
+	return err
+}
+
+// drain 依次执行排队的写入，直到没有新的写入
+func (w *writeCoalescer) drain(key string, slot *writeSlot, pw *pendingWrite, fn writeFunc) {
+	for ; pw != nil; pw = w.next(key, slot) {
+		pw.err = fn(pw.ctx, pw.data, pw.ttl)
+		close(pw.done)
+	}
+}
+
+// next 取出排队的写入；没有排队的写入时释放该键
+func (w *writeCoalescer) next(key string, slot *writeSlot) *pendingWrite {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	pw := slot.pending
+	if pw == nil {
+		delete(w.slots, key)
+	}
+	slot.pending = nil
+	return pw
+}
+
+// setRemote 写入 Remote 缓存，开启写入合并时同一键的并发写入只执行最新的一个
+func (c *LayeredCache) setRemote(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	if c.writes == nil {
+		return c.remote.Set(ctx, key, data, ttl)
+	}
+	return c.writes.write(ctx, key, data, ttl, func(ctx context.Context, data []byte, ttl time.Duration) error {
+		return c.remote.Set(ctx, key, data, ttl)
+	})
+}
@@ -0,0 +1,174 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// gatedRemote Set 需要等待放行，用于构造并发写入
+type gatedRemote struct {
+	storage.Remote
+	gate     chan struct{}
+	setCalls atomic.Int32
+}
+
+func (r *gatedRemote) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	r.setCalls.Add(1)
+	<-r.gate
+	return r.Remote.Set(ctx, key, value, ttl)
+}
+
+// pendingData 返回键当前排队的写入数据
+func (w *writeCoalescer) pendingData(key string) []byte {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if slot, ok := w.slots[key]; ok && slot.pending != nil {
+		return slot.pending.data
+	}
+	return nil
+}
+
+func TestLayeredCache_CoalesceWrites(t *testing.T) {
+	ctx := context.Background()
+	remote := &gatedRemote{Remote: createRemoteAdapter(t), gate: make(chan struct{})}
+	cache, err := NewCache(
+		WithConfigRemote(remote),
+		WithConfigCoalesceWrites(true),
+	)
+	assert.NoError(t, err)
+	c := cache.(*LayeredCache)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+
+	// 第一个写入阻塞在 Remote
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errs[0] = c.Set(ctx, "hot", 0)
+	}()
+	assert.Eventually(t, func() bool { return remote.setCalls.Load() == 1 }, time.Second, time.Millisecond)
+
+	// 后续写入依次排队，只保留最新的一个
+	for i := 1; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = c.Set(ctx, "hot", i)
+		}(i)
+		want := []byte(fmt.Sprint(i))
+		assert.Eventually(t, func() bool { return string(c.writes.pendingData("hot")) == string(want) }, time.Second, time.Millisecond)
+	}
+
+	close(remote.gate)
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), remote.setCalls.Load())
+
+	var result int
+	assert.NoError(t, c.Get(ctx, "hot", &result))
+	assert.Equal(t, 9, result)
+
+	c.writes.mu.Lock()
+	assert.Empty(t, c.writes.slots)
+	c.writes.mu.Unlock()
+}
+
+func TestLayeredCache_CoalesceWrites_Close(t *testing.T) {
+	ctx := context.Background()
+	remote := &gatedRemote{Remote: createRemoteAdapter(t), gate: make(chan struct{})}
+	cache, err := NewCache(
+		WithConfigRemote(remote),
+		WithConfigCoalesceWrites(true),
+	)
+	assert.NoError(t, err)
+	c := cache.(*LayeredCache)
+
+	done := make(chan error)
+	go func() { done <- c.Set(ctx, "hot", 1) }()
+	assert.Eventually(t, func() bool { return remote.setCalls.Load() == 1 }, time.Second, time.Millisecond)
+
+	// 排队的写入在调用方取消后仍由后台任务执行
+	waitCtx, cancel := context.WithCancel(ctx)
+	waiter := make(chan error)
+	go func() { waiter <- c.Set(waitCtx, "hot", 2) }()
+	assert.Eventually(t, func() bool { return string(c.writes.pendingData("hot")) == "2" }, time.Second, time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-waiter, context.Canceled)
+
+	remote.gate <- struct{}{}
+	assert.NoError(t, <-done)
+	assert.Eventually(t, func() bool { return remote.setCalls.Load() == 2 }, time.Second, time.Millisecond)
+
+	// Close 等待后台写入完成
+	closeCtx, closeCancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer closeCancel()
+	assert.ErrorIs(t, c.Close(closeCtx), context.DeadlineExceeded)
+
+	remote.gate <- struct{}{}
+	assert.NoError(t, c.Close(ctx))
+	data, err := remote.Remote.Get(ctx, "hot")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("2"), data)
+}
+
+func TestLayeredCache_CoalesceWrites_Disabled(t *testing.T) {
+	ctx := context.Background()
+	remote := &gatedRemote{Remote: createRemoteAdapter(t), gate: make(chan struct{})}
+	close(remote.gate)
+	cache, err := NewCache(WithConfigRemote(remote))
+	assert.NoError(t, err)
+	c := cache.(*LayeredCache)
+	assert.Nil(t, c.writes)
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, c.Set(ctx, "key", i))
+	}
+	assert.Equal(t, int32(5), remote.setCalls.Load())
+}
+
+func TestWriteCoalescer_ContextCanceled(t *testing.T) {
+	w := newWriteCoalescer(func(fn func()) bool {
+		go fn()
+		return true
+	})
+	gate := make(chan struct{})
+	var written atomic.Value
+
+	fn := func(ctx context.Context, data []byte, ttl time.Duration) error {
+		<-gate
+		written.Store(string(data))
+		return nil
+	}
+
+	done := make(chan error)
+	go func() { done <- w.write(context.Background(), "k", []byte("a"), 0, fn) }()
+	assert.Eventually(t, func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return len(w.slots) == 1
+	}, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	waiter := make(chan error)
+	go func() { waiter <- w.write(ctx, "k", []byte("b"), 0, fn) }()
+	assert.Eventually(t, func() bool { return string(w.pendingData("k")) == "b" }, time.Second, time.Millisecond)
+
+	cancel()
+	assert.ErrorIs(t, <-waiter, context.Canceled)
+
+	close(gate)
+	assert.NoError(t, <-done)
+	assert.Eventually(t, func() bool { return written.Load() == "b" }, time.Second, time.Millisecond)
+}
@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+)
+
+// WriteThroughFunc Set/MSet 成功后同步派生存储（搜索索引、本地反范式表等）的回调，
+// value 为序列化后的值，不含封装头，也不经过值中间件
+type WriteThroughFunc func(ctx context.Context, key string, value []byte) error
+
+// WriteThroughReporter 异步写穿失败时的回调
+type WriteThroughReporter func(key string, err error)
+
+// WriteThroughPolicy 写穿回调失败时的处理策略
+type WriteThroughPolicy int
+
+const (
+	// WriteThroughFailCall 同步执行回调，失败时 Set/MSet 返回错误，缓存已写入的数据不会回滚
+	WriteThroughFailCall WriteThroughPolicy = iota
+
+	// WriteThroughAsync 在后台执行回调，失败只通知 reporter，不影响 Set/MSet 的结果
+	WriteThroughAsync
+)
+
+// writeThrough 写穿配置
+type writeThrough struct {
+	fn       WriteThroughFunc
+	policy   WriteThroughPolicy
+	reporter WriteThroughReporter
+}
+
+// writeThrough 缓存写入成功后调用写穿回调，data 为写入缓存的数据
+func (c *LayeredCache) writeThrough(ctx context.Context, data map[string][]byte) error {
+	w := c.writeThroughHook
+	if w == nil || len(data) == 0 {
+		return nil
+	}
+
+	if w.policy == WriteThroughAsync {
+		ctx = context.WithoutCancel(ctx)
+		run := func() {
+			for key, value := range data {
+				if err := w.fn(ctx, key, c.payload(value)); err != nil && w.reporter != nil {
+					w.reporter(key, err)
+				}
+			}
+		}
+		// 缓存关闭后不再启动后台任务，改为同步执行
+		if !c.spawn(run) {
+			run()
+		}
+		return nil
+	}
+
+	for key, value := range data {
+		if err := w.fn(ctx, key, c.payload(value)); err != nil {
+			return fmt.Errorf("write through %s: %w", key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/biu7/layered-cache/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayeredCache_WriteThrough(t *testing.T) {
+	ctx := context.Background()
+	failed := stderrors.New("index unavailable")
+
+	t.Run("配置校验", func(t *testing.T) {
+		_, err := NewCache(WithConfigMemory(createMemoryAdapter(t)), WithConfigWriteThrough(nil, WriteThroughFailCall, nil))
+		assert.ErrorIs(t, err, errors.ErrInvalidWriteThrough)
+	})
+
+	t.Run("Set和MSet成功后同步回调", func(t *testing.T) {
+		indexed := make(map[string]string)
+		c, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigRemote(createRemoteAdapter(t)),
+			WithConfigEnvelope(true),
+			WithConfigWriteThrough(func(ctx context.Context, key string, value []byte) error {
+				indexed[key] = string(value)
+				return nil
+			}, WriteThroughFailCall, nil),
+		)
+		assert.NoError(t, err)
+
+		assert.NoError(t, c.Set(ctx, "product:1", "phone"))
+		assert.NoError(t, c.MSet(ctx, map[string]any{"product:2": "laptop", "product:3": "tablet"}))
+		assert.Equal(t, map[string]string{"product:1": "phone", "product:2": "laptop", "product:3": "tablet"}, indexed)
+	})
+
+	t.Run("同步回调失败时返回错误", func(t *testing.T) {
+		c, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigWriteThrough(func(ctx context.Context, key string, value []byte) error {
+				return failed
+			}, WriteThroughFailCall, nil),
+		)
+		assert.NoError(t, err)
+
+		assert.ErrorIs(t, c.Set(ctx, "product:1", "phone"), failed)
+
+		var value string
+		assert.NoError(t, c.Get(ctx, "product:1", &value), "缓存已写入")
+		assert.Equal(t, "phone", value)
+	})
+
+	t.Run("异步回调失败只通知reporter", func(t *testing.T) {
+		var mu sync.Mutex
+		var reported []string
+		done := make(chan struct{})
+		c, err := NewCache(
+			WithConfigMemory(createMemoryAdapter(t)),
+			WithConfigWriteThrough(func(ctx context.Context, key string, value []byte) error {
+				return failed
+			}, WriteThroughAsync, func(key string, err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				reported = append(reported, key)
+				close(done)
+			}),
+		)
+		assert.NoError(t, err)
+
+		assert.NoError(t, c.Set(ctx, "product:1", "phone"))
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("reporter not called")
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, []string{"product:1"}, reported)
+	})
+}